@@ -11,6 +11,7 @@ import (
 
 	"github.com/chaosduck/backend-go/internal/config"
 	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
 	"github.com/chaosduck/backend-go/internal/engine"
 	"github.com/chaosduck/backend-go/internal/handler"
 	"github.com/chaosduck/backend-go/internal/observability"
@@ -26,7 +27,8 @@ func main() {
 	if err != nil {
 		log.Printf("Warning: database not available: %v", err)
 	}
-	var queries *db.Queries
+	// Stays a nil interface without a database, which the nil checks downstream rely on
+	var queries db.Querier
 	if pool != nil {
 		queries = db.New(pool)
 		defer pool.Close()
@@ -35,7 +37,12 @@ func main() {
 	// Safety stack
 	esm := safety.NewEmergencyStopManager()
 	rollbackMgr := safety.NewRollbackManager()
+	rollbackMgr.SetQueries(queries)
 	snapshotMgr := safety.NewSnapshotManager(queries)
+	freezeMgr := safety.NewNamespaceFreezeManager(queries)
+	if err := freezeMgr.Load(ctx); err != nil {
+		log.Printf("Warning: namespace freezes not restored: %v", err)
+	}
 
 	// Engines (fail gracefully if not available)
 	var k8sEngine *engine.K8sEngine
@@ -43,6 +50,17 @@ func main() {
 	if err != nil {
 		log.Printf("Warning: K8s engine not available: %v", err)
 		k8sEngine = nil
+	} else {
+		k8sEngine.SetPodConcurrency(cfg.PodMutationConcurrency)
+		k8sEngine.SetPodReadyWait(time.Duration(cfg.PodReadyWaitSeconds) * time.Second)
+		k8sEngine.SetExecCommands(engine.ExecCommands{
+			TC:        cfg.TCBin,
+			Stress:    cfg.StressBin,
+			Pkill:     cfg.PkillBin,
+			NTPServer: cfg.ClockNTPServer,
+			Prefix:    cfg.ExecCommandPrefix,
+		})
+		k8sEngine.SetAPIRetry(cfg.K8sAPIRetryAttempts, time.Duration(cfg.K8sAPIRetryBackoffMs)*time.Millisecond)
 	}
 
 	var awsEngine *engine.AwsEngine
@@ -52,27 +70,92 @@ func main() {
 		awsEngine = nil
 	}
 
-	// Runner
-	runner := engine.NewRunner(k8sEngine, awsEngine, esm, rollbackMgr, snapshotMgr, queries, cfg.AIServiceURL)
-
 	// Metrics
-	metrics := observability.NewMetrics()
+	metrics := observability.NewMetrics(cfg.ExperimentDurationBuckets)
+	rollbackMgr.SetObserver(metrics.RecordRollback)
+
+	// Runner
+	runner := engine.NewRunner(k8sEngine, awsEngine, esm, rollbackMgr, snapshotMgr, queries, metrics, cfg.AIServiceURL)
+	aiTimeouts := engine.AITimeouts{
+		Request: time.Duration(cfg.AIRequestTimeoutSeconds) * time.Second,
+		Long:    time.Duration(cfg.AILongRequestTimeoutSeconds) * time.Second,
+	}
+	snapshotMgr.SetActiveCheck(runner.IsRunning)
+	runner.SetAITimeouts(aiTimeouts)
+	runner.SetAIToken(cfg.AIServiceToken)
+	runner.SetProbeCertDir(cfg.ProbeCertDir)
+	runner.SetFreezeManager(freezeMgr)
+	runner.SetAllowedChaosTypes(cfg.AllowedChaosTypes)
+	runner.SetSafeMode(cfg.SafeMode)
+	runner.SetPersistRetry(engine.PersistRetry{
+		Attempts: cfg.PersistRetryAttempts,
+		Backoff:  time.Duration(cfg.PersistRetryBackoffMs) * time.Millisecond,
+		SpillDir: cfg.PersistSpillDir,
+	})
 
 	// Handlers
 	chaosHandler := handler.NewChaosHandler(runner, queries, esm, rollbackMgr, metrics)
-	topoHandler := handler.NewTopologyHandler(k8sEngine, awsEngine)
-	analysisHandler := handler.NewAnalysisHandler(queries, cfg.AIServiceURL)
+	if cfg.SequentialMode {
+		chaosHandler.EnableSequentialMode(cfg.QueueMaxDepth)
+		log.Printf("Sequential mode: experiments run one at a time (queue depth %d)", cfg.QueueMaxDepth)
+	}
+	if cfg.SafeMode {
+		chaosHandler.EnableSafeMode()
+		log.Printf("Safe mode: every experiment is forced to dry-run")
+	}
+	topoHandler := handler.NewTopologyHandler(k8sEngine, awsEngine, time.Duration(cfg.TopologyCacheTTLSeconds)*time.Second)
+	analysisHandler := handler.NewAnalysisHandler(queries, cfg.AIServiceURL, aiTimeouts, metrics)
+	analysisHandler.SetAIToken(cfg.AIServiceToken)
+	analysisHandler.SetResilienceWeights(domain.ResilienceWeights{
+		ProbePassRate:       cfg.ResilienceWeightProbes,
+		HealthyRecovery:     cfg.ResilienceWeightRecovery,
+		RollbackSuccess:     cfg.ResilienceWeightRollback,
+		HypothesisAdherence: cfg.ResilienceWeightHypothesis,
+	})
+	healthHandler := handler.NewHealthHandler(pool, k8sEngine, awsEngine, cfg.AIServiceURL)
+
+	// Reap experiments left running by a previous process
+	if orphans, err := runner.ReconcileOrphaned(ctx); err != nil {
+		log.Printf("Warning: startup reconciliation failed: %v", err)
+	} else if len(orphans) > 0 {
+		log.Printf("Startup reconciliation marked %d orphaned experiment(s) failed", len(orphans))
+	}
+
+	// Delete old finished experiments when a retention period is set
+	janitorCtx, stopJanitor := context.WithCancel(ctx)
+	defer stopJanitor()
+	if queries != nil && cfg.ExperimentRetentionDays > 0 {
+		cleaner := engine.NewRetentionCleaner(queries,
+			time.Duration(cfg.ExperimentRetentionDays)*24*time.Hour,
+			time.Duration(max(cfg.RetentionSweepIntervalMinutes, 1))*time.Minute)
+		cleaner.SetDeleteHook(chaosHandler.InvalidateExperiment)
+		go cleaner.Run(janitorCtx)
+		log.Printf("Retention: finished experiments are deleted after %d day(s)", cfg.ExperimentRetentionDays)
+	}
+	// Runs still inside their timeout at startup are reaped once they outlive it
+	if queries != nil && cfg.ReconcileIntervalSeconds > 0 {
+		go runner.ReconcilePeriodically(janitorCtx, time.Duration(cfg.ReconcileIntervalSeconds)*time.Second)
+	}
 
 	// Router
-	r := handler.SetupRouter(chaosHandler, topoHandler, analysisHandler, esm, metrics, cfg.CORSAllowOrigin)
+	r := handler.SetupRouter(chaosHandler, topoHandler, analysisHandler, healthHandler, esm, freezeMgr, snapshotMgr, metrics, cfg.CORSAllowOrigin, int64(cfg.MaxRequestBodyBytes))
+	if cfg.EnablePprof {
+		if cfg.PprofToken == "" {
+			log.Printf("Warning: ENABLE_PPROF is set without PPROF_TOKEN; /debug/pprof will refuse every request")
+		}
+		handler.MountPprof(r, cfg.PprofToken)
+		log.Printf("Profiling: /debug/pprof enabled")
+	}
 
 	// Server with graceful shutdown and timeouts
 	srv := &http.Server{
-		Addr:         ":" + cfg.ServerPort,
-		Handler:      r,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 180 * time.Second, // long for experiment execution
-		IdleTimeout:  60 * time.Second,
+		Addr:              ":" + cfg.ServerPort,
+		Handler:           r,
+		ReadHeaderTimeout: time.Duration(cfg.ServerReadHeaderTimeoutSeconds) * time.Second,
+		ReadTimeout:       time.Duration(cfg.ServerReadTimeoutSeconds) * time.Second,
+		WriteTimeout:      time.Duration(cfg.ServerWriteTimeoutSeconds) * time.Second, // long for experiment execution
+		IdleTimeout:       time.Duration(cfg.ServerIdleTimeoutSeconds) * time.Second,
+		MaxHeaderBytes:    cfg.ServerMaxHeaderBytes,
 	}
 
 	go func() {
@@ -86,14 +169,27 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	stopJanitor()
+
+	// Running experiments get the grace period to finish, so none is
+	// rolled back mid-injection; the rest are aborted and roll themselves back
+	grace := time.Duration(cfg.ShutdownGraceSeconds) * time.Second
+	drainCtx, cancelDrain := context.WithTimeout(ctx, grace+10*time.Second)
+	defer cancelDrain()
+	log.Printf("Shutting down... waiting up to %v for running experiments", grace)
+	if aborted := runner.Drain(drainCtx, grace); len(aborted) > 0 {
+		log.Printf("Aborted %d experiment(s) still running after the grace period: %v", len(aborted), aborted)
+	}
 
-	log.Println("Shutting down... triggering emergency stop")
-	esm.Trigger()
-	rollbackMgr.RollbackAll()
-
+	// Rollback and server shutdown share the 10-second window
 	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
+	log.Println("Triggering emergency stop")
+	esm.Trigger()
+	rollbackMgr.RollbackAll(shutdownCtx)
+	chaosHandler.DrainQueue(shutdownCtx)
+
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Fatalf("Server forced shutdown: %v", err)
 	}
@@ -12,20 +12,56 @@ func TestLoadDefaults(t *testing.T) {
 	assert.Equal(t, "8080", cfg.ServerPort)
 	assert.Contains(t, cfg.DatabaseURL, "chaosduck")
 	assert.Equal(t, "http://localhost:8001", cfg.AIServiceURL)
+	assert.Empty(t, cfg.AIServiceToken)
 	assert.Equal(t, "us-east-1", cfg.AWSRegion)
 	assert.Equal(t, "http://localhost:5173", cfg.CORSAllowOrigin)
+	assert.Equal(t, 30, cfg.TopologyCacheTTLSeconds)
+	assert.Equal(t, 30, cfg.AIRequestTimeoutSeconds)
+	assert.Equal(t, 60, cfg.AILongRequestTimeoutSeconds)
+	assert.Empty(t, cfg.AllowedChaosTypes)
+	assert.False(t, cfg.SequentialMode)
+	assert.Equal(t, 20, cfg.QueueMaxDepth)
+	assert.Equal(t, 20, cfg.ShutdownGraceSeconds)
+	assert.Equal(t, 3, cfg.PersistRetryAttempts)
+	assert.Zero(t, cfg.PodReadyWaitSeconds)
+	assert.Equal(t, 200, cfg.PersistRetryBackoffMs)
+	assert.Empty(t, cfg.PersistSpillDir)
+	assert.Equal(t, 0, cfg.ExperimentRetentionDays)
+	assert.Equal(t, 60, cfg.RetentionSweepIntervalMinutes)
+	assert.Equal(t, 60, cfg.ReconcileIntervalSeconds)
+	assert.False(t, cfg.EnablePprof)
+	assert.Empty(t, cfg.PprofToken)
+	assert.Equal(t, "tc", cfg.TCBin)
+	assert.Equal(t, "stress-ng", cfg.StressBin)
+	assert.Equal(t, "pkill", cfg.PkillBin)
+	assert.Empty(t, cfg.ExecCommandPrefix)
+	assert.Empty(t, cfg.ClockNTPServer)
+	assert.Empty(t, cfg.ProbeCertDir)
+	assert.Equal(t, 3, cfg.K8sAPIRetryAttempts)
+	assert.Equal(t, 200, cfg.K8sAPIRetryBackoffMs)
+	assert.Nil(t, cfg.ExperimentDurationBuckets)
+	assert.Equal(t, 0.3, cfg.ResilienceWeightProbes)
+	assert.Equal(t, 0.25, cfg.ResilienceWeightRecovery)
+	assert.Equal(t, 0.25, cfg.ResilienceWeightRollback)
+	assert.Equal(t, 0.2, cfg.ResilienceWeightHypothesis)
 }
 
 func TestLoadFromEnv(t *testing.T) {
 	t.Setenv("SERVER_PORT", "9090")
 	t.Setenv("AI_SERVICE_URL", "http://ai:8001")
 	t.Setenv("AWS_DEFAULT_REGION", "ap-northeast-2")
+	t.Setenv("AI_REQUEST_TIMEOUT_SECONDS", "5")
+	t.Setenv("TC_BIN", "/usr/sbin/tc")
+	t.Setenv("EXEC_COMMAND_PREFIX", " sudo  -n ")
 
 	cfg := Load()
 
 	assert.Equal(t, "9090", cfg.ServerPort)
 	assert.Equal(t, "http://ai:8001", cfg.AIServiceURL)
 	assert.Equal(t, "ap-northeast-2", cfg.AWSRegion)
+	assert.Equal(t, 5, cfg.AIRequestTimeoutSeconds)
+	assert.Equal(t, "/usr/sbin/tc", cfg.TCBin)
+	assert.Equal(t, []string{"sudo", "-n"}, cfg.ExecCommandPrefix)
 }
 
 func TestEnvInt(t *testing.T) {
@@ -37,3 +73,49 @@ func TestEnvInt(t *testing.T) {
 	t.Setenv("TEST_BAD_INT", "notanumber")
 	assert.Equal(t, 42, EnvInt("TEST_BAD_INT", 42))
 }
+
+func TestEnvFloat(t *testing.T) {
+	assert.Equal(t, 0.5, EnvFloat("NONEXISTENT_VAR", 0.5))
+
+	t.Setenv("TEST_FLOAT", "1.5")
+	assert.Equal(t, 1.5, EnvFloat("TEST_FLOAT", 0.5))
+
+	t.Setenv("TEST_NEGATIVE_FLOAT", "-1")
+	assert.Equal(t, 0.5, EnvFloat("TEST_NEGATIVE_FLOAT", 0.5))
+
+	t.Setenv("TEST_BAD_FLOAT", "heavy")
+	assert.Equal(t, 0.5, EnvFloat("TEST_BAD_FLOAT", 0.5))
+}
+
+func TestEnvBool(t *testing.T) {
+	assert.True(t, EnvBool("NONEXISTENT_VAR", true))
+
+	t.Setenv("TEST_BOOL", "true")
+	assert.True(t, EnvBool("TEST_BOOL", false))
+	t.Setenv("TEST_BOOL", "nope")
+	assert.False(t, EnvBool("TEST_BOOL", false))
+}
+
+func TestEnvList(t *testing.T) {
+	assert.Nil(t, EnvList("NONEXISTENT_VAR"))
+
+	t.Setenv("TEST_LIST", " network_latency, network_loss ,,")
+	assert.Equal(t, []string{"network_latency", "network_loss"}, EnvList("TEST_LIST"))
+}
+
+func TestEnvFloatList(t *testing.T) {
+	fallback := []float64{1, 2}
+	assert.Equal(t, fallback, EnvFloatList("NONEXISTENT_VAR", fallback))
+
+	t.Setenv("TEST_FLOATS", "0.5, 1,90,300")
+	assert.Equal(t, []float64{0.5, 1, 90, 300}, EnvFloatList("TEST_FLOATS", fallback))
+
+	t.Setenv("TEST_FLOATS", "1,five,10")
+	assert.Equal(t, fallback, EnvFloatList("TEST_FLOATS", fallback))
+
+	t.Setenv("TEST_FLOATS", "10,5,30")
+	assert.Equal(t, fallback, EnvFloatList("TEST_FLOATS", fallback))
+
+	t.Setenv("TEST_FLOATS", "5,5")
+	assert.Equal(t, fallback, EnvFloatList("TEST_FLOATS", fallback))
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxCachedExperiments bounds how many records an ExperimentCache holds
+const maxCachedExperiments = 1024
+
+// ExperimentCache is a short-TTL read-through cache in front of GetExperiment.
+// Concurrent misses for the same ID share a single query, and the write
+// wrappers invalidate the entry so status changes are never hidden. Once the
+// cache is full, expired entries are pruned and then the oldest is evicted.
+type ExperimentCache struct {
+	q          Querier
+	ttl        time.Duration
+	now        func() time.Time
+	maxEntries int
+
+	mu       sync.Mutex
+	entries  map[string]cachedExperiment
+	inflight map[string]*experimentFetch
+}
+
+type cachedExperiment struct {
+	rec       Experiment
+	expiresAt time.Time
+}
+
+type experimentFetch struct {
+	done chan struct{}
+	rec  Experiment
+	err  error
+}
+
+// NewExperimentCache creates a cache that keeps records for ttl
+func NewExperimentCache(q Querier, ttl time.Duration) *ExperimentCache {
+	return &ExperimentCache{
+		q:          q,
+		ttl:        ttl,
+		now:        time.Now,
+		maxEntries: maxCachedExperiments,
+		entries:    make(map[string]cachedExperiment),
+		inflight:   make(map[string]*experimentFetch),
+	}
+}
+
+// GetExperiment returns a cached record or fetches it from the database
+func (c *ExperimentCache) GetExperiment(ctx context.Context, id string) (Experiment, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[id]; ok && c.now().Before(e.expiresAt) {
+		c.mu.Unlock()
+		return e.rec, nil
+	}
+	if f, ok := c.inflight[id]; ok {
+		c.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.rec, f.err
+		case <-ctx.Done():
+			return Experiment{}, ctx.Err()
+		}
+	}
+	f := &experimentFetch{done: make(chan struct{})}
+	c.inflight[id] = f
+	c.mu.Unlock()
+
+	f.rec, f.err = c.q.GetExperiment(ctx, id)
+
+	c.mu.Lock()
+	// A write during the fetch removes the in-flight marker; don't cache a
+	// result that may predate it
+	if c.inflight[id] == f {
+		delete(c.inflight, id)
+		if f.err == nil {
+			c.store(id, f.rec)
+		}
+	}
+	c.mu.Unlock()
+	close(f.done)
+
+	return f.rec, f.err
+}
+
+// store caches rec under id, making room first when the cache is full.
+// Callers hold c.mu.
+func (c *ExperimentCache) store(id string, rec Experiment) {
+	now := c.now()
+	if _, ok := c.entries[id]; !ok && len(c.entries) >= c.maxEntries {
+		for key, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+		// Every entry shares the TTL, so the one expiring first is the oldest
+		for len(c.entries) >= c.maxEntries {
+			oldest, oldestAt := "", time.Time{}
+			for key, e := range c.entries {
+				if oldest == "" || e.expiresAt.Before(oldestAt) {
+					oldest, oldestAt = key, e.expiresAt
+				}
+			}
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[id] = cachedExperiment{rec: rec, expiresAt: now.Add(c.ttl)}
+}
+
+// Invalidate drops any cached record for id
+func (c *ExperimentCache) Invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+	delete(c.inflight, id)
+}
+
+// UpdateExperiment writes through to the database and invalidates the entry
+func (c *ExperimentCache) UpdateExperiment(ctx context.Context, arg UpdateExperimentParams) error {
+	defer c.Invalidate(arg.ID)
+	return c.q.UpdateExperiment(ctx, arg)
+}
+
+// UpdateExperimentStatus writes through to the database and invalidates the entry
+func (c *ExperimentCache) UpdateExperimentStatus(ctx context.Context, arg UpdateExperimentStatusParams) error {
+	defer c.Invalidate(arg.ID)
+	return c.q.UpdateExperimentStatus(ctx, arg)
+}
+
+// UpdateExperimentConfig writes through to the database and invalidates the entry
+func (c *ExperimentCache) UpdateExperimentConfig(ctx context.Context, arg UpdateExperimentConfigParams) (Experiment, error) {
+	defer c.Invalidate(arg.ID)
+	return c.q.UpdateExperimentConfig(ctx, arg)
+}
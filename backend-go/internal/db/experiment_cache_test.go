@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingDB is a DBTX fake that serves a single experiment row and counts
+// how many times it was queried
+type countingDB struct {
+	queries atomic.Int32
+	delay   time.Duration
+	status  atomic.Value
+}
+
+func (d *countingDB) Exec(_ context.Context, _ string, args ...interface{}) (pgconn.CommandTag, error) {
+	if len(args) == 2 {
+		d.status.Store(args[1].(string))
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+func (d *countingDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	panic("not used")
+}
+
+func (d *countingDB) QueryRow(_ context.Context, _ string, args ...interface{}) pgx.Row {
+	d.queries.Add(1)
+	time.Sleep(d.delay)
+	return experimentRow{id: args[0].(string), status: d.status.Load().(string)}
+}
+
+type experimentRow struct {
+	id, status string
+}
+
+func (r experimentRow) Scan(dest ...any) error {
+	*dest[0].(*string) = r.id
+	*dest[1].(*json.RawMessage) = json.RawMessage(`{}`)
+	*dest[2].(*string) = r.status
+	*dest[3].(*string) = "inject"
+	return nil
+}
+
+func newCountingDB(delay time.Duration) *countingDB {
+	d := &countingDB{delay: delay}
+	d.status.Store("running")
+	return d
+}
+
+func TestExperimentCacheSharesConcurrentFetches(t *testing.T) {
+	fake := newCountingDB(20 * time.Millisecond)
+	cache := NewExperimentCache(New(fake), time.Second)
+
+	// 25 SSE streams polling the same experiment on the same tick
+	const streams = 25
+	var wg sync.WaitGroup
+	for i := 0; i < streams; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec, err := cache.GetExperiment(context.Background(), "exp-1")
+			assert.NoError(t, err)
+			assert.Equal(t, "running", rec.Status)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), fake.queries.Load())
+}
+
+func TestExperimentCacheExpires(t *testing.T) {
+	fake := newCountingDB(0)
+	cache := NewExperimentCache(New(fake), time.Second)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.GetExperiment(context.Background(), "exp-1")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), fake.queries.Load())
+
+	now = now.Add(2 * time.Second)
+	_, err := cache.GetExperiment(context.Background(), "exp-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fake.queries.Load())
+}
+
+func TestExperimentCacheInvalidatesOnStatusChange(t *testing.T) {
+	fake := newCountingDB(0)
+	cache := NewExperimentCache(New(fake), time.Minute)
+
+	rec, err := cache.GetExperiment(context.Background(), "exp-1")
+	require.NoError(t, err)
+	assert.Equal(t, "running", rec.Status)
+
+	require.NoError(t, cache.UpdateExperimentStatus(context.Background(), UpdateExperimentStatusParams{
+		ID: "exp-1", Status: "rolled_back",
+	}))
+
+	rec, err = cache.GetExperiment(context.Background(), "exp-1")
+	require.NoError(t, err)
+	assert.Equal(t, "rolled_back", rec.Status)
+	assert.Equal(t, int32(2), fake.queries.Load())
+}
+
+func TestExperimentCacheInvalidate(t *testing.T) {
+	fake := newCountingDB(0)
+	cache := NewExperimentCache(New(fake), time.Minute)
+
+	_, err := cache.GetExperiment(context.Background(), "exp-1")
+	require.NoError(t, err)
+	cache.Invalidate("exp-1")
+	_, err = cache.GetExperiment(context.Background(), "exp-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), fake.queries.Load())
+}
+
+func TestExperimentCacheBoundsItsSize(t *testing.T) {
+	fake := newCountingDB(0)
+	cache := NewExperimentCache(New(fake), time.Minute)
+	cache.maxEntries = 2
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for _, id := range []string{"exp-1", "exp-2", "exp-3"} {
+		_, err := cache.GetExperiment(context.Background(), id)
+		require.NoError(t, err)
+		now = now.Add(time.Second)
+	}
+	assert.Len(t, cache.entries, 2)
+	assert.NotContains(t, cache.entries, "exp-1", "the oldest entry is evicted")
+
+	// Expired entries are pruned before anything live is evicted
+	now = now.Add(time.Minute)
+	_, err := cache.GetExperiment(context.Background(), "exp-4")
+	require.NoError(t, err)
+	assert.Len(t, cache.entries, 1)
+	assert.Contains(t, cache.entries, "exp-4")
+}
@@ -13,9 +13,9 @@ import (
 )
 
 const createExperiment = `-- name: CreateExperiment :one
-INSERT INTO experiments (id, config, status, phase, started_at)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, config, status, phase, started_at, completed_at, steady_state, hypothesis, injection_result, observations, rollback_result, error, ai_insights
+INSERT INTO experiments (id, config, status, phase, started_at, rerun_of)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, config, status, phase, started_at, completed_at, steady_state, hypothesis, injection_result, observations, rollback_result, error, ai_insights, phase_timings, rerun_of
 `
 
 type CreateExperimentParams struct {
@@ -24,6 +24,7 @@ type CreateExperimentParams struct {
 	Status    string             `json:"status"`
 	Phase     string             `json:"phase"`
 	StartedAt pgtype.Timestamptz `json:"started_at"`
+	RerunOf   pgtype.Text        `json:"rerun_of"`
 }
 
 func (q *Queries) CreateExperiment(ctx context.Context, arg CreateExperimentParams) (Experiment, error) {
@@ -33,6 +34,7 @@ func (q *Queries) CreateExperiment(ctx context.Context, arg CreateExperimentPara
 		arg.Status,
 		arg.Phase,
 		arg.StartedAt,
+		arg.RerunOf,
 	)
 	var i Experiment
 	err := row.Scan(
@@ -49,12 +51,58 @@ func (q *Queries) CreateExperiment(ctx context.Context, arg CreateExperimentPara
 		&i.RollbackResult,
 		&i.Error,
 		&i.AiInsights,
+		&i.PhaseTimings,
+		&i.RerunOf,
 	)
 	return i, err
 }
 
+const deleteExperimentsOlderThan = `-- name: DeleteExperimentsOlderThan :many
+WITH expired AS (
+    DELETE FROM experiments
+    WHERE status NOT IN ('running', 'pending')
+      AND COALESCE(completed_at, started_at) < $1::timestamptz
+    RETURNING id
+), deleted_snapshots AS (
+    DELETE FROM snapshots WHERE experiment_id IN (SELECT id FROM expired)
+), deleted_probe_results AS (
+    DELETE FROM probe_results WHERE experiment_id IN (SELECT id FROM expired)
+), deleted_analysis_results AS (
+    DELETE FROM analysis_results WHERE experiment_id IN (SELECT id FROM expired)
+), deleted_rollback_actions AS (
+    DELETE FROM rollback_actions WHERE experiment_id IN (SELECT id FROM expired)
+), deleted_events AS (
+    DELETE FROM experiment_events WHERE experiment_id IN (SELECT id FROM expired)
+), deleted_pod_logs AS (
+    DELETE FROM experiment_pod_logs WHERE experiment_id IN (SELECT id FROM expired)
+)
+SELECT id FROM expired
+`
+
+// Deletes finished experiments that ended before the cutoff along with their
+// dependent rows. Running and pending experiments are never deleted.
+func (q *Queries) DeleteExperimentsOlderThan(ctx context.Context, cutoff pgtype.Timestamptz) ([]string, error) {
+	rows, err := q.db.Query(ctx, deleteExperimentsOlderThan, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getExperiment = `-- name: GetExperiment :one
-SELECT id, config, status, phase, started_at, completed_at, steady_state, hypothesis, injection_result, observations, rollback_result, error, ai_insights FROM experiments WHERE id = $1
+SELECT id, config, status, phase, started_at, completed_at, steady_state, hypothesis, injection_result, observations, rollback_result, error, ai_insights, phase_timings, rerun_of FROM experiments WHERE id = $1
 `
 
 func (q *Queries) GetExperiment(ctx context.Context, id string) (Experiment, error) {
@@ -74,12 +122,14 @@ func (q *Queries) GetExperiment(ctx context.Context, id string) (Experiment, err
 		&i.RollbackResult,
 		&i.Error,
 		&i.AiInsights,
+		&i.PhaseTimings,
+		&i.RerunOf,
 	)
 	return i, err
 }
 
 const listExperiments = `-- name: ListExperiments :many
-SELECT id, config, status, phase, started_at, completed_at, steady_state, hypothesis, injection_result, observations, rollback_result, error, ai_insights FROM experiments ORDER BY started_at DESC
+SELECT id, config, status, phase, started_at, completed_at, steady_state, hypothesis, injection_result, observations, rollback_result, error, ai_insights, phase_timings, rerun_of FROM experiments ORDER BY started_at DESC
 `
 
 func (q *Queries) ListExperiments(ctx context.Context) ([]Experiment, error) {
@@ -105,6 +155,98 @@ func (q *Queries) ListExperiments(ctx context.Context) ([]Experiment, error) {
 			&i.RollbackResult,
 			&i.Error,
 			&i.AiInsights,
+			&i.PhaseTimings,
+			&i.RerunOf,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExperimentsFiltered = `-- name: ListExperimentsFiltered :many
+SELECT id, config, status, phase, started_at, completed_at, steady_state, hypothesis, injection_result, observations, rollback_result, error, ai_insights, phase_timings, rerun_of FROM experiments
+WHERE ($1::timestamptz IS NULL OR started_at >= $1)
+  AND ($2::timestamptz IS NULL OR started_at <= $2)
+  AND ($3::text IS NULL OR config->>'owner' = $3)
+ORDER BY started_at DESC
+`
+
+type ListExperimentsFilteredParams struct {
+	Since pgtype.Timestamptz `json:"since"`
+	Until pgtype.Timestamptz `json:"until"`
+	Owner pgtype.Text        `json:"owner"`
+}
+
+func (q *Queries) ListExperimentsFiltered(ctx context.Context, arg ListExperimentsFilteredParams) ([]Experiment, error) {
+	rows, err := q.db.Query(ctx, listExperimentsFiltered, arg.Since, arg.Until, arg.Owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Experiment{}
+	for rows.Next() {
+		var i Experiment
+		if err := rows.Scan(
+			&i.ID,
+			&i.Config,
+			&i.Status,
+			&i.Phase,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.SteadyState,
+			&i.Hypothesis,
+			&i.InjectionResult,
+			&i.Observations,
+			&i.RollbackResult,
+			&i.Error,
+			&i.AiInsights,
+			&i.PhaseTimings,
+			&i.RerunOf,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExperimentsByStatus = `-- name: ListExperimentsByStatus :many
+SELECT id, config, status, phase, started_at, completed_at, steady_state, hypothesis, injection_result, observations, rollback_result, error, ai_insights, phase_timings, rerun_of FROM experiments WHERE status = $1 ORDER BY started_at
+`
+
+func (q *Queries) ListExperimentsByStatus(ctx context.Context, status string) ([]Experiment, error) {
+	rows, err := q.db.Query(ctx, listExperimentsByStatus, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Experiment{}
+	for rows.Next() {
+		var i Experiment
+		if err := rows.Scan(
+			&i.ID,
+			&i.Config,
+			&i.Status,
+			&i.Phase,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.SteadyState,
+			&i.Hypothesis,
+			&i.InjectionResult,
+			&i.Observations,
+			&i.RollbackResult,
+			&i.Error,
+			&i.AiInsights,
+			&i.PhaseTimings,
+			&i.RerunOf,
 		); err != nil {
 			return nil, err
 		}
@@ -127,7 +269,8 @@ SET status = $2,
     observations = $8,
     rollback_result = $9,
     error = $10,
-    ai_insights = $11
+    ai_insights = $11,
+    phase_timings = $12
 WHERE id = $1
 `
 
@@ -143,6 +286,7 @@ type UpdateExperimentParams struct {
 	RollbackResult  []byte             `json:"rollback_result"`
 	Error           pgtype.Text        `json:"error"`
 	AiInsights      []byte             `json:"ai_insights"`
+	PhaseTimings    []byte             `json:"phase_timings"`
 }
 
 func (q *Queries) UpdateExperiment(ctx context.Context, arg UpdateExperimentParams) error {
@@ -158,10 +302,45 @@ func (q *Queries) UpdateExperiment(ctx context.Context, arg UpdateExperimentPara
 		arg.RollbackResult,
 		arg.Error,
 		arg.AiInsights,
+		arg.PhaseTimings,
 	)
 	return err
 }
 
+const updateExperimentConfig = `-- name: UpdateExperimentConfig :one
+UPDATE experiments SET config = $2
+WHERE id = $1 AND status = 'pending'
+RETURNING id, config, status, phase, started_at, completed_at, steady_state, hypothesis, injection_result, observations, rollback_result, error, ai_insights, phase_timings, rerun_of
+`
+
+type UpdateExperimentConfigParams struct {
+	ID     string          `json:"id"`
+	Config json.RawMessage `json:"config"`
+}
+
+func (q *Queries) UpdateExperimentConfig(ctx context.Context, arg UpdateExperimentConfigParams) (Experiment, error) {
+	row := q.db.QueryRow(ctx, updateExperimentConfig, arg.ID, arg.Config)
+	var i Experiment
+	err := row.Scan(
+		&i.ID,
+		&i.Config,
+		&i.Status,
+		&i.Phase,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.SteadyState,
+		&i.Hypothesis,
+		&i.InjectionResult,
+		&i.Observations,
+		&i.RollbackResult,
+		&i.Error,
+		&i.AiInsights,
+		&i.PhaseTimings,
+		&i.RerunOf,
+	)
+	return i, err
+}
+
 const updateExperimentStatus = `-- name: UpdateExperimentStatus :exec
 UPDATE experiments SET status = $2 WHERE id = $1
 `
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_probe_results_executed_at;
+ALTER TABLE probe_results DROP COLUMN IF EXISTS probe_name;
+ALTER TABLE probe_results RENAME COLUMN detail TO result;
@@ -0,0 +1,3 @@
+ALTER TABLE probe_results RENAME COLUMN result TO detail;
+ALTER TABLE probe_results ADD COLUMN IF NOT EXISTS probe_name VARCHAR(100) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_probe_results_executed_at ON probe_results(executed_at);
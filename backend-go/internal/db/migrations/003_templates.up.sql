@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS templates (
+    name VARCHAR(100) PRIMARY KEY,
+    config JSONB NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE experiments ADD COLUMN IF NOT EXISTS phase_timings JSONB;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS rollback_actions (
+    id SERIAL PRIMARY KEY,
+    experiment_id VARCHAR(8) NOT NULL,
+    chaos_type VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rollback_actions_experiment_id ON rollback_actions(experiment_id);
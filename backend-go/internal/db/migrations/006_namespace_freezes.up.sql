@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS namespace_freezes (
+    pattern VARCHAR(253) PRIMARY KEY,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE experiments ADD COLUMN IF NOT EXISTS rerun_of VARCHAR(8);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS experiment_events (
+    id SERIAL PRIMARY KEY,
+    experiment_id VARCHAR(8) NOT NULL,
+    event_type VARCHAR(30) NOT NULL,
+    message TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_experiment_events_experiment_id ON experiment_events(experiment_id, occurred_at);
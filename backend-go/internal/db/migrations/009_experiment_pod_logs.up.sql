@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS experiment_pod_logs (
+    id SERIAL PRIMARY KEY,
+    experiment_id VARCHAR(8) NOT NULL,
+    stage VARCHAR(10) NOT NULL,
+    namespace VARCHAR(253) NOT NULL,
+    pod VARCHAR(253) NOT NULL,
+    container VARCHAR(253) NOT NULL DEFAULT '',
+    logs TEXT NOT NULL DEFAULT '',
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    error TEXT,
+    captured_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_experiment_pod_logs_experiment_id ON experiment_pod_logs(experiment_id, captured_at);
@@ -0,0 +1,17 @@
+-- Long dry-run preview IDs do not fit the narrower columns
+DELETE FROM snapshots WHERE length(experiment_id) > 8;
+DELETE FROM probe_results WHERE length(experiment_id) > 8;
+DELETE FROM analysis_results WHERE length(experiment_id) > 8;
+DELETE FROM rollback_actions WHERE length(experiment_id) > 8;
+DELETE FROM experiment_events WHERE length(experiment_id) > 8;
+DELETE FROM experiment_pod_logs WHERE length(experiment_id) > 8;
+DELETE FROM experiments WHERE length(id) > 8;
+
+ALTER TABLE experiments ALTER COLUMN id TYPE VARCHAR(8);
+ALTER TABLE experiments ALTER COLUMN rerun_of TYPE VARCHAR(8);
+ALTER TABLE snapshots ALTER COLUMN experiment_id TYPE VARCHAR(8);
+ALTER TABLE probe_results ALTER COLUMN experiment_id TYPE VARCHAR(8);
+ALTER TABLE analysis_results ALTER COLUMN experiment_id TYPE VARCHAR(8);
+ALTER TABLE rollback_actions ALTER COLUMN experiment_id TYPE VARCHAR(8);
+ALTER TABLE experiment_events ALTER COLUMN experiment_id TYPE VARCHAR(8);
+ALTER TABLE experiment_pod_logs ALTER COLUMN experiment_id TYPE VARCHAR(8);
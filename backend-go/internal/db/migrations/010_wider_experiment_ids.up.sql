@@ -0,0 +1,9 @@
+-- Dry-run preview IDs carry 16 random hex digits after their prefix
+ALTER TABLE experiments ALTER COLUMN id TYPE VARCHAR(32);
+ALTER TABLE experiments ALTER COLUMN rerun_of TYPE VARCHAR(32);
+ALTER TABLE snapshots ALTER COLUMN experiment_id TYPE VARCHAR(32);
+ALTER TABLE probe_results ALTER COLUMN experiment_id TYPE VARCHAR(32);
+ALTER TABLE analysis_results ALTER COLUMN experiment_id TYPE VARCHAR(32);
+ALTER TABLE rollback_actions ALTER COLUMN experiment_id TYPE VARCHAR(32);
+ALTER TABLE experiment_events ALTER COLUMN experiment_id TYPE VARCHAR(32);
+ALTER TABLE experiment_pod_logs ALTER COLUMN experiment_id TYPE VARCHAR(32);
@@ -0,0 +1,2 @@
+-- ?owner= filters experiments on the owner in their config
+CREATE INDEX IF NOT EXISTS idx_experiments_owner ON experiments ((config->>'owner'));
@@ -35,6 +35,36 @@ type Experiment struct {
 	RollbackResult  []byte             `json:"rollback_result"`
 	Error           pgtype.Text        `json:"error"`
 	AiInsights      []byte             `json:"ai_insights"`
+	PhaseTimings    []byte             `json:"phase_timings"`
+	RerunOf         pgtype.Text        `json:"rerun_of"`
+}
+
+type ExperimentEvent struct {
+	ID           int32              `json:"id"`
+	ExperimentID string             `json:"experiment_id"`
+	EventType    string             `json:"event_type"`
+	Message      string             `json:"message"`
+	Data         json.RawMessage    `json:"data"`
+	OccurredAt   pgtype.Timestamptz `json:"occurred_at"`
+}
+
+type ExperimentPodLog struct {
+	ID           int32              `json:"id"`
+	ExperimentID string             `json:"experiment_id"`
+	Stage        string             `json:"stage"`
+	Namespace    string             `json:"namespace"`
+	Pod          string             `json:"pod"`
+	Container    string             `json:"container"`
+	Logs         string             `json:"logs"`
+	Truncated    bool               `json:"truncated"`
+	Error        pgtype.Text        `json:"error"`
+	CapturedAt   pgtype.Timestamptz `json:"captured_at"`
+}
+
+type NamespaceFreeze struct {
+	Pattern   string             `json:"pattern"`
+	Reason    pgtype.Text        `json:"reason"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type ProbeResult struct {
@@ -42,9 +72,19 @@ type ProbeResult struct {
 	ExperimentID string             `json:"experiment_id"`
 	ProbeType    string             `json:"probe_type"`
 	Mode         string             `json:"mode"`
-	Result       json.RawMessage    `json:"result"`
+	Detail       json.RawMessage    `json:"detail"`
 	Passed       bool               `json:"passed"`
 	ExecutedAt   pgtype.Timestamptz `json:"executed_at"`
+	ProbeName    string             `json:"probe_name"`
+}
+
+type RollbackAction struct {
+	ID           int32              `json:"id"`
+	ExperimentID string             `json:"experiment_id"`
+	ChaosType    string             `json:"chaos_type"`
+	Description  string             `json:"description"`
+	Data         json.RawMessage    `json:"data"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 }
 
 type Snapshot struct {
@@ -55,3 +95,11 @@ type Snapshot struct {
 	Data         json.RawMessage    `json:"data"`
 	CapturedAt   pgtype.Timestamptz `json:"captured_at"`
 }
+
+type Template struct {
+	Name        string             `json:"name"`
+	Config      json.RawMessage    `json:"config"`
+	Description pgtype.Text        `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
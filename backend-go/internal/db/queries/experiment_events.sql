@@ -0,0 +1,6 @@
+-- name: CreateExperimentEvent :exec
+INSERT INTO experiment_events (experiment_id, event_type, message, data, occurred_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListExperimentEvents :many
+SELECT * FROM experiment_events WHERE experiment_id = $1 ORDER BY occurred_at ASC, id ASC;
@@ -0,0 +1,6 @@
+-- name: CreateExperimentPodLog :exec
+INSERT INTO experiment_pod_logs (experiment_id, stage, namespace, pod, container, logs, truncated, error, captured_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: ListExperimentPodLogs :many
+SELECT * FROM experiment_pod_logs WHERE experiment_id = $1 ORDER BY captured_at ASC, id ASC;
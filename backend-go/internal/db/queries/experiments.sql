@@ -4,9 +4,19 @@ SELECT * FROM experiments WHERE id = $1;
 -- name: ListExperiments :many
 SELECT * FROM experiments ORDER BY started_at DESC;
 
+-- name: ListExperimentsFiltered :many
+SELECT * FROM experiments
+WHERE (sqlc.narg('since')::timestamptz IS NULL OR started_at >= sqlc.narg('since'))
+  AND (sqlc.narg('until')::timestamptz IS NULL OR started_at <= sqlc.narg('until'))
+  AND (sqlc.narg('owner')::text IS NULL OR config->>'owner' = sqlc.narg('owner'))
+ORDER BY started_at DESC;
+
+-- name: ListExperimentsByStatus :many
+SELECT * FROM experiments WHERE status = $1 ORDER BY started_at;
+
 -- name: CreateExperiment :one
-INSERT INTO experiments (id, config, status, phase, started_at)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO experiments (id, config, status, phase, started_at, rerun_of)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: UpdateExperiment :exec
@@ -20,8 +30,37 @@ SET status = $2,
     observations = $8,
     rollback_result = $9,
     error = $10,
-    ai_insights = $11
+    ai_insights = $11,
+    phase_timings = $12
 WHERE id = $1;
 
 -- name: UpdateExperimentStatus :exec
 UPDATE experiments SET status = $2 WHERE id = $1;
+
+-- name: UpdateExperimentConfig :one
+UPDATE experiments SET config = $2
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: DeleteExperimentsOlderThan :many
+-- Deletes finished experiments that ended before the cutoff along with their
+-- dependent rows. Running and pending experiments are never deleted.
+WITH expired AS (
+    DELETE FROM experiments
+    WHERE status NOT IN ('running', 'pending')
+      AND COALESCE(completed_at, started_at) < sqlc.arg('cutoff')::timestamptz
+    RETURNING id
+), deleted_snapshots AS (
+    DELETE FROM snapshots WHERE experiment_id IN (SELECT id FROM expired)
+), deleted_probe_results AS (
+    DELETE FROM probe_results WHERE experiment_id IN (SELECT id FROM expired)
+), deleted_analysis_results AS (
+    DELETE FROM analysis_results WHERE experiment_id IN (SELECT id FROM expired)
+), deleted_rollback_actions AS (
+    DELETE FROM rollback_actions WHERE experiment_id IN (SELECT id FROM expired)
+), deleted_events AS (
+    DELETE FROM experiment_events WHERE experiment_id IN (SELECT id FROM expired)
+), deleted_pod_logs AS (
+    DELETE FROM experiment_pod_logs WHERE experiment_id IN (SELECT id FROM expired)
+)
+SELECT id FROM expired;
@@ -0,0 +1,12 @@
+-- name: UpsertNamespaceFreeze :one
+INSERT INTO namespace_freezes (pattern, reason)
+VALUES ($1, $2)
+ON CONFLICT (pattern) DO UPDATE
+SET reason = EXCLUDED.reason
+RETURNING *;
+
+-- name: DeleteNamespaceFreeze :exec
+DELETE FROM namespace_freezes WHERE pattern = $1;
+
+-- name: ListNamespaceFreezes :many
+SELECT * FROM namespace_freezes ORDER BY pattern ASC;
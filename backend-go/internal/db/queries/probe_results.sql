@@ -0,0 +1,7 @@
+-- name: CreateProbeResult :one
+INSERT INTO probe_results (experiment_id, probe_name, probe_type, mode, detail, passed, executed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListProbeResultsByExperiment :many
+SELECT * FROM probe_results WHERE experiment_id = $1 ORDER BY executed_at ASC, id ASC;
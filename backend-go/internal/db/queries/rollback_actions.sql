@@ -0,0 +1,10 @@
+-- name: CreateRollbackAction :one
+INSERT INTO rollback_actions (experiment_id, chaos_type, description, data)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListRollbackActions :many
+SELECT * FROM rollback_actions WHERE experiment_id = $1 ORDER BY id;
+
+-- name: DeleteRollbackAction :exec
+DELETE FROM rollback_actions WHERE id = $1;
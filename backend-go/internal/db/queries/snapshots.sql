@@ -3,5 +3,8 @@ INSERT INTO snapshots (experiment_id, type, namespace, data, captured_at)
 VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
+-- name: GetSnapshot :one
+SELECT * FROM snapshots WHERE experiment_id = $1 ORDER BY captured_at DESC LIMIT 1;
+
 -- name: GetSnapshotsByExperiment :many
 SELECT * FROM snapshots WHERE experiment_id = $1 ORDER BY captured_at DESC;
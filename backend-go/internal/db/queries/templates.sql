@@ -0,0 +1,14 @@
+-- name: UpsertTemplate :one
+INSERT INTO templates (name, config, description)
+VALUES ($1, $2, $3)
+ON CONFLICT (name) DO UPDATE
+SET config = EXCLUDED.config,
+    description = EXCLUDED.description,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetTemplate :one
+SELECT * FROM templates WHERE name = $1;
+
+-- name: ListTemplates :many
+SELECT * FROM templates ORDER BY name ASC;
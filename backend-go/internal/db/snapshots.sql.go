@@ -46,6 +46,24 @@ func (q *Queries) CreateSnapshot(ctx context.Context, arg CreateSnapshotParams)
 	return i, err
 }
 
+const getSnapshot = `-- name: GetSnapshot :one
+SELECT id, experiment_id, type, namespace, data, captured_at FROM snapshots WHERE experiment_id = $1 ORDER BY captured_at DESC LIMIT 1
+`
+
+func (q *Queries) GetSnapshot(ctx context.Context, experimentID string) (Snapshot, error) {
+	row := q.db.QueryRow(ctx, getSnapshot, experimentID)
+	var i Snapshot
+	err := row.Scan(
+		&i.ID,
+		&i.ExperimentID,
+		&i.Type,
+		&i.Namespace,
+		&i.Data,
+		&i.CapturedAt,
+	)
+	return i, err
+}
+
 const getSnapshotsByExperiment = `-- name: GetSnapshotsByExperiment :many
 SELECT id, experiment_id, type, namespace, data, captured_at FROM snapshots WHERE experiment_id = $1 ORDER BY captured_at DESC
 `
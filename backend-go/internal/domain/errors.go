@@ -6,6 +6,10 @@ var (
 	// ErrEmergencyStop is returned when the emergency stop is active
 	ErrEmergencyStop = errors.New("emergency stop is active")
 
+	// ErrExperimentAborted is the cause a running experiment is cancelled
+	// with by an abort-all, which unlike the emergency stop latches nothing
+	ErrExperimentAborted = errors.New("experiment aborted")
+
 	// ErrBlastRadiusExceeded is returned when blast radius validation fails
 	ErrBlastRadiusExceeded = errors.New("blast radius exceeded")
 
@@ -18,9 +22,43 @@ var (
 	// ErrNamespaceConfirmation is returned when production namespace requires confirmation
 	ErrNamespaceConfirmation = errors.New("production namespace requires confirmation")
 
+	// ErrNamespaceFrozen is returned when a target namespace is frozen from chaos
+	ErrNamespaceFrozen = errors.New("namespace is frozen from chaos")
+
+	// ErrChaosTypeNotAllowed is returned when a chaos type is outside the deployment's allowlist
+	ErrChaosTypeNotAllowed = errors.New("chaos type is not allowed in this deployment")
+
+	// ErrServerShuttingDown is returned when an experiment is started while
+	// the server drains running experiments for shutdown
+	ErrServerShuttingDown = errors.New("server is shutting down")
+
+	// ErrQueueFull is returned when the sequential experiment queue is at its max depth
+	ErrQueueFull = errors.New("experiment queue is full")
+
+	// ErrQueueClosed is returned when the experiment queue is draining for shutdown
+	ErrQueueClosed = errors.New("experiment queue is closed: server shutting down")
+
 	// ErrUnknownChaosType is returned for unrecognised chaos types
 	ErrUnknownChaosType = errors.New("unknown chaos type")
 
+	// ErrInvalidConfig is returned when an experiment config fails validation
+	ErrInvalidConfig = errors.New("invalid experiment config")
+
+	// ErrInvalidTargetResource is returned when target_resource is malformed or of an unsupported kind
+	ErrInvalidTargetResource = errors.New("invalid target resource")
+
+	// ErrInvalidTargetWorkload is returned when target_workload is malformed, of an unsupported kind or not found
+	ErrInvalidTargetWorkload = errors.New("invalid target workload")
+
+	// ErrClockSkewNotPermitted is returned when a container may not set its clock (no CAP_SYS_TIME)
+	ErrClockSkewNotPermitted = errors.New("setting the clock requires a privileged container with CAP_SYS_TIME")
+
+	// ErrStressToolMissing is returned when a stress target's container lacks stress-ng and no fallback was requested
+	ErrStressToolMissing = errors.New("target container lacks stress-ng; use an ephemeral debug container or set shell_fallback")
+
+	// ErrEphemeralContainersUnsupported is returned when the cluster does not serve the pods/ephemeralcontainers subresource
+	ErrEphemeralContainersUnsupported = errors.New("cluster does not support ephemeral containers (EphemeralContainers feature gate disabled or Kubernetes older than 1.23)")
+
 	// ErrAIServiceUnavailable is returned when the AI microservice is unreachable
 	ErrAIServiceUnavailable = errors.New("AI service unavailable")
 )
@@ -1,6 +1,16 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/fields"
+)
 
 // Experiment lifecycle phases
 type ExperimentPhase string
@@ -13,6 +23,19 @@ const (
 	PhaseRollback    ExperimentPhase = "rollback"
 )
 
+// ExperimentEventType classifies an entry in an experiment's audit timeline
+type ExperimentEventType string
+
+const (
+	EventStarted         ExperimentEventType = "started"
+	EventPhaseChanged    ExperimentEventType = "phase_changed"
+	EventProbe           ExperimentEventType = "probe"
+	EventInjected        ExperimentEventType = "injected"
+	EventInjectionFailed ExperimentEventType = "injection_failed"
+	EventRollback        ExperimentEventType = "rollback"
+	EventFinished        ExperimentEventType = "finished"
+)
+
 // Experiment status
 type ExperimentStatus string
 
@@ -31,26 +54,45 @@ type ChaosType string
 const (
 	// Kubernetes
 	ChaosTypePodDelete      ChaosType = "pod_delete"
+	ChaosTypePodEvict       ChaosType = "pod_evict"
 	ChaosTypeNetworkLatency ChaosType = "network_latency"
 	ChaosTypeNetworkLoss    ChaosType = "network_loss"
+	ChaosTypeNetworkFault   ChaosType = "network_fault"
 	ChaosTypeCPUStress      ChaosType = "cpu_stress"
 	ChaosTypeMemoryStress   ChaosType = "memory_stress"
+	ChaosTypeClockSkew      ChaosType = "clock_skew"
+	ChaosTypeProcessKill    ChaosType = "process_kill"
 	// AWS
 	ChaosTypeEC2Stop        ChaosType = "ec2_stop"
 	ChaosTypeRDSFailover    ChaosType = "rds_failover"
+	ChaosTypeRDSReboot      ChaosType = "rds_reboot"
 	ChaosTypeRouteBlackhole ChaosType = "route_blackhole"
+	ChaosTypeLambdaThrottle ChaosType = "lambda_throttle"
+	ChaosTypeSubnetIsolate  ChaosType = "subnet_isolate"
 )
 
+// ChaosTypes lists every supported chaos type
+var ChaosTypes = []ChaosType{
+	ChaosTypePodDelete, ChaosTypePodEvict, ChaosTypeNetworkLatency, ChaosTypeNetworkLoss,
+	ChaosTypeNetworkFault, ChaosTypeCPUStress, ChaosTypeMemoryStress, ChaosTypeClockSkew,
+	ChaosTypeProcessKill, ChaosTypeEC2Stop, ChaosTypeRDSFailover, ChaosTypeRDSReboot,
+	ChaosTypeRouteBlackhole, ChaosTypeLambdaThrottle, ChaosTypeSubnetIsolate,
+}
+
 // ProbeType identifies the probe implementation
 type ProbeType string
 
 const (
-	ProbeTypeHTTP       ProbeType = "http"
-	ProbeTypeCmd        ProbeType = "cmd"
-	ProbeTypeK8s        ProbeType = "k8s"
-	ProbeTypePrometheus ProbeType = "prometheus"
+	ProbeTypeHTTP         ProbeType = "http"
+	ProbeTypeCmd          ProbeType = "cmd"
+	ProbeTypeK8s          ProbeType = "k8s"
+	ProbeTypePrometheus   ProbeType = "prometheus"
+	ProbeTypeAlertmanager ProbeType = "alertmanager"
 )
 
+// ProbeTypes lists every supported probe type
+var ProbeTypes = []ProbeType{ProbeTypeHTTP, ProbeTypeCmd, ProbeTypeK8s, ProbeTypePrometheus, ProbeTypeAlertmanager}
+
 // ProbeMode defines when a probe executes during the experiment lifecycle
 type ProbeMode string
 
@@ -61,6 +103,9 @@ const (
 	ProbeModeOnChaos    ProbeMode = "on_chaos"    // After fault injection
 )
 
+// ProbeModes lists every probe execution mode
+var ProbeModes = []ProbeMode{ProbeModeSOT, ProbeModeEOT, ProbeModeContinuous, ProbeModeOnChaos}
+
 // ProbeConfig defines probe settings within an experiment
 type ProbeConfig struct {
 	Name       string            `json:"name" binding:"required"`
@@ -78,6 +123,38 @@ type SafetyConfig struct {
 	NamespacePattern          *string `json:"namespace_pattern,omitempty"`
 	HealthCheckInterval       int     `json:"health_check_interval" binding:"min=1,max=60"`
 	HealthCheckFailureThreshold int   `json:"health_check_failure_threshold" binding:"min=1,max=10"`
+	// VerifyRollback re-captures steady state after rollback and reports
+	// any drift from the pre-injection snapshot as residual_drift
+	VerifyRollback bool `json:"verify_rollback,omitempty"`
+	// CriticalityWeighting checks max_blast_radius against the affected
+	// share of criticality-weighted pods instead of the plain pod ratio.
+	// CriticalityWeights overrides DefaultCriticalityWeights per level.
+	CriticalityWeighting bool               `json:"criticality_weighting,omitempty"`
+	CriticalityWeights   map[string]float64 `json:"criticality_weights,omitempty"`
+}
+
+// CriticalityLabel is the pod label read by criticality-weighted blast
+// radius checks
+const CriticalityLabel = "chaosduck.io/criticality"
+
+// DefaultCriticality is assumed for pods without a CriticalityLabel or with
+// a level that has no weight
+const DefaultCriticality = "medium"
+
+// DefaultCriticalityWeights weighs pods by their CriticalityLabel value
+var DefaultCriticalityWeights = map[string]float64{"high": 4, "medium": 2, "low": 1}
+
+// CriticalityWeightTable returns DefaultCriticalityWeights overlaid with
+// the configured CriticalityWeights
+func (s SafetyConfig) CriticalityWeightTable() map[string]float64 {
+	weights := make(map[string]float64, len(DefaultCriticalityWeights)+len(s.CriticalityWeights))
+	for level, w := range DefaultCriticalityWeights {
+		weights[level] = w
+	}
+	for level, w := range s.CriticalityWeights {
+		weights[level] = w
+	}
+	return weights
 }
 
 // DefaultSafetyConfig returns safety config with safe defaults
@@ -97,13 +174,53 @@ type ExperimentConfig struct {
 	Name            string            `json:"name" binding:"required"`
 	ChaosType       ChaosType         `json:"chaos_type" binding:"required"`
 	TargetNamespace *string           `json:"target_namespace,omitempty"`
+	// TargetNamespaces fans a K8s experiment out across several namespaces
+	TargetNamespaces []string `json:"target_namespaces,omitempty"`
 	TargetLabels    map[string]string `json:"target_labels,omitempty"`
 	TargetResource  *string           `json:"target_resource,omitempty"`
+	// TargetWorkload selects the pods of a workload, e.g. "deployment/web",
+	// by the workload's own pod selector
+	TargetWorkload *string `json:"target_workload,omitempty"`
+	// FieldSelector narrows K8s pod targets by field, e.g. "spec.nodeName=node-1"
+	FieldSelector *string `json:"field_selector,omitempty"`
 	Parameters      map[string]any    `json:"parameters,omitempty"`
 	Safety          SafetyConfig      `json:"safety"`
 	Probes          []ProbeConfig     `json:"probes,omitempty"`
 	Description     *string           `json:"description,omitempty"`
 	AIEnabled       bool              `json:"ai_enabled"`
+	// Steps chains several faults into one experiment. When set they are
+	// injected in order instead of ChaosType alone, which must name the
+	// first step's type.
+	Steps []ChaosStep `json:"steps,omitempty"`
+	// Owner attributes the experiment to a person or team, e.g. an email
+	// address or Slack handle
+	Owner *string `json:"owner,omitempty"`
+}
+
+// ChaosStep is one fault of a chained experiment. It runs against the
+// experiment's targets with the experiment's parameters overlaid by its own.
+type ChaosStep struct {
+	ChaosType  ChaosType      `json:"chaos_type" binding:"required"`
+	Parameters map[string]any `json:"parameters,omitempty"`
+	// DelaySeconds is waited before the step is injected
+	DelaySeconds int `json:"delay_seconds,omitempty" binding:"min=0,max=60"`
+}
+
+// MaxChaosSteps caps how many steps one experiment may chain
+const MaxChaosSteps = 10
+
+// StepConfig returns the single-fault config step i runs with: the
+// experiment's targets and safety settings, the step's chaos type, and the
+// experiment's parameters overlaid by the step's
+func (c ExperimentConfig) StepConfig(i int) ExperimentConfig {
+	step := c.Steps[i]
+	c.Steps = nil
+	c.ChaosType = step.ChaosType
+	merged := make(map[string]any, len(c.Parameters)+len(step.Parameters))
+	maps.Copy(merged, c.Parameters)
+	maps.Copy(merged, step.Parameters)
+	c.Parameters = merged
+	return c
 }
 
 // ExperimentResult holds the full experiment outcome
@@ -121,15 +238,54 @@ type ExperimentResult struct {
 	RollbackResult  map[string]any   `json:"rollback_result,omitempty"`
 	Error           *string          `json:"error,omitempty"`
 	AIInsights      map[string]any   `json:"ai_insights,omitempty"`
+	// PhaseTimings holds the seconds spent in each lifecycle phase
+	PhaseTimings map[string]float64 `json:"phase_timings,omitempty"`
+	// RerunOf is the ID of the experiment this one re-ran, if any
+	RerunOf *string `json:"rerun_of,omitempty"`
+	// SafeMode is set when the deployment forced the experiment to dry-run
+	SafeMode bool `json:"safe_mode,omitempty"`
+	// PodLogs are tails of the target pods' logs from around the injection
+	PodLogs []PodLogCapture `json:"pod_logs,omitempty"`
 }
 
-// RollbackFunc is a function that undoes a chaos injection
-type RollbackFunc func() (map[string]any, error)
+// When a PodLogCapture was taken
+const (
+	PodLogsBeforeInjection  = "before"
+	PodLogsAfterObservation = "after"
+)
+
+// PodLogCapture is a size-bounded tail of one target pod's logs. Error is
+// set instead of Logs when the pod could not be read, e.g. because
+// pod_delete removed it.
+type PodLogCapture struct {
+	Stage      string    `json:"stage"`
+	Namespace  string    `json:"namespace"`
+	Pod        string    `json:"pod"`
+	Container  string    `json:"container,omitempty"`
+	Logs       string    `json:"logs,omitempty"`
+	Truncated  bool      `json:"truncated,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// RollbackFunc is a function that undoes a chaos injection. It should give
+// up when ctx is done.
+type RollbackFunc func(ctx context.Context) (map[string]any, error)
+
+// RollbackAction is the serializable form of a RollbackFunc. Reversible
+// chaos types return one alongside RollbackFn so the rollback can be
+// persisted and rebuilt after a restart.
+type RollbackAction struct {
+	ChaosType ChaosType       `json:"chaos_type"`
+	Data      json.RawMessage `json:"data"`
+}
 
 // ChaosResult is returned by chaos engine methods: (result, rollbackFn)
 type ChaosResult struct {
 	Result     map[string]any
 	RollbackFn RollbackFunc
+	// Rollback describes RollbackFn for persistence, when it can be rebuilt
+	Rollback *RollbackAction
 }
 
 // LabelSelectorString builds a comma-separated label selector
@@ -146,3 +302,65 @@ func LabelSelectorString(labels map[string]string) string {
 	}
 	return s
 }
+
+// podSelectableFields are the pod fields the API server accepts in a field
+// selector
+var podSelectableFields = []string{
+	"metadata.name", "metadata.namespace",
+	"spec.nodeName", "spec.restartPolicy", "spec.schedulerName", "spec.serviceAccountName", "spec.hostNetwork",
+	"status.phase", "status.podIP", "status.podIPs", "status.nominatedNodeName",
+}
+
+// ValidateFieldSelector checks a field_selector value such as
+// "spec.nodeName=node-1,status.phase=Running". Only fields the API server can
+// select pods by are accepted, so a typo fails here rather than mid-run.
+func ValidateFieldSelector(selector string) error {
+	sel, err := fields.ParseSelector(selector)
+	if err != nil {
+		return fmt.Errorf("%w: field_selector: %v", ErrInvalidConfig, err)
+	}
+	for _, req := range sel.Requirements() {
+		if !slices.Contains(podSelectableFields, req.Field) {
+			return fmt.Errorf("%w: field_selector: pods cannot be selected by %q", ErrInvalidConfig, req.Field)
+		}
+	}
+	return nil
+}
+
+// WorkloadKind is a pod controller kind target_workload can name
+type WorkloadKind string
+
+const (
+	WorkloadDeployment  WorkloadKind = "deployment"
+	WorkloadStatefulSet WorkloadKind = "statefulset"
+	WorkloadDaemonSet   WorkloadKind = "daemonset"
+)
+
+// WorkloadKinds lists the kinds accepted by target_workload
+var WorkloadKinds = []WorkloadKind{WorkloadDeployment, WorkloadStatefulSet, WorkloadDaemonSet}
+
+// ParseTargetWorkload splits a target_workload value of the form
+// "kind/name", e.g. "deployment/web"
+func ParseTargetWorkload(workload string) (WorkloadKind, string, error) {
+	kind, name, ok := strings.Cut(workload, "/")
+	if !ok || kind == "" || name == "" {
+		return "", "", fmt.Errorf("%w: %q (expected kind/name)", ErrInvalidTargetWorkload, workload)
+	}
+	if !slices.Contains(WorkloadKinds, WorkloadKind(kind)) {
+		return "", "", fmt.Errorf("%w: unsupported kind %q", ErrInvalidTargetWorkload, kind)
+	}
+	return WorkloadKind(kind), name, nil
+}
+
+// ParseTargetResource splits a target_resource value of the form "kind/name".
+// Only pods can currently be targeted by name (e.g. "pod/worker-1").
+func ParseTargetResource(resource string) (ResourceType, string, error) {
+	kind, name, ok := strings.Cut(resource, "/")
+	if !ok || kind == "" || name == "" {
+		return "", "", fmt.Errorf("%w: %q (expected kind/name)", ErrInvalidTargetResource, resource)
+	}
+	if ResourceType(kind) != ResourcePod {
+		return "", "", fmt.Errorf("%w: unsupported kind %q", ErrInvalidTargetResource, kind)
+	}
+	return ResourcePod, name, nil
+}
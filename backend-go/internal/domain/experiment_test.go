@@ -101,9 +101,52 @@ func TestProbeTypeAndMode(t *testing.T) {
 	assert.Equal(t, ProbeType("cmd"), ProbeTypeCmd)
 	assert.Equal(t, ProbeType("k8s"), ProbeTypeK8s)
 	assert.Equal(t, ProbeType("prometheus"), ProbeTypePrometheus)
+	assert.Equal(t, ProbeType("alertmanager"), ProbeTypeAlertmanager)
 
 	assert.Equal(t, ProbeMode("sot"), ProbeModeSOT)
 	assert.Equal(t, ProbeMode("eot"), ProbeModeEOT)
 	assert.Equal(t, ProbeMode("continuous"), ProbeModeContinuous)
 	assert.Equal(t, ProbeMode("on_chaos"), ProbeModeOnChaos)
 }
+
+func TestParseTargetResource(t *testing.T) {
+	kind, name, err := ParseTargetResource("pod/worker-1")
+	assert.NoError(t, err)
+	assert.Equal(t, ResourcePod, kind)
+	assert.Equal(t, "worker-1", name)
+
+	for _, bad := range []string{"worker-1", "pod/", "/worker-1", "deployment/web", ""} {
+		_, _, err := ParseTargetResource(bad)
+		assert.ErrorIs(t, err, ErrInvalidTargetResource, bad)
+	}
+}
+
+func TestParseTargetWorkload(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		kind WorkloadKind
+	}{
+		{"deployment/web", WorkloadDeployment},
+		{"statefulset/db", WorkloadStatefulSet},
+		{"daemonset/agent", WorkloadDaemonSet},
+	} {
+		kind, name, err := ParseTargetWorkload(tt.in)
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.kind, kind)
+		assert.NotEmpty(t, name)
+	}
+
+	for _, bad := range []string{"web", "deployment/", "/web", "replicaset/web", "pod/web-1", ""} {
+		_, _, err := ParseTargetWorkload(bad)
+		assert.ErrorIs(t, err, ErrInvalidTargetWorkload, bad)
+	}
+}
+
+func TestValidateFieldSelector(t *testing.T) {
+	for _, ok := range []string{"", "spec.nodeName=node-1", "status.phase=Running,spec.nodeName!=node-2"} {
+		assert.NoError(t, ValidateFieldSelector(ok), ok)
+	}
+	for _, bad := range []string{"spec.nodeName", "spec.nodeName=a=b", "metadata.labels.app=web", "spec.containers=x"} {
+		assert.ErrorIs(t, ValidateFieldSelector(bad), ErrInvalidConfig, bad)
+	}
+}
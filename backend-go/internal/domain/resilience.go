@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// Categories of a locally computed ResilienceScore
+const (
+	ResilienceProbePassRate       = "probe_pass_rate"
+	ResilienceHealthyRecovery     = "healthy_ratio_recovery"
+	ResilienceRollbackSuccess     = "rollback_success"
+	ResilienceHypothesisAdherence = "hypothesis_adherence"
+)
+
+// ResilienceScoreSourceLocal marks a score computed by ScoreResilience
+// rather than by the AI service
+const ResilienceScoreSourceLocal = "local"
+
+// resilienceRecommendThreshold is the category score below which
+// ScoreResilience recommends a fix
+const resilienceRecommendThreshold = 80
+
+// ResilienceWeights weigh the categories of a locally computed score. They
+// are relative: a category without data is left out and the rest share its
+// weight. Negative weights count as zero.
+type ResilienceWeights struct {
+	ProbePassRate       float64
+	HealthyRecovery     float64
+	RollbackSuccess     float64
+	HypothesisAdherence float64
+}
+
+// DefaultResilienceWeights returns the weights used when none are configured
+func DefaultResilienceWeights() ResilienceWeights {
+	return ResilienceWeights{
+		ProbePassRate:       0.3,
+		HealthyRecovery:     0.25,
+		RollbackSuccess:     0.25,
+		HypothesisAdherence: 0.2,
+	}
+}
+
+// ScoreResilience computes a resilience score from stored experiment results
+// without the AI service. Each category is scored 0-100:
+//
+//   - probe_pass_rate: the share of probe results that passed
+//   - healthy_ratio_recovery: pods_healthy_ratio after chaos relative to the
+//     steady state, capped at 100 per experiment
+//   - rollback_success: the share of rollbacks that succeeded; residual drift
+//     found by rollback verification counts as a failed rollback
+//   - hypothesis_adherence: the share of experiments that completed with
+//     every probe passing
+//
+// Overall is the weighted mean of the categories that have data. Pending,
+// running and dry-run experiments are skipped.
+func ScoreResilience(results []ExperimentResult, w ResilienceWeights) ResilienceScore {
+	var (
+		probesPassed, probesTotal int
+		recoverySum               float64
+		recoveryCount             int
+		rollbacksOK, rollbacks    int
+		held, scored              int
+	)
+	for _, r := range results {
+		if r.Status == StatusPending || r.Status == StatusRunning || r.Config.Safety.DryRun {
+			continue
+		}
+		scored++
+
+		passed, total := probeOutcomes(r.Observations)
+		probesPassed += passed
+		probesTotal += total
+
+		if recovery, ok := healthyRecovery(r.SteadyState, r.Observations); ok {
+			recoverySum += recovery
+			recoveryCount++
+		}
+
+		ok, count := rollbackOutcomes(r.RollbackResult)
+		rollbacksOK += ok
+		rollbacks += count
+
+		if r.Status == StatusCompleted && passed == total {
+			held++
+		}
+	}
+
+	score := ResilienceScore{Categories: map[string]float64{}, Source: ResilienceScoreSourceLocal}
+	if scored == 0 {
+		details := "Computed locally: no finished experiments to score"
+		score.Details = &details
+		return score
+	}
+
+	type category struct {
+		name   string
+		value  float64
+		weight float64
+		advice string
+	}
+	categories := []category{{
+		name: ResilienceHypothesisAdherence, value: ratio(held, scored), weight: w.HypothesisAdherence,
+		advice: fmt.Sprintf("%d of %d experiments did not hold their hypothesis; review the failed runs before raising the blast radius", scored-held, scored),
+	}}
+	if probesTotal > 0 {
+		categories = append(categories, category{
+			name: ResilienceProbePassRate, value: ratio(probesPassed, probesTotal), weight: w.ProbePassRate,
+			advice: fmt.Sprintf("%d of %d probe checks failed; investigate the failing probes", probesTotal-probesPassed, probesTotal),
+		})
+	}
+	if recoveryCount > 0 {
+		categories = append(categories, category{
+			name: ResilienceHealthyRecovery, value: 100 * recoverySum / float64(recoveryCount), weight: w.HealthyRecovery,
+			advice: "Pods did not keep their healthy ratio under chaos; check replica counts and readiness probes",
+		})
+	}
+	if rollbacks > 0 {
+		categories = append(categories, category{
+			name: ResilienceRollbackSuccess, value: ratio(rollbacksOK, rollbacks), weight: w.RollbackSuccess,
+			advice: fmt.Sprintf("%d of %d rollbacks failed or left drift; check rollback_result for manual cleanup", rollbacks-rollbacksOK, rollbacks),
+		})
+	}
+
+	var weighted, totalWeight float64
+	for _, c := range categories {
+		score.Categories[c.name] = roundScore(c.value)
+		if c.value < resilienceRecommendThreshold {
+			score.Recommendations = append(score.Recommendations, c.advice)
+		}
+		if c.weight > 0 {
+			weighted += c.weight * c.value
+			totalWeight += c.weight
+		}
+	}
+	if totalWeight > 0 {
+		score.Overall = roundScore(weighted / totalWeight)
+	}
+	details := fmt.Sprintf("Computed locally from %d experiment(s)", scored)
+	score.Details = &details
+	return score
+}
+
+// probeOutcomes counts the passed and total probe results in observations,
+// as built by a run or decoded from the database
+func probeOutcomes(observations map[string]any) (passed, total int) {
+	var entries []map[string]any
+	switch list := observations["probe_results"].(type) {
+	case []map[string]any:
+		entries = list
+	case []any:
+		for _, e := range list {
+			if m, ok := e.(map[string]any); ok {
+				entries = append(entries, m)
+			}
+		}
+	}
+	for _, e := range entries {
+		total++
+		if p, _ := e["passed"].(bool); p {
+			passed++
+		}
+	}
+	return passed, total
+}
+
+// healthyRecovery returns the observed pods_healthy_ratio as a fraction of
+// the steady state's, capped at 1
+func healthyRecovery(steadyState, observations map[string]any) (float64, bool) {
+	before, ok := steadyState["pods_healthy_ratio"].(float64)
+	if !ok || before <= 0 {
+		return 0, false
+	}
+	after, ok := observations["pods_healthy_ratio"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return math.Min(after/before, 1), true
+}
+
+// rollbackOutcomes counts the successful and total rollbacks in a stored
+// rollback_result, counting non-empty residual drift as one more failure
+func rollbackOutcomes(rollback map[string]any) (ok, total int) {
+	for _, r := range PersistedRollbackResults(rollback) {
+		total++
+		if r.Status == "success" {
+			ok++
+		}
+	}
+	if len(PersistedResidualDrift(rollback)) > 0 {
+		total++
+	}
+	return ok, total
+}
+
+func ratio(n, total int) float64 {
+	return 100 * float64(n) / float64(total)
+}
+
+func roundScore(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syntheticResults decodes experiment results the way they come back from
+// the database
+func syntheticResults(t *testing.T, data string) []ExperimentResult {
+	t.Helper()
+	var results []ExperimentResult
+	require.NoError(t, json.Unmarshal([]byte(data), &results))
+	return results
+}
+
+const scoredResultsJSON = `[
+	{"experiment_id": "exp00001", "status": "completed",
+	 "steady_state": {"pods_healthy_ratio": 1.0},
+	 "observations": {"pods_healthy_ratio": 1.0,
+	   "probe_results": [{"probe": "web", "passed": true}, {"probe": "api", "passed": true}]},
+	 "rollback_result": {"rollback_0": {"status": "success"}, "residual_drift": []}},
+	{"experiment_id": "exp00002", "status": "failed",
+	 "steady_state": {"pods_healthy_ratio": 1.0},
+	 "observations": {"pods_healthy_ratio": 0.5,
+	   "probe_results": [{"probe": "web", "passed": true}, {"probe": "api", "passed": false}]},
+	 "rollback_result": {"rollback_0": {"status": "failed"}}},
+	{"experiment_id": "exp00003", "status": "running",
+	 "observations": {"probe_results": [{"probe": "web", "passed": false}]}},
+	{"experiment_id": "exp00004", "status": "completed", "config": {"safety": {"dry_run": true}},
+	 "rollback_result": {"rollback_0": {"status": "failed"}}}
+]`
+
+func TestScoreResilienceCategories(t *testing.T) {
+	score := ScoreResilience(syntheticResults(t, scoredResultsJSON), DefaultResilienceWeights())
+
+	assert.Equal(t, map[string]float64{
+		ResilienceHypothesisAdherence: 50,
+		ResilienceProbePassRate:       75,
+		ResilienceHealthyRecovery:     75,
+		ResilienceRollbackSuccess:     50,
+	}, score.Categories, "running and dry-run experiments are skipped")
+	// 0.3*75 + 0.25*75 + 0.25*50 + 0.2*50
+	assert.InDelta(t, 63.75, score.Overall, 0.1)
+	assert.Len(t, score.Recommendations, 4)
+	assert.Equal(t, ResilienceScoreSourceLocal, score.Source)
+	require.NotNil(t, score.Details)
+	assert.Contains(t, *score.Details, "Computed locally from 2 experiment(s)")
+}
+
+func TestScoreResilienceCountsReconciledRollbacks(t *testing.T) {
+	score := ScoreResilience(syntheticResults(t, `[
+		{"experiment_id": "exp00001", "status": "failed",
+		 "rollback_result": {"reconciled_at": "2026-01-01T00:00:00Z",
+		   "rollbacks": [{"description": "pod_delete", "status": "success"},
+		                 {"description": "ec2_stop", "status": "failed"}],
+		   "drift": [{"action": "pod_missing"}]}}
+	]`), DefaultResilienceWeights())
+
+	// One of two replayed rollbacks succeeded, plus the drift left behind
+	assert.Equal(t, 33.3, score.Categories[ResilienceRollbackSuccess])
+}
+
+func TestScoreResilienceWeights(t *testing.T) {
+	results := syntheticResults(t, scoredResultsJSON)
+
+	score := ScoreResilience(results, ResilienceWeights{ProbePassRate: 1})
+	assert.InDelta(t, 75, score.Overall, 0.01)
+
+	score = ScoreResilience(results, ResilienceWeights{RollbackSuccess: 3, HypothesisAdherence: 1, HealthyRecovery: -1})
+	assert.InDelta(t, 50, score.Overall, 0.01)
+	assert.Len(t, score.Categories, 4, "zero-weight categories are still reported")
+}
+
+func TestScoreResilienceRange(t *testing.T) {
+	perfect := syntheticResults(t, `[
+		{"experiment_id": "exp00001", "status": "completed",
+		 "steady_state": {"pods_healthy_ratio": 0.5}, "observations": {"pods_healthy_ratio": 1.0}}
+	]`)
+	score := ScoreResilience(perfect, DefaultResilienceWeights())
+	assert.Equal(t, 100.0, score.Overall, "recovery above the steady state is capped")
+	assert.Equal(t, map[string]float64{ResilienceHypothesisAdherence: 100, ResilienceHealthyRecovery: 100}, score.Categories,
+		"categories without data are left out")
+	assert.Empty(t, score.Recommendations)
+
+	broken := syntheticResults(t, `[
+		{"experiment_id": "exp00001", "status": "emergency_stopped",
+		 "steady_state": {"pods_healthy_ratio": 1.0}, "observations": {"pods_healthy_ratio": 0.0,
+		   "probe_results": [{"probe": "web", "passed": false}]},
+		 "rollback_result": {"rollback_0": {"status": "failed"}, "residual_drift": [{"action": "pod_missing"}]}}
+	]`)
+	score = ScoreResilience(broken, DefaultResilienceWeights())
+	assert.Equal(t, 0.0, score.Overall)
+	for name, v := range score.Categories {
+		assert.Equal(t, 0.0, v, name)
+	}
+
+	score = ScoreResilience(nil, DefaultResilienceWeights())
+	assert.Equal(t, 0.0, score.Overall)
+	assert.Empty(t, score.Categories)
+	require.NotNil(t, score.Details)
+	assert.Contains(t, *score.Details, "no finished experiments")
+}
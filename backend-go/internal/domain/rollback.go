@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RollbackResult describes the outcome of a single rollback operation
+type RollbackResult struct {
+	Description string         `json:"description"`
+	Status      string         `json:"status"`
+	Result      map[string]any `json:"result,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// PersistedRollbackResults decodes a stored rollback_result into an ordered
+// list. The runner writes {"rollback_0": {...}, "rollback_1": {...}, ...};
+// reconciliation of an orphaned run writes {"rollbacks": [...], ...}.
+// Entries that are not rollback results are skipped.
+func PersistedRollbackResults(rollbackResult map[string]any) []RollbackResult {
+	var entries []any
+	if list, ok := rollbackResult["rollbacks"].([]any); ok {
+		entries = list
+	} else {
+		indexed := map[int]any{}
+		for key, v := range rollbackResult {
+			if n, ok := strings.CutPrefix(key, "rollback_"); ok {
+				if i, err := strconv.Atoi(n); err == nil {
+					indexed[i] = v
+				}
+			}
+		}
+		for _, i := range slices.Sorted(maps.Keys(indexed)) {
+			entries = append(entries, indexed[i])
+		}
+	}
+
+	results := make([]RollbackResult, 0, len(entries))
+	for _, e := range entries {
+		if _, isMap := e.(map[string]any); !isMap {
+			continue
+		}
+		raw, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		var rr RollbackResult
+		if err := json.Unmarshal(raw, &rr); err != nil {
+			continue
+		}
+		results = append(results, rr)
+	}
+	return results
+}
+
+// PersistedResidualDrift returns the drift left after a stored rollback:
+// "residual_drift" from the runner or "drift" from reconciliation
+func PersistedResidualDrift(rollbackResult map[string]any) []any {
+	if drift, ok := rollbackResult["residual_drift"].([]any); ok {
+		return drift
+	}
+	drift, _ := rollbackResult["drift"].([]any)
+	return drift
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistedRollbackResults(t *testing.T) {
+	var runnerShape map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"rollback_10": {"description": "last", "status": "success"},
+		"rollback_0": {"description": "first", "status": "success", "result": {"recreated": 2}},
+		"rollback_1": {"description": "second", "status": "failed", "error": "timeout"},
+		"residual_drift": [{"action": "pod_missing"}]
+	}`), &runnerShape))
+
+	results := PersistedRollbackResults(runnerShape)
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"first", "second", "last"},
+		[]string{results[0].Description, results[1].Description, results[2].Description})
+	assert.Equal(t, "timeout", results[1].Error)
+	assert.Equal(t, 2.0, results[0].Result["recreated"])
+	assert.Len(t, PersistedResidualDrift(runnerShape), 1)
+
+	var reconciled map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"reconciled_at": "2026-01-01T00:00:00Z",
+		"rollbacks": [{"description": "pod_delete", "status": "failed", "error": "forbidden"}, "junk"],
+		"drift": [{"action": "pod_missing"}, {"action": "replicas_changed"}]
+	}`), &reconciled))
+	results = PersistedRollbackResults(reconciled)
+	require.Len(t, results, 1)
+	assert.Equal(t, "forbidden", results[0].Error)
+	assert.Len(t, PersistedResidualDrift(reconciled), 2)
+
+	assert.Empty(t, PersistedRollbackResults(nil))
+	assert.Empty(t, PersistedResidualDrift(nil))
+}
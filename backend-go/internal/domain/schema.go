@@ -0,0 +1,230 @@
+package domain
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// requiredParams lists the parameters each chaos type cannot run without
+var requiredParams = map[ChaosType][]string{
+	ChaosTypeProcessKill:    {"process_pattern"},
+	ChaosTypeEC2Stop:        {"instance_ids"},
+	ChaosTypeRDSFailover:    {"db_cluster_id"},
+	ChaosTypeRDSReboot:      {"db_instance_id"},
+	ChaosTypeRouteBlackhole: {"route_table_id", "destination_cidr"},
+	ChaosTypeLambdaThrottle: {"function_name"},
+	ChaosTypeSubnetIsolate:  {"subnet_id", "deny_acl_id"},
+}
+
+// oneOfRequiredParams lists, for chaos types that need at least one of
+// several parameters, the parameters that may satisfy it. The first is the
+// one ValidateChaosParams reports when all are missing.
+var oneOfRequiredParams = map[ChaosType][]string{
+	ChaosTypeNetworkFault: {FaultLatencyMsParam.Key, FaultLossPercentParam.Key, DuplicatePercentParam.Key},
+}
+
+// paramSchemas describes the non-numeric chaos parameters
+var paramSchemas = map[string]map[string]any{
+	"grace_period_seconds":    {"type": "integer", "minimum": 0, "maximum": MaxGracePeriodSeconds},
+	"memory_bytes":            {"type": "string", "default": DefaultMemoryBytes},
+	"shell_fallback":          {"type": "boolean", "default": false},
+	"use_ephemeral_container": {"type": "boolean", "default": false},
+	"ephemeral_image":         {"type": "string", "minLength": 1, "default": DefaultEphemeralImage},
+	"interface":               {"type": "string", "default": DefaultNetworkInterface},
+	"distribution":            {"type": "string", "enum": LatencyDistributions},
+	"process_pattern":         {"type": "string", "minLength": 1},
+	"signal":                  {"type": "string", "enum": ProcessSignals, "default": DefaultProcessSignal},
+	"instance_ids":            {"type": "array", "items": map[string]any{"type": "string"}, "minItems": 1},
+	"db_cluster_id":           {"type": "string", "minLength": 1},
+	"db_instance_id":          {"type": "string", "minLength": 1},
+	"force_failover":          {"type": "boolean", "default": false},
+	"route_table_id":          {"type": "string", "minLength": 1},
+	"destination_cidr":        {"type": "string", "minLength": 1},
+	"function_name":           {"type": "string", "minLength": 1},
+	"subnet_id":               {"type": "string", "minLength": 1},
+	"deny_acl_id":             {"type": "string", "minLength": 1},
+}
+
+// typeParams lists the non-numeric parameters accepted by each chaos type
+var typeParams = map[ChaosType][]string{
+	ChaosTypePodDelete:      {"grace_period_seconds"},
+	ChaosTypeNetworkLatency: {"interface", "distribution"},
+	ChaosTypeNetworkLoss:    {"interface"},
+	ChaosTypeNetworkFault:   {"interface", "distribution"},
+	ChaosTypeCPUStress:      {"shell_fallback", "use_ephemeral_container", "ephemeral_image"},
+	ChaosTypeMemoryStress:   {"memory_bytes", "shell_fallback", "use_ephemeral_container", "ephemeral_image"},
+	ChaosTypeProcessKill:    {"process_pattern", "signal"},
+	ChaosTypeEC2Stop:        {"instance_ids"},
+	ChaosTypeRDSFailover:    {"db_cluster_id"},
+	ChaosTypeRDSReboot:      {"db_instance_id", "force_failover"},
+	ChaosTypeRouteBlackhole: {"route_table_id", "destination_cidr"},
+	ChaosTypeLambdaThrottle: {"function_name"},
+	ChaosTypeSubnetIsolate:  {"subnet_id", "deny_acl_id"},
+}
+
+// schemaEnums maps the string enum types to their allowed values
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeFor[ChaosType](): enumValues(ChaosTypes),
+	reflect.TypeFor[ProbeType](): enumValues(ProbeTypes),
+	reflect.TypeFor[ProbeMode](): enumValues(ProbeModes),
+}
+
+func enumValues[T ~string](values []T) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = string(v)
+	}
+	return out
+}
+
+// ExperimentConfigSchema returns a JSON Schema (draft 2020-12) for
+// ExperimentConfig. The structure is generated from the struct's json and
+// binding tags; the per-chaos-type parameter rules come from the same tables
+// ValidateChaosParams uses.
+func ExperimentConfigSchema() map[string]any {
+	schema := JSONSchema(reflect.TypeFor[ExperimentConfig]())
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "ExperimentConfig"
+
+	props := schema["properties"].(map[string]any)
+	props["parameters"] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			HoldSecondsParam.Key:       intParamSchema(HoldSecondsParam),
+			WarmupSecondsParam.Key:     intParamSchema(WarmupSecondsParam),
+			MinHealthyRatioParam.Key:   floatParamSchema(MinHealthyRatioParam),
+			AbortHealthyRatioParam.Key: floatParamSchema(AbortHealthyRatioParam),
+		},
+	}
+
+	var rules []any
+	for _, t := range ChaosTypes {
+		paramProps := map[string]any{}
+		for _, p := range intParams[t] {
+			paramProps[p.Key] = intParamSchema(p)
+		}
+		for _, key := range typeParams[t] {
+			paramProps[key] = paramSchemas[key]
+		}
+		if len(paramProps) == 0 {
+			continue
+		}
+
+		paramsSchema := map[string]any{"properties": paramProps}
+		then := map[string]any{"properties": map[string]any{"parameters": paramsSchema}}
+		if req := requiredParams[t]; len(req) > 0 {
+			paramsSchema["required"] = req
+			then["required"] = []string{"parameters"}
+		}
+		if keys := oneOfRequiredParams[t]; len(keys) > 0 {
+			anyOf := make([]any, 0, len(keys))
+			for _, key := range keys {
+				anyOf = append(anyOf, map[string]any{
+					"required":   []string{key},
+					"properties": map[string]any{key: map[string]any{"exclusiveMinimum": 0}},
+				})
+			}
+			paramsSchema["anyOf"] = anyOf
+			then["required"] = []string{"parameters"}
+		}
+		rules = append(rules, map[string]any{
+			"if": map[string]any{
+				"properties": map[string]any{"chaos_type": map[string]any{"const": string(t)}},
+			},
+			"then": then,
+		})
+	}
+	schema["allOf"] = rules
+
+	return schema
+}
+
+func intParamSchema(p IntParam) map[string]any {
+	return map[string]any{"type": "integer", "minimum": p.Min, "maximum": p.Max, "default": p.Default}
+}
+
+func floatParamSchema(p FloatParam) map[string]any {
+	return map[string]any{"type": "number", "minimum": p.Min, "maximum": p.Max, "default": p.Default}
+}
+
+// JSONSchema builds a JSON Schema for t from its json and binding struct tags
+func JSONSchema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if values, ok := schemaEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+	if t == reflect.TypeFor[time.Time]() {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": JSONSchema(t.Elem())}
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.Interface {
+			return map[string]any{"type": "object"}
+		}
+		return map[string]any{"type": "object", "additionalProperties": JSONSchema(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	}
+	return map[string]any{}
+}
+
+func structSchema(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := JSONSchema(f.Type)
+		for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+			key, val, _ := strings.Cut(rule, "=")
+			switch key {
+			case "required":
+				required = append(required, name)
+			case "min", "max":
+				n, err := strconv.ParseFloat(val, 64)
+				if err != nil {
+					continue
+				}
+				if key == "min" {
+					fs["minimum"] = n
+				} else {
+					fs["maximum"] = n
+				}
+			}
+		}
+		props[name] = fs
+	}
+
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
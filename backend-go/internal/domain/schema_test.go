@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func schemaEnum(t *testing.T, schema map[string]any, path ...string) []string {
+	t.Helper()
+	node := schema
+	for _, p := range path {
+		next, ok := node[p].(map[string]any)
+		require.True(t, ok, "missing %s", p)
+		node = next
+	}
+	values, ok := node["enum"].([]string)
+	require.True(t, ok)
+	return values
+}
+
+func TestExperimentConfigSchemaEnumsMatchConstants(t *testing.T) {
+	schema := ExperimentConfigSchema()
+
+	assert.ElementsMatch(t,
+		[]string{"pod_delete", "pod_evict", "network_latency", "network_loss", "network_fault", "cpu_stress", "memory_stress", "clock_skew", "process_kill",
+			"ec2_stop", "rds_failover", "rds_reboot", "route_blackhole", "lambda_throttle", "subnet_isolate"},
+		schemaEnum(t, schema, "properties", "chaos_type"))
+
+	probeItems := []string{"properties", "probes", "items", "properties"}
+	assert.ElementsMatch(t, []string{"http", "cmd", "k8s", "prometheus", "alertmanager"},
+		schemaEnum(t, schema, append(probeItems, "type")...))
+	assert.ElementsMatch(t, []string{"sot", "eot", "continuous", "on_chaos"},
+		schemaEnum(t, schema, append(probeItems, "mode")...))
+
+	for _, ct := range ChaosTypes {
+		assert.True(t, IsKnownChaosType(ct))
+	}
+}
+
+func TestExperimentConfigSchemaFromTags(t *testing.T) {
+	schema := ExperimentConfigSchema()
+	assert.ElementsMatch(t, []string{"name", "chaos_type"}, schema["required"])
+
+	props := schema["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+		props["target_labels"])
+
+	timeout := props["safety"].(map[string]any)["properties"].(map[string]any)["timeout_seconds"].(map[string]any)
+	assert.Equal(t, "integer", timeout["type"])
+	assert.Equal(t, 1.0, timeout["minimum"])
+	assert.Equal(t, 120.0, timeout["maximum"])
+}
+
+func TestExperimentConfigSchemaRequiredParamsMatchValidation(t *testing.T) {
+	schema := ExperimentConfigSchema()
+
+	required := map[string][]string{}
+	for _, rule := range schema["allOf"].([]any) {
+		r := rule.(map[string]any)
+		ct := r["if"].(map[string]any)["properties"].(map[string]any)["chaos_type"].(map[string]any)["const"].(string)
+		params := r["then"].(map[string]any)["properties"].(map[string]any)["parameters"].(map[string]any)
+		if req, ok := params["required"].([]string); ok {
+			required[ct] = req
+		}
+		// Of "at least one of" alternatives, validation reports the first
+		if anyOf, ok := params["anyOf"].([]any); ok {
+			required[ct] = append(required[ct], anyOf[0].(map[string]any)["required"].([]string)...)
+		}
+	}
+
+	// Every parameter the schema marks required is one ValidateChaosParams
+	// rejects when missing, and vice versa
+	for _, ct := range ChaosTypes {
+		var missing []string
+		for _, e := range ValidateChaosParams(ExperimentConfig{ChaosType: ct}) {
+			missing = append(missing, e.Field[len("parameters."):])
+		}
+		assert.ElementsMatch(t, required[string(ct)], missing, "chaos type %s", ct)
+	}
+}
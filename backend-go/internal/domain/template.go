@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergeConfig overlays overrides (a decoded JSON object) onto a stored
+// template config. Nested objects such as parameters, target_labels and
+// safety are merged key by key; all other values replace the template's.
+func MergeConfig(base ExperimentConfig, overrides map[string]any) (ExperimentConfig, error) {
+	raw, err := json.Marshal(base)
+	if err != nil {
+		return ExperimentConfig{}, fmt.Errorf("marshal template config: %w", err)
+	}
+	var merged map[string]any
+	if err := json.Unmarshal(raw, &merged); err != nil {
+		return ExperimentConfig{}, fmt.Errorf("decode template config: %w", err)
+	}
+
+	mergeMaps(merged, overrides)
+
+	raw, err = json.Marshal(merged)
+	if err != nil {
+		return ExperimentConfig{}, fmt.Errorf("marshal merged config: %w", err)
+	}
+	var cfg ExperimentConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return ExperimentConfig{}, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return cfg, nil
+}
+
+func mergeMaps(dst, src map[string]any) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]any)
+		dstMap, dstIsMap := dst[k].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+}
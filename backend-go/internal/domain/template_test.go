@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeConfigOverlaysNestedObjects(t *testing.T) {
+	ns := "staging"
+	base := ExperimentConfig{
+		Name:            "stop-workers",
+		ChaosType:       ChaosTypeEC2Stop,
+		TargetNamespace: &ns,
+		TargetLabels:    map[string]string{"app": "worker", "tier": "batch"},
+		Parameters:      map[string]any{"instance_ids": []any{"i-placeholder"}, "note": "keep"},
+		Safety:          DefaultSafetyConfig(),
+	}
+
+	merged, err := MergeConfig(base, map[string]any{
+		"target_namespace": "payments",
+		"target_labels":    map[string]any{"app": "billing"},
+		"parameters":       map[string]any{"instance_ids": []any{"i-111", "i-222"}},
+		"safety":           map[string]any{"timeout_seconds": float64(60)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "stop-workers", merged.Name)
+	assert.Equal(t, "payments", *merged.TargetNamespace)
+	assert.Equal(t, map[string]string{"app": "billing", "tier": "batch"}, merged.TargetLabels)
+	assert.Equal(t, []any{"i-111", "i-222"}, merged.Parameters["instance_ids"])
+	assert.Equal(t, "keep", merged.Parameters["note"])
+	assert.Equal(t, 60, merged.Safety.TimeoutSeconds)
+	assert.Equal(t, 0.3, merged.Safety.MaxBlastRadius)
+
+	// The stored template is untouched
+	assert.Equal(t, "staging", *base.TargetNamespace)
+	assert.Equal(t, []any{"i-placeholder"}, base.Parameters["instance_ids"])
+}
+
+func TestMergeConfigNoOverrides(t *testing.T) {
+	base := ExperimentConfig{Name: "pods", ChaosType: ChaosTypePodDelete, Safety: DefaultSafetyConfig()}
+
+	merged, err := MergeConfig(base, nil)
+	require.NoError(t, err)
+	assert.Equal(t, base, merged)
+}
+
+func TestMergeConfigRejectsWrongTypes(t *testing.T) {
+	base := ExperimentConfig{Name: "pods", ChaosType: ChaosTypePodDelete}
+
+	_, err := MergeConfig(base, map[string]any{"safety": "off"})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
@@ -1,5 +1,11 @@
 package domain
 
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
 // ResourceType identifies the kind of infrastructure resource
 type ResourceType string
 
@@ -51,10 +57,101 @@ type InfraTopology struct {
 	Timestamp *string        `json:"timestamp,omitempty"`
 }
 
+// TopologyTimestamp formats a scan time for InfraTopology.Timestamp
+func TopologyTimestamp(t time.Time) *string {
+	ts := t.UTC().Format(time.RFC3339Nano)
+	return &ts
+}
+
+// OlderTimestamp returns whichever of two topology timestamps is earlier,
+// ignoring nil or unparseable values
+func OlderTimestamp(a, b *string) *string {
+	ta, errA := parseTimestamp(a)
+	tb, errB := parseTimestamp(b)
+	switch {
+	case errA != nil:
+		if errB != nil {
+			return nil
+		}
+		return b
+	case errB != nil || !tb.Before(ta):
+		return a
+	}
+	return b
+}
+
+func parseTimestamp(ts *string) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, errors.New("no timestamp")
+	}
+	return time.Parse(time.RFC3339Nano, *ts)
+}
+
+// LinkNodesToInstances returns runs_on edges from K8s nodes to the EC2
+// instances backing them. Nodes are matched by the instance ID in
+// Spec.ProviderID ("aws:///<zone>/<instance-id>"), falling back to the
+// private DNS name. Nodes outside EC2 produce no edges.
+func LinkNodesToInstances(k8s, aws *InfraTopology) []TopologyEdge {
+	edges := make([]TopologyEdge, 0)
+	if k8s == nil || aws == nil {
+		return edges
+	}
+
+	instances := make(map[string]bool)
+	byDNS := make(map[string]string)
+	for _, n := range aws.Nodes {
+		if n.ResourceType != ResourceEC2 {
+			continue
+		}
+		instances[n.ID] = true
+		if dns, _ := n.Metadata["private_dns_name"].(string); dns != "" {
+			byDNS[dns] = n.ID
+		}
+	}
+
+	for _, n := range k8s.Nodes {
+		if n.ResourceType != ResourceNode {
+			continue
+		}
+		instanceID := ""
+		if providerID, _ := n.Metadata["provider_id"].(string); providerID != "" {
+			if id, ok := EC2InstanceIDFromProviderID(providerID); ok && instances[id] {
+				instanceID = id
+			}
+		}
+		if instanceID == "" {
+			if dns, _ := n.Metadata["internal_dns"].(string); dns != "" {
+				instanceID = byDNS[dns]
+			}
+		}
+		if instanceID != "" {
+			edges = append(edges, TopologyEdge{Source: n.ID, Target: instanceID, Relation: "runs_on"})
+		}
+	}
+	return edges
+}
+
+// EC2InstanceIDFromProviderID extracts the instance ID from an AWS node
+// provider ID such as "aws:///us-east-1a/i-0123456789abcdef0"
+func EC2InstanceIDFromProviderID(providerID string) (string, bool) {
+	rest, ok := strings.CutPrefix(providerID, "aws://")
+	if !ok {
+		return "", false
+	}
+	id := rest[strings.LastIndex(rest, "/")+1:]
+	if !strings.HasPrefix(id, "i-") {
+		return "", false
+	}
+	return id, true
+}
+
 // ResilienceScore summarizes system resilience
 type ResilienceScore struct {
 	Overall         float64            `json:"overall"`
 	Categories      map[string]float64 `json:"categories,omitempty"`
 	Recommendations []string           `json:"recommendations,omitempty"`
 	Details         *string            `json:"details,omitempty"`
+	// Source is ResilienceScoreSourceLocal when the score was computed
+	// without the AI service
+	Source string `json:"source,omitempty"`
 }
@@ -3,6 +3,7 @@ package domain
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -101,3 +102,64 @@ func TestResilienceScoreJSON(t *testing.T) {
 	assert.Len(t, decoded.Recommendations, 1)
 	assert.Equal(t, "Good resilience", *decoded.Details)
 }
+
+func TestOlderTimestamp(t *testing.T) {
+	early := TopologyTimestamp(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	late := TopologyTimestamp(time.Date(2024, 1, 1, 12, 0, 5, 0, time.UTC))
+	bad := "not-a-time"
+
+	assert.Equal(t, early, OlderTimestamp(early, late))
+	assert.Equal(t, early, OlderTimestamp(late, early))
+	assert.Equal(t, late, OlderTimestamp(nil, late))
+	assert.Equal(t, early, OlderTimestamp(early, &bad))
+	assert.Nil(t, OlderTimestamp(nil, nil))
+}
+
+func TestEC2InstanceIDFromProviderID(t *testing.T) {
+	id, ok := EC2InstanceIDFromProviderID("aws:///us-east-1a/i-0123456789abcdef0")
+	assert.True(t, ok)
+	assert.Equal(t, "i-0123456789abcdef0", id)
+
+	for _, providerID := range []string{
+		"gce://my-project/us-central1-a/node-1",
+		"kind://docker/kind/kind-control-plane",
+		"aws:///us-east-1a/",
+		"",
+	} {
+		_, ok := EC2InstanceIDFromProviderID(providerID)
+		assert.False(t, ok, providerID)
+	}
+}
+
+func TestLinkNodesToInstances(t *testing.T) {
+	k8s := &InfraTopology{Nodes: []TopologyNode{
+		{ID: "node/by-provider", ResourceType: ResourceNode,
+			Metadata: map[string]any{"provider_id": "aws:///us-east-1a/i-aaa"}},
+		{ID: "node/by-dns", ResourceType: ResourceNode,
+			Metadata: map[string]any{"internal_dns": "ip-10-0-0-2.ec2.internal"}},
+		{ID: "node/gke", ResourceType: ResourceNode,
+			Metadata: map[string]any{"provider_id": "gce://p/z/gke-node"}},
+		{ID: "pod/web", ResourceType: ResourcePod},
+	}}
+	aws := &InfraTopology{Nodes: []TopologyNode{
+		{ID: "i-aaa", ResourceType: ResourceEC2, Metadata: map[string]any{"private_dns_name": "ip-10-0-0-1.ec2.internal"}},
+		{ID: "i-bbb", ResourceType: ResourceEC2, Metadata: map[string]any{"private_dns_name": "ip-10-0-0-2.ec2.internal"}},
+	}}
+
+	edges := LinkNodesToInstances(k8s, aws)
+	assert.ElementsMatch(t, []TopologyEdge{
+		{Source: "node/by-provider", Target: "i-aaa", Relation: "runs_on"},
+		{Source: "node/by-dns", Target: "i-bbb", Relation: "runs_on"},
+	}, edges)
+}
+
+func TestLinkNodesToInstancesWithoutEC2(t *testing.T) {
+	k8s := &InfraTopology{Nodes: []TopologyNode{
+		{ID: "node/kind", ResourceType: ResourceNode,
+			Metadata: map[string]any{"provider_id": "kind://docker/kind/kind-control-plane"}},
+	}}
+
+	assert.Empty(t, LinkNodesToInstances(k8s, &InfraTopology{}))
+	assert.Empty(t, LinkNodesToInstances(k8s, nil))
+	assert.Empty(t, LinkNodesToInstances(nil, nil))
+}
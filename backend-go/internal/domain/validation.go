@@ -0,0 +1,670 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/params"
+)
+
+// ValidationError describes a single invalid field in an experiment config
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// IntParam describes a bounded integer chaos parameter and its default
+type IntParam struct {
+	Key      string
+	Default  int
+	Min, Max int
+}
+
+// Get reads the parameter from a config's parameters map
+func (p IntParam) Get(m map[string]any) (int, error) {
+	return params.GetIntInRange(m, p.Key, p.Default, p.Min, p.Max)
+}
+
+var (
+	LatencyMsParam   = IntParam{Key: "latency_ms", Default: 100, Min: 1, Max: 60000}
+	LossPercentParam = IntParam{Key: "loss_percent", Default: 10, Min: 1, Max: 100}
+	CoresParam       = IntParam{Key: "cores", Default: 1, Min: 1, Max: 64}
+	// VMWorkersParam is how many stress-ng --vm workers memory_stress
+	// starts; each allocates memory_bytes
+	VMWorkersParam = IntParam{Key: "vm_workers", Default: 1, Min: 1, Max: 16}
+	// JitterMsParam varies each packet's delay around latency_ms; 0 keeps it fixed
+	JitterMsParam = IntParam{Key: "jitter_ms", Default: 0, Min: 0, Max: 60000}
+	// network_fault combines optional netem impairments; 0 leaves one out
+	FaultLatencyMsParam   = IntParam{Key: "latency_ms", Default: 0, Min: 0, Max: 60000}
+	FaultLossPercentParam = IntParam{Key: "loss_percent", Default: 0, Min: 0, Max: 100}
+	DuplicatePercentParam = IntParam{Key: "duplicate_percent", Default: 0, Min: 0, Max: 100}
+	// ClockOffsetParam shifts the container clock; negative values move it back
+	ClockOffsetParam = IntParam{Key: "offset_seconds", Default: 300, Min: -86400, Max: 86400}
+	// HoldSecondsParam keeps the fault in place while probes and steady state
+	// are monitored; 0 means no hold
+	HoldSecondsParam = IntParam{Key: "hold_seconds", Default: 0, Min: 0, Max: 120}
+	// WarmupSecondsParam lets the system settle between the SOT probes and
+	// injection; 0 means no warmup
+	WarmupSecondsParam = IntParam{Key: "warmup_seconds", Default: 0, Min: 0, Max: 300}
+	// ProbeTimeoutMsParam is the timeout_ms property of an HTTP, cmd or
+	// Prometheus probe; absent keeps the probe type's default timeout
+	ProbeTimeoutMsParam = IntParam{Key: "timeout_ms", Default: 0, Min: 1, Max: 60000}
+	// PromQueryTimeoutMsParam is the evaluation timeout a Prometheus probe
+	// sends with its query; absent leaves the server's default
+	PromQueryTimeoutMsParam = IntParam{Key: "query_timeout_ms", Default: 0, Min: 1, Max: 300000}
+)
+
+// PromProbeTime reads the "time" property of a Prometheus probe, the instant
+// its query is evaluated at: RFC 3339 or Unix seconds. Zero means now.
+func PromProbeTime(m map[string]any) (time.Time, error) {
+	switch v := m["time"].(type) {
+	case nil:
+		return time.Time{}, nil
+	case float64:
+		if v <= 0 {
+			return time.Time{}, &params.Error{Key: "time", Message: fmt.Sprintf("must be a positive Unix timestamp, got %v", v)}
+		}
+		sec, frac := math.Modf(v)
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, &params.Error{Key: "time", Message: fmt.Sprintf("must be RFC 3339 or Unix seconds, got %q", v)}
+		}
+		return t, nil
+	default:
+		return time.Time{}, &params.Error{Key: "time", Message: fmt.Sprintf("must be RFC 3339 or Unix seconds, got %T", v)}
+	}
+}
+
+// FloatParam describes a bounded numeric chaos parameter and its default
+type FloatParam struct {
+	Key      string
+	Default  float64
+	Min, Max float64
+}
+
+// Get reads the parameter from a config's parameters map
+func (p FloatParam) Get(m map[string]any) (float64, error) {
+	return params.GetFloatInRange(m, p.Key, p.Default, p.Min, p.Max)
+}
+
+// MinHealthyRatioParam aborts a hold when pods_healthy_ratio drops below it
+var MinHealthyRatioParam = FloatParam{Key: "min_healthy_ratio", Default: 0.5, Min: 0, Max: 1}
+
+// AbortHealthyRatioParam rolls an experiment back immediately after injection
+// when pods_healthy_ratio is already below it; 0 disables the check
+var AbortHealthyRatioParam = FloatParam{Key: "abort_on_healthy_ratio_below", Default: 0, Min: 0, Max: 1}
+
+// DefaultMemoryBytes is the memory_stress allocation when none is given
+const DefaultMemoryBytes = "256M"
+
+// ShellFallback reads the shell_fallback parameter of cpu_stress and
+// memory_stress: when set, pods whose container lacks stress-ng are stressed
+// with plain shell loops instead of failing the injection
+func ShellFallback(m map[string]any) (bool, error) {
+	return params.GetBool(m, "shell_fallback", false)
+}
+
+// DefaultEphemeralImage is the stress image an ephemeral container runs
+// when ephemeral_image is not given; its entrypoint must be stress-ng
+const DefaultEphemeralImage = "alexeiled/stress-ng:latest"
+
+// EphemeralImage reads the use_ephemeral_container and ephemeral_image
+// parameters of cpu_stress and memory_stress. It returns the image to run
+// stress-ng from in an ephemeral container attached to each target pod, or
+// "" to exec in the app container.
+func EphemeralImage(m map[string]any) (string, error) {
+	use, err := params.GetBool(m, "use_ephemeral_container", false)
+	if err != nil || !use {
+		return "", err
+	}
+	image, err := params.GetString(m, "ephemeral_image", DefaultEphemeralImage)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(image) == "" {
+		return "", &params.Error{Key: "ephemeral_image", Message: "must not be empty"}
+	}
+	return image, nil
+}
+
+// MemoryMiB converts a stress-ng size ("512", "64k", "256M", "1g") to whole
+// MiB, rounding up, for the shell fallback of memory_stress. Percentages of
+// available memory need stress-ng and are rejected.
+func MemoryMiB(size string) (int, error) {
+	invalid := &params.Error{Key: "memory_bytes", Message: fmt.Sprintf("%q is not a size the shell fallback supports (bytes with an optional k, m or g suffix)", size)}
+	s := strings.ToLower(strings.TrimSpace(size))
+	mult := 1
+	switch {
+	case strings.HasSuffix(s, "k"):
+		mult, s = 1<<10, strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "m"):
+		mult, s = 1<<20, strings.TrimSuffix(s, "m")
+	case strings.HasSuffix(s, "g"):
+		mult, s = 1<<30, strings.TrimSuffix(s, "g")
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, invalid
+	}
+	return (n*mult + 1<<20 - 1) >> 20, nil
+}
+
+const (
+	// DefaultNetworkInterface is the device tc-based chaos targets when no
+	// interface parameter is given
+	DefaultNetworkInterface = "eth0"
+	// AutoNetworkInterface detects each pod's default-route device instead
+	AutoNetworkInterface = "auto"
+)
+
+// interfaceNamePattern matches Linux network device names (IFNAMSIZ - 1)
+var interfaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.@-]{1,15}$`)
+
+// NetworkInterface reads the "interface" parameter of tc-based chaos types
+func NetworkInterface(m map[string]any) (string, error) {
+	iface, err := params.GetString(m, "interface", DefaultNetworkInterface)
+	if err != nil {
+		return "", err
+	}
+	if iface != AutoNetworkInterface && !interfaceNamePattern.MatchString(iface) {
+		return "", &params.Error{Key: "interface", Message: fmt.Sprintf("%q is not a valid network interface name", iface)}
+	}
+	return iface, nil
+}
+
+// LatencyDistributions lists the netem delay distributions network_latency
+// accepts for its jitter
+var LatencyDistributions = []string{"normal", "pareto", "paretonormal"}
+
+// NetemDelay is the delay network_latency adds with netem
+type NetemDelay struct {
+	LatencyMs int
+	JitterMs  int
+	// Distribution shapes the jitter; empty leaves netem's uniform default
+	Distribution string
+}
+
+// NetworkDelay reads the latency_ms, jitter_ms and distribution parameters
+// of network_latency. Jitter must stay below the base latency, and a
+// distribution needs jitter to shape.
+func NetworkDelay(m map[string]any) (NetemDelay, error) {
+	latency, err := LatencyMsParam.Get(m)
+	if err != nil {
+		return NetemDelay{}, err
+	}
+	jitter, err := JitterMsParam.Get(m)
+	if err != nil {
+		return NetemDelay{}, err
+	}
+	if jitter >= latency {
+		return NetemDelay{}, &params.Error{Key: "jitter_ms", Message: fmt.Sprintf("must be less than latency_ms (%d)", latency)}
+	}
+	dist, err := params.GetString(m, "distribution", "")
+	if err != nil {
+		return NetemDelay{}, err
+	}
+	if dist != "" {
+		if !slices.Contains(LatencyDistributions, dist) {
+			return NetemDelay{}, &params.Error{Key: "distribution", Message: fmt.Sprintf("unsupported distribution %q, expected one of %v", dist, LatencyDistributions)}
+		}
+		if jitter == 0 {
+			return NetemDelay{}, &params.Error{Key: "distribution", Message: "requires jitter_ms"}
+		}
+	}
+	return NetemDelay{LatencyMs: latency, JitterMs: jitter, Distribution: dist}, nil
+}
+
+// NetemFault combines the impairments one netem qdisc applies; zero fields
+// are left out
+type NetemFault struct {
+	Delay            NetemDelay
+	LossPercent      int
+	DuplicatePercent int
+}
+
+// NetworkFault reads the parameters of network_fault. Latency (with
+// jitter_ms and distribution, as for network_latency), loss_percent and
+// duplicate_percent are each optional, but at least one must be set.
+func NetworkFault(m map[string]any) (NetemFault, error) {
+	latency, err := FaultLatencyMsParam.Get(m)
+	if err != nil {
+		return NetemFault{}, err
+	}
+	loss, err := FaultLossPercentParam.Get(m)
+	if err != nil {
+		return NetemFault{}, err
+	}
+	duplicate, err := DuplicatePercentParam.Get(m)
+	if err != nil {
+		return NetemFault{}, err
+	}
+	if latency == 0 && loss == 0 && duplicate == 0 {
+		return NetemFault{}, &params.Error{Key: "latency_ms", Message: "network_fault needs latency_ms, loss_percent or duplicate_percent above 0"}
+	}
+
+	fault := NetemFault{LossPercent: loss, DuplicatePercent: duplicate}
+	if latency > 0 {
+		if fault.Delay, err = NetworkDelay(m); err != nil {
+			return NetemFault{}, err
+		}
+		return fault, nil
+	}
+	for _, key := range []string{"jitter_ms", "distribution"} {
+		if m[key] != nil {
+			return NetemFault{}, &params.Error{Key: key, Message: "requires latency_ms"}
+		}
+	}
+	return fault, nil
+}
+
+// DefaultProcessSignal is the signal process_kill sends when none is given
+const DefaultProcessSignal = "TERM"
+
+// ProcessSignals lists the signals process_kill may send
+var ProcessSignals = []string{"TERM", "KILL", "INT", "HUP", "QUIT", "USR1", "USR2"}
+
+// matchAllPatterns are pkill -f patterns that would match every process in
+// the container, including its init process
+var matchAllPatterns = []string{".", "*", ".*", ".+", "^", "$", "^.*$", "\\w"}
+
+// ProcessPattern reads the required "process_pattern" parameter of
+// process_kill and rejects patterns that match every process
+func ProcessPattern(m map[string]any) (string, error) {
+	pattern, err := params.GetStringRequired(m, "process_pattern")
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(pattern) == "" {
+		return "", &params.Error{Key: "process_pattern", Message: "must not be blank"}
+	}
+	if slices.Contains(matchAllPatterns, strings.TrimSpace(pattern)) {
+		return "", &params.Error{Key: "process_pattern", Message: fmt.Sprintf("%q would match every process", pattern)}
+	}
+	return pattern, nil
+}
+
+// ProcessSignal reads the "signal" parameter of process_kill
+func ProcessSignal(m map[string]any) (string, error) {
+	signal, err := params.GetString(m, "signal", DefaultProcessSignal)
+	if err != nil {
+		return "", err
+	}
+	signal = strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	if !slices.Contains(ProcessSignals, signal) {
+		return "", &params.Error{Key: "signal", Message: fmt.Sprintf("unsupported signal %q, expected one of %v", signal, ProcessSignals)}
+	}
+	return signal, nil
+}
+
+// MaxOwnerLength caps an experiment's owner, long enough for any email address
+const MaxOwnerLength = 254
+
+// MaxGracePeriodSeconds caps pod_delete's grace_period_seconds
+const MaxGracePeriodSeconds = 3600
+
+// PodDeleteGracePeriod reads the "grace_period_seconds" parameter of
+// pod_delete. nil leaves each pod's terminationGracePeriodSeconds in effect;
+// 0 kills the pods at once, like a crash.
+func PodDeleteGracePeriod(m map[string]any) (*int64, error) {
+	if m["grace_period_seconds"] == nil {
+		return nil, nil
+	}
+	secs, err := params.GetIntInRange(m, "grace_period_seconds", 0, 0, MaxGracePeriodSeconds)
+	if err != nil {
+		return nil, err
+	}
+	grace := int64(secs)
+	return &grace, nil
+}
+
+// intParams lists the numeric parameters accepted by each chaos type
+var intParams = map[ChaosType][]IntParam{
+	ChaosTypeNetworkLatency: {LatencyMsParam, JitterMsParam},
+	ChaosTypeNetworkLoss:    {LossPercentParam},
+	ChaosTypeNetworkFault:   {FaultLatencyMsParam, JitterMsParam, FaultLossPercentParam, DuplicatePercentParam},
+	ChaosTypeCPUStress:      {CoresParam},
+	ChaosTypeMemoryStress:   {VMWorkersParam},
+	ChaosTypeClockSkew:      {ClockOffsetParam},
+}
+
+// IsKnownChaosType reports whether t is a supported chaos type
+func IsKnownChaosType(t ChaosType) bool {
+	return slices.Contains(ChaosTypes, t)
+}
+
+// IsK8sChaosType reports whether t injects faults into Kubernetes workloads
+func IsK8sChaosType(t ChaosType) bool {
+	switch t {
+	case ChaosTypePodDelete, ChaosTypePodEvict, ChaosTypeNetworkLatency, ChaosTypeNetworkLoss,
+		ChaosTypeNetworkFault, ChaosTypeCPUStress, ChaosTypeMemoryStress, ChaosTypeClockSkew,
+		ChaosTypeProcessKill:
+		return true
+	}
+	return false
+}
+
+// ValidateConfig checks an experiment config without executing it and
+// returns every problem found rather than stopping at the first
+func ValidateConfig(cfg ExperimentConfig) []ValidationError {
+	errs := []ValidationError{}
+	add := func(field, format string, args ...any) {
+		errs = append(errs, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if cfg.Name == "" {
+		add("name", "is required")
+	}
+	if cfg.ChaosType == "" {
+		add("chaos_type", "is required")
+	} else if !IsKnownChaosType(cfg.ChaosType) {
+		add("chaos_type", "unknown chaos type %q", cfg.ChaosType)
+	}
+	if cfg.Owner != nil {
+		if owner := strings.TrimSpace(*cfg.Owner); owner == "" {
+			add("owner", "must not be blank")
+		} else if len(owner) > MaxOwnerLength {
+			add("owner", "at most %d characters, got %d", MaxOwnerLength, len(owner))
+		}
+	}
+
+	if cfg.TargetResource != nil && *cfg.TargetResource != "" {
+		if _, _, err := ParseTargetResource(*cfg.TargetResource); err != nil {
+			add("target_resource", "%v", err)
+		}
+	}
+
+	if cfg.TargetWorkload != nil && *cfg.TargetWorkload != "" {
+		switch {
+		case cfg.TargetResource != nil && *cfg.TargetResource != "":
+			add("target_workload", "cannot be combined with target_resource")
+		case cfg.ChaosType != "" && !IsK8sChaosType(cfg.ChaosType):
+			add("target_workload", "only applies to Kubernetes chaos types")
+		default:
+			if _, _, err := ParseTargetWorkload(*cfg.TargetWorkload); err != nil {
+				add("target_workload", "%v", err)
+			}
+		}
+	}
+
+	if cfg.FieldSelector != nil && *cfg.FieldSelector != "" {
+		if cfg.ChaosType != "" && !IsK8sChaosType(cfg.ChaosType) {
+			add("field_selector", "only applies to Kubernetes chaos types")
+		} else if err := ValidateFieldSelector(*cfg.FieldSelector); err != nil {
+			add("field_selector", "%v", err)
+		}
+	}
+
+	if len(cfg.TargetNamespaces) > 0 {
+		switch {
+		case cfg.TargetNamespace != nil && *cfg.TargetNamespace != "":
+			add("target_namespaces", "cannot be combined with target_namespace")
+		case cfg.ChaosType != "" && !IsK8sChaosType(cfg.ChaosType):
+			add("target_namespaces", "only applies to Kubernetes chaos types")
+		}
+		seen := make(map[string]bool, len(cfg.TargetNamespaces))
+		for i, ns := range cfg.TargetNamespaces {
+			if ns == "" {
+				add(fmt.Sprintf("target_namespaces[%d]", i), "must not be empty")
+			} else if seen[ns] {
+				add(fmt.Sprintf("target_namespaces[%d]", i), "duplicate namespace %q", ns)
+			}
+			seen[ns] = true
+		}
+	}
+
+	if len(cfg.Steps) == 0 {
+		errs = append(errs, ValidateChaosParams(cfg)...)
+	} else {
+		errs = append(errs, validateSteps(cfg)...)
+	}
+
+	for i, pc := range cfg.Probes {
+		switch pc.Type {
+		case ProbeTypeHTTP, ProbeTypeCmd, ProbeTypePrometheus:
+			var pe *params.Error
+			if _, err := ProbeTimeoutMsParam.Get(pc.Properties); errors.As(err, &pe) {
+				add(fmt.Sprintf("probes[%d].properties.timeout_ms", i), "%s", pe.Message)
+			}
+		}
+		if pc.Type == ProbeTypePrometheus {
+			var pe *params.Error
+			if _, err := PromQueryTimeoutMsParam.Get(pc.Properties); errors.As(err, &pe) {
+				add(fmt.Sprintf("probes[%d].properties.%s", i, pe.Key), "%s", pe.Message)
+			}
+			if _, err := PromProbeTime(pc.Properties); errors.As(err, &pe) {
+				add(fmt.Sprintf("probes[%d].properties.%s", i, pe.Key), "%s", pe.Message)
+			}
+		}
+
+		key := ""
+		switch pc.Type {
+		case ProbeTypeHTTP:
+			key = "headers"
+		case ProbeTypeAlertmanager:
+			key = "match_labels"
+		default:
+			continue
+		}
+		if _, err := params.GetStringMap(pc.Properties, key); err != nil {
+			add(fmt.Sprintf("probes[%d].properties.%s", i, key), "must be an object of string values")
+		}
+	}
+
+	s := cfg.Safety
+	if s.TimeoutSeconds < 1 || s.TimeoutSeconds > 120 {
+		add("safety.timeout_seconds", "must be 1-120, got %d", s.TimeoutSeconds)
+	}
+	if s.MaxBlastRadius < 0 || s.MaxBlastRadius > 1 {
+		add("safety.max_blast_radius", "must be 0-1, got %g", s.MaxBlastRadius)
+	}
+	if s.HealthCheckInterval < 1 || s.HealthCheckInterval > 60 {
+		add("safety.health_check_interval", "must be 1-60, got %d", s.HealthCheckInterval)
+	}
+	if s.HealthCheckFailureThreshold < 1 || s.HealthCheckFailureThreshold > 10 {
+		add("safety.health_check_failure_threshold", "must be 1-10, got %d", s.HealthCheckFailureThreshold)
+	}
+	for _, level := range slices.Sorted(maps.Keys(s.CriticalityWeights)) {
+		if w := s.CriticalityWeights[level]; w <= 0 {
+			add("safety.criticality_weights."+level, "must be greater than 0, got %g", w)
+		}
+	}
+
+	return errs
+}
+
+// validateSteps checks each step of a chained experiment as the single-fault
+// experiment it runs as
+func validateSteps(cfg ExperimentConfig) []ValidationError {
+	errs := []ValidationError{}
+	add := func(field, format string, args ...any) {
+		errs = append(errs, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(cfg.Steps) > MaxChaosSteps {
+		add("steps", "at most %d steps, got %d", MaxChaosSteps, len(cfg.Steps))
+	}
+	if cfg.ChaosType != "" && cfg.ChaosType != cfg.Steps[0].ChaosType {
+		add("chaos_type", "must match the first step's chaos type %q", cfg.Steps[0].ChaosType)
+	}
+	k8sTargeting := len(cfg.TargetNamespaces) > 0 ||
+		(cfg.TargetWorkload != nil && *cfg.TargetWorkload != "") ||
+		(cfg.FieldSelector != nil && *cfg.FieldSelector != "")
+	for i, step := range cfg.Steps {
+		prefix := fmt.Sprintf("steps[%d].", i)
+		if step.DelaySeconds < 0 || step.DelaySeconds > 60 {
+			add(prefix+"delay_seconds", "must be 0-60, got %d", step.DelaySeconds)
+		}
+		switch {
+		case step.ChaosType == "":
+			add(prefix+"chaos_type", "is required")
+			continue
+		case !IsKnownChaosType(step.ChaosType):
+			add(prefix+"chaos_type", "unknown chaos type %q", step.ChaosType)
+			continue
+		case k8sTargeting && !IsK8sChaosType(step.ChaosType):
+			add(prefix+"chaos_type", "cannot use Kubernetes targeting (target_namespaces, target_workload, field_selector)")
+		}
+		for _, e := range ValidateChaosParams(cfg.StepConfig(i)) {
+			errs = append(errs, ValidationError{Field: prefix + e.Field, Message: e.Message})
+		}
+	}
+	return errs
+}
+
+// ValidateChaosParams checks the chaos-type specific parameters: required
+// values and numeric ranges
+func ValidateChaosParams(cfg ExperimentConfig) []ValidationError {
+	errs := []ValidationError{}
+	add := func(field, format string, args ...any) {
+		errs = append(errs, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	addErr := func(err error) {
+		var pe *params.Error
+		if errors.As(err, &pe) {
+			add("parameters."+pe.Key, "%s", pe.Message)
+			return
+		}
+		add("parameters", "%v", err)
+	}
+
+	for _, p := range intParams[cfg.ChaosType] {
+		if _, err := p.Get(cfg.Parameters); err != nil {
+			addErr(err)
+		}
+	}
+
+	if _, err := HoldSecondsParam.Get(cfg.Parameters); err != nil {
+		addErr(err)
+	}
+	if _, err := WarmupSecondsParam.Get(cfg.Parameters); err != nil {
+		addErr(err)
+	}
+	if _, err := MinHealthyRatioParam.Get(cfg.Parameters); err != nil {
+		addErr(err)
+	}
+	if _, err := AbortHealthyRatioParam.Get(cfg.Parameters); err != nil {
+		addErr(err)
+	}
+
+	switch cfg.ChaosType {
+	case ChaosTypePodDelete:
+		if _, err := PodDeleteGracePeriod(cfg.Parameters); err != nil {
+			addErr(err)
+		}
+	case ChaosTypeNetworkLatency:
+		if _, err := NetworkInterface(cfg.Parameters); err != nil {
+			addErr(err)
+		}
+		// Out-of-range latency_ms and jitter_ms were reported with intParams
+		_, latencyErr := LatencyMsParam.Get(cfg.Parameters)
+		_, jitterErr := JitterMsParam.Get(cfg.Parameters)
+		if latencyErr == nil && jitterErr == nil {
+			if _, err := NetworkDelay(cfg.Parameters); err != nil {
+				addErr(err)
+			}
+		}
+	case ChaosTypeNetworkLoss:
+		if _, err := NetworkInterface(cfg.Parameters); err != nil {
+			addErr(err)
+		}
+	case ChaosTypeNetworkFault:
+		if _, err := NetworkInterface(cfg.Parameters); err != nil {
+			addErr(err)
+		}
+		// Out-of-range values were reported with intParams
+		inRange := true
+		for _, p := range intParams[ChaosTypeNetworkFault] {
+			if _, err := p.Get(cfg.Parameters); err != nil {
+				inRange = false
+			}
+		}
+		if inRange {
+			if _, err := NetworkFault(cfg.Parameters); err != nil {
+				addErr(err)
+			}
+		}
+	case ChaosTypeCPUStress:
+		if _, err := ShellFallback(cfg.Parameters); err != nil {
+			addErr(err)
+		}
+		if _, err := EphemeralImage(cfg.Parameters); err != nil {
+			addErr(err)
+		}
+	case ChaosTypeMemoryStress:
+		size, err := params.GetString(cfg.Parameters, "memory_bytes", DefaultMemoryBytes)
+		if err != nil {
+			addErr(err)
+		}
+		if _, err := EphemeralImage(cfg.Parameters); err != nil {
+			addErr(err)
+		}
+		fallback, fallbackErr := ShellFallback(cfg.Parameters)
+		if fallbackErr != nil {
+			addErr(fallbackErr)
+		} else if fallback && err == nil {
+			if _, err := MemoryMiB(size); err != nil {
+				addErr(err)
+			}
+		}
+	case ChaosTypeProcessKill:
+		if _, err := ProcessPattern(cfg.Parameters); err != nil {
+			addErr(err)
+		}
+		if _, err := ProcessSignal(cfg.Parameters); err != nil {
+			addErr(err)
+		}
+	case ChaosTypeEC2Stop:
+		if _, err := params.GetStringSliceRequired(cfg.Parameters, "instance_ids"); err != nil {
+			addErr(err)
+		}
+	case ChaosTypeRDSFailover:
+		if _, err := params.GetStringRequired(cfg.Parameters, "db_cluster_id"); err != nil {
+			addErr(err)
+		}
+	case ChaosTypeRDSReboot:
+		if _, err := params.GetStringRequired(cfg.Parameters, "db_instance_id"); err != nil {
+			addErr(err)
+		}
+		if _, err := params.GetBool(cfg.Parameters, "force_failover", false); err != nil {
+			addErr(err)
+		}
+	case ChaosTypeRouteBlackhole:
+		if _, err := params.GetStringRequired(cfg.Parameters, "route_table_id"); err != nil {
+			addErr(err)
+		}
+		cidr, err := params.GetStringRequired(cfg.Parameters, "destination_cidr")
+		if err != nil {
+			addErr(err)
+		} else if _, _, err := net.ParseCIDR(cidr); err != nil {
+			add("parameters.destination_cidr", "invalid CIDR %q", cidr)
+		}
+	case ChaosTypeLambdaThrottle:
+		if _, err := params.GetStringRequired(cfg.Parameters, "function_name"); err != nil {
+			addErr(err)
+		}
+	case ChaosTypeSubnetIsolate:
+		if _, err := params.GetStringRequired(cfg.Parameters, "subnet_id"); err != nil {
+			addErr(err)
+		}
+		if _, err := params.GetStringRequired(cfg.Parameters, "deny_acl_id"); err != nil {
+			addErr(err)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,458 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig(chaosType ChaosType, params map[string]any) ExperimentConfig {
+	return ExperimentConfig{
+		Name:       "test",
+		ChaosType:  chaosType,
+		Parameters: params,
+		Safety:     DefaultSafetyConfig(),
+	}
+}
+
+func TestValidateConfigValid(t *testing.T) {
+	cfgs := []ExperimentConfig{
+		validConfig(ChaosTypePodDelete, nil),
+		validConfig(ChaosTypeNetworkLatency, map[string]any{"latency_ms": float64(250)}),
+		validConfig(ChaosTypeCPUStress, map[string]any{"cores": 4}),
+		validConfig(ChaosTypeEC2Stop, map[string]any{"instance_ids": []any{"i-123"}}),
+		validConfig(ChaosTypeRouteBlackhole, map[string]any{"route_table_id": "rtb-1", "destination_cidr": "10.0.0.0/16"}),
+	}
+	for _, cfg := range cfgs {
+		assert.Empty(t, ValidateConfig(cfg), string(cfg.ChaosType))
+	}
+}
+
+func TestValidateConfigCollectsAllErrors(t *testing.T) {
+	cfg := validConfig(ChaosTypeRouteBlackhole, map[string]any{"destination_cidr": "not-a-cidr"})
+	cfg.Name = ""
+	cfg.Safety.MaxBlastRadius = 1.5
+
+	errs := ValidateConfig(cfg)
+	fields := make([]string, 0, len(errs))
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.ElementsMatch(t, []string{
+		"name",
+		"parameters.route_table_id",
+		"parameters.destination_cidr",
+		"safety.max_blast_radius",
+	}, fields)
+}
+
+func TestValidateConfigCriticalityWeights(t *testing.T) {
+	cfg := validConfig(ChaosTypePodDelete, nil)
+	cfg.Safety.CriticalityWeighting = true
+	cfg.Safety.CriticalityWeights = map[string]float64{"high": 10, "low": -1}
+
+	errs := ValidateConfig(cfg)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "safety.criticality_weights.low", errs[0].Field)
+
+	cfg.Safety.CriticalityWeights["low"] = 0
+	errs = ValidateConfig(cfg)
+	require.Len(t, errs, 1, "a zero weight would let the level's pods out of the blast radius")
+	assert.Equal(t, "safety.criticality_weights.low", errs[0].Field)
+
+	cfg.Safety.CriticalityWeights["low"] = 0.5
+	assert.Empty(t, ValidateConfig(cfg))
+	assert.Equal(t, map[string]float64{"high": 10, "medium": 2, "low": 0.5}, cfg.Safety.CriticalityWeightTable())
+}
+
+func TestValidateConfigTargetWorkload(t *testing.T) {
+	workload := func(chaosType ChaosType, w string, resource *string) []ValidationError {
+		cfg := validConfig(chaosType, map[string]any{"instance_ids": []any{"i-1"}})
+		cfg.TargetWorkload = &w
+		cfg.TargetResource = resource
+		return ValidateConfig(cfg)
+	}
+
+	assert.Empty(t, workload(ChaosTypePodDelete, "deployment/web", nil))
+
+	pod := "pod/web-1"
+
+	for _, errs := range [][]ValidationError{
+		workload(ChaosTypePodDelete, "replicaset/web", nil),
+		workload(ChaosTypePodDelete, "deployment/web", &pod),
+		workload(ChaosTypeEC2Stop, "deployment/web", nil),
+	} {
+		require.Len(t, errs, 1)
+		assert.Equal(t, "target_workload", errs[0].Field)
+	}
+}
+
+func TestValidateChaosParamsRanges(t *testing.T) {
+	tests := []struct {
+		chaosType ChaosType
+		param     string
+		value     any
+		wantErr   bool
+	}{
+		{ChaosTypeNetworkLatency, "latency_ms", float64(0), true},
+		{ChaosTypeNetworkLatency, "latency_ms", float64(60000), false},
+		{ChaosTypeNetworkLoss, "loss_percent", float64(101), true},
+		{ChaosTypeNetworkLoss, "loss_percent", "ten", true},
+		{ChaosTypeCPUStress, "cores", float64(64), false},
+		{ChaosTypeCPUStress, "cores", float64(65), true},
+		{ChaosTypeMemoryStress, "vm_workers", float64(16), false},
+		{ChaosTypeMemoryStress, "vm_workers", float64(0), true},
+		{ChaosTypeMemoryStress, "vm_workers", float64(17), true},
+		{ChaosTypeMemoryStress, "memory_bytes", float64(512), true},
+		{ChaosTypeClockSkew, "offset_seconds", float64(-3600), false},
+		{ChaosTypeClockSkew, "offset_seconds", float64(86401), true},
+		{ChaosTypeProcessKill, "signal", "KILL", true}, // process_pattern missing
+		{ChaosTypePodDelete, "grace_period_seconds", float64(0), false},
+		{ChaosTypePodDelete, "grace_period_seconds", float64(-1), true},
+		{ChaosTypePodDelete, "grace_period_seconds", float64(1.5), true},
+		{ChaosTypeNetworkLatency, "interface", "net1", false},
+		{ChaosTypeNetworkLoss, "interface", "auto", false},
+		{ChaosTypeNetworkLoss, "interface", "eth0; reboot", true},
+		{ChaosTypeNetworkLatency, "interface", float64(0), true},
+	}
+	for _, tt := range tests {
+		errs := ValidateChaosParams(validConfig(tt.chaosType, map[string]any{tt.param: tt.value}))
+		assert.Equal(t, tt.wantErr, len(errs) > 0, "%s=%v", tt.param, tt.value)
+	}
+}
+
+func TestPodDeleteGracePeriod(t *testing.T) {
+	grace, err := PodDeleteGracePeriod(nil)
+	require.NoError(t, err)
+	assert.Nil(t, grace, "unset keeps the pods' own grace period")
+
+	grace, err = PodDeleteGracePeriod(map[string]any{"grace_period_seconds": float64(0)})
+	require.NoError(t, err)
+	require.NotNil(t, grace)
+	assert.Equal(t, int64(0), *grace)
+}
+
+func TestValidateConfigUnknownChaosType(t *testing.T) {
+	errs := ValidateConfig(validConfig("disk_fill", nil))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "chaos_type", errs[0].Field)
+}
+
+func TestValidateConfigProbeHeaders(t *testing.T) {
+	cfg := validConfig(ChaosTypePodDelete, nil)
+	cfg.Probes = []ProbeConfig{{
+		Name:       "auth",
+		Type:       ProbeTypeHTTP,
+		Mode:       ProbeModeSOT,
+		Properties: map[string]any{"headers": map[string]any{"X-Retry": float64(1)}},
+	}}
+
+	errs := ValidateConfig(cfg)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "probes[0].properties.headers", errs[0].Field)
+}
+
+func TestValidateConfigProbeTimeout(t *testing.T) {
+	cfg := validConfig(ChaosTypePodDelete, nil)
+	cfg.Probes = []ProbeConfig{
+		{Name: "ok", Type: ProbeTypeHTTP, Mode: ProbeModeSOT, Properties: map[string]any{"timeout_ms": float64(2000)}},
+		{Name: "zero", Type: ProbeTypeCmd, Mode: ProbeModeSOT, Properties: map[string]any{"timeout_ms": float64(0)}},
+		{Name: "huge", Type: ProbeTypePrometheus, Mode: ProbeModeSOT, Properties: map[string]any{"timeout_ms": float64(600000)}},
+		{Name: "text", Type: ProbeTypeHTTP, Mode: ProbeModeSOT, Properties: map[string]any{"timeout_ms": "5s"}},
+	}
+
+	errs := ValidateConfig(cfg)
+	require.Len(t, errs, 3)
+	assert.Equal(t, "probes[1].properties.timeout_ms", errs[0].Field)
+	assert.Equal(t, "probes[2].properties.timeout_ms", errs[1].Field)
+	assert.Equal(t, "probes[3].properties.timeout_ms", errs[2].Field)
+}
+
+func TestValidateConfigPrometheusTime(t *testing.T) {
+	prom := func(props map[string]any) ProbeConfig {
+		props["endpoint"], props["query"] = "http://prom", "up"
+		return ProbeConfig{Name: "prom", Type: ProbeTypePrometheus, Mode: ProbeModeSOT, Properties: props}
+	}
+	cfg := validConfig(ChaosTypePodDelete, nil)
+	cfg.Probes = []ProbeConfig{
+		prom(map[string]any{"time": "2024-05-01T12:00:00Z", "query_timeout_ms": float64(1500)}),
+		prom(map[string]any{"time": float64(1714564800)}),
+		prom(map[string]any{"time": "yesterday"}),
+		prom(map[string]any{"query_timeout_ms": float64(0)}),
+	}
+
+	errs := ValidateConfig(cfg)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "probes[2].properties.time", errs[0].Field)
+	assert.Equal(t, "probes[3].properties.query_timeout_ms", errs[1].Field)
+
+	at, err := PromProbeTime(map[string]any{"time": 1714564800.25})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 250_000_000, time.UTC), at)
+}
+
+func TestValidateConfigOwner(t *testing.T) {
+	owner := func(s string) *string { return &s }
+	cfg := validConfig(ChaosTypePodDelete, nil)
+
+	cfg.Owner = owner("payments@example.com")
+	assert.Empty(t, ValidateConfig(cfg))
+
+	for _, bad := range []string{"", "   ", strings.Repeat("a", MaxOwnerLength+1)} {
+		cfg.Owner = owner(bad)
+		errs := ValidateConfig(cfg)
+		require.Len(t, errs, 1)
+		assert.Equal(t, "owner", errs[0].Field)
+	}
+}
+
+func TestValidateConfigAlertmanagerMatchLabels(t *testing.T) {
+	cfg := validConfig(ChaosTypePodDelete, nil)
+	cfg.Probes = []ProbeConfig{{
+		Name:       "alerts",
+		Type:       ProbeTypeAlertmanager,
+		Mode:       ProbeModeContinuous,
+		Properties: map[string]any{"match_labels": []any{"severity"}},
+	}}
+
+	errs := ValidateConfig(cfg)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "probes[0].properties.match_labels", errs[0].Field)
+}
+
+func TestValidateConfigTargetNamespaces(t *testing.T) {
+	cfg := validConfig(ChaosTypePodDelete, nil)
+	cfg.TargetNamespaces = []string{"team-a", "team-b"}
+	assert.Empty(t, ValidateConfig(cfg))
+
+	cfg.TargetNamespaces = []string{"team-a", "", "team-a"}
+	fields := []string{}
+	for _, e := range ValidateConfig(cfg) {
+		fields = append(fields, e.Field)
+	}
+	assert.ElementsMatch(t, []string{"target_namespaces[1]", "target_namespaces[2]"}, fields)
+
+	aws := validConfig(ChaosTypeRDSFailover, map[string]any{"db_cluster_id": "db-1"})
+	aws.TargetNamespaces = []string{"team-a"}
+	errs := ValidateConfig(aws)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "target_namespaces", errs[0].Field)
+
+	ns := "team-c"
+	both := validConfig(ChaosTypePodDelete, nil)
+	both.TargetNamespace = &ns
+	both.TargetNamespaces = []string{"team-a"}
+	assert.Len(t, ValidateConfig(both), 1)
+}
+
+func TestValidateConfigFieldSelector(t *testing.T) {
+	cfg := validConfig(ChaosTypeCPUStress, nil)
+	sel := "spec.nodeName=node-1"
+	cfg.FieldSelector = &sel
+	assert.Empty(t, ValidateConfig(cfg))
+
+	bad := "spec.nodename=node-1"
+	cfg.FieldSelector = &bad
+	errs := ValidateConfig(cfg)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "field_selector", errs[0].Field)
+
+	aws := validConfig(ChaosTypeRDSFailover, map[string]any{"db_cluster_id": "db-1"})
+	aws.FieldSelector = &sel
+	errs = ValidateConfig(aws)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Message, "Kubernetes")
+}
+
+func TestValidateConfigSteps(t *testing.T) {
+	cfg := validConfig(ChaosTypeNetworkLatency, map[string]any{"latency_ms": 100})
+	cfg.Steps = []ChaosStep{
+		{ChaosType: ChaosTypeNetworkLatency},
+		{ChaosType: ChaosTypePodDelete, DelaySeconds: 10},
+	}
+	assert.Empty(t, ValidateConfig(cfg))
+
+	cfg.ChaosType = ChaosTypePodDelete
+	cfg.Steps = []ChaosStep{
+		{ChaosType: ChaosTypeNetworkLatency, Parameters: map[string]any{"latency_ms": -1}},
+		{ChaosType: "disk_fill", DelaySeconds: 61},
+		{},
+	}
+	fields := []string{}
+	for _, e := range ValidateConfig(cfg) {
+		fields = append(fields, e.Field)
+	}
+	assert.ElementsMatch(t, []string{
+		"chaos_type",
+		"steps[0].parameters.latency_ms",
+		"steps[1].delay_seconds",
+		"steps[1].chaos_type",
+		"steps[2].chaos_type",
+	}, fields)
+}
+
+func TestStepConfigOverlaysParameters(t *testing.T) {
+	cfg := validConfig(ChaosTypeNetworkLatency, map[string]any{"latency_ms": 100, "interface": "eth1"})
+	cfg.Steps = []ChaosStep{
+		{ChaosType: ChaosTypeNetworkLatency},
+		{ChaosType: ChaosTypeNetworkLatency, Parameters: map[string]any{"latency_ms": 500}},
+	}
+	step := cfg.StepConfig(1)
+	assert.Nil(t, step.Steps)
+	assert.Equal(t, map[string]any{"latency_ms": 500, "interface": "eth1"}, step.Parameters)
+	// The experiment's own parameters are left untouched
+	assert.Equal(t, 100, cfg.Parameters["latency_ms"])
+}
+
+func TestValidateProcessKillParams(t *testing.T) {
+	tests := []struct {
+		params  map[string]any
+		wantErr string
+	}{
+		{map[string]any{"process_pattern": "nginx: worker"}, ""},
+		{map[string]any{"process_pattern": "gunicorn", "signal": "sigkill"}, ""},
+		{map[string]any{}, "parameters.process_pattern"},
+		{map[string]any{"process_pattern": "  "}, "parameters.process_pattern"},
+		{map[string]any{"process_pattern": "."}, "parameters.process_pattern"},
+		{map[string]any{"process_pattern": "*"}, "parameters.process_pattern"},
+		{map[string]any{"process_pattern": ".*"}, "parameters.process_pattern"},
+		{map[string]any{"process_pattern": "nginx", "signal": "SEGV"}, "parameters.signal"},
+		{map[string]any{"process_pattern": "nginx", "signal": float64(9)}, "parameters.signal"},
+	}
+	for _, tt := range tests {
+		errs := ValidateChaosParams(validConfig(ChaosTypeProcessKill, tt.params))
+		if tt.wantErr == "" {
+			assert.Empty(t, errs, "%v", tt.params)
+			continue
+		}
+		require.Len(t, errs, 1, "%v", tt.params)
+		assert.Equal(t, tt.wantErr, errs[0].Field)
+	}
+}
+
+func TestValidateNetworkLatencyJitter(t *testing.T) {
+	tests := []struct {
+		params  map[string]any
+		wantErr string
+	}{
+		{map[string]any{"latency_ms": float64(100), "jitter_ms": float64(20)}, ""},
+		{map[string]any{"latency_ms": float64(100), "jitter_ms": float64(20), "distribution": "pareto"}, ""},
+		{map[string]any{"latency_ms": float64(100), "jitter_ms": float64(-1)}, "parameters.jitter_ms"},
+		{map[string]any{"latency_ms": float64(100), "jitter_ms": float64(100)}, "parameters.jitter_ms"},
+		{map[string]any{"jitter_ms": float64(150)}, "parameters.jitter_ms"}, // default latency is 100
+		{map[string]any{"jitter_ms": float64(20), "distribution": "gaussian"}, "parameters.distribution"},
+		{map[string]any{"distribution": "normal"}, "parameters.distribution"}, // no jitter to shape
+	}
+	for _, tt := range tests {
+		errs := ValidateChaosParams(validConfig(ChaosTypeNetworkLatency, tt.params))
+		if tt.wantErr == "" {
+			assert.Empty(t, errs, "%v", tt.params)
+			continue
+		}
+		require.Len(t, errs, 1, "%v", tt.params)
+		assert.Equal(t, tt.wantErr, errs[0].Field)
+	}
+}
+
+func TestValidateNetworkFaultParams(t *testing.T) {
+	tests := []struct {
+		params  map[string]any
+		wantErr string
+	}{
+		{map[string]any{"latency_ms": float64(100), "jitter_ms": float64(20), "loss_percent": float64(5)}, ""},
+		{map[string]any{"loss_percent": float64(5), "duplicate_percent": float64(1)}, ""},
+		{map[string]any{"duplicate_percent": float64(2), "interface": "auto"}, ""},
+		{nil, "parameters.latency_ms"}, // no impairment at all
+		{map[string]any{"latency_ms": float64(0), "loss_percent": float64(0)}, "parameters.latency_ms"},
+		{map[string]any{"loss_percent": float64(101)}, "parameters.loss_percent"},
+		{map[string]any{"duplicate_percent": float64(-1), "loss_percent": float64(5)}, "parameters.duplicate_percent"},
+		{map[string]any{"loss_percent": float64(5), "jitter_ms": float64(20)}, "parameters.jitter_ms"}, // jitter needs latency
+		{map[string]any{"latency_ms": float64(100), "jitter_ms": float64(100)}, "parameters.jitter_ms"},
+		{map[string]any{"loss_percent": float64(5), "interface": "eth0; reboot"}, "parameters.interface"},
+	}
+	for _, tt := range tests {
+		errs := ValidateChaosParams(validConfig(ChaosTypeNetworkFault, tt.params))
+		if tt.wantErr == "" {
+			assert.Empty(t, errs, "%v", tt.params)
+			continue
+		}
+		require.Len(t, errs, 1, "%v", tt.params)
+		assert.Equal(t, tt.wantErr, errs[0].Field)
+	}
+
+	fault, err := NetworkFault(map[string]any{"latency_ms": float64(100), "loss_percent": float64(5), "duplicate_percent": float64(1)})
+	require.NoError(t, err)
+	assert.Equal(t, NetemFault{Delay: NetemDelay{LatencyMs: 100}, LossPercent: 5, DuplicatePercent: 1}, fault)
+}
+
+func TestValidateStressParams(t *testing.T) {
+	tests := []struct {
+		chaosType ChaosType
+		params    map[string]any
+		wantErr   string
+	}{
+		{ChaosTypeCPUStress, map[string]any{"shell_fallback": true}, ""},
+		{ChaosTypeCPUStress, map[string]any{"shell_fallback": "yes"}, "parameters.shell_fallback"},
+		{ChaosTypeMemoryStress, map[string]any{"shell_fallback": true}, ""},
+		{ChaosTypeMemoryStress, map[string]any{"memory_bytes": "50%"}, ""}, // stress-ng handles percentages
+		{ChaosTypeMemoryStress, map[string]any{"memory_bytes": "50%", "shell_fallback": true}, "parameters.memory_bytes"},
+		{ChaosTypeCPUStress, map[string]any{"use_ephemeral_container": true}, ""},
+		{ChaosTypeCPUStress, map[string]any{"use_ephemeral_container": "true"}, "parameters.use_ephemeral_container"},
+		{ChaosTypeMemoryStress, map[string]any{"use_ephemeral_container": true, "ephemeral_image": " "}, "parameters.ephemeral_image"},
+	}
+	for _, tt := range tests {
+		errs := ValidateChaosParams(validConfig(tt.chaosType, tt.params))
+		if tt.wantErr == "" {
+			assert.Empty(t, errs, "%v", tt.params)
+			continue
+		}
+		require.Len(t, errs, 1, "%v", tt.params)
+		assert.Equal(t, tt.wantErr, errs[0].Field)
+	}
+}
+
+func TestEphemeralImage(t *testing.T) {
+	image, err := EphemeralImage(map[string]any{"ephemeral_image": "stress:1"})
+	require.NoError(t, err)
+	assert.Empty(t, image, "the image alone doesn't switch to an ephemeral container")
+
+	image, err = EphemeralImage(map[string]any{"use_ephemeral_container": true})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultEphemeralImage, image)
+}
+
+func TestMemoryMiB(t *testing.T) {
+	tests := []struct {
+		size string
+		want int
+	}{
+		{"256M", 256},
+		{"1g", 1024},
+		{"1536k", 2},
+		{"1048576", 1},
+		{"1", 1}, // rounded up
+	}
+	for _, tt := range tests {
+		got, err := MemoryMiB(tt.size)
+		require.NoError(t, err, tt.size)
+		assert.Equal(t, tt.want, got, tt.size)
+	}
+
+	for _, size := range []string{"50%", "", "M", "-1M", "1T"} {
+		_, err := MemoryMiB(size)
+		assert.Error(t, err, size)
+	}
+}
+
+func TestProcessSignalNormalizesName(t *testing.T) {
+	signal, err := ProcessSignal(map[string]any{"signal": "SIGhup"})
+	require.NoError(t, err)
+	assert.Equal(t, "HUP", signal)
+
+	signal, err = ProcessSignal(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProcessSignal, signal)
+}
@@ -0,0 +1,37 @@
+package engine
+
+import "time"
+
+// Default AI request timeouts
+const (
+	DefaultAIRequestTimeout     = 30 * time.Second
+	DefaultAILongRequestTimeout = 60 * time.Second
+)
+
+// longAIPaths are AI endpoints that generate long output and get the long
+// timeout
+var longAIPaths = map[string]bool{"/report": true, "/analyze": true}
+
+// AITimeouts bounds requests to the AI service
+type AITimeouts struct {
+	// Request applies to every AI call without an override
+	Request time.Duration
+	// Long applies to the long-running /report and /analyze calls
+	Long time.Duration
+}
+
+// DefaultAITimeouts returns the built-in AI timeouts
+func DefaultAITimeouts() AITimeouts {
+	return AITimeouts{Request: DefaultAIRequestTimeout, Long: DefaultAILongRequestTimeout}
+}
+
+// For returns the timeout for a request to path
+func (t AITimeouts) For(path string) time.Duration {
+	if longAIPaths[path] && t.Long > 0 {
+		return t.Long
+	}
+	if t.Request > 0 {
+		return t.Request
+	}
+	return DefaultAIRequestTimeout
+}
@@ -4,22 +4,63 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/chaosduck/backend-go/internal/domain"
 	"github.com/chaosduck/backend-go/internal/safety"
 )
 
+// ec2API is the subset of the EC2 client the engine uses; *ec2.Client
+// implements it
+type ec2API interface {
+	DescribeRegions(ctx context.Context, in *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error)
+	DescribeInstances(ctx context.Context, in *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	StopInstances(ctx context.Context, in *ec2.StopInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error)
+	StartInstances(ctx context.Context, in *ec2.StartInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error)
+	DescribeRouteTables(ctx context.Context, in *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error)
+	CreateRoute(ctx context.Context, in *ec2.CreateRouteInput, optFns ...func(*ec2.Options)) (*ec2.CreateRouteOutput, error)
+	ReplaceRoute(ctx context.Context, in *ec2.ReplaceRouteInput, optFns ...func(*ec2.Options)) (*ec2.ReplaceRouteOutput, error)
+	DeleteRoute(ctx context.Context, in *ec2.DeleteRouteInput, optFns ...func(*ec2.Options)) (*ec2.DeleteRouteOutput, error)
+	DescribeVpcs(ctx context.Context, in *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	DescribeSubnets(ctx context.Context, in *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	DescribeNetworkAcls(ctx context.Context, in *ec2.DescribeNetworkAclsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkAclsOutput, error)
+	ReplaceNetworkAclAssociation(ctx context.Context, in *ec2.ReplaceNetworkAclAssociationInput, optFns ...func(*ec2.Options)) (*ec2.ReplaceNetworkAclAssociationOutput, error)
+}
+
+// rdsAPI is the subset of the RDS client the engine uses; *rds.Client
+// implements it
+type rdsAPI interface {
+	DescribeDBClusters(ctx context.Context, in *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error)
+	DescribeDBInstances(ctx context.Context, in *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	FailoverDBCluster(ctx context.Context, in *rds.FailoverDBClusterInput, optFns ...func(*rds.Options)) (*rds.FailoverDBClusterOutput, error)
+	RebootDBInstance(ctx context.Context, in *rds.RebootDBInstanceInput, optFns ...func(*rds.Options)) (*rds.RebootDBInstanceOutput, error)
+}
+
+// lambdaAPI is the subset of the Lambda client the engine uses;
+// *lambda.Client implements it
+type lambdaAPI interface {
+	GetFunctionConcurrency(ctx context.Context, in *lambda.GetFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionConcurrencyOutput, error)
+	PutFunctionConcurrency(ctx context.Context, in *lambda.PutFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.PutFunctionConcurrencyOutput, error)
+	DeleteFunctionConcurrency(ctx context.Context, in *lambda.DeleteFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.DeleteFunctionConcurrencyOutput, error)
+}
+
+// awsTopologyTimeout bounds a whole topology scan so a very large account
+// cannot hang the topology endpoint
+const awsTopologyTimeout = 30 * time.Second
+
 // AwsEngine implements chaos operations against AWS resources.
 // All mutation methods return (result, rollbackFn).
 type AwsEngine struct {
-	ec2Client *ec2.Client
-	rdsClient *rds.Client
-	esm       *safety.EmergencyStopManager
+	ec2Client    ec2API
+	rdsClient    rdsAPI
+	lambdaClient lambdaAPI
+	esm          *safety.EmergencyStopManager
 }
 
 // NewAwsEngine creates an AwsEngine with the specified region
@@ -30,9 +71,10 @@ func NewAwsEngine(ctx context.Context, region string, esm *safety.EmergencyStopM
 	}
 
 	return &AwsEngine{
-		ec2Client: ec2.NewFromConfig(cfg),
-		rdsClient: rds.NewFromConfig(cfg),
-		esm:       esm,
+		ec2Client:    ec2.NewFromConfig(cfg),
+		rdsClient:    rds.NewFromConfig(cfg),
+		lambdaClient: lambda.NewFromConfig(cfg),
+		esm:          esm,
 	}, nil
 }
 
@@ -40,6 +82,84 @@ func (e *AwsEngine) checkEmergencyStop() error {
 	return e.esm.CheckEmergencyStop()
 }
 
+// Ping makes a lightweight read-only EC2 call to confirm credentials and
+// endpoint reachability
+func (e *AwsEngine) Ping(ctx context.Context) error {
+	if _, err := e.ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{}); err != nil {
+		return fmt.Errorf("describe regions: %w", err)
+	}
+	return nil
+}
+
+// AWS resource types whose steady state GetSteadyState captures; they are the
+// resource_type of the resulting AWS snapshot
+const (
+	AWSResourceEC2         = "ec2"
+	AWSResourceRDSCluster  = "rds_cluster"
+	AWSResourceRDSInstance = "rds_instance"
+)
+
+// GetSteadyState captures the state of the AWS resources a fault targets:
+// the state of each EC2 instance, or the status of an RDS cluster (with its
+// writer) or DB instance. It is read-only and safe to call during an
+// emergency stop.
+func (e *AwsEngine) GetSteadyState(ctx context.Context, resourceType string, ids []string) (map[string]any, error) {
+	switch resourceType {
+	case AWSResourceEC2:
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("no instance_ids specified")
+		}
+		out, err := e.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{InstanceIds: ids})
+		if err != nil {
+			return nil, fmt.Errorf("describe EC2 instances: %w", err)
+		}
+		instances := make(map[string]any, len(ids))
+		for _, res := range out.Reservations {
+			for _, inst := range res.Instances {
+				state := ""
+				if inst.State != nil {
+					state = string(inst.State.Name)
+				}
+				instances[aws.ToString(inst.InstanceId)] = state
+			}
+		}
+		return map[string]any{"instances": instances}, nil
+
+	case AWSResourceRDSCluster:
+		if len(ids) != 1 {
+			return nil, fmt.Errorf("expected one db_cluster_id, got %d", len(ids))
+		}
+		out, err := e.rdsClient.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{
+			DBClusterIdentifier: aws.String(ids[0]),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("describe RDS cluster %s: %w", ids[0], err)
+		}
+		if len(out.DBClusters) == 0 {
+			return nil, fmt.Errorf("RDS cluster not found: %s", ids[0])
+		}
+		cluster := out.DBClusters[0]
+		writer := ""
+		for _, m := range cluster.DBClusterMembers {
+			if aws.ToBool(m.IsClusterWriter) {
+				writer = aws.ToString(m.DBInstanceIdentifier)
+			}
+		}
+		return map[string]any{"status": aws.ToString(cluster.Status), "writer": writer}, nil
+
+	case AWSResourceRDSInstance:
+		if len(ids) != 1 {
+			return nil, fmt.Errorf("expected one db_instance_id, got %d", len(ids))
+		}
+		status, err := e.describeDBInstance(ctx, ids[0])
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"status": status}, nil
+	}
+	return nil, fmt.Errorf("unsupported AWS resource type %q", resourceType)
+}
+
 // StopEC2 stops EC2 instances
 func (e *AwsEngine) StopEC2(ctx context.Context, instanceIDs []string, dryRun bool) (*domain.ChaosResult, error) {
 	if err := e.checkEmergencyStop(); err != nil {
@@ -47,9 +167,15 @@ func (e *AwsEngine) StopEC2(ctx context.Context, instanceIDs []string, dryRun bo
 	}
 
 	if dryRun {
+		found, err := e.describeInstanceIDs(ctx, instanceIDs)
 		return &domain.ChaosResult{
-			Result: map[string]any{"action": "stop_ec2", "instance_ids": instanceIDs, "dry_run": true},
-		}, nil
+			Result: map[string]any{
+				"action":       "stop_ec2",
+				"instance_ids": instanceIDs,
+				"dry_run":      true,
+				"would_affect": map[string]any{"count": len(found), "names": found},
+			},
+		}, err
 	}
 
 	_, err := e.ec2Client.StopInstances(ctx, &ec2.StopInstancesInput{
@@ -60,9 +186,17 @@ func (e *AwsEngine) StopEC2(ctx context.Context, instanceIDs []string, dryRun bo
 	}
 	log.Printf("Stopped EC2 instances: %v", instanceIDs)
 
-	rollback := func() (map[string]any, error) {
-		rbCtx := context.Background()
-		_, err := e.ec2Client.StartInstances(rbCtx, &ec2.StartInstancesInput{
+	return &domain.ChaosResult{
+		Result:     map[string]any{"action": "stop_ec2", "instance_ids": instanceIDs},
+		RollbackFn: e.startInstancesRollback(instanceIDs),
+		Rollback:   newRollbackAction(domain.ChaosTypeEC2Stop, ec2StopRollback{InstanceIDs: instanceIDs}),
+	}, nil
+}
+
+// startInstancesRollback restarts instances stopped by StopEC2
+func (e *AwsEngine) startInstancesRollback(instanceIDs []string) domain.RollbackFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		_, err := e.ec2Client.StartInstances(ctx, &ec2.StartInstancesInput{
 			InstanceIds: instanceIDs,
 		})
 		if err != nil {
@@ -71,11 +205,42 @@ func (e *AwsEngine) StopEC2(ctx context.Context, instanceIDs []string, dryRun bo
 		log.Printf("Rollback: started EC2 instances: %v", instanceIDs)
 		return map[string]any{"started": instanceIDs}, nil
 	}
+}
 
-	return &domain.ChaosResult{
-		Result:     map[string]any{"action": "stop_ec2", "instance_ids": instanceIDs},
-		RollbackFn: rollback,
-	}, nil
+// describeInstanceIDs returns the subset of instanceIDs that exist, erroring
+// if any of them cannot be found
+func (e *AwsEngine) describeInstanceIDs(ctx context.Context, instanceIDs []string) ([]string, error) {
+	// An empty filter would describe every instance in the account
+	if len(instanceIDs) == 0 {
+		return []string{}, fmt.Errorf("no instance_ids specified")
+	}
+
+	out, err := e.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: instanceIDs,
+	})
+	if err != nil {
+		return []string{}, fmt.Errorf("describe EC2 instances: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, res := range out.Reservations {
+		for _, inst := range res.Instances {
+			seen[aws.ToString(inst.InstanceId)] = true
+		}
+	}
+	found := make([]string, 0, len(instanceIDs))
+	var missing []string
+	for _, id := range instanceIDs {
+		if seen[id] {
+			found = append(found, id)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return found, fmt.Errorf("EC2 instances not found: %v", missing)
+	}
+	return found, nil
 }
 
 // FailoverRDS forces an RDS cluster failover
@@ -99,7 +264,7 @@ func (e *AwsEngine) FailoverRDS(ctx context.Context, dbClusterID string, dryRun
 	log.Printf("Triggered RDS failover: %s", dbClusterID)
 
 	// RDS failover is self-healing
-	rollback := func() (map[string]any, error) {
+	rollback := func(context.Context) (map[string]any, error) {
 		log.Printf("RDS failover rollback: cluster will self-heal")
 		return map[string]any{"note": "RDS failover is self-healing"}, nil
 	}
@@ -110,6 +275,75 @@ func (e *AwsEngine) FailoverRDS(ctx context.Context, dbClusterID string, dryRun
 	}, nil
 }
 
+// RebootRDSInstance reboots a single RDS DB instance, optionally forcing a
+// Multi-AZ failover. The instance is looked up first so a typo fails before
+// anything is touched. Rollback is a no-op: the instance comes back on its own.
+func (e *AwsEngine) RebootRDSInstance(ctx context.Context, dbInstanceID string, forceFailover, dryRun bool) (*domain.ChaosResult, error) {
+	if err := e.checkEmergencyStop(); err != nil {
+		return nil, err
+	}
+
+	status, err := e.describeDBInstance(ctx, dbInstanceID)
+	if dryRun {
+		found := []string{}
+		if err == nil {
+			found = append(found, dbInstanceID)
+		}
+		return &domain.ChaosResult{
+			Result: map[string]any{
+				"action":         "rds_reboot",
+				"db_instance_id": dbInstanceID,
+				"force_failover": forceFailover,
+				"dry_run":        true,
+				"would_affect":   map[string]any{"count": len(found), "names": found},
+			},
+		}, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = e.rdsClient.RebootDBInstance(ctx, &rds.RebootDBInstanceInput{
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+		ForceFailover:        aws.Bool(forceFailover),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reboot RDS instance: %w", err)
+	}
+	log.Printf("Rebooted RDS instance: %s (force_failover=%v)", dbInstanceID, forceFailover)
+
+	// A reboot cannot be undone; the instance returns to available by itself
+	rollback := func(context.Context) (map[string]any, error) {
+		log.Printf("RDS reboot rollback: instance %s recovers on its own", dbInstanceID)
+		return map[string]any{"note": "RDS reboot is self-healing"}, nil
+	}
+
+	return &domain.ChaosResult{
+		Result: map[string]any{
+			"action":          "rds_reboot",
+			"db_instance_id":  dbInstanceID,
+			"force_failover":  forceFailover,
+			"previous_status": status,
+		},
+		RollbackFn: rollback,
+	}, nil
+}
+
+// describeDBInstance returns the status of an RDS DB instance, erroring if
+// it does not exist
+func (e *AwsEngine) describeDBInstance(ctx context.Context, dbInstanceID string) (string, error) {
+	out, err := e.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("describe RDS instance %s: %w", dbInstanceID, err)
+	}
+	if len(out.DBInstances) == 0 {
+		return "", fmt.Errorf("RDS instance not found: %s", dbInstanceID)
+	}
+	return aws.ToString(out.DBInstances[0].DBInstanceStatus), nil
+}
+
 // BlackholeRoute creates a blackhole route in a VPC route table
 func (e *AwsEngine) BlackholeRoute(ctx context.Context, routeTableID, destCIDR string, dryRun bool) (*domain.ChaosResult, error) {
 	if err := e.checkEmergencyStop(); err != nil {
@@ -157,9 +391,22 @@ func (e *AwsEngine) BlackholeRoute(ctx context.Context, routeTableID, destCIDR s
 	}
 	log.Printf("Created blackhole route: %s -> %s", routeTableID, destCIDR)
 
-	rollback := func() (map[string]any, error) {
-		rbCtx := context.Background()
-		_, err := e.ec2Client.DeleteRoute(rbCtx, &ec2.DeleteRouteInput{
+	return &domain.ChaosResult{
+		Result:     map[string]any{"action": "route_blackhole", "route_table_id": routeTableID, "destination_cidr": destCIDR},
+		RollbackFn: e.restoreRouteRollback(routeTableID, destCIDR, originalGateway),
+		Rollback: newRollbackAction(domain.ChaosTypeRouteBlackhole, routeBlackholeRollback{
+			RouteTableID:      routeTableID,
+			DestinationCIDR:   destCIDR,
+			OriginalGatewayID: originalGateway,
+		}),
+	}, nil
+}
+
+// restoreRouteRollback removes the blackhole route and restores the original
+// gateway route, if there was one
+func (e *AwsEngine) restoreRouteRollback(routeTableID, destCIDR string, originalGateway *string) domain.RollbackFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		_, err := e.ec2Client.DeleteRoute(ctx, &ec2.DeleteRouteInput{
 			RouteTableId:         aws.String(routeTableID),
 			DestinationCidrBlock: aws.String(destCIDR),
 		})
@@ -167,7 +414,7 @@ func (e *AwsEngine) BlackholeRoute(ctx context.Context, routeTableID, destCIDR s
 			return nil, fmt.Errorf("delete route: %w", err)
 		}
 		if originalGateway != nil {
-			_, err := e.ec2Client.CreateRoute(rbCtx, &ec2.CreateRouteInput{
+			_, err := e.ec2Client.CreateRoute(ctx, &ec2.CreateRouteInput{
 				RouteTableId:         aws.String(routeTableID),
 				DestinationCidrBlock: aws.String(destCIDR),
 				GatewayId:            originalGateway,
@@ -179,76 +426,275 @@ func (e *AwsEngine) BlackholeRoute(ctx context.Context, routeTableID, destCIDR s
 		log.Printf("Rollback: restored route %s", destCIDR)
 		return map[string]any{"restored": destCIDR}, nil
 	}
+}
+
+// IsolateSubnet cuts a subnet off by moving its network ACL association to
+// a deny-all ACL. Replacing an association gives it a new ID, so the
+// rollback moves that new association back to the original ACL.
+func (e *AwsEngine) IsolateSubnet(ctx context.Context, subnetID, denyACLID string, dryRun bool) (*domain.ChaosResult, error) {
+	if err := e.checkEmergencyStop(); err != nil {
+		return nil, err
+	}
+
+	assocID, originalACLID, err := e.subnetACLAssociation(ctx, subnetID)
+	if dryRun {
+		found := []string{}
+		if err == nil {
+			found = append(found, subnetID)
+		}
+		return &domain.ChaosResult{
+			Result: map[string]any{
+				"action":       "subnet_isolate",
+				"subnet_id":    subnetID,
+				"deny_acl_id":  denyACLID,
+				"dry_run":      true,
+				"would_affect": map[string]any{"count": len(found), "names": found},
+			},
+		}, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	if originalACLID == denyACLID {
+		return nil, fmt.Errorf("%w: subnet %s is already associated with %s", domain.ErrInvalidConfig, subnetID, denyACLID)
+	}
+
+	out, err := e.ec2Client.ReplaceNetworkAclAssociation(ctx, &ec2.ReplaceNetworkAclAssociationInput{
+		AssociationId: aws.String(assocID),
+		NetworkAclId:  aws.String(denyACLID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("replace network ACL association: %w", err)
+	}
+	newAssocID := aws.ToString(out.NewAssociationId)
+	log.Printf("Isolated subnet %s: %s -> %s", subnetID, originalACLID, denyACLID)
 
 	return &domain.ChaosResult{
-		Result:     map[string]any{"action": "route_blackhole", "route_table_id": routeTableID, "destination_cidr": destCIDR},
-		RollbackFn: rollback,
+		Result: map[string]any{
+			"action":          "subnet_isolate",
+			"subnet_id":       subnetID,
+			"deny_acl_id":     denyACLID,
+			"original_acl_id": originalACLID,
+		},
+		RollbackFn: e.restoreACLRollback(subnetID, newAssocID, originalACLID),
+		Rollback: newRollbackAction(domain.ChaosTypeSubnetIsolate, subnetIsolateRollback{
+			SubnetID:      subnetID,
+			AssociationID: newAssocID,
+			OriginalACLID: originalACLID,
+		}),
 	}, nil
 }
 
+// subnetACLAssociation returns the ID of the subnet's network ACL
+// association and the ACL it points at. Every subnet has exactly one.
+func (e *AwsEngine) subnetACLAssociation(ctx context.Context, subnetID string) (string, string, error) {
+	out, err := e.ec2Client.DescribeNetworkAcls(ctx, &ec2.DescribeNetworkAclsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("association.subnet-id"), Values: []string{subnetID}}},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("describe network ACLs for %s: %w", subnetID, err)
+	}
+	for _, acl := range out.NetworkAcls {
+		for _, assoc := range acl.Associations {
+			if aws.ToString(assoc.SubnetId) == subnetID {
+				return aws.ToString(assoc.NetworkAclAssociationId), aws.ToString(acl.NetworkAclId), nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("no network ACL association found for subnet %s", subnetID)
+}
+
+// restoreACLRollback moves the subnet's association back to its original ACL
+func (e *AwsEngine) restoreACLRollback(subnetID, assocID, originalACLID string) domain.RollbackFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		_, err := e.ec2Client.ReplaceNetworkAclAssociation(ctx, &ec2.ReplaceNetworkAclAssociationInput{
+			AssociationId: aws.String(assocID),
+			NetworkAclId:  aws.String(originalACLID),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("restore network ACL association: %w", err)
+		}
+		log.Printf("Rollback: subnet %s re-associated with %s", subnetID, originalACLID)
+		return map[string]any{"restored": subnetID, "network_acl_id": originalACLID}, nil
+	}
+}
+
+// ThrottleLambda sets a function's reserved concurrency to 0 so every
+// invocation is throttled. Rollback restores the reserved concurrency the
+// function had before, or removes the limit if it had none.
+func (e *AwsEngine) ThrottleLambda(ctx context.Context, functionName string, dryRun bool) (*domain.ChaosResult, error) {
+	if err := e.checkEmergencyStop(); err != nil {
+		return nil, err
+	}
+
+	// Read the current limit first; this also fails fast on an unknown function
+	out, err := e.lambdaClient.GetFunctionConcurrency(ctx, &lambda.GetFunctionConcurrencyInput{
+		FunctionName: aws.String(functionName),
+	})
+	if err != nil {
+		err = fmt.Errorf("get Lambda concurrency for %s: %w", functionName, err)
+	}
+	var prior *int32
+	if out != nil {
+		prior = out.ReservedConcurrentExecutions
+	}
+
+	if dryRun {
+		found := []string{}
+		if err == nil {
+			found = append(found, functionName)
+		}
+		return &domain.ChaosResult{
+			Result: map[string]any{
+				"action":        "lambda_throttle",
+				"function_name": functionName,
+				"dry_run":       true,
+				"would_affect":  map[string]any{"count": len(found), "names": found},
+			},
+		}, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = e.lambdaClient.PutFunctionConcurrency(ctx, &lambda.PutFunctionConcurrencyInput{
+		FunctionName:                 aws.String(functionName),
+		ReservedConcurrentExecutions: aws.Int32(0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("throttle Lambda function: %w", err)
+	}
+	log.Printf("Throttled Lambda function: %s", functionName)
+
+	result := map[string]any{"action": "lambda_throttle", "function_name": functionName}
+	if prior != nil {
+		result["previous_reserved_concurrency"] = *prior
+	}
+	return &domain.ChaosResult{
+		Result:     result,
+		RollbackFn: e.restoreConcurrencyRollback(functionName, prior),
+		Rollback: newRollbackAction(domain.ChaosTypeLambdaThrottle, lambdaThrottleRollback{
+			FunctionName:        functionName,
+			ReservedConcurrency: prior,
+		}),
+	}, nil
+}
+
+// restoreConcurrencyRollback puts back the reserved concurrency a throttled
+// function had, or deletes the limit when there was none
+func (e *AwsEngine) restoreConcurrencyRollback(functionName string, prior *int32) domain.RollbackFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		if prior == nil {
+			_, err := e.lambdaClient.DeleteFunctionConcurrency(ctx, &lambda.DeleteFunctionConcurrencyInput{
+				FunctionName: aws.String(functionName),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("delete Lambda concurrency: %w", err)
+			}
+			log.Printf("Rollback: removed reserved concurrency from %s", functionName)
+			return map[string]any{"restored": functionName, "reserved_concurrency": nil}, nil
+		}
+
+		_, err := e.lambdaClient.PutFunctionConcurrency(ctx, &lambda.PutFunctionConcurrencyInput{
+			FunctionName:                 aws.String(functionName),
+			ReservedConcurrentExecutions: prior,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("restore Lambda concurrency: %w", err)
+		}
+		log.Printf("Rollback: restored reserved concurrency %d on %s", *prior, functionName)
+		return map[string]any{"restored": functionName, "reserved_concurrency": *prior}, nil
+	}
+}
+
 // GetTopology discovers AWS resource topology
 func (e *AwsEngine) GetTopology(ctx context.Context) (*domain.InfraTopology, error) {
+	ctx, cancel := context.WithTimeout(ctx, awsTopologyTimeout)
+	defer cancel()
+
+	scannedAt := time.Now()
 	nodes := make([]domain.TopologyNode, 0)
 	edges := make([]domain.TopologyEdge, 0)
 
-	// EC2 instances
-	reservations, err := e.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{})
-	if err != nil {
-		return nil, fmt.Errorf("describe EC2 instances: %w", err)
+	// VPCs and subnets; without them instances hang off their VPC ID alone
+	subnets := map[string]bool{}
+	if netNodes, netEdges, err := e.networkTopology(ctx); err != nil {
+		log.Printf("VPC/subnet describe failed (non-fatal): %v", err)
+	} else {
+		nodes = append(nodes, netNodes...)
+		edges = append(edges, netEdges...)
+		for _, n := range netNodes {
+			if n.ResourceType == domain.ResourceSubnet {
+				subnets[n.ID] = true
+			}
+		}
 	}
 
-	for _, res := range reservations.Reservations {
-		for _, inst := range res.Instances {
-			instID := aws.ToString(inst.InstanceId)
-			tags := make(map[string]string)
-			instName := instID
-			for _, t := range inst.Tags {
-				tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
-				if aws.ToString(t.Key) == "Name" {
-					instName = aws.ToString(t.Value)
-				}
-			}
+	// EC2 instances, every page of them
+	instancePages := ec2.NewDescribeInstancesPaginator(e.ec2Client, &ec2.DescribeInstancesInput{})
+	for instancePages.HasMorePages() {
+		page, err := instancePages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("describe EC2 instances: %w", err)
+		}
 
-			health := domain.HealthUnknown
-			stateName := ""
-			if inst.State != nil {
-				stateName = string(inst.State.Name)
-				switch inst.State.Name {
-				case ec2types.InstanceStateNameRunning:
-					health = domain.HealthHealthy
-				case ec2types.InstanceStateNameStopped:
-					health = domain.HealthUnhealthy
-				}
-			}
+		for _, res := range page.Reservations {
+			for _, inst := range res.Instances {
+				instID := aws.ToString(inst.InstanceId)
+				instName, tags := ec2NameAndTags(instID, inst.Tags)
 
-			nodes = append(nodes, domain.TopologyNode{
-				ID:           instID,
-				Name:         instName,
-				ResourceType: domain.ResourceEC2,
-				Labels:       tags,
-				Health:       health,
-				Metadata: map[string]any{
-					"state": stateName,
-					"type":  string(inst.InstanceType),
-				},
-			})
+				health := domain.HealthUnknown
+				stateName := ""
+				if inst.State != nil {
+					stateName = string(inst.State.Name)
+					switch inst.State.Name {
+					case ec2types.InstanceStateNameRunning:
+						health = domain.HealthHealthy
+					case ec2types.InstanceStateNameStopped:
+						health = domain.HealthUnhealthy
+					}
+				}
 
-			if inst.VpcId != nil {
-				edges = append(edges, domain.TopologyEdge{
-					Source:   aws.ToString(inst.VpcId),
-					Target:   instID,
-					Relation: "contains",
+				nodes = append(nodes, domain.TopologyNode{
+					ID:           instID,
+					Name:         instName,
+					ResourceType: domain.ResourceEC2,
+					Labels:       tags,
+					Health:       health,
+					Metadata: map[string]any{
+						"state":            stateName,
+						"type":             string(inst.InstanceType),
+						"private_dns_name": aws.ToString(inst.PrivateDnsName),
+					},
 				})
+
+				switch {
+				case subnets[aws.ToString(inst.SubnetId)]:
+					edges = append(edges, domain.TopologyEdge{
+						Source:   aws.ToString(inst.SubnetId),
+						Target:   instID,
+						Relation: "contains",
+					})
+				case inst.VpcId != nil:
+					edges = append(edges, domain.TopologyEdge{
+						Source:   aws.ToString(inst.VpcId),
+						Target:   instID,
+						Relation: "contains",
+					})
+				}
 			}
 		}
 	}
 
-	// RDS clusters
-	clusters, err := e.rdsClient.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{})
-	if err != nil {
-		log.Printf("RDS describe failed (non-fatal): %v", err)
-	} else {
-		for _, cluster := range clusters.DBClusters {
+	// RDS clusters; a failing page keeps the clusters listed so far
+	clusterPages := rds.NewDescribeDBClustersPaginator(e.rdsClient, &rds.DescribeDBClustersInput{})
+	for clusterPages.HasMorePages() {
+		page, err := clusterPages.NextPage(ctx)
+		if err != nil {
+			log.Printf("RDS describe failed (non-fatal): %v", err)
+			break
+		}
+		for _, cluster := range page.DBClusters {
 			clusterID := aws.ToString(cluster.DBClusterIdentifier)
 			health := domain.HealthDegraded
 			if aws.ToString(cluster.Status) == "available" {
@@ -267,5 +713,88 @@ func (e *AwsEngine) GetTopology(ctx context.Context) (*domain.InfraTopology, err
 		}
 	}
 
-	return &domain.InfraTopology{Nodes: nodes, Edges: edges}, nil
+	return &domain.InfraTopology{Nodes: nodes, Edges: edges, Timestamp: domain.TopologyTimestamp(scannedAt)}, nil
+}
+
+// networkTopology returns a node per VPC and subnet, with a "contains" edge
+// from each VPC to its subnets. Both listings are paginated.
+func (e *AwsEngine) networkTopology(ctx context.Context) ([]domain.TopologyNode, []domain.TopologyEdge, error) {
+	var nodes []domain.TopologyNode
+	var edges []domain.TopologyEdge
+
+	vpcPages := ec2.NewDescribeVpcsPaginator(e.ec2Client, &ec2.DescribeVpcsInput{})
+	for vpcPages.HasMorePages() {
+		page, err := vpcPages.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("describe VPCs: %w", err)
+		}
+		for _, vpc := range page.Vpcs {
+			vpcID := aws.ToString(vpc.VpcId)
+			name, tags := ec2NameAndTags(vpcID, vpc.Tags)
+			health := domain.HealthDegraded
+			if vpc.State == ec2types.VpcStateAvailable {
+				health = domain.HealthHealthy
+			}
+			nodes = append(nodes, domain.TopologyNode{
+				ID:           vpcID,
+				Name:         name,
+				ResourceType: domain.ResourceVPC,
+				Labels:       tags,
+				Health:       health,
+				Metadata: map[string]any{
+					"cidr_block": aws.ToString(vpc.CidrBlock),
+					"is_default": aws.ToBool(vpc.IsDefault),
+					"state":      string(vpc.State),
+				},
+			})
+		}
+	}
+
+	subnetPages := ec2.NewDescribeSubnetsPaginator(e.ec2Client, &ec2.DescribeSubnetsInput{})
+	for subnetPages.HasMorePages() {
+		page, err := subnetPages.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("describe subnets: %w", err)
+		}
+		for _, subnet := range page.Subnets {
+			subnetID := aws.ToString(subnet.SubnetId)
+			name, tags := ec2NameAndTags(subnetID, subnet.Tags)
+			health := domain.HealthDegraded
+			if subnet.State == ec2types.SubnetStateAvailable {
+				health = domain.HealthHealthy
+			}
+			nodes = append(nodes, domain.TopologyNode{
+				ID:           subnetID,
+				Name:         name,
+				ResourceType: domain.ResourceSubnet,
+				Labels:       tags,
+				Health:       health,
+				Metadata: map[string]any{
+					"cidr_block":          aws.ToString(subnet.CidrBlock),
+					"availability_zone":   aws.ToString(subnet.AvailabilityZone),
+					"available_addresses": aws.ToInt32(subnet.AvailableIpAddressCount),
+				},
+			})
+			edges = append(edges, domain.TopologyEdge{
+				Source:   aws.ToString(subnet.VpcId),
+				Target:   subnetID,
+				Relation: "contains",
+			})
+		}
+	}
+
+	return nodes, edges, nil
+}
+
+// ec2NameAndTags flattens EC2 tags, using the Name tag (or id) as the name
+func ec2NameAndTags(id string, ec2Tags []ec2types.Tag) (string, map[string]string) {
+	name := id
+	tags := make(map[string]string, len(ec2Tags))
+	for _, t := range ec2Tags {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+		if aws.ToString(t.Key) == "Name" {
+			name = aws.ToString(t.Value)
+		}
+	}
+	return name, tags
 }
@@ -0,0 +1,616 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeRDS serves the RDS query API for a single DB instance and records the
+// actions it was called with
+type fakeRDS struct {
+	instanceID string
+
+	mu      sync.Mutex
+	actions []string
+}
+
+func (f *fakeRDS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	action := r.Form.Get("Action")
+	f.mu.Lock()
+	f.actions = append(f.actions, action)
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/xml")
+	if r.Form.Get("DBInstanceIdentifier") != f.instanceID {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>DBInstanceNotFound</Code>` +
+			`<Message>DBInstance not found</Message></Error></ErrorResponse>`))
+		return
+	}
+	instance := `<DBInstance><DBInstanceIdentifier>` + f.instanceID + `</DBInstanceIdentifier>` +
+		`<DBInstanceStatus>available</DBInstanceStatus></DBInstance>`
+	switch action {
+	case "DescribeDBInstances":
+		_, _ = w.Write([]byte(`<DescribeDBInstancesResponse><DescribeDBInstancesResult><DBInstances>` +
+			instance + `</DBInstances></DescribeDBInstancesResult></DescribeDBInstancesResponse>`))
+	case "RebootDBInstance":
+		_, _ = w.Write([]byte(`<RebootDBInstanceResponse><RebootDBInstanceResult>` +
+			instance + `</RebootDBInstanceResult></RebootDBInstanceResponse>`))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (f *fakeRDS) calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.actions...)
+}
+
+func newTestAwsEngine(t *testing.T, fake *fakeRDS) *AwsEngine {
+	t.Helper()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	return &AwsEngine{
+		rdsClient: rds.New(rds.Options{
+			Region:       "us-east-1",
+			BaseEndpoint: aws.String(srv.URL),
+			Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+		}),
+		esm: safety.NewEmergencyStopManager(),
+	}
+}
+
+func rdsRebootConfig(params map[string]any) *domain.ExperimentConfig {
+	return &domain.ExperimentConfig{
+		Name:       "reboot",
+		ChaosType:  domain.ChaosTypeRDSReboot,
+		Parameters: params,
+		Safety:     domain.DefaultSafetyConfig(),
+	}
+}
+
+func TestExecuteChaosRDSReboot(t *testing.T) {
+	fake := &fakeRDS{instanceID: "orders-db"}
+	runner := NewRunner(nil, newTestAwsEngine(t, fake), safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(), safety.NewSnapshotManager(nil), nil, nil, "")
+
+	res, err := runner.executeChaos(context.Background(), rdsRebootConfig(map[string]any{
+		"db_instance_id": "orders-db",
+		"force_failover": true,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "rds_reboot", res.Result["action"])
+	assert.Equal(t, true, res.Result["force_failover"])
+	assert.Equal(t, "available", res.Result["previous_status"])
+	assert.Equal(t, []string{"DescribeDBInstances", "RebootDBInstance"}, fake.calls())
+
+	rb, err := res.RollbackFn(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, rb, "note")
+}
+
+func TestExecuteChaosRDSRebootParams(t *testing.T) {
+	fake := &fakeRDS{instanceID: "orders-db"}
+	runner := NewRunner(nil, newTestAwsEngine(t, fake), safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(), safety.NewSnapshotManager(nil), nil, nil, "")
+
+	_, err := runner.executeChaos(context.Background(), rdsRebootConfig(nil))
+	assert.ErrorIs(t, err, domain.ErrInvalidConfig)
+
+	_, err = runner.executeChaos(context.Background(), rdsRebootConfig(map[string]any{
+		"db_instance_id": "orders-db",
+		"force_failover": "yes",
+	}))
+	assert.ErrorIs(t, err, domain.ErrInvalidConfig)
+	assert.Empty(t, fake.calls())
+}
+
+func TestRebootRDSInstanceNotFound(t *testing.T) {
+	fake := &fakeRDS{instanceID: "orders-db"}
+	e := newTestAwsEngine(t, fake)
+
+	_, err := e.RebootRDSInstance(context.Background(), "typo-db", false, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "typo-db")
+	assert.Equal(t, []string{"DescribeDBInstances"}, fake.calls())
+
+	preview, err := e.RebootRDSInstance(context.Background(), "typo-db", false, true)
+	require.Error(t, err)
+	assert.Equal(t, true, preview.Result["dry_run"])
+	assert.Equal(t, 0, preview.Result["would_affect"].(map[string]any)["count"])
+}
+
+// fakeTopologyEC2 serves the EC2 describe calls GetTopology makes, handing
+// out VPCs and instances one per page to exercise pagination
+type fakeTopologyEC2 struct {
+	ec2API
+	vpcs          []ec2types.Vpc
+	subnets       []ec2types.Subnet
+	instances     []ec2types.Instance
+	vpcCalls      int
+	instanceCalls int
+}
+
+func (f *fakeTopologyEC2) DescribeVpcs(_ context.Context, in *ec2.DescribeVpcsInput, _ ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	f.vpcCalls++
+	if len(f.vpcs) == 0 {
+		return &ec2.DescribeVpcsOutput{}, nil
+	}
+	i := 0
+	if in.NextToken != nil {
+		i, _ = strconv.Atoi(*in.NextToken)
+	}
+	out := &ec2.DescribeVpcsOutput{Vpcs: f.vpcs[i : i+1]}
+	if i+1 < len(f.vpcs) {
+		out.NextToken = aws.String(strconv.Itoa(i + 1))
+	}
+	return out, nil
+}
+
+func (f *fakeTopologyEC2) DescribeSubnets(context.Context, *ec2.DescribeSubnetsInput, ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return &ec2.DescribeSubnetsOutput{Subnets: f.subnets}, nil
+}
+
+func (f *fakeTopologyEC2) DescribeInstances(_ context.Context, in *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.instanceCalls++
+	out := &ec2.DescribeInstancesOutput{}
+	if len(f.instances) == 0 {
+		return out, nil
+	}
+	i := 0
+	if in.NextToken != nil {
+		i, _ = strconv.Atoi(*in.NextToken)
+	}
+	out.Reservations = []ec2types.Reservation{{Instances: f.instances[i : i+1]}}
+	if i+1 < len(f.instances) {
+		out.NextToken = aws.String(strconv.Itoa(i + 1))
+	}
+	return out, nil
+}
+
+// fakeTopologyRDS hands out DB clusters one per page, failing the page at
+// failAt when set
+type fakeTopologyRDS struct {
+	rdsAPI
+	clusters []rdstypes.DBCluster
+	failAt   int
+	calls    int
+}
+
+func (f *fakeTopologyRDS) DescribeDBClusters(_ context.Context, in *rds.DescribeDBClustersInput, _ ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	f.calls++
+	i := 0
+	if in.Marker != nil {
+		i, _ = strconv.Atoi(*in.Marker)
+	}
+	if f.failAt > 0 && i == f.failAt {
+		return nil, errors.New("throttled")
+	}
+	out := &rds.DescribeDBClustersOutput{DBClusters: f.clusters[i : i+1]}
+	if i+1 < len(f.clusters) {
+		out.Marker = aws.String(strconv.Itoa(i + 1))
+	}
+	return out, nil
+}
+
+func TestGetTopologyConnectsVPCSubnetAndInstance(t *testing.T) {
+	fake := &fakeTopologyEC2{
+		vpcs: []ec2types.Vpc{
+			{VpcId: aws.String("vpc-1"), State: ec2types.VpcStateAvailable, CidrBlock: aws.String("10.0.0.0/16"),
+				Tags: []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String("main")}}},
+			{VpcId: aws.String("vpc-2"), State: ec2types.VpcStateAvailable},
+		},
+		subnets: []ec2types.Subnet{
+			{SubnetId: aws.String("subnet-a"), VpcId: aws.String("vpc-1"), State: ec2types.SubnetStateAvailable,
+				AvailabilityZone: aws.String("us-east-1a")},
+		},
+		instances: []ec2types.Instance{
+			{InstanceId: aws.String("i-1"), VpcId: aws.String("vpc-1"), SubnetId: aws.String("subnet-a")},
+			{InstanceId: aws.String("i-2"), VpcId: aws.String("vpc-2"), SubnetId: aws.String("subnet-unknown")},
+		},
+	}
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.ec2Client = fake
+
+	topo, err := e.GetTopology(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.vpcCalls)
+
+	types := map[string]domain.ResourceType{}
+	for _, n := range topo.Nodes {
+		types[n.ID] = n.ResourceType
+	}
+	assert.Equal(t, map[string]domain.ResourceType{
+		"vpc-1": domain.ResourceVPC, "vpc-2": domain.ResourceVPC, "subnet-a": domain.ResourceSubnet,
+		"i-1": domain.ResourceEC2, "i-2": domain.ResourceEC2,
+	}, types)
+
+	assert.ElementsMatch(t, []domain.TopologyEdge{
+		{Source: "vpc-1", Target: "subnet-a", Relation: "contains"},
+		{Source: "subnet-a", Target: "i-1", Relation: "contains"},
+		// The subnet was not listed, so the instance hangs off its VPC
+		{Source: "vpc-2", Target: "i-2", Relation: "contains"},
+	}, topo.Edges)
+
+	// Every edge endpoint is a node
+	for _, edge := range topo.Edges {
+		assert.Contains(t, types, edge.Source)
+		assert.Contains(t, types, edge.Target)
+	}
+}
+
+func TestGetTopologyReadsEveryPage(t *testing.T) {
+	ec2Fake := &fakeTopologyEC2{
+		instances: []ec2types.Instance{
+			{InstanceId: aws.String("i-1")},
+			{InstanceId: aws.String("i-2")},
+		},
+	}
+	rdsFake := &fakeTopologyRDS{
+		clusters: []rdstypes.DBCluster{
+			{DBClusterIdentifier: aws.String("orders"), Status: aws.String("available")},
+			{DBClusterIdentifier: aws.String("billing"), Status: aws.String("failing-over")},
+		},
+	}
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.ec2Client = ec2Fake
+	e.rdsClient = rdsFake
+
+	topo, err := e.GetTopology(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, ec2Fake.instanceCalls)
+	assert.Equal(t, 2, rdsFake.calls)
+
+	health := map[string]domain.HealthStatus{}
+	for _, n := range topo.Nodes {
+		health[n.ID] = n.Health
+	}
+	assert.Contains(t, health, "i-1")
+	assert.Contains(t, health, "i-2")
+	assert.Equal(t, domain.HealthHealthy, health["orders"])
+	assert.Equal(t, domain.HealthDegraded, health["billing"])
+}
+
+func TestGetTopologyKeepsClustersBeforeFailedPage(t *testing.T) {
+	rdsFake := &fakeTopologyRDS{
+		clusters: []rdstypes.DBCluster{
+			{DBClusterIdentifier: aws.String("orders"), Status: aws.String("available")},
+			{DBClusterIdentifier: aws.String("billing"), Status: aws.String("available")},
+		},
+		failAt: 1,
+	}
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.ec2Client = &fakeTopologyEC2{}
+	e.rdsClient = rdsFake
+
+	topo, err := e.GetTopology(context.Background())
+	require.NoError(t, err)
+
+	var ids []string
+	for _, n := range topo.Nodes {
+		ids = append(ids, n.ID)
+	}
+	assert.Equal(t, []string{"orders"}, ids)
+}
+
+// fakeLambda tracks one function's reserved concurrency and records the
+// concurrency calls made against it
+type fakeLambda struct {
+	reserved *int32
+	calls    []string
+}
+
+func (f *fakeLambda) GetFunctionConcurrency(context.Context, *lambda.GetFunctionConcurrencyInput, ...func(*lambda.Options)) (*lambda.GetFunctionConcurrencyOutput, error) {
+	f.calls = append(f.calls, "GetFunctionConcurrency")
+	return &lambda.GetFunctionConcurrencyOutput{ReservedConcurrentExecutions: f.reserved}, nil
+}
+
+func (f *fakeLambda) PutFunctionConcurrency(_ context.Context, in *lambda.PutFunctionConcurrencyInput, _ ...func(*lambda.Options)) (*lambda.PutFunctionConcurrencyOutput, error) {
+	f.calls = append(f.calls, "PutFunctionConcurrency")
+	f.reserved = aws.Int32(aws.ToInt32(in.ReservedConcurrentExecutions))
+	return &lambda.PutFunctionConcurrencyOutput{ReservedConcurrentExecutions: f.reserved}, nil
+}
+
+func (f *fakeLambda) DeleteFunctionConcurrency(context.Context, *lambda.DeleteFunctionConcurrencyInput, ...func(*lambda.Options)) (*lambda.DeleteFunctionConcurrencyOutput, error) {
+	f.calls = append(f.calls, "DeleteFunctionConcurrency")
+	f.reserved = nil
+	return &lambda.DeleteFunctionConcurrencyOutput{}, nil
+}
+
+func TestThrottleLambdaRestoresPriorLimit(t *testing.T) {
+	fake := &fakeLambda{reserved: aws.Int32(25)}
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.lambdaClient = fake
+
+	res, err := e.ThrottleLambda(context.Background(), "checkout", false)
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), *fake.reserved)
+	assert.Equal(t, int32(25), res.Result["previous_reserved_concurrency"])
+
+	rb, err := res.RollbackFn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(25), rb["reserved_concurrency"])
+	require.NotNil(t, fake.reserved)
+	assert.Equal(t, int32(25), *fake.reserved)
+	assert.Equal(t, []string{"GetFunctionConcurrency", "PutFunctionConcurrency", "PutFunctionConcurrency"}, fake.calls)
+}
+
+func TestThrottleLambdaRemovesLimitWhenNoneBefore(t *testing.T) {
+	fake := &fakeLambda{}
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.lambdaClient = fake
+
+	res, err := e.ThrottleLambda(context.Background(), "checkout", false)
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), *fake.reserved)
+	assert.NotContains(t, res.Result, "previous_reserved_concurrency")
+
+	// The persisted rollback must also remove the limit after a restart
+	fn, err := ReconstructRollback(*res.Rollback, nil, e)
+	require.NoError(t, err)
+	_, err = fn(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, fake.reserved)
+	assert.Equal(t, []string{"GetFunctionConcurrency", "PutFunctionConcurrency", "DeleteFunctionConcurrency"}, fake.calls)
+}
+
+func TestThrottleLambdaDryRunLeavesConcurrencyAlone(t *testing.T) {
+	fake := &fakeLambda{}
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.lambdaClient = fake
+
+	res, err := e.ThrottleLambda(context.Background(), "checkout", true)
+	require.NoError(t, err)
+	assert.Equal(t, true, res.Result["dry_run"])
+	assert.Nil(t, res.RollbackFn)
+	assert.Equal(t, []string{"GetFunctionConcurrency"}, fake.calls)
+}
+
+// fakeACLEC2 holds network ACL associations and mimics AWS by issuing a new
+// association ID whenever one is replaced
+type fakeACLEC2 struct {
+	ec2API
+	acls     []ec2types.NetworkAcl
+	replaced []ec2.ReplaceNetworkAclAssociationInput
+}
+
+func (f *fakeACLEC2) DescribeNetworkAcls(_ context.Context, in *ec2.DescribeNetworkAclsInput, _ ...func(*ec2.Options)) (*ec2.DescribeNetworkAclsOutput, error) {
+	subnetID := in.Filters[0].Values[0]
+	out := &ec2.DescribeNetworkAclsOutput{}
+	for _, acl := range f.acls {
+		for _, assoc := range acl.Associations {
+			if aws.ToString(assoc.SubnetId) == subnetID {
+				out.NetworkAcls = append(out.NetworkAcls, acl)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeACLEC2) ReplaceNetworkAclAssociation(_ context.Context, in *ec2.ReplaceNetworkAclAssociationInput, _ ...func(*ec2.Options)) (*ec2.ReplaceNetworkAclAssociationOutput, error) {
+	f.replaced = append(f.replaced, *in)
+	newID := "aclassoc-" + strconv.Itoa(len(f.replaced)+100)
+	var moved *ec2types.NetworkAclAssociation
+	for i := range f.acls {
+		kept := f.acls[i].Associations[:0]
+		for _, assoc := range f.acls[i].Associations {
+			if aws.ToString(assoc.NetworkAclAssociationId) == aws.ToString(in.AssociationId) {
+				a := assoc
+				moved = &a
+				continue
+			}
+			kept = append(kept, assoc)
+		}
+		f.acls[i].Associations = kept
+	}
+	if moved == nil {
+		return nil, errors.New("InvalidAssociationID.NotFound")
+	}
+	moved.NetworkAclAssociationId = aws.String(newID)
+	moved.NetworkAclId = in.NetworkAclId
+	for i := range f.acls {
+		if aws.ToString(f.acls[i].NetworkAclId) == aws.ToString(in.NetworkAclId) {
+			f.acls[i].Associations = append(f.acls[i].Associations, *moved)
+		}
+	}
+	return &ec2.ReplaceNetworkAclAssociationOutput{NewAssociationId: aws.String(newID)}, nil
+}
+
+func newFakeACLEC2() *fakeACLEC2 {
+	return &fakeACLEC2{acls: []ec2types.NetworkAcl{
+		{NetworkAclId: aws.String("acl-default"), Associations: []ec2types.NetworkAclAssociation{
+			{NetworkAclAssociationId: aws.String("aclassoc-1"), NetworkAclId: aws.String("acl-default"), SubnetId: aws.String("subnet-a")},
+			{NetworkAclAssociationId: aws.String("aclassoc-2"), NetworkAclId: aws.String("acl-default"), SubnetId: aws.String("subnet-b")},
+		}},
+		{NetworkAclId: aws.String("acl-deny")},
+	}}
+}
+
+func TestIsolateSubnetRollbackUsesNewAssociation(t *testing.T) {
+	fake := newFakeACLEC2()
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.ec2Client = fake
+
+	res, err := e.IsolateSubnet(context.Background(), "subnet-b", "acl-deny", false)
+	require.NoError(t, err)
+	assert.Equal(t, "acl-default", res.Result["original_acl_id"])
+	require.Len(t, fake.replaced, 1)
+	assert.Equal(t, "aclassoc-2", aws.ToString(fake.replaced[0].AssociationId))
+
+	// Restore through the persisted form, as startup reconciliation would
+	fn, err := ReconstructRollback(*res.Rollback, nil, e)
+	require.NoError(t, err)
+	rb, err := fn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "acl-default", rb["network_acl_id"])
+
+	require.Len(t, fake.replaced, 2)
+	assert.Equal(t, "aclassoc-101", aws.ToString(fake.replaced[1].AssociationId))
+	assert.Equal(t, "acl-default", aws.ToString(fake.replaced[1].NetworkAclId))
+	_, aclID, err := e.subnetACLAssociation(context.Background(), "subnet-b")
+	require.NoError(t, err)
+	assert.Equal(t, "acl-default", aclID)
+}
+
+func TestIsolateSubnetUnknownSubnet(t *testing.T) {
+	fake := newFakeACLEC2()
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.ec2Client = fake
+
+	_, err := e.IsolateSubnet(context.Background(), "subnet-typo", "acl-deny", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "subnet-typo")
+
+	preview, err := e.IsolateSubnet(context.Background(), "subnet-typo", "acl-deny", true)
+	require.Error(t, err)
+	assert.Equal(t, 0, preview.Result["would_affect"].(map[string]any)["count"])
+	assert.Empty(t, fake.replaced)
+}
+
+func TestIsolateSubnetRejectsDenyACLAlreadyAssociated(t *testing.T) {
+	fake := newFakeACLEC2()
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.ec2Client = fake
+
+	_, err := e.IsolateSubnet(context.Background(), "subnet-a", "acl-default", false)
+	assert.ErrorIs(t, err, domain.ErrInvalidConfig)
+	assert.Empty(t, fake.replaced)
+}
+
+// fakeStateEC2 describes instances by ID with the states it holds
+type fakeStateEC2 struct {
+	ec2API
+	states map[string]ec2types.InstanceStateName
+}
+
+func (f *fakeStateEC2) DescribeInstances(_ context.Context, in *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	var instances []ec2types.Instance
+	for _, id := range in.InstanceIds {
+		if state, ok := f.states[id]; ok {
+			instances = append(instances, ec2types.Instance{
+				InstanceId: aws.String(id),
+				State:      &ec2types.InstanceState{Name: state},
+			})
+		}
+	}
+	return &ec2.DescribeInstancesOutput{Reservations: []ec2types.Reservation{{Instances: instances}}}, nil
+}
+
+func TestAwsGetSteadyState(t *testing.T) {
+	e := newTestAwsEngine(t, &fakeRDS{instanceID: "orders-db"})
+	e.ec2Client = &fakeStateEC2{states: map[string]ec2types.InstanceStateName{
+		"i-1": ec2types.InstanceStateNameRunning,
+		"i-2": ec2types.InstanceStateNameStopped,
+	}}
+
+	state, err := e.GetSteadyState(context.Background(), AWSResourceEC2, []string{"i-1", "i-2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"i-1": "running", "i-2": "stopped"}, state["instances"])
+
+	state, err = e.GetSteadyState(context.Background(), AWSResourceRDSInstance, []string{"orders-db"})
+	require.NoError(t, err)
+	assert.Equal(t, "available", state["status"])
+
+	_, err = e.GetSteadyState(context.Background(), AWSResourceRDSInstance, []string{"typo-db"})
+	assert.Error(t, err)
+	_, err = e.GetSteadyState(context.Background(), "lambda", []string{"fn"})
+	assert.Error(t, err)
+}
+
+func TestRunCapturesAWSSnapshotAndVerifiesRollback(t *testing.T) {
+	ctx := context.Background()
+	ec2Fake := &fakeStateEC2{states: map[string]ec2types.InstanceStateName{
+		"i-1": ec2types.InstanceStateNameRunning,
+		"i-2": ec2types.InstanceStateNameRunning,
+	}}
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.ec2Client = ec2Fake
+	snapshots := safety.NewSnapshotManager(nil)
+	runner := NewRunner(nil, e, safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(), snapshots, nil, nil, "")
+
+	safetyCfg := domain.DefaultSafetyConfig()
+	safetyCfg.DryRun = true
+	result, err := runner.Run(ctx, "ec2-state", domain.ExperimentConfig{
+		Name:       "stop",
+		ChaosType:  domain.ChaosTypeEC2Stop,
+		Parameters: map[string]any{"instance_ids": []any{"i-1", "i-2"}},
+		Safety:     safetyCfg,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"instances": map[string]any{"i-1": "running", "i-2": "running"}}, result.SteadyState["aws"])
+
+	snap, ok := snapshots.GetSnapshot("ec2-state")
+	require.True(t, ok)
+	assert.Equal(t, "aws", snap["type"])
+	assert.Equal(t, "ec2", snap["resource_type"])
+	assert.Equal(t, "i-1,i-2", snap["resource_id"])
+
+	drift, err := runner.VerifyRollback(ctx, "ec2-state")
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	// An instance the rollback failed to restart shows up as drift
+	ec2Fake.states["i-2"] = ec2types.InstanceStateNameStopped
+	drift, err = runner.VerifyRollback(ctx, "ec2-state")
+	require.NoError(t, err)
+	require.Len(t, drift, 1)
+	assert.Equal(t, "i-2", drift[0]["instance_id"])
+	assert.Equal(t, "stopped", drift[0]["current_state"])
+}
+
+func TestVerifyRollbackChecksBothLayersOfCrossLayerRun(t *testing.T) {
+	ctx := context.Background()
+	ec2Fake := &fakeStateEC2{states: map[string]ec2types.InstanceStateName{
+		"i-1": ec2types.InstanceStateNameRunning,
+	}}
+	e := newTestAwsEngine(t, &fakeRDS{})
+	e.ec2Client = ec2Fake
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	snapshots := safety.NewSnapshotManager(nil)
+	runner := NewRunner(k8s, e, safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(), snapshots, nil, nil, "")
+
+	namespace := "default"
+	safetyCfg := domain.DefaultSafetyConfig()
+	safetyCfg.DryRun = true
+	_, err := runner.Run(ctx, "cross-layer", domain.ExperimentConfig{
+		Name:            "stop",
+		ChaosType:       domain.ChaosTypeEC2Stop,
+		TargetNamespace: &namespace,
+		Parameters:      map[string]any{"instance_ids": []any{"i-1"}},
+		Safety:          safetyCfg,
+	})
+	require.NoError(t, err)
+	assert.Len(t, snapshots.GetSnapshots("cross-layer"), 2, "the AWS snapshot does not replace the Kubernetes one")
+
+	ec2Fake.states["i-1"] = ec2types.InstanceStateNameStopped
+	require.NoError(t, k8s.clientset.CoreV1().Pods("default").Delete(ctx, "web-1", metav1.DeleteOptions{}))
+
+	drift, err := runner.VerifyRollback(ctx, "cross-layer")
+	require.NoError(t, err)
+	require.Len(t, drift, 2)
+	assert.Equal(t, "state_drift", drift[0]["action"])
+	assert.Equal(t, "pod_missing", drift[1]["action"])
+}
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/probe"
+	"github.com/chaosduck/backend-go/internal/safety"
+)
+
+// healthProbe adapts a probe.Probe to safety.HealthProbe
+type healthProbe struct {
+	probe.Probe
+}
+
+// Execute runs the wrapped probe and reports whether it passed
+func (h healthProbe) Execute(ctx context.Context) (bool, error) {
+	pr, err := h.Probe.Execute(ctx)
+	if err != nil {
+		return false, err
+	}
+	return pr.Passed, nil
+}
+
+// startHealthCheck starts a HealthCheckLoop over the experiment's SOT probes,
+// which roll the experiment back once they fail
+// Safety.HealthCheckFailureThreshold polls in a row. It returns nil when
+// there is nothing to watch: no SOT probes, or a dry run that injected
+// nothing. The caller must Stop the returned loop.
+func (r *Runner) startHealthCheck(experimentID string, cfg domain.ExperimentConfig, probes []probe.Probe) *safety.HealthCheckLoop {
+	if cfg.Safety.DryRun {
+		return nil
+	}
+	var watched []safety.HealthProbe
+	for _, p := range probes {
+		if p.Mode() == domain.ProbeModeSOT {
+			watched = append(watched, healthProbe{p})
+		}
+	}
+	if len(watched) == 0 {
+		return nil
+	}
+
+	loop := safety.NewHealthCheckLoop(experimentID, watched,
+		time.Duration(max(cfg.Safety.HealthCheckInterval, 1))*time.Second,
+		max(cfg.Safety.HealthCheckFailureThreshold, 1),
+		r.rollbackMgr,
+	)
+	loop.Start()
+	if r.healthLoopHook != nil {
+		r.healthLoopHook(loop)
+	}
+	return loop
+}
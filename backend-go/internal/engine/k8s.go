@@ -1,30 +1,103 @@
 package engine
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/chaosduck/backend-go/internal/domain"
 	"github.com/chaosduck/backend-go/internal/safety"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/kubectl/pkg/scheme"
 )
 
+// DefaultPodConcurrency is how many pods are mutated at once unless
+// configured otherwise
+const DefaultPodConcurrency = 10
+
+// DefaultAPIRetry retries a List call or exec twice, 200ms then 400ms
+// after a transient API server error
+var DefaultAPIRetry = wait.Backoff{Steps: 3, Duration: 200 * time.Millisecond, Factor: 2, Jitter: 0.1}
+
+// ExperimentAnnotation marks pods affected by a running exec-based
+// experiment with its ID so operators can trace them back
+const ExperimentAnnotation = "chaosduck.io/experiment-id"
+
+type experimentIDKey struct{}
+
+// WithExperimentID attaches the experiment ID the engine stamps on the pods
+// it mutates
+func WithExperimentID(ctx context.Context, experimentID string) context.Context {
+	return context.WithValue(ctx, experimentIDKey{}, experimentID)
+}
+
+// experimentIDFrom returns the ID set by WithExperimentID, or ""
+func experimentIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(experimentIDKey{}).(string)
+	return id
+}
+
 // K8sEngine implements chaos operations against a Kubernetes cluster.
 // All mutation methods return (result, rollbackFn).
 type K8sEngine struct {
 	clientset   kubernetes.Interface
 	restConfig  *rest.Config
 	esm         *safety.EmergencyStopManager
+	// podConcurrency bounds how many pods are mutated at once
+	podConcurrency int
+	// podReadyWait is how long a pod_delete rollback waits for recreated
+	// pods to become ready; 0 returns right after recreating them
+	podReadyWait time.Duration
+	// cmds locates the tools exec'd in target containers
+	cmds ExecCommands
+	// apiRetry bounds retries of List calls and execs that hit transient
+	// API server errors; the zero value uses DefaultAPIRetry
+	apiRetry wait.Backoff
+	// exec replaces the SPDY exec in execInPod when set (tests)
+	exec func(ctx context.Context, namespace, podName string, command []string) (string, error)
+}
+
+// ExecCommands locates the tools the engine runs inside target containers,
+// for hardened images that keep them off PATH or behind sudo. Empty fields
+// fall back to the bare command names.
+type ExecCommands struct {
+	TC     string
+	Stress string
+	Pkill  string
+	// NTPServer, when set, is queried with ntpdate to restore a skewed clock
+	// if chrony and ntpd are not available
+	NTPServer string
+	// Prefix is prepended to every exec'd command, e.g. ["sudo", "-n"]
+	Prefix []string
 }
 
+func (c ExecCommands) tc() string     { return cmp.Or(c.TC, "tc") }
+func (c ExecCommands) stress() string { return cmp.Or(c.Stress, "stress-ng") }
+func (c ExecCommands) pkill() string  { return cmp.Or(c.Pkill, "pkill") }
+
 // NewK8sEngine creates a K8sEngine with in-cluster or kubeconfig auth
 func NewK8sEngine(kubeconfig string, esm *safety.EmergencyStopManager) (*K8sEngine, error) {
 	var cfg *rest.Config
@@ -51,250 +124,898 @@ func NewK8sEngine(kubeconfig string, esm *safety.EmergencyStopManager) (*K8sEngi
 	return &K8sEngine{clientset: cs, restConfig: cfg, esm: esm}, nil
 }
 
+// NewK8sEngineWithClientset creates a K8sEngine around an existing clientset.
+// Exec-based actions need a rest config and are unavailable.
+func NewK8sEngineWithClientset(cs kubernetes.Interface, esm *safety.EmergencyStopManager) *K8sEngine {
+	return &K8sEngine{clientset: cs, esm: esm}
+}
+
 // Clientset exposes the underlying kubernetes.Interface for probes
 func (e *K8sEngine) Clientset() kubernetes.Interface {
 	return e.clientset
 }
 
+// ServerVersion returns the API server's version, confirming it is reachable
+func (e *K8sEngine) ServerVersion() (string, error) {
+	v, err := e.clientset.Discovery().ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("server version: %w", err)
+	}
+	return v.GitVersion, nil
+}
+
+// SetPodConcurrency sets how many pods are mutated at once
+func (e *K8sEngine) SetPodConcurrency(n int) {
+	e.podConcurrency = n
+}
+
+// SetPodReadyWait makes pod_delete rollbacks wait up to d for recreated pods
+// to become Running and Ready. The wait never outlasts the rollback's own
+// timeout.
+func (e *K8sEngine) SetPodReadyWait(d time.Duration) {
+	e.podReadyWait = d
+}
+
+// SetExecCommands sets the paths of the tools exec'd in target containers
+// and a prefix, such as sudo, to run them with
+func (e *K8sEngine) SetExecCommands(c ExecCommands) {
+	e.cmds = c
+}
+
+// SetAPIRetry makes List calls and execs try up to attempts times, waiting
+// backoff after the first transient API server error and doubling after each
+// further one. attempts below 2 disables retries.
+func (e *K8sEngine) SetAPIRetry(attempts int, backoff time.Duration) {
+	e.apiRetry = wait.Backoff{Steps: max(attempts, 1), Duration: backoff, Factor: 2, Jitter: 0.1}
+}
+
+// retryTransient runs fn until it succeeds, fails with an error that is not
+// a transient API server error, or runs out of attempts
+func (e *K8sEngine) retryTransient(ctx context.Context, fn func() error) error {
+	backoff := e.apiRetry
+	if backoff.Steps == 0 {
+		backoff = DefaultAPIRetry
+	}
+	return retry.OnError(backoff, func(err error) bool {
+		return ctx.Err() == nil && isTransientAPIError(err)
+	}, fn)
+}
+
+// isTransientAPIError reports whether err is API server throttling or brief
+// unavailability, which is worth retrying
+func isTransientAPIError(err error) bool {
+	return apierrors.IsTooManyRequests(err) || apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) || apierrors.IsServiceUnavailable(err)
+}
+
+// retryList calls list, retrying transient API server errors
+func retryList[T any](ctx context.Context, e *K8sEngine, list func(context.Context, metav1.ListOptions) (T, error), opts metav1.ListOptions) (T, error) {
+	var out T
+	err := e.retryTransient(ctx, func() error {
+		var err error
+		out, err = list(ctx, opts)
+		return err
+	})
+	return out, err
+}
+
 func (e *K8sEngine) checkEmergencyStop() error {
 	return e.esm.CheckEmergencyStop()
 }
 
-// PodDelete deletes pods matching the label selector
-func (e *K8sEngine) PodDelete(ctx context.Context, namespace, labelSelector string, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+// PodDelete deletes pods matching the label selector. A non-nil gracePeriod
+// overrides the pods' terminationGracePeriodSeconds; 0 kills them at once.
+func (e *K8sEngine) PodDelete(ctx context.Context, namespace, labelSelector string, gracePeriod *int64, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
 	if err := e.checkEmergencyStop(); err != nil {
 		return nil, err
 	}
 
-	pods, err := e.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	pods, all, err := e.listTargets(ctx, namespace, labelSelector, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("list pods: %w", err)
+		return nil, err
 	}
-	podNames := make([]string, 0, len(pods.Items))
-	for _, p := range pods.Items {
-		podNames = append(podNames, p.Name)
+	podNames := podNameList(pods)
+	blastErr := validatePodBlastRadius(pods.Items, all, cfg)
+
+	var extra map[string]any
+	if gracePeriod != nil {
+		extra = map[string]any{"grace_period_seconds": *gracePeriod}
+	}
+
+	if cfg != nil && cfg.Safety.DryRun {
+		return &domain.ChaosResult{
+			Result: dryRunPreview("pod_delete", podNames, len(all), cfg, extra),
+		}, blastErr
+	}
+	if blastErr != nil {
+		return nil, blastErr
+	}
+
+	// Delete pods concurrently and save the specs of the deleted ones for rollback
+	deletedPods, err := mutatePods(ctx, pods.Items, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+		return e.clientset.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: gracePeriod})
+	})
+	if len(deletedPods) == 0 && err != nil {
+		return nil, fmt.Errorf("delete pods: %w", err)
 	}
+	log.Printf("Deleted %d/%d pods in %s", len(deletedPods), len(pods.Items), namespace)
 
-	// Blast radius check
-	allPods, err := e.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	result := map[string]any{"action": "pod_delete", "pods": podNameListFromPods(deletedPods)}
+	maps.Copy(result, extra)
 	if err != nil {
-		return nil, fmt.Errorf("list all pods: %w", err)
+		// Partial failure: the rollback covers only the pods actually deleted
+		result["failed_pods"] = unmutatedPodNames(pods.Items, deletedPods)
+		err = fmt.Errorf("delete pods: %w", err)
 	}
-	maxRatio := 0.3
-	if cfg != nil {
-		maxRatio = cfg.Safety.MaxBlastRadius
+	return &domain.ChaosResult{
+		Result:     result,
+		RollbackFn: buildPodRollback(e.clientset, namespace, deletedPods, e.podReadyWait),
+		Rollback:   newRollbackAction(domain.ChaosTypePodDelete, podDeleteRollback{Namespace: namespace, Pods: deletedPods}),
+	}, err
+}
+
+// PodEvict evicts target pods through the eviction subresource, so unlike
+// PodDelete it honors PodDisruptionBudgets: the API server refuses an
+// eviction that would violate a budget. Pods are evicted one at a time so
+// each eviction sees the budget left by the previous one. Refused pods are
+// reported under "blocked_pods" rather than failing the injection, since a
+// budget holding is the behaviour under test. The rollback is a no-op:
+// controllers reschedule evicted pods.
+func (e *K8sEngine) PodEvict(ctx context.Context, namespace, labelSelector string, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+	if err := e.checkEmergencyStop(); err != nil {
+		return nil, err
 	}
-	if err := safety.ValidateBlastRadius(len(podNames), len(allPods.Items), maxRatio); err != nil {
-		return nil, fmt.Errorf("%w: %d/%d pods", err, len(podNames), len(allPods.Items))
+
+	pods, all, err := e.listTargets(ctx, namespace, labelSelector, cfg)
+	if err != nil {
+		return nil, err
 	}
+	podNames := podNameList(pods)
+	blastErr := validatePodBlastRadius(pods.Items, all, cfg)
 
 	if cfg != nil && cfg.Safety.DryRun {
 		return &domain.ChaosResult{
-			Result: map[string]any{"action": "pod_delete", "pods": podNames, "dry_run": true},
-		}, nil
+			Result: dryRunPreview("pod_evict", podNames, len(all), cfg, nil),
+		}, blastErr
+	}
+	if blastErr != nil {
+		return nil, blastErr
 	}
 
-	// Delete pods and save specs for rollback
-	deletedPods := make([]corev1.Pod, 0, len(pods.Items))
-	for _, pod := range pods.Items {
-		if err := e.clientset.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
-			// Partial failure: return rollback for already-deleted pods
-			log.Printf("Failed to delete pod %s (deleted %d/%d): %v", pod.Name, len(deletedPods), len(pods.Items), err)
-			rollback := buildPodRollback(e.clientset, namespace, deletedPods)
-			return &domain.ChaosResult{
-				Result:     map[string]any{"action": "pod_delete", "pods": podNameListFromPods(deletedPods), "partial_failure": pod.Name},
-				RollbackFn: rollback,
-			}, fmt.Errorf("delete pod %s: %w", pod.Name, err)
+	var mu sync.Mutex
+	blocked := map[string]string{}
+	requested, err := mutatePods(ctx, pods.Items, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+		err := e.clientset.PolicyV1().Evictions(namespace).Evict(ctx, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: namespace},
+		})
+		if apierrors.IsTooManyRequests(err) {
+			// 429 is how the API server refuses an eviction a budget forbids
+			mu.Lock()
+			blocked[pod.Name] = err.Error()
+			mu.Unlock()
+			return nil
+		}
+		return err
+	})
+	evicted := []string{}
+	for _, pod := range requested {
+		if _, ok := blocked[pod.Name]; !ok {
+			evicted = append(evicted, pod.Name)
 		}
-		deletedPods = append(deletedPods, pod)
 	}
-	log.Printf("Deleted %d pods in %s", len(deletedPods), namespace)
+	failed := unmutatedPodNames(pods.Items, requested)
+	if len(evicted) == 0 && len(blocked) == 0 && err != nil {
+		return nil, fmt.Errorf("evict pods: %w", err)
+	}
+	log.Printf("Evicted %d/%d pods in %s (%d blocked by disruption budgets)", len(evicted), len(pods.Items), namespace, len(blocked))
 
-	rollback := buildPodRollback(e.clientset, namespace, deletedPods)
+	rollback := func(context.Context) (map[string]any, error) {
+		return map[string]any{"note": "evicted pods are rescheduled by their controllers; nothing to undo"}, nil
+	}
 
+	result := map[string]any{"action": "pod_evict", "pods": evicted, "blocked_pods": blocked}
+	if err != nil {
+		result["failed_pods"] = failed
+		err = fmt.Errorf("evict pods: %w", err)
+	}
 	return &domain.ChaosResult{
-		Result:     map[string]any{"action": "pod_delete", "pods": podNames},
+		Result:     result,
 		RollbackFn: rollback,
-	}, nil
+	}, err
+}
+
+// NetworkFault applies latency, jitter, loss and duplication together as a
+// single netem qdisc, so the impairments combine instead of the second
+// "tc qdisc add ... root" failing with "File exists". One rollback deletes
+// the root qdisc.
+func (e *K8sEngine) NetworkFault(ctx context.Context, namespace, labelSelector string, fault domain.NetemFault, iface string, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+	if fault.Delay.LatencyMs <= 0 && fault.LossPercent <= 0 && fault.DuplicatePercent <= 0 {
+		return nil, fmt.Errorf("%w: network fault needs latency, loss or duplicate", domain.ErrInvalidConfig)
+	}
+	return e.netemFault(ctx, "network_fault", namespace, labelSelector, fault, iface, cfg)
+}
+
+// NetworkLatency injects network latency, optionally with jitter, using tc in
+// pod containers
+func (e *K8sEngine) NetworkLatency(ctx context.Context, namespace, labelSelector string, delay domain.NetemDelay, iface string, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+	return e.netemFault(ctx, "network_latency", namespace, labelSelector, domain.NetemFault{Delay: delay}, iface, cfg)
+}
+
+// NetworkLoss injects network packet loss
+func (e *K8sEngine) NetworkLoss(ctx context.Context, namespace, labelSelector string, lossPercent int, iface string, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+	return e.netemFault(ctx, "network_loss", namespace, labelSelector, domain.NetemFault{LossPercent: lossPercent}, iface, cfg)
 }
 
-// NetworkLatency injects network latency using tc in pod containers
-func (e *K8sEngine) NetworkLatency(ctx context.Context, namespace, labelSelector string, latencyMs int, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+// netemFault adds a root netem qdisc on the target pods. action names the
+// injection in results, e.g. "network_latency"; its suffix names the
+// rollback result key and error prefix ("removed_latency", "inject latency").
+func (e *K8sEngine) netemFault(ctx context.Context, action, namespace, labelSelector string, fault domain.NetemFault, iface string, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
 	if err := e.checkEmergencyStop(); err != nil {
 		return nil, err
 	}
 
-	pods, err := e.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	pods, all, err := e.listTargets(ctx, namespace, labelSelector, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("list pods: %w", err)
+		return nil, err
 	}
 	podNames := podNameList(pods)
+	blastErr := validatePodBlastRadius(pods.Items, all, cfg)
 
 	if cfg != nil && cfg.Safety.DryRun {
 		return &domain.ChaosResult{
-			Result: map[string]any{"action": "network_latency", "pods": podNames, "latency_ms": latencyMs, "dry_run": true},
-		}, nil
+			Result: dryRunPreview(action, podNames, len(all), cfg, netemFields(fault, iface)),
+		}, blastErr
+	}
+	if blastErr != nil {
+		return nil, blastErr
 	}
 
-	for _, pod := range pods.Items {
-		if _, err := e.execInPod(ctx, namespace, pod.Name, []string{"tc", "qdisc", "add", "dev", "eth0", "root", "netem", "delay", fmt.Sprintf("%dms", latencyMs)}); err != nil {
-			return nil, fmt.Errorf("inject latency on %s: %w", pod.Name, err)
+	kind := strings.TrimPrefix(action, "network_")
+	unannotate := e.annotatePods(ctx, namespace, pods.Items)
+	injected, devices, err := e.tcOnPods(ctx, namespace, pods.Items, iface, func(dev string) []string {
+		return netemCommand(e.cmds.tc(), dev, fault)
+	})
+	if len(injected) == 0 && err != nil {
+		unannotate(ctx)
+		return nil, fmt.Errorf("inject %s: %w", kind, err)
+	}
+	log.Printf("Injected netem %s on %d/%d pods in %s", strings.Join(netemArgs(fault), " "), len(injected), len(pods.Items), namespace)
+
+	rollback := func(ctx context.Context) (map[string]any, error) {
+		undone, err := e.removeQdisc(ctx, namespace, injected, devices)
+		if err != nil {
+			log.Printf("Rollback: remove %s failed: %v", kind, err)
 		}
+		unannotate(ctx)
+		return map[string]any{"removed_" + kind: len(undone)}, nil
 	}
-	log.Printf("Injected %dms latency on %d pods in %s", latencyMs, len(podNames), namespace)
 
-	rollback := func() (map[string]any, error) {
-		rbCtx := context.Background()
-		for _, pod := range pods.Items {
-			if _, err := e.execInPod(rbCtx, namespace, pod.Name, []string{"tc", "qdisc", "del", "dev", "eth0", "root"}); err != nil {
-				log.Printf("Rollback: remove latency from %s failed: %v", pod.Name, err)
+	result := netemFields(fault, iface)
+	result["action"] = action
+	result["pods"] = podNameListFromPods(injected)
+	if iface == domain.AutoNetworkInterface {
+		result["interfaces"] = devices
+	}
+	if err != nil {
+		result["failed_pods"] = unmutatedPodNames(pods.Items, injected)
+		err = fmt.Errorf("inject %s: %w", kind, err)
+	}
+	return &domain.ChaosResult{
+		Result:     result,
+		RollbackFn: rollback,
+	}, err
+}
+
+// netemCommand builds the command adding fault on dev with the tc binary, e.g.
+// "tc qdisc add dev eth0 root netem delay 100ms 20ms distribution normal loss 5% duplicate 1%"
+func netemCommand(tc, dev string, fault domain.NetemFault) []string {
+	return append([]string{tc, "qdisc", "add", "dev", dev, "root", "netem"}, netemArgs(fault)...)
+}
+
+// netemArgs lists the netem options for fault, skipping unset impairments
+func netemArgs(fault domain.NetemFault) []string {
+	var args []string
+	if delay := fault.Delay; delay.LatencyMs > 0 {
+		args = append(args, "delay", fmt.Sprintf("%dms", delay.LatencyMs))
+		if delay.JitterMs > 0 {
+			args = append(args, fmt.Sprintf("%dms", delay.JitterMs))
+			if delay.Distribution != "" {
+				args = append(args, "distribution", delay.Distribution)
+			}
+		}
+	}
+	if fault.LossPercent > 0 {
+		args = append(args, "loss", fmt.Sprintf("%d%%", fault.LossPercent))
+	}
+	if fault.DuplicatePercent > 0 {
+		args = append(args, "duplicate", fmt.Sprintf("%d%%", fault.DuplicatePercent))
+	}
+	return args
+}
+
+// netemFields describes a netem injection in results and previews
+func netemFields(fault domain.NetemFault, iface string) map[string]any {
+	fields := map[string]any{"interface": iface}
+	if delay := fault.Delay; delay.LatencyMs > 0 {
+		fields["latency_ms"] = delay.LatencyMs
+		if delay.JitterMs > 0 {
+			fields["jitter_ms"] = delay.JitterMs
+			if delay.Distribution != "" {
+				fields["distribution"] = delay.Distribution
 			}
 		}
-		return map[string]any{"removed_latency": len(podNames)}, nil
 	}
+	if fault.LossPercent > 0 {
+		fields["loss_percent"] = fault.LossPercent
+	}
+	if fault.DuplicatePercent > 0 {
+		fields["duplicate_percent"] = fault.DuplicatePercent
+	}
+	return fields
+}
+
+// StressOptions choose how cpu_stress and memory_stress reach a pod
+type StressOptions struct {
+	// ShellFallback stresses pods whose container lacks stress-ng with shell
+	// loops instead of failing the injection
+	ShellFallback bool
+	// EphemeralImage, when set, runs stress-ng from this image in an
+	// ephemeral container attached to each pod instead of execing in the app
+	// container, for distroless and minimal images
+	EphemeralImage string
+}
+
+// CPUStress injects CPU stress via stress-ng. Pods whose container lacks
+// stress-ng fail with domain.ErrStressToolMissing unless opts pick the shell
+// fallback or an ephemeral container.
+func (e *K8sEngine) CPUStress(ctx context.Context, namespace, labelSelector string, cores, durationSec int, opts StressOptions, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+	if err := e.checkEmergencyStop(); err != nil {
+		return nil, err
+	}
+
+	pods, all, err := e.listTargets(ctx, namespace, labelSelector, cfg)
+	if err != nil {
+		return nil, err
+	}
+	podNames := podNameList(pods)
+	blastErr := validatePodBlastRadius(pods.Items, all, cfg)
+
+	if cfg != nil && cfg.Safety.DryRun {
+		return &domain.ChaosResult{
+			Result: dryRunPreview("cpu_stress", podNames, len(all), cfg, stressPreview(opts, map[string]any{"cores": cores})),
+		}, blastErr
+	}
+	if blastErr != nil {
+		return nil, blastErr
+	}
+
+	unannotate := e.annotatePods(ctx, namespace, pods.Items)
+	run, err := e.startStress(ctx, namespace, pods.Items, opts, stressCommands{
+		tool: []string{
+			e.cmds.stress(), "--cpu", fmt.Sprintf("%d", cores),
+			"--timeout", fmt.Sprintf("%ds", durationSec), "--quiet",
+		},
+		shell: shellCPUBurnCommand(cores, durationSec),
+	})
+	if len(run.injected) == 0 && err != nil {
+		unannotate(ctx)
+		return nil, fmt.Errorf("cpu stress: %w", err)
+	}
+	log.Printf("CPU stress on %d/%d pods in %s", len(run.injected), len(pods.Items), namespace)
 
+	result := map[string]any{"action": "cpu_stress", "pods": podNameListFromPods(run.injected), "cores": cores}
+	run.describe(result)
+	if err != nil {
+		result["failed_pods"] = unmutatedPodNames(pods.Items, run.injected)
+		err = fmt.Errorf("cpu stress: %w", err)
+	}
 	return &domain.ChaosResult{
-		Result:     map[string]any{"action": "network_latency", "pods": podNames, "latency_ms": latencyMs},
-		RollbackFn: rollback,
-	}, nil
+		Result:     result,
+		RollbackFn: e.stressRollback(namespace, run, nil, unannotate),
+	}, err
 }
 
-// NetworkLoss injects network packet loss
-func (e *K8sEngine) NetworkLoss(ctx context.Context, namespace, labelSelector string, lossPercent int, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+// MemoryStress injects memory stress via stress-ng with vmWorkers workers,
+// each allocating memoryBytes. Pods whose container lacks stress-ng fail
+// with domain.ErrStressToolMissing unless opts pick an ephemeral container or
+// the shell fallback, which writes memoryBytes once to a file in /dev/shm
+// that is charged to the container's memory.
+func (e *K8sEngine) MemoryStress(ctx context.Context, namespace, labelSelector string, memoryBytes string, vmWorkers, durationSec int, opts StressOptions, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
 	if err := e.checkEmergencyStop(); err != nil {
 		return nil, err
 	}
 
-	pods, err := e.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	var shellCmd []string
+	if opts.ShellFallback && opts.EphemeralImage == "" {
+		mib, err := domain.MemoryMiB(memoryBytes)
+		if err != nil {
+			return nil, fmt.Errorf("memory stress: %w: %v", domain.ErrInvalidConfig, err)
+		}
+		shellCmd = shellMemoryFillCommand(mib, durationSec)
+	}
+
+	pods, all, err := e.listTargets(ctx, namespace, labelSelector, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("list pods: %w", err)
+		return nil, err
 	}
 	podNames := podNameList(pods)
+	blastErr := validatePodBlastRadius(pods.Items, all, cfg)
 
 	if cfg != nil && cfg.Safety.DryRun {
 		return &domain.ChaosResult{
-			Result: map[string]any{"action": "network_loss", "pods": podNames, "loss_percent": lossPercent, "dry_run": true},
-		}, nil
+			Result: dryRunPreview("memory_stress", podNames, len(all), cfg, stressPreview(opts, map[string]any{"memory_bytes": memoryBytes, "vm_workers": vmWorkers})),
+		}, blastErr
+	}
+	if blastErr != nil {
+		return nil, blastErr
 	}
 
-	for _, pod := range pods.Items {
-		if _, err := e.execInPod(ctx, namespace, pod.Name, []string{"tc", "qdisc", "add", "dev", "eth0", "root", "netem", "loss", fmt.Sprintf("%d%%", lossPercent)}); err != nil {
-			return nil, fmt.Errorf("inject loss on %s: %w", pod.Name, err)
+	unannotate := e.annotatePods(ctx, namespace, pods.Items)
+	run, err := e.startStress(ctx, namespace, pods.Items, opts, stressCommands{
+		tool: []string{
+			e.cmds.stress(), "--vm", fmt.Sprintf("%d", vmWorkers), "--vm-bytes", memoryBytes,
+			"--timeout", fmt.Sprintf("%ds", durationSec), "--quiet",
+		},
+		shell: shellCmd,
+	})
+	if len(run.injected) == 0 && err != nil {
+		unannotate(ctx)
+		return nil, fmt.Errorf("memory stress: %w", err)
+	}
+	log.Printf("Memory stress on %d/%d pods in %s", len(run.injected), len(pods.Items), namespace)
+
+	result := map[string]any{"action": "memory_stress", "pods": podNameListFromPods(run.injected), "memory_bytes": memoryBytes, "vm_workers": vmWorkers}
+	run.describe(result)
+	if err != nil {
+		result["failed_pods"] = unmutatedPodNames(pods.Items, run.injected)
+		err = fmt.Errorf("memory stress: %w", err)
+	}
+	return &domain.ChaosResult{
+		Result:     result,
+		RollbackFn: e.stressRollback(namespace, run, []string{"rm", "-f", shellMemoryFile}, unannotate),
+	}, err
+}
+
+// stressMarker is passed as $0 to the shell fallbacks, so every process they
+// fork carries it in its command line for pkill -f to find
+const stressMarker = "chaosduck-stress"
+
+// shellMemoryFile holds the memory_stress shell fallback's allocation
+const shellMemoryFile = "/dev/shm/" + stressMarker
+
+// stressToolCheckCommand succeeds only in containers where the stress binary
+// is on PATH or, given as a path, executable, and otherwise exits 127 like a
+// shell running a command it cannot find
+func stressToolCheckCommand(stress string) []string {
+	return []string{"sh", "-c", "command -v " + stress + " >/dev/null || exit 127"}
+}
+
+// isCommandNotFound reports whether an exec failed because the command, or
+// the shell running it, does not exist in the container
+func isCommandNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus() == 127
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "exit code 127") || strings.Contains(msg, "executable file not found")
+}
+
+// selfExcludingPattern turns a literal into a pkill -f pattern that matches
+// it but not itself ("stress-ng" -> "stress-n[g]"), so a sudo or sh wrapping
+// the pkill is not killed along with the targets
+func selfExcludingPattern(literal string) string {
+	if literal == "" {
+		return literal
+	}
+	last := literal[len(literal)-1:]
+	if strings.ContainsAny(last, `]^\`) {
+		return regexp.QuoteMeta(literal)
+	}
+	return regexp.QuoteMeta(literal[:len(literal)-1]) + "[" + last + "]"
+}
+
+// selfExcludingRegex does for a user-supplied pkill -f regex what
+// selfExcludingPattern does for a literal: a leading plain character is
+// wrapped in a bracket expression ("nginx" -> "[n]ginx"), so the command line
+// of a sudo or sh wrapping the pkill no longer contains a match. A pattern
+// anchored with ^ cannot match the wrapper and is returned as is.
+func selfExcludingRegex(pattern string) string {
+	if pattern == "" || pattern[0] >= utf8.RuneSelf || strings.ContainsRune(`.[]()*+?{}|^$\`, rune(pattern[0])) {
+		return pattern
+	}
+	return "[" + pattern[:1] + "]" + pattern[1:]
+}
+
+// stressCommands are what a stress chaos type runs in each pod: tool when
+// the container has stress-ng, shell for the shell fallback
+type stressCommands struct {
+	tool  []string
+	shell []string
+}
+
+// stressRun records where startStress put the stress
+type stressRun struct {
+	injected []corev1.Pod
+	// shellPods run the shell fallback rather than stress-ng
+	shellPods map[string]bool
+	// ephemeral is the name of the ephemeral container added to every
+	// injected pod, or "" when the stress was exec'd
+	ephemeral string
+}
+
+// describe adds how the stress was run to an injection result
+func (r stressRun) describe(result map[string]any) {
+	if len(r.shellPods) > 0 {
+		result["shell_fallback_pods"] = slices.Sorted(maps.Keys(r.shellPods))
+	}
+	if r.ephemeral != "" {
+		result["ephemeral_container"] = r.ephemeral
+	}
+}
+
+// stressPreview adds the chosen stress options to a dry-run preview
+func stressPreview(opts StressOptions, extra map[string]any) map[string]any {
+	extra["shell_fallback"] = opts.ShellFallback
+	if opts.EphemeralImage != "" {
+		extra["ephemeral_image"] = opts.EphemeralImage
+	}
+	return extra
+}
+
+// shellCPUBurnCommand spins cores busy loops for durationSec, then stops them
+func shellCPUBurnCommand(cores, durationSec int) []string {
+	return []string{"sh", "-c", fmt.Sprintf(
+		`pids=""; i=0; while [ $i -lt %d ]; do (while :; do :; done) & pids="$pids $!"; i=$((i+1)); done; `+
+			`sleep %d; kill $pids`, cores, durationSec), stressMarker}
+}
+
+// shellMemoryFillCommand holds mib MiB in shellMemoryFile for durationSec
+func shellMemoryFillCommand(mib, durationSec int) []string {
+	return []string{"sh", "-c", fmt.Sprintf(
+		`dd if=/dev/zero of=%s bs=1048576 count=%d 2>/dev/null && sleep %d; rm -f %[1]s`,
+		shellMemoryFile, mib, durationSec), stressMarker}
+}
+
+// startStress runs cmds.tool in every pod, either in an ephemeral container
+// or by exec, as opts choose
+func (e *K8sEngine) startStress(ctx context.Context, namespace string, pods []corev1.Pod, opts StressOptions, cmds stressCommands) (stressRun, error) {
+	if opts.EphemeralImage != "" {
+		name := ephemeralContainerName(ctx)
+		injected, err := e.attachStressContainers(ctx, namespace, pods, name, opts.EphemeralImage, cmds.tool[1:])
+		return stressRun{injected: injected, ephemeral: name}, err
+	}
+	injected, shellPods, err := e.execStress(ctx, namespace, pods, cmds, opts.ShellFallback)
+	return stressRun{injected: injected, shellPods: shellPods}, err
+}
+
+// execStress checks every pod for stress-ng, then runs cmds.tool in the pods
+// that have it and cmds.shell in the rest. Without shellFallback, any pod
+// lacking stress-ng fails the whole injection up front with
+// domain.ErrStressToolMissing. It returns the pods stressed and which of
+// them run the shell fallback.
+func (e *K8sEngine) execStress(ctx context.Context, namespace string, pods []corev1.Pod, cmds stressCommands, shellFallback bool) ([]corev1.Pod, map[string]bool, error) {
+	// Only a missing command means a missing tool: RBAC denials, timeouts
+	// and vanished pods fail the injection instead of changing how it runs
+	var mu sync.Mutex
+	shellPods := map[string]bool{}
+	_, err := mutatePods(ctx, pods, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+		_, err := e.execInPod(ctx, namespace, pod.Name, stressToolCheckCommand(e.cmds.stress()))
+		if isCommandNotFound(err) {
+			mu.Lock()
+			shellPods[pod.Name] = true
+			mu.Unlock()
+			return nil
 		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("check for %s: %w", e.cmds.stress(), err)
+	}
+	if len(shellPods) > 0 && !shellFallback {
+		return nil, nil, fmt.Errorf("%w (pods: %s)", domain.ErrStressToolMissing,
+			strings.Join(slices.Sorted(maps.Keys(shellPods)), ", "))
 	}
-	log.Printf("Injected %d%% packet loss on %d pods in %s", lossPercent, len(podNames), namespace)
 
-	rollback := func() (map[string]any, error) {
-		rbCtx := context.Background()
-		for _, pod := range pods.Items {
-			if _, err := e.execInPod(rbCtx, namespace, pod.Name, []string{"tc", "qdisc", "del", "dev", "eth0", "root"}); err != nil {
-				log.Printf("Rollback: remove loss from %s failed: %v", pod.Name, err)
-			}
+	injected, err := mutatePods(ctx, pods, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+		command := cmds.tool
+		if shellPods[pod.Name] {
+			command = cmds.shell
 		}
-		return map[string]any{"removed_loss": len(podNames)}, nil
+		_, err := e.execInPod(ctx, namespace, pod.Name, command)
+		return err
+	})
+	return injected, shellPods, err
+}
+
+// ephemeralContainerName names the stress container after the experiment,
+// falling back to a timestamp outside of one. Ephemeral containers can't be
+// removed, so the name must not repeat within a pod.
+func ephemeralContainerName(ctx context.Context) string {
+	if id := experimentIDFrom(ctx); id != "" {
+		return stressMarker + "-" + id
 	}
+	return stressMarker + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
 
-	return &domain.ChaosResult{
-		Result:     map[string]any{"action": "network_loss", "pods": podNames, "loss_percent": lossPercent},
-		RollbackFn: rollback,
-	}, nil
+// attachStressContainers adds an ephemeral container running image with args
+// to every pod through the pods/ephemeralcontainers subresource. It targets
+// the pod's first container, sharing its process namespace, and its usage
+// is charged to the pod. Clusters that don't serve the subresource fail with
+// domain.ErrEphemeralContainersUnsupported.
+func (e *K8sEngine) attachStressContainers(ctx context.Context, namespace string, pods []corev1.Pod, name, image string, args []string) ([]corev1.Pod, error) {
+	return mutatePods(ctx, pods, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+		current, err := e.clientset.CoreV1().Pods(namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("get pod: %w", err)
+		}
+		if len(current.Spec.Containers) == 0 {
+			return errors.New("pod has no containers to target")
+		}
+		current.Spec.EphemeralContainers = append(current.Spec.EphemeralContainers, corev1.EphemeralContainer{
+			EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+				Name:  name,
+				Image: image,
+				Args:  args,
+			},
+			TargetContainerName: current.Spec.Containers[0].Name,
+		})
+		_, err = e.clientset.CoreV1().Pods(namespace).UpdateEphemeralContainers(ctx, pod.Name, current, metav1.UpdateOptions{})
+		if apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) {
+			// The pod was just read, so a 404 here is the subresource itself
+			return fmt.Errorf("%w: %w", domain.ErrEphemeralContainersUnsupported, err)
+		}
+		return err
+	})
 }
 
-// CPUStress injects CPU stress via stress-ng
-func (e *K8sEngine) CPUStress(ctx context.Context, namespace, labelSelector string, cores, durationSec int, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+// stressRollback stops the stress startStress began. Exec'd stress-ng and the
+// shell fallback are killed, followed by shellCleanup (if any) for the
+// latter. Ephemeral containers can't be stopped or removed, so their
+// rollback is the stress-ng --timeout ending them.
+func (e *K8sEngine) stressRollback(namespace string, run stressRun, shellCleanup []string, unannotate func(context.Context)) domain.RollbackFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		defer unannotate(ctx)
+		if run.ephemeral != "" {
+			return map[string]any{
+				"ephemeral_container": run.ephemeral,
+				"pods":                podNameListFromPods(run.injected),
+				"note":                "ephemeral containers exit when their stress-ng --timeout elapses",
+			}, nil
+		}
+		undone, err := mutatePods(ctx, run.injected, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+			if !run.shellPods[pod.Name] {
+				_, err := e.execInPod(ctx, namespace, pod.Name,
+					[]string{e.cmds.pkill(), "-f", selfExcludingPattern(path.Base(e.cmds.stress()))})
+				return err
+			}
+			_, err := e.execInPod(ctx, namespace, pod.Name, []string{e.cmds.pkill(), "-f", selfExcludingPattern(stressMarker)})
+			if shellCleanup != nil {
+				_, cleanupErr := e.execInPod(ctx, namespace, pod.Name, shellCleanup)
+				err = errors.Join(err, cleanupErr)
+			}
+			return err
+		})
+		if err != nil {
+			log.Printf("Rollback: kill stress failed: %v", err)
+		}
+		return map[string]any{"killed_stress": len(undone)}, nil
+	}
+}
+
+// ClockSkew shifts the clock of the nodes running the target pods by
+// offsetSeconds with `date -s`. Containers share the node's kernel clock, so
+// this needs a privileged container with CAP_SYS_TIME and moves the clock of
+// everything else on the node as well; without the capability the injection
+// fails with domain.ErrClockSkewNotPermitted instead of silently passing.
+// The command runs in one target pod per node, so each node is skewed and
+// restored exactly once however many targets it hosts.
+func (e *K8sEngine) ClockSkew(ctx context.Context, namespace, labelSelector string, offsetSeconds int, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
 	if err := e.checkEmergencyStop(); err != nil {
 		return nil, err
 	}
 
-	pods, err := e.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	pods, all, err := e.listTargets(ctx, namespace, labelSelector, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("list pods: %w", err)
+		return nil, err
 	}
 	podNames := podNameList(pods)
+	blastErr := validatePodBlastRadius(pods.Items, all, cfg)
 
 	if cfg != nil && cfg.Safety.DryRun {
 		return &domain.ChaosResult{
-			Result: map[string]any{"action": "cpu_stress", "pods": podNames, "cores": cores, "dry_run": true},
-		}, nil
+			Result: dryRunPreview("clock_skew", podNames, len(all), cfg, map[string]any{"offset_seconds": offsetSeconds}),
+		}, blastErr
+	}
+	if blastErr != nil {
+		return nil, blastErr
 	}
 
-	for _, pod := range pods.Items {
-		if _, err := e.execInPod(ctx, namespace, pod.Name, []string{
-			"stress-ng", "--cpu", fmt.Sprintf("%d", cores),
-			"--timeout", fmt.Sprintf("%ds", durationSec), "--quiet",
-		}); err != nil {
-			return nil, fmt.Errorf("cpu stress on %s: %w", pod.Name, err)
-		}
+	byNode := podsByNode(pods.Items)
+	leaders := make([]corev1.Pod, 0, len(byNode))
+	for _, node := range slices.Sorted(maps.Keys(byNode)) {
+		leaders = append(leaders, byNode[node][0])
 	}
-	log.Printf("CPU stress on %d pods in %s", len(podNames), namespace)
 
-	rollback := func() (map[string]any, error) {
-		rbCtx := context.Background()
-		for _, pod := range pods.Items {
-			if _, err := e.execInPod(rbCtx, namespace, pod.Name, []string{"pkill", "-f", "stress-ng"}); err != nil {
-				log.Printf("Rollback: kill stress on %s failed: %v", pod.Name, err)
+	unannotate := e.annotatePods(ctx, namespace, pods.Items)
+	injected, err := e.execOnPods(ctx, namespace, leaders, shiftClockCommand(offsetSeconds))
+	err = clockPermissionError(err)
+	if len(injected) == 0 && err != nil {
+		unannotate(ctx)
+		return nil, fmt.Errorf("clock skew: %w", err)
+	}
+	var skewedNodes []string
+	var skewedPods []corev1.Pod
+	for _, leader := range injected {
+		node := podNodeKey(leader)
+		skewedNodes = append(skewedNodes, node)
+		skewedPods = append(skewedPods, byNode[node]...)
+	}
+	log.Printf("Clock skew of %ds on %d/%d nodes (%d pods) in %s", offsetSeconds, len(injected), len(leaders), len(skewedPods), namespace)
+
+	rollback := func(ctx context.Context) (map[string]any, error) {
+		var mu sync.Mutex
+		methods := map[string]string{}
+		// Each node is restored through the pod that skewed it: retrying on
+		// another pod of the node could reverse the offset twice
+		undone, err := mutatePods(ctx, injected, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+			out, err := e.execInPod(ctx, namespace, pod.Name, resyncClockCommand(offsetSeconds, e.cmds.NTPServer))
+			if err != nil {
+				return err
 			}
+			mu.Lock()
+			methods[podNodeKey(pod)] = strings.TrimSpace(out)
+			mu.Unlock()
+			return nil
+		})
+		out := map[string]any{"resynced": len(undone), "methods": methods}
+		if err != nil {
+			log.Printf("Rollback: clock resync failed: %v", err)
+			var unresynced []string
+			for _, pod := range injected {
+				if _, ok := methods[podNodeKey(pod)]; !ok {
+					unresynced = append(unresynced, podNodeKey(pod))
+				}
+			}
+			out["unresynced_nodes"] = unresynced
 		}
-		return map[string]any{"killed_stress": len(podNames)}, nil
+		unannotate(ctx)
+		return out, nil
 	}
 
+	result := map[string]any{
+		"action":         "clock_skew",
+		"pods":           podNameListFromPods(skewedPods),
+		"nodes":          skewedNodes,
+		"offset_seconds": offsetSeconds,
+		// Without NTP the rollback subtracts the offset from the skewed clock,
+		// so the time spent between the two commands is lost or gained
+		"rollback_accuracy": "best effort: NTP resync when available, otherwise the offset is reversed and drifts by the exec latency",
+	}
+	if err != nil {
+		result["failed_pods"] = unmutatedPodNames(pods.Items, skewedPods)
+		err = fmt.Errorf("clock skew: %w", err)
+	}
 	return &domain.ChaosResult{
-		Result:     map[string]any{"action": "cpu_stress", "pods": podNames, "cores": cores},
+		Result:     result,
 		RollbackFn: rollback,
-	}, nil
+	}, err
+}
+
+// shiftClockCommand moves the clock by offset seconds relative to now
+func shiftClockCommand(offset int) []string {
+	return []string{"sh", "-c", fmt.Sprintf(`date -s "@$(( $(date +%%s) + (%d) ))"`, offset)}
+}
+
+// resyncClockCommand restores the clock, preferring an NTP step with the
+// container's own chrony or ntpd configuration, then ntpdate against
+// ntpServer if one is configured, and falling back to reversing the offset.
+// It prints the method used.
+func resyncClockCommand(offset int, ntpServer string) []string {
+	ntp := "chronyc -a makestep || ntpd -q -n"
+	if ntpServer != "" {
+		ntp += ` || ntpdate -u "$1"`
+	}
+	command := []string{"sh", "-c", fmt.Sprintf(
+		`if (%s) >/dev/null 2>&1; then echo ntp; `+
+			`else date -s "@$(( $(date +%%s) - (%d) ))" >/dev/null && echo offset; fi`, ntp, offset)}
+	if ntpServer != "" {
+		command = append(command, "sh", ntpServer)
+	}
+	return command
+}
+
+// podsByNode groups pods by the node they run on, keyed by podNodeKey
+func podsByNode(pods []corev1.Pod) map[string][]corev1.Pod {
+	byNode := map[string][]corev1.Pod{}
+	for _, pod := range pods {
+		key := podNodeKey(pod)
+		byNode[key] = append(byNode[key], pod)
+	}
+	return byNode
 }
 
-// MemoryStress injects memory stress via stress-ng
-func (e *K8sEngine) MemoryStress(ctx context.Context, namespace, labelSelector string, memoryBytes string, durationSec int, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+// podNodeKey is the pod's node name, or the pod's own name for a pod that
+// is not scheduled yet
+func podNodeKey(pod corev1.Pod) string {
+	return cmp.Or(pod.Spec.NodeName, "pod/"+pod.Name)
+}
+
+// clockPermissionError marks exec failures caused by a missing CAP_SYS_TIME
+func clockPermissionError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "Operation not permitted") || strings.Contains(msg, "cannot set date") {
+		return fmt.Errorf("%w: %w", domain.ErrClockSkewNotPermitted, err)
+	}
+	return err
+}
+
+// ProcessKill sends signal to the processes whose command line matches
+// pattern in every target pod. The rollback only removes the experiment
+// annotation: restarting the process is left to the container's process
+// manager or the kubelet, which is the behaviour under test.
+func (e *K8sEngine) ProcessKill(ctx context.Context, namespace, labelSelector, pattern, signal string, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
 	if err := e.checkEmergencyStop(); err != nil {
 		return nil, err
 	}
 
-	pods, err := e.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	pods, all, err := e.listTargets(ctx, namespace, labelSelector, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("list pods: %w", err)
+		return nil, err
 	}
 	podNames := podNameList(pods)
+	blastErr := validatePodBlastRadius(pods.Items, all, cfg)
 
 	if cfg != nil && cfg.Safety.DryRun {
 		return &domain.ChaosResult{
-			Result: map[string]any{"action": "memory_stress", "pods": podNames, "memory_bytes": memoryBytes, "dry_run": true},
-		}, nil
+			Result: dryRunPreview("process_kill", podNames, len(all), cfg, map[string]any{"process_pattern": pattern, "signal": signal}),
+		}, blastErr
+	}
+	if blastErr != nil {
+		return nil, blastErr
 	}
 
-	for _, pod := range pods.Items {
-		if _, err := e.execInPod(ctx, namespace, pod.Name, []string{
-			"stress-ng", "--vm", "1", "--vm-bytes", memoryBytes,
-			"--timeout", fmt.Sprintf("%ds", durationSec), "--quiet",
-		}); err != nil {
-			return nil, fmt.Errorf("memory stress on %s: %w", pod.Name, err)
-		}
+	// pkill exits 1 when nothing matched, which counts as a failed pod. "--"
+	// keeps a pattern starting with "-" from being read as an option.
+	unannotate := e.annotatePods(ctx, namespace, pods.Items)
+	killed, err := e.execOnPods(ctx, namespace, pods.Items,
+		[]string{e.cmds.pkill(), "-" + signal, "-f", "--", selfExcludingRegex(pattern)})
+	if len(killed) == 0 && err != nil {
+		unannotate(ctx)
+		return nil, fmt.Errorf("process kill: %w", err)
 	}
-	log.Printf("Memory stress on %d pods in %s", len(podNames), namespace)
+	log.Printf("Sent SIG%s to %q on %d/%d pods in %s", signal, pattern, len(killed), len(pods.Items), namespace)
 
-	rollback := func() (map[string]any, error) {
-		rbCtx := context.Background()
-		for _, pod := range pods.Items {
-			if _, err := e.execInPod(rbCtx, namespace, pod.Name, []string{"pkill", "-f", "stress-ng"}); err != nil {
-				log.Printf("Rollback: kill stress on %s failed: %v", pod.Name, err)
-			}
-		}
-		return map[string]any{"killed_stress": len(podNames)}, nil
+	rollback := func(ctx context.Context) (map[string]any, error) {
+		unannotate(ctx)
+		return map[string]any{"note": "killed processes are restarted by their process manager; nothing to undo"}, nil
 	}
 
+	result := map[string]any{"action": "process_kill", "pods": podNameListFromPods(killed), "process_pattern": pattern, "signal": signal}
+	if err != nil {
+		result["failed_pods"] = unmutatedPodNames(pods.Items, killed)
+		err = fmt.Errorf("process kill: %w", err)
+	}
 	return &domain.ChaosResult{
-		Result:     map[string]any{"action": "memory_stress", "pods": podNames, "memory_bytes": memoryBytes},
+		Result:     result,
 		RollbackFn: rollback,
-	}, nil
+	}, err
 }
 
 // GetTopology discovers K8s resource topology
 func (e *K8sEngine) GetTopology(ctx context.Context, namespace string) (*domain.InfraTopology, error) {
+	scannedAt := time.Now()
 	nodes := make([]domain.TopologyNode, 0)
 	edges := make([]domain.TopologyEdge, 0)
 
 	// Deployments
-	deployments, err := e.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	deployments, err := retryList(ctx, e, e.clientset.AppsV1().Deployments(namespace).List, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("list deployments: %w", err)
 	}
@@ -315,7 +1036,7 @@ func (e *K8sEngine) GetTopology(ctx context.Context, namespace string) (*domain.
 	}
 
 	// ReplicaSets - build RS-to-Deployment ownership map
-	replicaSets, err := e.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	replicaSets, err := retryList(ctx, e, e.clientset.AppsV1().ReplicaSets(namespace).List, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("list replicasets: %w", err)
 	}
@@ -329,7 +1050,7 @@ func (e *K8sEngine) GetTopology(ctx context.Context, namespace string) (*domain.
 	}
 
 	// Pods
-	pods, err := e.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	pods, err := retryList(ctx, e, e.clientset.CoreV1().Pods(namespace).List, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("list pods: %w", err)
 	}
@@ -365,11 +1086,32 @@ func (e *K8sEngine) GetTopology(ctx context.Context, namespace string) (*domain.
 		}
 	}
 
+	// Cluster nodes the pods run on. Nodes are cluster-scoped, so a
+	// namespace-scoped service account may not be allowed to list them.
+	k8sNodes, err := retryList(ctx, e, e.clientset.CoreV1().Nodes().List, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("Topology: skipping nodes: %v", err)
+	} else {
+		for _, n := range k8sNodes.Items {
+			nodes = append(nodes, nodeTopology(n))
+		}
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName != "" {
+				edges = append(edges, domain.TopologyEdge{
+					Source:   "pod/" + pod.Name,
+					Target:   "node/" + pod.Spec.NodeName,
+					Relation: "runs_on",
+				})
+			}
+		}
+	}
+
 	// Services
-	services, err := e.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	services, err := retryList(ctx, e, e.clientset.CoreV1().Services(namespace).List, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("list services: %w", err)
 	}
+	var manualEndpoints map[string][]string
 	for _, svc := range services.Items {
 		svcID := "svc/" + svc.Name
 		nodes = append(nodes, domain.TopologyNode{
@@ -380,23 +1122,106 @@ func (e *K8sEngine) GetTopology(ctx context.Context, namespace string) (*domain.
 			Labels:       svc.Labels,
 			Health:       domain.HealthHealthy,
 		})
+
+		// Services with a selector route to the pods it matches; selector-less
+		// services route to whatever pods their manually managed endpoints name
+		var targets []string
+		if len(svc.Spec.Selector) > 0 {
+			selector := labels.SelectorFromSet(svc.Spec.Selector)
+			for _, pod := range pods.Items {
+				if selector.Matches(labels.Set(pod.Labels)) {
+					targets = append(targets, pod.Name)
+				}
+			}
+		} else {
+			if manualEndpoints == nil {
+				manualEndpoints = e.endpointPods(ctx, namespace)
+			}
+			targets = manualEndpoints[svc.Name]
+		}
+		for _, podName := range targets {
+			edges = append(edges, domain.TopologyEdge{
+				Source:   svcID,
+				Target:   "pod/" + podName,
+				Relation: "routes_to",
+			})
+		}
 	}
 
-	return &domain.InfraTopology{Nodes: nodes, Edges: edges}, nil
+	return &domain.InfraTopology{Nodes: nodes, Edges: edges, Timestamp: domain.TopologyTimestamp(scannedAt)}, nil
+}
+
+// endpointPods maps service names to the pods named in their EndpointSlices
+func (e *K8sEngine) endpointPods(ctx context.Context, namespace string) map[string][]string {
+	result := make(map[string][]string)
+	list, err := retryList(ctx, e, e.clientset.DiscoveryV1().EndpointSlices(namespace).List, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("Topology: skipping endpoint slices: %v", err)
+		return result
+	}
+	for _, slice := range list.Items {
+		svcName := slice.Labels[discoveryv1.LabelServiceName]
+		if svcName == "" {
+			continue
+		}
+		for _, ep := range slice.Endpoints {
+			if ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" {
+				result[svcName] = append(result[svcName], ep.TargetRef.Name)
+			}
+		}
+	}
+	return result
+}
+
+// nodeTopology describes a cluster node, keeping the provider ID and
+// internal DNS name used to match it to a cloud instance
+func nodeTopology(n corev1.Node) domain.TopologyNode {
+	health := domain.HealthUnknown
+	for _, cond := range n.Status.Conditions {
+		if cond.Type != corev1.NodeReady {
+			continue
+		}
+		if cond.Status == corev1.ConditionTrue {
+			health = domain.HealthHealthy
+		} else {
+			health = domain.HealthUnhealthy
+		}
+	}
+
+	metadata := map[string]any{}
+	if n.Spec.ProviderID != "" {
+		metadata["provider_id"] = n.Spec.ProviderID
+	}
+	for _, addr := range n.Status.Addresses {
+		if addr.Type == corev1.NodeInternalDNS {
+			metadata["internal_dns"] = addr.Address
+		}
+	}
+
+	return domain.TopologyNode{
+		ID:           "node/" + n.Name,
+		Name:         n.Name,
+		ResourceType: domain.ResourceNode,
+		Labels:       n.Labels,
+		Health:       health,
+		Metadata:     metadata,
+	}
 }
 
 // GetSteadyState captures current steady state metrics
 func (e *K8sEngine) GetSteadyState(ctx context.Context, namespace string) (map[string]any, error) {
-	pods, err := e.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	pods, err := retryList(ctx, e, e.clientset.CoreV1().Pods(namespace).List, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("list pods: %w", err)
 	}
 
 	running := 0
+	podList := make([]any, 0, len(pods.Items))
 	for _, p := range pods.Items {
 		if p.Status.Phase == corev1.PodRunning {
 			running++
 		}
+		podList = append(podList, map[string]any{"name": p.Name, "phase": string(p.Status.Phase)})
 	}
 	total := len(pods.Items)
 	ratio := 1.0
@@ -409,10 +1234,30 @@ func (e *K8sEngine) GetSteadyState(ctx context.Context, namespace string) (map[s
 		"pods_total":         total,
 		"pods_running":       running,
 		"pods_healthy_ratio": ratio,
+		"pods":               podList,
 	}, nil
 }
 
+// execInPod runs command in the pod's default container. Only failures to
+// start the exec, such as throttling, are retried: a command that ran is not
+// run again.
 func (e *K8sEngine) execInPod(ctx context.Context, namespace, podName string, command []string) (string, error) {
+	if len(e.cmds.Prefix) > 0 {
+		command = append(slices.Clone(e.cmds.Prefix), command...)
+	}
+	var out string
+	err := e.retryTransient(ctx, func() error {
+		var err error
+		out, err = e.execOnce(ctx, namespace, podName, command)
+		return err
+	})
+	return out, err
+}
+
+func (e *K8sEngine) execOnce(ctx context.Context, namespace, podName string, command []string) (string, error) {
+	if e.exec != nil {
+		return e.exec(ctx, namespace, podName, command)
+	}
 	req := e.clientset.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Name(podName).
@@ -439,6 +1284,166 @@ func (e *K8sEngine) execInPod(ctx context.Context, namespace, podName string, co
 	return stdout.String(), nil
 }
 
+// listTargets resolves the pods to act on along with every pod in the
+// namespace, which is the blast radius denominator. An explicit
+// cfg.TargetResource takes precedence over the label and field selectors;
+// cfg.TargetWorkload narrows them to the workload's pods.
+func (e *K8sEngine) listTargets(ctx context.Context, namespace, labelSelector string, cfg *domain.ExperimentConfig) (*corev1.PodList, []corev1.Pod, error) {
+	var pods *corev1.PodList
+	if cfg != nil && cfg.TargetResource != nil && *cfg.TargetResource != "" {
+		_, name, err := domain.ParseTargetResource(*cfg.TargetResource)
+		if err != nil {
+			return nil, nil, err
+		}
+		pod, err := e.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("get pod %s: %w", name, err)
+		}
+		pods = &corev1.PodList{Items: []corev1.Pod{*pod}}
+	} else {
+		var err error
+		if cfg != nil && cfg.TargetWorkload != nil && *cfg.TargetWorkload != "" {
+			labelSelector, err = e.workloadSelector(ctx, namespace, *cfg.TargetWorkload, labelSelector)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		opts := metav1.ListOptions{LabelSelector: labelSelector}
+		if cfg != nil && cfg.FieldSelector != nil {
+			if err := domain.ValidateFieldSelector(*cfg.FieldSelector); err != nil {
+				return nil, nil, err
+			}
+			opts.FieldSelector = *cfg.FieldSelector
+		}
+		pods, err = retryList(ctx, e, e.clientset.CoreV1().Pods(namespace).List, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("list pods: %w", err)
+		}
+	}
+	allPods, err := retryList(ctx, e, e.clientset.CoreV1().Pods(namespace).List, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("list all pods: %w", err)
+	}
+	return pods, allPods.Items, nil
+}
+
+// workloadSelector resolves a "kind/name" workload to the pod selector in
+// its spec, ANDed with extra when that is not empty
+func (e *K8sEngine) workloadSelector(ctx context.Context, namespace, workload, extra string) (string, error) {
+	kind, name, err := domain.ParseTargetWorkload(workload)
+	if err != nil {
+		return "", err
+	}
+
+	selector, err := e.podSelectorOf(ctx, namespace, kind, name)
+	if apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("%w: %s not found in %s", domain.ErrInvalidTargetWorkload, workload, namespace)
+	}
+	if err != nil {
+		return "", fmt.Errorf("get %s: %w", workload, err)
+	}
+
+	// An empty selector would match every pod in the namespace
+	if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+		return "", fmt.Errorf("%w: %s has an empty pod selector", domain.ErrInvalidTargetWorkload, workload)
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s selector: %v", domain.ErrInvalidTargetWorkload, workload, err)
+	}
+	if extra == "" {
+		return sel.String(), nil
+	}
+	return sel.String() + "," + extra, nil
+}
+
+// podSelectorOf fetches the pod selector from a workload's spec
+func (e *K8sEngine) podSelectorOf(ctx context.Context, namespace string, kind domain.WorkloadKind, name string) (*metav1.LabelSelector, error) {
+	apps := e.clientset.AppsV1()
+	switch kind {
+	case domain.WorkloadDeployment:
+		d, err := apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return d.Spec.Selector, nil
+	case domain.WorkloadStatefulSet:
+		s, err := apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return s.Spec.Selector, nil
+	case domain.WorkloadDaemonSet:
+		d, err := apps.DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return d.Spec.Selector, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported kind %q", domain.ErrInvalidTargetWorkload, kind)
+}
+
+// validatePodBlastRadius checks the affected pods against every pod in the
+// namespace, weighing them by criticality when the config opts in
+func validatePodBlastRadius(affected, all []corev1.Pod, cfg *domain.ExperimentConfig) error {
+	if cfg != nil && cfg.Safety.CriticalityWeighting {
+		err := safety.ValidateWeightedBlastRadius(podCriticalities(affected), podCriticalities(all),
+			cfg.Safety.CriticalityWeightTable(), maxBlastRadius(cfg))
+		if err != nil {
+			return fmt.Errorf("%w: %d/%d pods, weighted by criticality", err, len(affected), len(all))
+		}
+		return nil
+	}
+	if err := safety.ValidateBlastRadius(len(affected), len(all), maxBlastRadius(cfg)); err != nil {
+		return fmt.Errorf("%w: %d/%d pods", err, len(affected), len(all))
+	}
+	return nil
+}
+
+// podCriticalities returns the CriticalityLabel value of each pod
+func podCriticalities(pods []corev1.Pod) []string {
+	levels := make([]string, len(pods))
+	for i, p := range pods {
+		levels[i] = p.Labels[domain.CriticalityLabel]
+	}
+	return levels
+}
+
+func maxBlastRadius(cfg *domain.ExperimentConfig) float64 {
+	if cfg == nil {
+		return 0.3
+	}
+	return cfg.Safety.MaxBlastRadius
+}
+
+// dryRunPreview builds the result returned by K8s chaos methods in dry-run mode:
+// the pods that would be affected and the computed blast radius
+func dryRunPreview(action string, podNames []string, total int, cfg *domain.ExperimentConfig, extra map[string]any) map[string]any {
+	ratio := 0.0
+	if total > 0 {
+		ratio = float64(len(podNames)) / float64(total)
+	}
+	result := map[string]any{
+		"action":  action,
+		"pods":    podNames,
+		"dry_run": true,
+		"would_affect": map[string]any{
+			"count": len(podNames),
+			"names": podNames,
+		},
+		"blast_radius": map[string]any{
+			"affected":  len(podNames),
+			"total":     total,
+			"ratio":     ratio,
+			"max_ratio": maxBlastRadius(cfg),
+		},
+	}
+	for k, v := range extra {
+		result[k] = v
+	}
+	return result
+}
+
 func podNameList(pods *corev1.PodList) []string {
 	names := make([]string, 0, len(pods.Items))
 	for _, p := range pods.Items {
@@ -455,18 +1460,328 @@ func podNameListFromPods(pods []corev1.Pod) []string {
 	return names
 }
 
-func buildPodRollback(clientset kubernetes.Interface, namespace string, pods []corev1.Pod) domain.RollbackFunc {
-	return func() (map[string]any, error) {
-		rbCtx := context.Background()
+// execOnPods runs command in every pod concurrently and returns the pods it
+// succeeded in
+func (e *K8sEngine) execOnPods(ctx context.Context, namespace string, pods []corev1.Pod, command []string) ([]corev1.Pod, error) {
+	return mutatePods(ctx, pods, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+		_, err := e.execInPod(ctx, namespace, pod.Name, command)
+		return err
+	})
+}
+
+// annotatePods stamps ExperimentAnnotation on every pod before injection and
+// returns a func that removes it again. Without an experiment ID in ctx both
+// are no-ops. Failures are logged and never block the injection.
+func (e *K8sEngine) annotatePods(ctx context.Context, namespace string, pods []corev1.Pod) func(context.Context) {
+	experimentID := experimentIDFrom(ctx)
+	if experimentID == "" {
+		return func(context.Context) {}
+	}
+	annotated, err := mutatePods(ctx, pods, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+		return e.updatePodAnnotations(ctx, namespace, pod.Name, func(a map[string]string) map[string]string {
+			if a == nil {
+				a = map[string]string{}
+			}
+			a[ExperimentAnnotation] = experimentID
+			return a
+		})
+	})
+	if err != nil {
+		log.Printf("Annotate pods with experiment %s: %v", experimentID, err)
+	}
+	return func(ctx context.Context) {
+		_, err := mutatePods(ctx, annotated, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+			err := e.updatePodAnnotations(ctx, namespace, pod.Name, func(a map[string]string) map[string]string {
+				// Leave the annotation alone if another experiment took the pod over
+				if a[ExperimentAnnotation] == experimentID {
+					delete(a, ExperimentAnnotation)
+				}
+				return a
+			})
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			log.Printf("Remove experiment %s annotation: %v", experimentID, err)
+		}
+	}
+}
+
+// updatePodAnnotations applies mutate to a fresh copy of the pod's
+// annotations, retrying when the update loses a race with another writer
+func (e *K8sEngine) updatePodAnnotations(ctx context.Context, namespace, podName string, mutate func(map[string]string) map[string]string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pod, err := e.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		pod.Annotations = mutate(pod.Annotations)
+		_, err = e.clientset.CoreV1().Pods(namespace).Update(ctx, pod, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// tcOnPods runs the tc command built by args in every pod against iface.
+// With iface "auto" each pod's default-route device is detected first. The
+// device used per pod is returned so the rollback can target the same one.
+func (e *K8sEngine) tcOnPods(ctx context.Context, namespace string, pods []corev1.Pod, iface string, args func(dev string) []string) ([]corev1.Pod, map[string]string, error) {
+	var mu sync.Mutex
+	devices := make(map[string]string, len(pods))
+	mutated, err := mutatePods(ctx, pods, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+		dev := iface
+		if iface == domain.AutoNetworkInterface {
+			detected, err := e.defaultRouteInterface(ctx, namespace, pod.Name)
+			if err != nil {
+				return err
+			}
+			dev = detected
+		}
+		if _, err := e.execInPod(ctx, namespace, pod.Name, args(dev)); err != nil {
+			return err
+		}
+		mu.Lock()
+		devices[pod.Name] = dev
+		mu.Unlock()
+		return nil
+	})
+	return mutated, devices, err
+}
+
+// removeQdisc deletes the root qdisc tcOnPods added to each pod's device
+func (e *K8sEngine) removeQdisc(ctx context.Context, namespace string, pods []corev1.Pod, devices map[string]string) ([]corev1.Pod, error) {
+	return mutatePods(ctx, pods, e.podConcurrency, func(ctx context.Context, pod corev1.Pod) error {
+		_, err := e.execInPod(ctx, namespace, pod.Name, []string{e.cmds.tc(), "qdisc", "del", "dev", devices[pod.Name], "root"})
+		return err
+	})
+}
+
+// defaultRouteInterface asks the pod which device routes to the internet
+func (e *K8sEngine) defaultRouteInterface(ctx context.Context, namespace, podName string) (string, error) {
+	out, err := e.execInPod(ctx, namespace, podName, []string{"ip", "route", "get", "1.1.1.1"})
+	if err != nil {
+		return "", fmt.Errorf("detect interface: %w", err)
+	}
+	dev := parseRouteDevice(out)
+	if dev == "" {
+		return "", fmt.Errorf("detect interface: no device in %q", strings.TrimSpace(out))
+	}
+	return dev, nil
+}
+
+// parseRouteDevice extracts the device from `ip route get` output such as
+// "1.1.1.1 via 10.0.0.1 dev eth0 src 10.0.0.5 uid 0"
+func parseRouteDevice(out string) string {
+	fields := strings.Fields(out)
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == "dev" {
+			return fields[i+1]
+		}
+	}
+	return ""
+}
+
+// mutatePods applies fn to every pod with at most concurrency calls in
+// flight, so a multi-pod fault lands (nearly) simultaneously. It returns the
+// pods fn succeeded on, in their original order, and the per-pod errors
+// joined together.
+func mutatePods(ctx context.Context, pods []corev1.Pod, concurrency int, fn func(context.Context, corev1.Pod) error) ([]corev1.Pod, error) {
+	if concurrency < 1 {
+		concurrency = DefaultPodConcurrency
+	}
+
+	errs := make([]error, len(pods))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, pod := range pods {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, pod); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", pod.Name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mutated := make([]corev1.Pod, 0, len(pods))
+	for i, pod := range pods {
+		if errs[i] == nil {
+			mutated = append(mutated, pod)
+		}
+	}
+	return mutated, errors.Join(errs...)
+}
+
+// unmutatedPodNames returns the names of pods missing from mutated
+func unmutatedPodNames(pods, mutated []corev1.Pod) []string {
+	done := make(map[string]bool, len(mutated))
+	for _, p := range mutated {
+		done[p.Name] = true
+	}
+	names := []string{}
+	for _, p := range pods {
+		if !done[p.Name] {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// buildPodRollback recreates deleted standalone pods. Controller-owned pods
+// are recreated by their controller, so recreating them here would collide
+// with (or duplicate) the controller's replacement; for those the rollback
+// only checks that each owning ReplicaSet/StatefulSet is back at its desired
+// replica count. With a readyWait the rollback then waits for the recreated
+// pods to become ready and reports their readiness.
+func buildPodRollback(clientset kubernetes.Interface, namespace string, pods []corev1.Pod, readyWait time.Duration) domain.RollbackFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		recreated := 0
+		var recreatedNames []string
+		owners := map[string]metav1.OwnerReference{}
 		for _, pod := range pods {
+			if ref := metav1.GetControllerOf(&pod); ref != nil {
+				owners[ref.Kind+"/"+ref.Name] = *ref
+				continue
+			}
 			pod.ResourceVersion = ""
 			pod.Status = corev1.PodStatus{}
 			pod.UID = ""
-			if _, err := clientset.CoreV1().Pods(namespace).Create(rbCtx, &pod, metav1.CreateOptions{}); err != nil {
+			_, err := clientset.CoreV1().Pods(namespace).Create(ctx, &pod, metav1.CreateOptions{})
+			switch {
+			case apierrors.IsAlreadyExists(err):
+				log.Printf("Rollback: pod %s already exists, skipping", pod.Name)
+			case err != nil:
 				log.Printf("Rollback: failed to recreate pod %s: %v", pod.Name, err)
+			default:
+				recreated++
+				recreatedNames = append(recreatedNames, pod.Name)
+			}
+		}
+
+		controllers := make([]map[string]any, 0, len(owners))
+		for _, ref := range owners {
+			controllers = append(controllers, controllerReplicaStatus(ctx, clientset, namespace, ref))
+		}
+		log.Printf("Rollback: recreated %d pods in %s, %d controller(s) own the rest", recreated, namespace, len(controllers))
+		result := map[string]any{"recreated": recreated, "controllers": controllers}
+		if readyWait > 0 && len(recreatedNames) > 0 {
+			readiness, allReady := waitPodsReady(ctx, clientset, namespace, recreatedNames, readyWait)
+			result["readiness"] = readiness
+			result["all_ready"] = allReady
+		}
+		return result, nil
+	}
+}
+
+// podReadyPollInterval is how often waitPodsReady checks the recreated pods
+var podReadyPollInterval = time.Second
+
+// podReadyReserve is kept free before the rollback deadline so the readiness
+// report is returned before the rollback itself times out
+const podReadyReserve = time.Second
+
+// waitPodsReady polls the named pods until all are Running and Ready or wait
+// elapses, whichever comes first. It returns each pod's last seen phase and
+// readiness, and whether all of them became ready.
+func waitPodsReady(ctx context.Context, clientset kubernetes.Interface, namespace string, names []string, wait time.Duration) (map[string]any, bool) {
+	if deadline, ok := ctx.Deadline(); ok {
+		wait = min(wait, time.Until(deadline)-podReadyReserve)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, max(wait, 0))
+	defer cancel()
+
+	readiness := make(map[string]any, len(names))
+	ticker := time.NewTicker(podReadyPollInterval)
+	defer ticker.Stop()
+	for {
+		allReady := true
+		for _, name := range names {
+			if status, ok := readiness[name].(map[string]any); ok && status["ready"] == true {
+				continue
 			}
+			status := map[string]any{"ready": false}
+			pod, err := clientset.CoreV1().Pods(namespace).Get(waitCtx, name, metav1.GetOptions{})
+			if err != nil {
+				status["error"] = err.Error()
+			} else {
+				status["phase"] = string(pod.Status.Phase)
+				status["ready"] = podReady(pod)
+			}
+			readiness[name] = status
+			if status["ready"] != true {
+				allReady = false
+			}
+		}
+		if allReady {
+			log.Printf("Rollback: %d recreated pod(s) ready in %s", len(names), namespace)
+			return readiness, true
+		}
+		select {
+		case <-waitCtx.Done():
+			log.Printf("Rollback: recreated pods in %s not ready after %s", namespace, wait)
+			return readiness, false
+		case <-ticker.C:
+		}
+	}
+}
+
+// podReady reports whether pod is Running with its Ready condition true
+func podReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
 		}
-		log.Printf("Rollback: recreated %d pods in %s", len(pods), namespace)
-		return map[string]any{"recreated": len(pods)}, nil
 	}
+	return false
+}
+
+// controllerReplicaStatus reports whether the controller behind ref has
+// restored its desired replica count
+func controllerReplicaStatus(ctx context.Context, clientset kubernetes.Interface, namespace string, ref metav1.OwnerReference) map[string]any {
+	status := map[string]any{"kind": ref.Kind, "name": ref.Name}
+
+	var desired, current int32
+	switch ref.Kind {
+	case "ReplicaSet":
+		rs, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			status["error"] = err.Error()
+			return status
+		}
+		desired, current = ptrInt32(rs.Spec.Replicas), rs.Status.Replicas
+	case "StatefulSet":
+		sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			status["error"] = err.Error()
+			return status
+		}
+		desired, current = ptrInt32(sts.Spec.Replicas), sts.Status.Replicas
+	default:
+		// Other controllers (DaemonSet, Job, ...) manage their own pods
+		status["note"] = "replica count not verified for this kind"
+		return status
+	}
+
+	status["desired"] = desired
+	status["current"] = current
+	status["restored"] = current >= desired
+	if current < desired {
+		log.Printf("Rollback: %s %s has %d/%d replicas", ref.Kind, ref.Name, current, desired)
+	}
+	return status
+}
+
+// ptrInt32 dereferences a replica count, defaulting to 1 like the API server
+func ptrInt32(p *int32) int32 {
+	if p == nil {
+		return 1
+	}
+	return *p
 }
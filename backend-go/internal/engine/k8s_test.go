@@ -0,0 +1,1673 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+func testPod(name, namespace string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func newTestK8sEngine(objects ...runtime.Object) *K8sEngine {
+	return &K8sEngine{
+		clientset: fake.NewSimpleClientset(objects...),
+		esm:       safety.NewEmergencyStopManager(),
+	}
+}
+
+func dryRunConfig(maxBlastRadius float64) *domain.ExperimentConfig {
+	return &domain.ExperimentConfig{
+		Name:      "preview",
+		ChaosType: domain.ChaosTypePodDelete,
+		Safety:    domain.SafetyConfig{DryRun: true, MaxBlastRadius: maxBlastRadius},
+	}
+}
+
+func TestPodDeleteDryRunPreview(t *testing.T) {
+	e := newTestK8sEngine(
+		testPod("web-1", "default", map[string]string{"app": "web"}),
+		testPod("api-1", "default", map[string]string{"app": "api"}),
+		testPod("api-2", "default", map[string]string{"app": "api"}),
+		testPod("db-1", "default", map[string]string{"app": "db"}),
+	)
+
+	res, err := e.PodDelete(context.Background(), "default", "app=web", nil, dryRunConfig(0.3))
+	require.NoError(t, err)
+
+	assert.Equal(t, true, res.Result["dry_run"])
+	assert.Nil(t, res.RollbackFn)
+	wouldAffect := res.Result["would_affect"].(map[string]any)
+	assert.Equal(t, 1, wouldAffect["count"])
+	assert.Equal(t, []string{"web-1"}, wouldAffect["names"])
+	blast := res.Result["blast_radius"].(map[string]any)
+	assert.Equal(t, 4, blast["total"])
+	assert.Equal(t, 0.25, blast["ratio"])
+
+	// Nothing was deleted
+	pods, err := e.clientset.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pods.Items, 4)
+}
+
+func TestPodDeleteDryRunReportsBlastRadiusError(t *testing.T) {
+	e := newTestK8sEngine(
+		testPod("api-1", "default", map[string]string{"app": "api"}),
+		testPod("api-2", "default", map[string]string{"app": "api"}),
+		testPod("db-1", "default", map[string]string{"app": "db"}),
+	)
+
+	res, err := e.PodDelete(context.Background(), "default", "app=api", nil, dryRunConfig(0.3))
+	assert.ErrorIs(t, err, domain.ErrBlastRadiusExceeded)
+	require.NotNil(t, res, "dry run should still return the preview")
+	wouldAffect := res.Result["would_affect"].(map[string]any)
+	assert.Equal(t, 2, wouldAffect["count"])
+
+	pods, err := e.clientset.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pods.Items, 3)
+}
+
+func TestPodDeleteCriticalityWeightedBlastRadius(t *testing.T) {
+	crit := func(app, level string) map[string]string {
+		return map[string]string{"app": app, domain.CriticalityLabel: level}
+	}
+	objects := func() []runtime.Object {
+		return []runtime.Object{
+			testPod("db-1", "default", crit("db", "high")),
+			testPod("db-2", "default", crit("db", "high")),
+			testPod("db-3", "default", crit("db", "high")),
+			testPod("web-1", "default", crit("web", "low")),
+			testPod("web-2", "default", crit("web", "low")),
+			testPod("web-3", "default", crit("web", "low")),
+			testPod("web-4", "default", crit("web", "low")),
+			testPod("web-5", "default", crit("web", "low")),
+			testPod("web-6", "default", crit("web", "low")),
+		}
+	}
+
+	// Six of nine pods is well over a flat 35%...
+	_, err := newTestK8sEngine(objects()...).PodDelete(context.Background(), "default", "app=web", nil, dryRunConfig(0.35))
+	assert.ErrorIs(t, err, domain.ErrBlastRadiusExceeded)
+
+	// ...but the stateless tier is only a third of the weighted total
+	weighted := dryRunConfig(0.35)
+	weighted.Safety.CriticalityWeighting = true
+	_, err = newTestK8sEngine(objects()...).PodDelete(context.Background(), "default", "app=web", nil, weighted)
+	assert.NoError(t, err)
+
+	// Half as many database pods weigh two thirds of the total
+	_, err = newTestK8sEngine(objects()...).PodDelete(context.Background(), "default", "app=db", nil, weighted)
+	assert.ErrorIs(t, err, domain.ErrBlastRadiusExceeded)
+	assert.Contains(t, err.Error(), "weighted by criticality")
+
+	// Flattening the weights brings back the plain ratio
+	weighted.Safety.CriticalityWeights = map[string]float64{"high": 1}
+	_, err = newTestK8sEngine(objects()...).PodDelete(context.Background(), "default", "app=web", nil, weighted)
+	assert.ErrorIs(t, err, domain.ErrBlastRadiusExceeded)
+}
+
+func TestListTargetsResolvesWorkloadSelector(t *testing.T) {
+	replicas := int32(2)
+	e := newTestK8sEngine(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web", "tier": "frontend"}},
+			},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+			Spec: appsv1.StatefulSetSpec{
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"db"}},
+				}},
+			},
+		},
+		testPod("web-1", "shop", map[string]string{"app": "web", "tier": "frontend", "track": "stable"}),
+		testPod("web-2", "shop", map[string]string{"app": "web", "tier": "frontend", "track": "canary"}),
+		// Shares app=web but is not part of the deployment
+		testPod("web-admin", "shop", map[string]string{"app": "web", "tier": "admin"}),
+		testPod("db-0", "shop", map[string]string{"app": "db"}),
+	)
+	target := func(workload string, labels map[string]string) ([]string, error) {
+		cfg := &domain.ExperimentConfig{TargetWorkload: &workload}
+		pods, _, err := e.listTargets(context.Background(), "shop", domain.LabelSelectorString(labels), cfg)
+		if err != nil {
+			return nil, err
+		}
+		return podNameList(pods), nil
+	}
+
+	names, err := target("deployment/web", nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"web-1", "web-2"}, names)
+
+	// Target labels narrow the workload's pods further
+	names, err = target("deployment/web", map[string]string{"track": "canary"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web-2"}, names)
+
+	names, err = target("statefulset/db", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db-0"}, names)
+
+	_, err = target("daemonset/agent", nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidTargetWorkload)
+	assert.Contains(t, err.Error(), "not found")
+
+	_, err = target("replicaset/web", nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidTargetWorkload)
+}
+
+func TestPodDeleteTargetsWorkloadPods(t *testing.T) {
+	e := newTestK8sEngine(
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+			Spec: appsv1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}},
+			},
+		},
+		testPod("agent-a", "default", map[string]string{"app": "agent"}),
+		testPod("api-1", "default", map[string]string{"app": "api"}),
+	)
+	workload := "daemonset/agent"
+	cfg := &domain.ExperimentConfig{TargetWorkload: &workload, Safety: domain.SafetyConfig{MaxBlastRadius: 1}}
+
+	res, err := e.PodDelete(context.Background(), "default", "", nil, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agent-a"}, res.Result["pods"])
+
+	pods, err := e.clientset.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api-1"}, podNameList(pods))
+}
+
+func TestNetworkLatencyBlastRadiusEnforced(t *testing.T) {
+	e := newTestK8sEngine(
+		testPod("api-1", "default", map[string]string{"app": "api"}),
+		testPod("api-2", "default", map[string]string{"app": "api"}),
+	)
+	cfg := dryRunConfig(0.3)
+	cfg.Safety.DryRun = false
+
+	res, err := e.NetworkLatency(context.Background(), "default", "app=api", domain.NetemDelay{LatencyMs: 100}, "eth0", cfg)
+	assert.ErrorIs(t, err, domain.ErrBlastRadiusExceeded)
+	assert.Nil(t, res)
+}
+
+func TestRunnerDryRunCollectsErrors(t *testing.T) {
+	e := newTestK8sEngine(
+		testPod("api-1", "production", map[string]string{"app": "api"}),
+		testPod("api-2", "production", map[string]string{"app": "api"}),
+	)
+	runner := NewRunner(e, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, nil, "",
+	)
+
+	ns := "production"
+	cfg := domain.ExperimentConfig{
+		Name:            "prod-preview",
+		ChaosType:       domain.ChaosTypePodDelete,
+		TargetNamespace: &ns,
+		TargetLabels:    map[string]string{"app": "api"},
+		Safety:          domain.SafetyConfig{MaxBlastRadius: 0.3},
+	}
+
+	result, errs := runner.DryRun(context.Background(), "dry-test", cfg)
+	require.Len(t, errs, 2)
+	assert.ErrorIs(t, errs[0], domain.ErrNamespaceConfirmation)
+	assert.ErrorIs(t, errs[1], domain.ErrBlastRadiusExceeded)
+	assert.Equal(t, domain.StatusFailed, result.Status)
+	assert.True(t, result.Config.Safety.DryRun)
+
+	wouldAffect := result.InjectionResult["would_affect"].(map[string]any)
+	assert.Equal(t, 2, wouldAffect["count"])
+}
+
+func TestRunnerDryRunEmergencyStop(t *testing.T) {
+	esm := safety.NewEmergencyStopManager()
+	esm.Trigger()
+	runner := NewRunner(newTestK8sEngine(), nil, esm,
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, nil, "",
+	)
+
+	_, errs := runner.DryRun(context.Background(), "dry-test", domain.ExperimentConfig{
+		Name: "stopped", ChaosType: domain.ChaosTypePodDelete,
+	})
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], domain.ErrEmergencyStop)
+}
+
+func TestRunnerRejectsFrozenNamespace(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "payments", map[string]string{"app": "web"}))
+	runner := NewRunner(e, nil, safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, nil, "",
+	)
+	freezes := safety.NewNamespaceFreezeManager(nil)
+	_, err := freezes.Freeze(context.Background(), "pay*", "incident")
+	require.NoError(t, err)
+	runner.SetFreezeManager(freezes)
+
+	ns := "payments"
+	cfg := domain.ExperimentConfig{
+		Name: "frozen", ChaosType: domain.ChaosTypePodDelete, TargetNamespace: &ns,
+		Safety: domain.SafetyConfig{MaxBlastRadius: 1, TimeoutSeconds: 30},
+	}
+	result, err := runner.Run(context.Background(), "frozen-1", cfg)
+	assert.ErrorIs(t, err, domain.ErrNamespaceFrozen)
+	assert.Nil(t, result)
+
+	pods, err := e.clientset.CoreV1().Pods("payments").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pods.Items, 1)
+
+	_, errs := runner.DryRun(context.Background(), "dry-frozen", cfg)
+	require.NotEmpty(t, errs)
+	assert.ErrorIs(t, errs[0], domain.ErrNamespaceFrozen)
+}
+
+func TestRunnerChaosTypeAllowlist(t *testing.T) {
+	newRunner := func() *Runner {
+		e := newTestK8sEngine(testPod("web-1", "shop", map[string]string{"app": "web"}))
+		return NewRunner(e, nil, safety.NewEmergencyStopManager(),
+			safety.NewRollbackManager(),
+			safety.NewSnapshotManager(nil),
+			nil, nil, "",
+		)
+	}
+	ns := "shop"
+	cfg := func(ct domain.ChaosType) domain.ExperimentConfig {
+		return domain.ExperimentConfig{
+			Name: "allowlist", ChaosType: ct, TargetNamespace: &ns,
+			Safety: domain.SafetyConfig{MaxBlastRadius: 1, TimeoutSeconds: 30},
+		}
+	}
+
+	runner := newRunner()
+	runner.SetAllowedChaosTypes([]string{"network_latency", "network_loss", "fog"})
+
+	result, err := runner.Run(context.Background(), "allow-1", cfg(domain.ChaosTypePodDelete))
+	assert.ErrorIs(t, err, domain.ErrChaosTypeNotAllowed)
+	assert.Nil(t, result)
+
+	_, errs := runner.DryRun(context.Background(), "dry-allow-1", cfg(domain.ChaosTypePodDelete))
+	require.NotEmpty(t, errs)
+	assert.ErrorIs(t, errs[0], domain.ErrChaosTypeNotAllowed)
+
+	_, errs = runner.DryRun(context.Background(), "dry-allow-2", cfg(domain.ChaosTypeNetworkLatency))
+	for _, err := range errs {
+		assert.NotErrorIs(t, err, domain.ErrChaosTypeNotAllowed)
+	}
+
+	// An empty list allows everything
+	runner = newRunner()
+	runner.SetAllowedChaosTypes(nil)
+	assert.NoError(t, runner.checkAllowed(cfg(domain.ChaosTypePodDelete)))
+
+	// A list of only unknown types allows nothing
+	runner.SetAllowedChaosTypes([]string{"fog"})
+	assert.ErrorIs(t, runner.checkAllowed(cfg(domain.ChaosTypeNetworkLatency)), domain.ErrChaosTypeNotAllowed)
+}
+
+func TestPodDeleteTargetResource(t *testing.T) {
+	e := newTestK8sEngine(
+		testPod("worker-1", "default", map[string]string{"app": "worker"}),
+		testPod("worker-2", "default", map[string]string{"app": "worker"}),
+		testPod("worker-3", "default", map[string]string{"app": "worker"}),
+		testPod("worker-4", "default", map[string]string{"app": "worker"}),
+	)
+	target := "pod/worker-2"
+	cfg := &domain.ExperimentConfig{
+		Name:           "single-pod",
+		ChaosType:      domain.ChaosTypePodDelete,
+		TargetResource: &target,
+		Safety:         domain.SafetyConfig{MaxBlastRadius: 0.3},
+	}
+
+	res, err := e.PodDelete(context.Background(), "default", "", nil, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"worker-2"}, res.Result["pods"])
+
+	pods, err := e.clientset.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pods.Items, 3)
+	for _, p := range pods.Items {
+		assert.NotEqual(t, "worker-2", p.Name)
+	}
+}
+
+func TestTargetResourcePreferredOverLabels(t *testing.T) {
+	e := newTestK8sEngine(
+		testPod("web-1", "default", map[string]string{"app": "web"}),
+		testPod("web-2", "default", map[string]string{"app": "web"}),
+		testPod("worker-1", "default", map[string]string{"app": "worker"}),
+		testPod("worker-2", "default", map[string]string{"app": "worker"}),
+	)
+	target := "pod/worker-1"
+	cfg := dryRunConfig(0.3)
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.TargetResource = &target
+
+	res, err := e.PodDelete(context.Background(), "default", domain.LabelSelectorString(cfg.TargetLabels), nil, cfg)
+	require.NoError(t, err)
+	wouldAffect := res.Result["would_affect"].(map[string]any)
+	assert.Equal(t, []string{"worker-1"}, wouldAffect["names"])
+	blast := res.Result["blast_radius"].(map[string]any)
+	assert.Equal(t, 0.25, blast["ratio"])
+}
+
+func TestTargetResourceUnsupportedKind(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", nil))
+	target := "deployment/web"
+	cfg := dryRunConfig(1.0)
+	cfg.TargetResource = &target
+
+	_, err := e.PodDelete(context.Background(), "default", "", nil, cfg)
+	assert.ErrorIs(t, err, domain.ErrInvalidTargetResource)
+}
+
+// filterPodsByField makes the fake clientset honour pod field selectors on
+// spec.nodeName and status.phase, as the API server does
+func filterPodsByField(e *K8sEngine) {
+	cs := e.clientset.(*fake.Clientset)
+	cs.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sel := action.(k8stesting.ListAction).GetListRestrictions().Fields
+		if sel == nil || sel.Empty() {
+			return false, nil, nil
+		}
+		obj, err := cs.Tracker().List(corev1.SchemeGroupVersion.WithResource("pods"),
+			corev1.SchemeGroupVersion.WithKind("Pod"), action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		list := obj.(*corev1.PodList)
+		matched := &corev1.PodList{}
+		for _, p := range list.Items {
+			if sel.Matches(fields.Set{"spec.nodeName": p.Spec.NodeName, "status.phase": string(p.Status.Phase)}) {
+				matched.Items = append(matched.Items, p)
+			}
+		}
+		return true, matched, nil
+	})
+}
+
+func TestFieldSelectorTargetsPodsOnNode(t *testing.T) {
+	var pods []runtime.Object
+	for i, node := range []string{"node-a", "node-a", "node-b", "node-b"} {
+		p := testPod(fmt.Sprintf("web-%d", i+1), "default", map[string]string{"app": "web"})
+		p.Spec.NodeName = node
+		pods = append(pods, p)
+	}
+	e := newTestK8sEngine(pods...)
+	filterPodsByField(e)
+	sel := "spec.nodeName=node-a"
+	cfg := &domain.ExperimentConfig{
+		Name:          "node-a",
+		ChaosType:     domain.ChaosTypePodDelete,
+		FieldSelector: &sel,
+		Safety:        domain.SafetyConfig{MaxBlastRadius: 0.5},
+	}
+
+	res, err := e.PodDelete(context.Background(), "default", "app=web", nil, cfg)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"web-1", "web-2"}, res.Result["pods"])
+
+	left, err := e.clientset.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"web-3", "web-4"}, podNameList(left))
+}
+
+func TestFieldSelectorRejectedBeforeListing(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", nil))
+	sel := "spec.node=node-a"
+	cfg := dryRunConfig(1.0)
+	cfg.FieldSelector = &sel
+
+	_, err := e.PodDelete(context.Background(), "default", "", nil, cfg)
+	assert.ErrorIs(t, err, domain.ErrInvalidConfig)
+}
+
+func TestGetTopologyIncludesNodes(t *testing.T) {
+	pod := testPod("web-1", "default", nil)
+	pod.Spec.NodeName = "ip-10-0-0-1"
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "ip-10-0-0-1"},
+		Spec:       corev1.NodeSpec{ProviderID: "aws:///us-east-1a/i-aaa"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalDNS, Address: "ip-10-0-0-1.ec2.internal"}},
+		},
+	}
+	e := newTestK8sEngine(pod, node)
+
+	topo, err := e.GetTopology(context.Background(), "default")
+	require.NoError(t, err)
+
+	var found *domain.TopologyNode
+	for i := range topo.Nodes {
+		if topo.Nodes[i].ID == "node/ip-10-0-0-1" {
+			found = &topo.Nodes[i]
+		}
+	}
+	require.NotNil(t, found)
+	assert.Equal(t, domain.ResourceNode, found.ResourceType)
+	assert.Equal(t, domain.HealthHealthy, found.Health)
+	assert.Equal(t, "aws:///us-east-1a/i-aaa", found.Metadata["provider_id"])
+	assert.Equal(t, "ip-10-0-0-1.ec2.internal", found.Metadata["internal_dns"])
+
+	assert.Contains(t, topo.Edges, domain.TopologyEdge{
+		Source: "pod/web-1", Target: "node/ip-10-0-0-1", Relation: "runs_on",
+	})
+}
+
+func routesTo(edges []domain.TopologyEdge, svc string) []string {
+	var pods []string
+	for _, e := range edges {
+		if e.Source == "svc/"+svc && e.Relation == "routes_to" {
+			pods = append(pods, e.Target)
+		}
+	}
+	return pods
+}
+
+func TestGetTopologyServiceRoutesToSelectedPods(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	}
+	headless := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-headless", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone, Selector: map[string]string{"app": "web", "tier": "canary"}},
+	}
+	e := newTestK8sEngine(svc, headless,
+		testPod("web-1", "default", map[string]string{"app": "web"}),
+		testPod("web-2", "default", map[string]string{"app": "web", "tier": "canary"}),
+		testPod("worker-1", "default", map[string]string{"app": "worker"}),
+	)
+
+	topo, err := e.GetTopology(context.Background(), "default")
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"pod/web-1", "pod/web-2"}, routesTo(topo.Edges, "web"))
+	assert.ElementsMatch(t, []string{"pod/web-2"}, routesTo(topo.Edges, "web-headless"))
+}
+
+func TestGetTopologySelectorlessService(t *testing.T) {
+	manual := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "default"}}
+	external := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "external-db", Namespace: "default"}}
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "legacy-abc",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "legacy"},
+		},
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.5"}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "worker-1"}},
+			{Addresses: []string{"10.0.9.9"}},
+		},
+	}
+	e := newTestK8sEngine(manual, external, slice,
+		testPod("worker-1", "default", map[string]string{"app": "worker"}),
+	)
+
+	topo, err := e.GetTopology(context.Background(), "default")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pod/worker-1"}, routesTo(topo.Edges, "legacy"))
+	assert.Empty(t, routesTo(topo.Edges, "external-db"))
+}
+
+func TestBuildProbesServiceExpectedValue(t *testing.T) {
+	ready := true
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-abc",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+		},
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.5"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+		},
+	}
+	runner := NewRunner(newTestK8sEngine(svc, slice), nil, safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, nil, "",
+	)
+	serviceProbe := func(name string, expected any) domain.ProbeConfig {
+		props := map[string]any{"namespace": "default", "resource_kind": "service", "resource_name": "web"}
+		if expected != nil {
+			props["expected_value"] = expected
+		}
+		return domain.ProbeConfig{Name: name, Type: domain.ProbeTypeK8s, Mode: domain.ProbeModeSOT, Properties: props}
+	}
+
+	probes := runner.buildProbes(domain.ExperimentConfig{Probes: []domain.ProbeConfig{
+		serviceProbe("two-ready", float64(2)),
+		serviceProbe("one-ready", "1"),
+		serviceProbe("default", nil),
+	}})
+	require.Len(t, probes, 3)
+
+	for i, want := range []bool{false, true, true} {
+		result, err := probes[i].Execute(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, want, result.Passed, probes[i].Name())
+		assert.Equal(t, 1, result.Detail["ready_addresses"])
+	}
+}
+
+// ownedPod returns a pod controlled by the named ReplicaSet
+func ownedPod(name, namespace, replicaSet string) *corev1.Pod {
+	pod := testPod(name, namespace, map[string]string{"app": "web"})
+	controller := true
+	pod.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "apps/v1",
+		Kind:       "ReplicaSet",
+		Name:       replicaSet,
+		Controller: &controller,
+	}}
+	return pod
+}
+
+func TestPodRollbackSkipsControllerOwnedPods(t *testing.T) {
+	ctx := context.Background()
+	replicas := int32(2)
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "default"},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+		Status:     appsv1.ReplicaSetStatus{Replicas: 2},
+	}
+	pod := ownedPod("web-abc-1", "default", "web-abc")
+	e := newTestK8sEngine(rs, pod)
+
+	rollback := buildPodRollback(e.clientset, "default", []corev1.Pod{*pod}, 0)
+	require.NoError(t, e.clientset.CoreV1().Pods("default").Delete(ctx, pod.Name, metav1.DeleteOptions{}))
+
+	out, err := rollback(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, out["recreated"])
+
+	// The pod is left to the ReplicaSet rather than recreated as a zombie
+	_, err = e.clientset.CoreV1().Pods("default").Get(ctx, pod.Name, metav1.GetOptions{})
+	assert.Error(t, err)
+
+	controllers := out["controllers"].([]map[string]any)
+	require.Len(t, controllers, 1)
+	assert.Equal(t, "ReplicaSet", controllers[0]["kind"])
+	assert.Equal(t, "web-abc", controllers[0]["name"])
+	assert.Equal(t, true, controllers[0]["restored"])
+}
+
+func TestPodRollbackReportsUnrestoredReplicaSet(t *testing.T) {
+	replicas := int32(3)
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "default"},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+		Status:     appsv1.ReplicaSetStatus{Replicas: 2},
+	}
+	e := newTestK8sEngine(rs)
+
+	out, err := buildPodRollback(e.clientset, "default", []corev1.Pod{*ownedPod("web-abc-1", "default", "web-abc")}, 0)(context.Background())
+	require.NoError(t, err)
+	controllers := out["controllers"].([]map[string]any)
+	require.Len(t, controllers, 1)
+	assert.Equal(t, false, controllers[0]["restored"])
+	assert.Equal(t, int32(3), controllers[0]["desired"])
+	assert.Equal(t, int32(2), controllers[0]["current"])
+}
+
+func TestPodRollbackRecreatesStandalonePods(t *testing.T) {
+	ctx := context.Background()
+	standalone := testPod("debug", "default", map[string]string{"app": "debug"})
+	existing := testPod("batch", "default", map[string]string{"app": "batch"})
+	e := newTestK8sEngine(existing)
+
+	out, err := buildPodRollback(e.clientset, "default", []corev1.Pod{*standalone, *existing}, 0)(context.Background())
+	require.NoError(t, err)
+
+	// "batch" still exists, so only "debug" is created and no error is raised
+	assert.Equal(t, 1, out["recreated"])
+	assert.Empty(t, out["controllers"])
+	_, err = e.clientset.CoreV1().Pods("default").Get(ctx, "debug", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestPodRollbackWaitsForRecreatedPodReady(t *testing.T) {
+	defer func(d time.Duration) { podReadyPollInterval = d }(podReadyPollInterval)
+	podReadyPollInterval = 10 * time.Millisecond
+
+	ctx := context.Background()
+	e := newTestK8sEngine()
+	cs := e.clientset.(*fake.Clientset)
+	// The kubelet starts the recreated pod a little after it is created
+	cs.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		created := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod).DeepCopy()
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			created.Status = corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			}
+			_, _ = cs.CoreV1().Pods("default").UpdateStatus(ctx, created, metav1.UpdateOptions{})
+		}()
+		return false, nil, nil
+	})
+
+	start := time.Now()
+	out, err := buildPodRollback(e.clientset, "default", []corev1.Pod{*testPod("debug", "default", nil)}, 5*time.Second)(ctx)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second, "the wait ends once the pod is ready")
+	assert.Equal(t, 1, out["recreated"])
+	assert.Equal(t, true, out["all_ready"])
+	readiness := out["readiness"].(map[string]any)
+	assert.Equal(t, map[string]any{"phase": "Running", "ready": true}, readiness["debug"])
+}
+
+func TestPodRollbackReadyWaitTimesOut(t *testing.T) {
+	defer func(d time.Duration) { podReadyPollInterval = d }(podReadyPollInterval)
+	podReadyPollInterval = 10 * time.Millisecond
+
+	e := newTestK8sEngine()
+	out, err := buildPodRollback(e.clientset, "default", []corev1.Pod{*testPod("debug", "default", nil)}, 100*time.Millisecond)(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, false, out["all_ready"])
+	assert.Equal(t, false, out["readiness"].(map[string]any)["debug"].(map[string]any)["ready"])
+
+	// Without a wait the rollback returns as soon as the pod is recreated
+	require.NoError(t, e.clientset.CoreV1().Pods("default").Delete(context.Background(), "debug", metav1.DeleteOptions{}))
+	out, err = buildPodRollback(e.clientset, "default", []corev1.Pod{*testPod("debug", "default", nil)}, 0)(context.Background())
+	require.NoError(t, err)
+	assert.NotContains(t, out, "readiness")
+}
+
+func TestPodReadyWaitIsBoundedByRollbackTimeout(t *testing.T) {
+	defer func(d time.Duration) { podReadyPollInterval = d }(podReadyPollInterval)
+	podReadyPollInterval = 10 * time.Millisecond
+
+	e := newTestK8sEngine()
+	ctx, cancel := context.WithTimeout(context.Background(), podReadyReserve+200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	out, err := buildPodRollback(e.clientset, "default", []corev1.Pod{*testPod("debug", "default", nil)}, time.Minute)(ctx)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), podReadyReserve+time.Second)
+	assert.Equal(t, false, out["all_ready"])
+	require.NoError(t, ctx.Err(), "the report is returned before the rollback deadline")
+}
+
+func podsNamed(n int) []corev1.Pod {
+	pods := make([]corev1.Pod, n)
+	for i := range pods {
+		pods[i] = *testPod(fmt.Sprintf("pod-%d", i+1), "default", nil)
+	}
+	return pods
+}
+
+func TestMutatePodsBoundsConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	mutated, err := mutatePods(context.Background(), podsNamed(20), 4, func(context.Context, corev1.Pod) error {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		inFlight.Add(-1)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, mutated, 20)
+	assert.LessOrEqual(t, peak.Load(), int32(4))
+	assert.Greater(t, peak.Load(), int32(1), "pods should be mutated concurrently")
+}
+
+func TestMutatePodsAggregatesErrors(t *testing.T) {
+	pods := podsNamed(5)
+	mutated, err := mutatePods(context.Background(), pods, 0, func(_ context.Context, pod corev1.Pod) error {
+		if pod.Name == "pod-2" || pod.Name == "pod-5" {
+			return errors.New("exec failed")
+		}
+		return nil
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pod-2: exec failed")
+	assert.Contains(t, err.Error(), "pod-5: exec failed")
+	assert.Equal(t, []string{"pod-1", "pod-3", "pod-4"}, podNameListFromPods(mutated))
+	assert.Equal(t, []string{"pod-2", "pod-5"}, unmutatedPodNames(pods, mutated))
+}
+
+func TestPodDeletePartialFailureRollsBackDeletedPods(t *testing.T) {
+	ctx := context.Background()
+	web := map[string]string{"app": "web"}
+	e := newTestK8sEngine(testPod("web-1", "default", web), testPod("web-2", "default", web), testPod("web-3", "default", web))
+	e.clientset.(*fake.Clientset).PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.DeleteAction).GetName() == "web-2" {
+			return true, nil, errors.New("forbidden")
+		}
+		return false, nil, nil
+	})
+
+	res, err := e.PodDelete(ctx, "default", "app=web", nil, &domain.ExperimentConfig{
+		Name:      "partial",
+		ChaosType: domain.ChaosTypePodDelete,
+		Safety:    domain.SafetyConfig{MaxBlastRadius: 1},
+	})
+	require.Error(t, err)
+	require.NotNil(t, res)
+	assert.Equal(t, []string{"web-2"}, res.Result["failed_pods"])
+	assert.ElementsMatch(t, []string{"web-1", "web-3"}, res.Result["pods"])
+
+	out, err := res.RollbackFn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, out["recreated"])
+	for _, name := range []string{"web-1", "web-2", "web-3"} {
+		_, err := e.clientset.CoreV1().Pods("default").Get(ctx, name, metav1.GetOptions{})
+		assert.NoError(t, err, name)
+	}
+}
+
+// budgetedEvictions makes the fake clientset act like the API server's
+// eviction subresource guarded by a disruption budget of minAvailable pods:
+// evictions delete the pod until one more would breach the budget, which
+// is refused with 429
+func budgetedEvictions(cs *fake.Clientset, available, minAvailable int) {
+	cs.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		if available-1 < minAvailable {
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		}
+		available--
+		return true, nil, cs.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), eviction.Namespace, eviction.Name)
+	})
+}
+
+func TestPodEvictHonorsDisruptionBudget(t *testing.T) {
+	ctx := context.Background()
+	web := map[string]string{"app": "web"}
+	e := newTestK8sEngine(testPod("web-1", "default", web), testPod("web-2", "default", web), testPod("web-3", "default", web))
+	budgetedEvictions(e.clientset.(*fake.Clientset), 3, 2)
+
+	res, err := e.PodEvict(ctx, "default", "app=web", &domain.ExperimentConfig{
+		Name:      "evict",
+		ChaosType: domain.ChaosTypePodEvict,
+		Safety:    domain.SafetyConfig{MaxBlastRadius: 1},
+	})
+	require.NoError(t, err)
+	evicted := res.Result["pods"].([]string)
+	blocked := res.Result["blocked_pods"].(map[string]string)
+	require.Len(t, evicted, 1)
+	assert.Len(t, blocked, 2)
+	for name, reason := range blocked {
+		assert.NotContains(t, evicted, name)
+		assert.Contains(t, reason, "disruption budget")
+	}
+	assert.Nil(t, res.Result["failed_pods"])
+
+	pods, err := e.clientset.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pods.Items, 2)
+
+	out, err := res.RollbackFn(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, out["note"], "nothing to undo")
+}
+
+func TestPodEvictFullyBlockedIsNotAnError(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	e := newTestK8sEngine(testPod("web-1", "default", web))
+	budgetedEvictions(e.clientset.(*fake.Clientset), 1, 1)
+
+	res, err := e.PodEvict(context.Background(), "default", "app=web", &domain.ExperimentConfig{
+		Name:      "evict",
+		ChaosType: domain.ChaosTypePodEvict,
+		Safety:    domain.SafetyConfig{MaxBlastRadius: 1},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, res.Result["pods"])
+	assert.Contains(t, res.Result["blocked_pods"], "web-1")
+}
+
+func TestPodEvictReportsFailedPods(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	e := newTestK8sEngine(testPod("web-1", "default", web), testPod("web-2", "default", web))
+	cs := e.clientset.(*fake.Clientset)
+	budgetedEvictions(cs, 2, 0)
+	cs.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		eviction, ok := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		if ok && eviction.Name == "web-2" {
+			return true, nil, errors.New("forbidden")
+		}
+		return false, nil, nil
+	})
+
+	res, err := e.PodEvict(context.Background(), "default", "app=web", &domain.ExperimentConfig{
+		Name:      "evict",
+		ChaosType: domain.ChaosTypePodEvict,
+		Safety:    domain.SafetyConfig{MaxBlastRadius: 1},
+	})
+	require.Error(t, err)
+	require.NotNil(t, res)
+	assert.Equal(t, []string{"web-1"}, res.Result["pods"])
+	assert.Equal(t, []string{"web-2"}, res.Result["failed_pods"])
+}
+
+func TestPodEvictRunsThroughThePodPool(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	objs := []runtime.Object{}
+	for i := 1; i <= 8; i++ {
+		objs = append(objs, testPod(fmt.Sprintf("web-%d", i), "default", web))
+	}
+	e := newTestK8sEngine(objs...)
+	e.SetPodConcurrency(3)
+	budgetedEvictions(e.clientset.(*fake.Clientset), 8, 0)
+
+	res, err := e.PodEvict(context.Background(), "default", "app=web", &domain.ExperimentConfig{
+		Name:      "evict",
+		ChaosType: domain.ChaosTypePodEvict,
+		Safety:    domain.SafetyConfig{MaxBlastRadius: 1},
+	})
+	require.NoError(t, err)
+	// Concurrent evictions still report pods in target order
+	assert.Equal(t, []string{"web-1", "web-2", "web-3", "web-4", "web-5", "web-6", "web-7", "web-8"}, res.Result["pods"])
+	assert.Empty(t, res.Result["blocked_pods"])
+}
+
+func TestPodDeletePassesGracePeriod(t *testing.T) {
+	zero, long := int64(0), int64(120)
+	tests := []struct {
+		name  string
+		grace *int64
+	}{
+		{"pod default", nil},
+		{"abrupt", &zero},
+		{"extended", &long},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+			var got []metav1.DeleteOptions
+			e.clientset.(*fake.Clientset).PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				got = append(got, action.(k8stesting.DeleteAction).GetDeleteOptions())
+				return false, nil, nil
+			})
+
+			res, err := e.PodDelete(context.Background(), "default", "app=web", tt.grace, &domain.ExperimentConfig{
+				Name:      "grace",
+				ChaosType: domain.ChaosTypePodDelete,
+				Safety:    domain.SafetyConfig{MaxBlastRadius: 1},
+			})
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, tt.grace, got[0].GracePeriodSeconds)
+			if tt.grace == nil {
+				assert.NotContains(t, res.Result, "grace_period_seconds")
+			} else {
+				assert.Equal(t, *tt.grace, res.Result["grace_period_seconds"])
+			}
+		})
+	}
+}
+
+func TestListRetriesTransientErrors(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	e.SetAPIRetry(3, time.Millisecond)
+	calls := 0
+	e.clientset.(*fake.Clientset).PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		if calls == 1 {
+			return true, nil, apierrors.NewTooManyRequests("slow down", 1)
+		}
+		return false, nil, nil
+	})
+
+	state, err := e.GetSteadyState(context.Background(), "default")
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 1, state["pods_total"])
+}
+
+func TestListRetryIsBounded(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", nil))
+	e.SetAPIRetry(3, time.Millisecond)
+	calls := 0
+	e.clientset.(*fake.Clientset).PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		return true, nil, apierrors.NewServiceUnavailable("apiserver restarting")
+	})
+
+	_, err := e.GetSteadyState(context.Background(), "default")
+	require.Error(t, err)
+	assert.True(t, apierrors.IsServiceUnavailable(err))
+	assert.Equal(t, 3, calls)
+}
+
+func TestListDoesNotRetryPermanentErrors(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", nil))
+	e.SetAPIRetry(3, time.Millisecond)
+	calls := 0
+	e.clientset.(*fake.Clientset).PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		return true, nil, apierrors.NewForbidden(corev1.Resource("pods"), "", errors.New("rbac"))
+	})
+
+	_, err := e.GetSteadyState(context.Background(), "default")
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestExecRetriesTransientErrors(t *testing.T) {
+	e := newTestK8sEngine()
+	e.SetAPIRetry(3, time.Millisecond)
+	var attempts atomic.Int32
+	e.exec = func(context.Context, string, string, []string) (string, error) {
+		if attempts.Add(1) == 1 {
+			return "", fmt.Errorf("exec in web-1: %w", apierrors.NewTooManyRequests("slow down", 1))
+		}
+		return "ok", nil
+	}
+
+	out, err := e.execInPod(context.Background(), "default", "web-1", []string{"true"})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", out)
+	assert.Equal(t, int32(2), attempts.Load())
+
+	// A command that ran and failed is not run again
+	attempts.Store(0)
+	e.exec = func(context.Context, string, string, []string) (string, error) {
+		attempts.Add(1)
+		return "", errors.New("command terminated with exit code 1")
+	}
+	_, err = e.execInPod(context.Background(), "default", "web-1", []string{"false"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestExecRollbackStopsAtDeadline(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	e := newTestK8sEngine(testPod("web-1", "default", web), testPod("web-2", "default", web))
+	e.exec = func(ctx context.Context, _, _ string, command []string) (string, error) {
+		if command[1] == "qdisc" && command[2] == "del" {
+			// A wedged exec stream that only ends when the context does
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return "", nil
+	}
+
+	res, err := e.NetworkLatency(context.Background(), "default", "app=web", domain.NetemDelay{LatencyMs: 100}, "eth0",
+		&domain.ExperimentConfig{Name: "latency", ChaosType: domain.ChaosTypeNetworkLatency, Safety: domain.SafetyConfig{MaxBlastRadius: 1}})
+	require.NoError(t, err)
+
+	rm := safety.NewRollbackManager()
+	rm.SetTimeout(50 * time.Millisecond)
+	rm.Push("exp-1", res.RollbackFn, "network_latency")
+
+	start := time.Now()
+	results := rm.Rollback(context.Background(), "exp-1")
+	assert.Less(t, time.Since(start), time.Second)
+	require.Len(t, results, 1)
+	if results[0].Status == "success" {
+		assert.Equal(t, 0, results[0].Result["removed_latency"])
+	}
+}
+
+// recordExec captures every command run in a pod, answering `ip route get`
+// with a route through routeDev
+func recordExec(e *K8sEngine, routeDev string) func() [][]string {
+	var mu sync.Mutex
+	var commands [][]string
+	e.exec = func(_ context.Context, _, _ string, command []string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, command)
+		if command[0] == "ip" {
+			return fmt.Sprintf("1.1.1.1 via 10.0.0.1 dev %s src 10.0.0.5 uid 0\n    cache\n", routeDev), nil
+		}
+		return "", nil
+	}
+	return func() [][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(commands)
+	}
+}
+
+func TestNetemCommand(t *testing.T) {
+	tests := []struct {
+		fault domain.NetemFault
+		want  string
+	}{
+		{domain.NetemFault{Delay: domain.NetemDelay{LatencyMs: 100}}, "tc qdisc add dev eth0 root netem delay 100ms"},
+		{domain.NetemFault{Delay: domain.NetemDelay{LatencyMs: 100, JitterMs: 20}}, "tc qdisc add dev eth0 root netem delay 100ms 20ms"},
+		{domain.NetemFault{Delay: domain.NetemDelay{LatencyMs: 250, JitterMs: 50, Distribution: "pareto"}},
+			"tc qdisc add dev eth0 root netem delay 250ms 50ms distribution pareto"},
+		{domain.NetemFault{LossPercent: 10}, "tc qdisc add dev eth0 root netem loss 10%"},
+		{domain.NetemFault{Delay: domain.NetemDelay{LatencyMs: 100, JitterMs: 20}, LossPercent: 5, DuplicatePercent: 1},
+			"tc qdisc add dev eth0 root netem delay 100ms 20ms loss 5% duplicate 1%"},
+		{domain.NetemFault{LossPercent: 5, DuplicatePercent: 2}, "tc qdisc add dev eth0 root netem loss 5% duplicate 2%"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, strings.Join(netemCommand("tc", "eth0", tt.fault), " "))
+	}
+}
+
+func TestNetworkFaultAppliesOneQdiscWithOneRollback(t *testing.T) {
+	e := newTestK8sEngine(
+		testPod("web-1", "default", map[string]string{"app": "web"}),
+		testPod("web-2", "default", map[string]string{"app": "web"}),
+	)
+	commands := recordExec(e, "eth0")
+	fault := domain.NetemFault{Delay: domain.NetemDelay{LatencyMs: 100, JitterMs: 20}, LossPercent: 5, DuplicatePercent: 1}
+
+	res, err := e.NetworkFault(context.Background(), "default", "app=web", fault, "eth0", fullBlastRadius)
+	require.NoError(t, err)
+	assert.Equal(t, "network_fault", res.Result["action"])
+	assert.Equal(t, 100, res.Result["latency_ms"])
+	assert.Equal(t, 20, res.Result["jitter_ms"])
+	assert.Equal(t, 5, res.Result["loss_percent"])
+	assert.Equal(t, 1, res.Result["duplicate_percent"])
+
+	combined := []string{"tc", "qdisc", "add", "dev", "eth0", "root", "netem", "delay", "100ms", "20ms", "loss", "5%", "duplicate", "1%"}
+	cmds := commands()
+	require.Len(t, cmds, 2, "one qdisc add per pod")
+	for _, cmd := range cmds {
+		assert.Equal(t, combined, cmd)
+	}
+
+	out, err := res.RollbackFn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, out["removed_fault"])
+	cmds = commands()[2:]
+	require.Len(t, cmds, 2, "one qdisc del per pod")
+	for _, cmd := range cmds {
+		assert.Equal(t, []string{"tc", "qdisc", "del", "dev", "eth0", "root"}, cmd)
+	}
+}
+
+func TestNetworkFaultRequiresAnImpairment(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	commands := recordExec(e, "eth0")
+
+	_, err := e.NetworkFault(context.Background(), "default", "app=web", domain.NetemFault{}, "eth0", fullBlastRadius)
+	assert.ErrorIs(t, err, domain.ErrInvalidConfig)
+	assert.Empty(t, commands())
+}
+
+func TestRunnerThreadsLatencyJitter(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	commands := recordExec(e, "eth0")
+	runner := NewRunner(e, nil, safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, nil, "",
+	)
+
+	res, err := runner.executeChaos(context.Background(), &domain.ExperimentConfig{
+		Name:         "jitter",
+		ChaosType:    domain.ChaosTypeNetworkLatency,
+		TargetLabels: map[string]string{"app": "web"},
+		Parameters:   map[string]any{"latency_ms": float64(100), "jitter_ms": float64(20), "distribution": "normal"},
+		Safety:       domain.SafetyConfig{MaxBlastRadius: 1},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 20, res.Result["jitter_ms"])
+	assert.Equal(t, "normal", res.Result["distribution"])
+	require.Len(t, commands(), 1)
+	assert.Equal(t, "tc qdisc add dev eth0 root netem delay 100ms 20ms distribution normal", strings.Join(commands()[0], " "))
+
+	_, err = runner.executeChaos(context.Background(), &domain.ExperimentConfig{
+		Name:       "too-much-jitter",
+		ChaosType:  domain.ChaosTypeNetworkLatency,
+		Parameters: map[string]any{"latency_ms": float64(100), "jitter_ms": float64(100)},
+		Safety:     domain.SafetyConfig{MaxBlastRadius: 1},
+	})
+	assert.ErrorIs(t, err, domain.ErrInvalidConfig)
+}
+
+func TestRunnerRoutesNetworkFault(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	commands := recordExec(e, "eth0")
+	runner := NewRunner(e, nil, safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, nil, "",
+	)
+
+	res, err := runner.executeChaos(context.Background(), &domain.ExperimentConfig{
+		Name:         "slow-and-lossy",
+		ChaosType:    domain.ChaosTypeNetworkFault,
+		TargetLabels: map[string]string{"app": "web"},
+		Parameters: map[string]any{
+			"latency_ms": float64(100), "jitter_ms": float64(20), "loss_percent": float64(5), "duplicate_percent": float64(1),
+		},
+		Safety: domain.SafetyConfig{MaxBlastRadius: 1},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "network_fault", res.Result["action"])
+	require.Len(t, commands(), 1, "latency, loss and duplication share one qdisc")
+	assert.Equal(t, "tc qdisc add dev eth0 root netem delay 100ms 20ms loss 5% duplicate 1%", strings.Join(commands()[0], " "))
+
+	_, err = runner.executeChaos(context.Background(), &domain.ExperimentConfig{
+		Name:      "no-impairment",
+		ChaosType: domain.ChaosTypeNetworkFault,
+		Safety:    domain.SafetyConfig{MaxBlastRadius: 1},
+	})
+	assert.ErrorIs(t, err, domain.ErrInvalidConfig)
+}
+
+func TestNetworkChaosUsesConfiguredInterface(t *testing.T) {
+	cfg := &domain.ExperimentConfig{Name: "tc", Safety: domain.SafetyConfig{MaxBlastRadius: 1}}
+	inject := map[string]func(e *K8sEngine) (*domain.ChaosResult, error){
+		"latency": func(e *K8sEngine) (*domain.ChaosResult, error) {
+			return e.NetworkLatency(context.Background(), "default", "app=web", domain.NetemDelay{LatencyMs: 100}, "net1", cfg)
+		},
+		"loss": func(e *K8sEngine) (*domain.ChaosResult, error) {
+			return e.NetworkLoss(context.Background(), "default", "app=web", 10, "net1", cfg)
+		},
+	}
+	for name, fn := range inject {
+		t.Run(name, func(t *testing.T) {
+			e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+			commands := recordExec(e, "eth0")
+
+			res, err := fn(e)
+			require.NoError(t, err)
+			assert.Equal(t, "net1", res.Result["interface"])
+			_, err = res.RollbackFn(context.Background())
+			require.NoError(t, err)
+
+			cmds := commands()
+			require.Len(t, cmds, 2)
+			assert.Equal(t, []string{"tc", "qdisc", "add", "dev", "net1", "root", "netem"}, cmds[0][:7])
+			assert.Equal(t, []string{"tc", "qdisc", "del", "dev", "net1", "root"}, cmds[1])
+		})
+	}
+}
+
+func TestNetworkChaosAutoDetectsInterface(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	commands := recordExec(e, "ens5")
+
+	res, err := e.NetworkLoss(context.Background(), "default", "app=web", 10, domain.AutoNetworkInterface,
+		&domain.ExperimentConfig{Name: "tc", Safety: domain.SafetyConfig{MaxBlastRadius: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"web-1": "ens5"}, res.Result["interfaces"])
+	_, err = res.RollbackFn(context.Background())
+	require.NoError(t, err)
+
+	cmds := commands()
+	require.Len(t, cmds, 3)
+	assert.Equal(t, []string{"ip", "route", "get", "1.1.1.1"}, cmds[0])
+	assert.Equal(t, []string{"tc", "qdisc", "add", "dev", "ens5", "root", "netem", "loss", "10%"}, cmds[1])
+	assert.Equal(t, []string{"tc", "qdisc", "del", "dev", "ens5", "root"}, cmds[2])
+}
+
+func TestParseRouteDevice(t *testing.T) {
+	assert.Equal(t, "eth0", parseRouteDevice("1.1.1.1 via 10.0.0.1 dev eth0 src 10.0.0.5 uid 0"))
+	assert.Equal(t, "net1", parseRouteDevice("1.1.1.1 dev net1 src 192.168.1.5"))
+	assert.Empty(t, parseRouteDevice("RTNETLINK answers: Network is unreachable"))
+	assert.Empty(t, parseRouteDevice("1.1.1.1 dev"))
+}
+
+func TestClockSkewShiftsAndResyncsClock(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	var commands [][]string
+	e.exec = func(_ context.Context, _, _ string, command []string) (string, error) {
+		commands = append(commands, command)
+		return "offset\n", nil
+	}
+
+	res, err := e.ClockSkew(context.Background(), "default", "app=web", -120,
+		&domain.ExperimentConfig{Name: "skew", Safety: domain.SafetyConfig{MaxBlastRadius: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, -120, res.Result["offset_seconds"])
+	assert.Contains(t, res.Result, "rollback_accuracy")
+
+	undo, err := res.RollbackFn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, undo["resynced"])
+	assert.Equal(t, map[string]string{"pod/web-1": "offset"}, undo["methods"])
+
+	require.Len(t, commands, 2)
+	assert.Contains(t, commands[0][2], "+ (-120)")
+	assert.Contains(t, commands[1][2], "- (-120)")
+	assert.NotContains(t, commands[1][2], "ntpdate", "no NTP server is configured")
+}
+
+func TestClockSkewShiftsEachNodeOnce(t *testing.T) {
+	onNode := func(name, node string) *corev1.Pod {
+		pod := testPod(name, "default", map[string]string{"app": "web"})
+		pod.Spec.NodeName = node
+		return pod
+	}
+	e := newTestK8sEngine(onNode("web-1", "node-a"), onNode("web-2", "node-a"), onNode("web-3", "node-b"))
+	e.SetExecCommands(ExecCommands{NTPServer: "ntp.internal"})
+	var mu sync.Mutex
+	commands := map[string][][]string{}
+	e.exec = func(_ context.Context, _, podName string, command []string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands[podName] = append(commands[podName], command)
+		return "ntp\n", nil
+	}
+
+	res, err := e.ClockSkew(context.Background(), "default", "app=web", 60, fullBlastRadius)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node-a", "node-b"}, res.Result["nodes"])
+	assert.ElementsMatch(t, []string{"web-1", "web-2", "web-3"}, res.Result["pods"])
+
+	undo, err := res.RollbackFn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, undo["resynced"])
+	assert.Equal(t, map[string]string{"node-a": "ntp", "node-b": "ntp"}, undo["methods"])
+
+	require.Len(t, commands, 2, "one pod per node runs the commands")
+	for _, pod := range []string{"web-1", "web-3"} {
+		require.Len(t, commands[pod], 2, pod)
+		assert.Contains(t, commands[pod][0][2], "+ (60)")
+		assert.Equal(t, "ntp.internal", commands[pod][1][len(commands[pod][1])-1])
+		assert.NotContains(t, commands[pod][1][2], "pool.ntp.org")
+	}
+}
+
+func TestClockSkewReportsMissingCapability(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	e.exec = func(_ context.Context, _, podName string, _ []string) (string, error) {
+		return "", fmt.Errorf("exec in %s: command terminated with exit code 1 (stderr: date: cannot set date: Operation not permitted)", podName)
+	}
+
+	res, err := e.ClockSkew(context.Background(), "default", "app=web", 300,
+		&domain.ExperimentConfig{Name: "skew", Safety: domain.SafetyConfig{MaxBlastRadius: 1}})
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, domain.ErrClockSkewNotPermitted)
+}
+
+// stressExec fakes a cluster where only the pods in hasTool ship stress-ng
+func stressExec(e *K8sEngine, hasTool ...string) func() map[string][][]string {
+	var mu sync.Mutex
+	commands := map[string][][]string{}
+	e.exec = func(_ context.Context, _, podName string, command []string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if slices.Equal(command, stressToolCheckCommand("stress-ng")) {
+			if !slices.Contains(hasTool, podName) {
+				return "", fmt.Errorf("exec in %s: command terminated with exit code 127", podName)
+			}
+			return "/usr/bin/stress-ng\n", nil
+		}
+		commands[podName] = append(commands[podName], command)
+		return "", nil
+	}
+	return func() map[string][][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return maps.Clone(commands)
+	}
+}
+
+func TestCPUStressRequiresStressNG(t *testing.T) {
+	e := newTestK8sEngine(
+		testPod("web-1", "default", map[string]string{"app": "web"}),
+		testPod("web-2", "default", map[string]string{"app": "web"}),
+	)
+	commands := stressExec(e, "web-1")
+
+	res, err := e.CPUStress(context.Background(), "default", "app=web", 1, 30, StressOptions{}, fullBlastRadius)
+	assert.Nil(t, res)
+	require.ErrorIs(t, err, domain.ErrStressToolMissing)
+	assert.Contains(t, err.Error(), "web-2")
+	assert.Empty(t, commands(), "nothing is stressed when a pod lacks stress-ng")
+}
+
+func TestCPUStressFailsOnToolCheckErrors(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	var commands [][]string
+	e.exec = func(_ context.Context, _, podName string, command []string) (string, error) {
+		commands = append(commands, command)
+		return "", fmt.Errorf(`exec in %s: pods "%s" is forbidden: cannot create resource "pods/exec"`, podName, podName)
+	}
+
+	res, err := e.CPUStress(context.Background(), "default", "app=web", 1, 30,
+		StressOptions{ShellFallback: true}, fullBlastRadius)
+	assert.Nil(t, res)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrStressToolMissing)
+	assert.Contains(t, err.Error(), "forbidden")
+	assert.Len(t, commands, 1, "a denied check does not fall back to the shell loop")
+}
+
+func TestIsCommandNotFound(t *testing.T) {
+	assert.True(t, isCommandNotFound(utilexec.CodeExitError{Err: errors.New("exit"), Code: 127}))
+	assert.False(t, isCommandNotFound(utilexec.CodeExitError{Err: errors.New("exit"), Code: 1}))
+	assert.True(t, isCommandNotFound(errors.New(`exec: "sh": executable file not found in $PATH`)))
+	assert.False(t, isCommandNotFound(errors.New("context deadline exceeded")))
+	assert.False(t, isCommandNotFound(nil))
+}
+
+func TestCPUStressShellFallback(t *testing.T) {
+	e := newTestK8sEngine(
+		testPod("web-1", "default", map[string]string{"app": "web"}),
+		testPod("web-2", "default", map[string]string{"app": "web"}),
+	)
+	commands := stressExec(e, "web-1")
+
+	res, err := e.CPUStress(context.Background(), "default", "app=web", 2, 30, StressOptions{ShellFallback: true}, fullBlastRadius)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web-2"}, res.Result["shell_fallback_pods"])
+
+	undo, err := res.RollbackFn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, undo["killed_stress"])
+
+	got := commands()
+	require.Len(t, got["web-1"], 2)
+	assert.Equal(t, "stress-ng", got["web-1"][0][0])
+	assert.Equal(t, []string{"pkill", "-f", "stress-n[g]"}, got["web-1"][1])
+	require.Len(t, got["web-2"], 2)
+	assert.Equal(t, shellCPUBurnCommand(2, 30), got["web-2"][0])
+	assert.Equal(t, []string{"pkill", "-f", "chaosduck-stres[s]"}, got["web-2"][1])
+}
+
+func TestMemoryStressShellFallback(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	commands := stressExec(e)
+
+	res, err := e.MemoryStress(context.Background(), "default", "app=web", "64M", 1, 30, StressOptions{ShellFallback: true}, fullBlastRadius)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web-1"}, res.Result["shell_fallback_pods"])
+
+	_, err = res.RollbackFn(context.Background())
+	require.NoError(t, err)
+
+	got := commands()["web-1"]
+	require.Len(t, got, 3)
+	assert.Contains(t, got[0][2], "count=64")
+	assert.Equal(t, []string{"pkill", "-f", "chaosduck-stres[s]"}, got[1])
+	assert.Equal(t, []string{"rm", "-f", shellMemoryFile}, got[2])
+}
+
+func TestMemoryStressVMWorkers(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+		commands := stressExec(e, "web-1")
+
+		res, err := e.MemoryStress(context.Background(), "default", "app=web", "64M", workers, 30, StressOptions{}, fullBlastRadius)
+		require.NoError(t, err)
+		assert.Equal(t, workers, res.Result["vm_workers"])
+
+		got := commands()["web-1"]
+		require.NotEmpty(t, got)
+		assert.Equal(t, []string{"stress-ng", "--vm", fmt.Sprint(workers), "--vm-bytes", "64M"}, got[len(got)-1][:5])
+	}
+}
+
+func TestMemoryStressShellFallbackRejectsPercentage(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	stressExec(e)
+
+	_, err := e.MemoryStress(context.Background(), "default", "app=web", "50%", 1, 30, StressOptions{ShellFallback: true}, fullBlastRadius)
+	assert.ErrorIs(t, err, domain.ErrInvalidConfig)
+}
+
+func ephemeralUpdates(e *K8sEngine) []*corev1.Pod {
+	var pods []*corev1.Pod
+	for _, a := range e.clientset.(*fake.Clientset).Actions() {
+		if a.GetVerb() == "update" && a.GetSubresource() == "ephemeralcontainers" {
+			pods = append(pods, a.(k8stesting.UpdateAction).GetObject().(*corev1.Pod))
+		}
+	}
+	return pods
+}
+
+func TestCPUStressEphemeralContainer(t *testing.T) {
+	pod := testPod("web-1", "default", map[string]string{"app": "web"})
+	pod.Spec.Containers = []corev1.Container{{Name: "app", Image: "gcr.io/distroless/static"}}
+	e := newTestK8sEngine(pod)
+	e.exec = func(context.Context, string, string, []string) (string, error) {
+		return "", errors.New("distroless: no shell")
+	}
+
+	ctx := WithExperimentID(context.Background(), "exp00001")
+	res, err := e.CPUStress(ctx, "default", "app=web", 2, 30, StressOptions{EphemeralImage: "stress:1"}, fullBlastRadius)
+	require.NoError(t, err)
+	assert.Equal(t, "chaosduck-stress-exp00001", res.Result["ephemeral_container"])
+
+	updates := ephemeralUpdates(e)
+	require.Len(t, updates, 1)
+	require.Len(t, updates[0].Spec.EphemeralContainers, 1)
+	ec := updates[0].Spec.EphemeralContainers[0]
+	assert.Equal(t, "chaosduck-stress-exp00001", ec.Name)
+	assert.Equal(t, "stress:1", ec.Image)
+	assert.Equal(t, "app", ec.TargetContainerName)
+	assert.Equal(t, []string{"--cpu", "2", "--timeout", "30s", "--quiet"}, ec.Args)
+
+	// Nothing to exec on rollback: stress-ng exits on its own timeout
+	undo, err := res.RollbackFn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "chaosduck-stress-exp00001", undo["ephemeral_container"])
+}
+
+func TestMemoryStressEphemeralContainerUnsupported(t *testing.T) {
+	pod := testPod("web-1", "default", map[string]string{"app": "web"})
+	pod.Spec.Containers = []corev1.Container{{Name: "app"}}
+	e := newTestK8sEngine(pod)
+	e.clientset.(*fake.Clientset).PrependReactor("update", "pods", func(a k8stesting.Action) (bool, runtime.Object, error) {
+		if a.GetSubresource() != "ephemeralcontainers" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewNotFound(corev1.Resource("pods/ephemeralcontainers"), "web-1")
+	})
+
+	res, err := e.MemoryStress(context.Background(), "default", "app=web", "64M", 1, 30, StressOptions{EphemeralImage: "stress:1"}, fullBlastRadius)
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, domain.ErrEphemeralContainersUnsupported)
+}
+
+func TestExecCommandsAreConfigurable(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	e.SetExecCommands(ExecCommands{
+		TC:     "/usr/sbin/tc",
+		Stress: "/opt/bin/stress-ng",
+		Pkill:  "/usr/bin/pkill",
+		Prefix: []string{"sudo", "-n"},
+	})
+	commands := recordExec(e, "eth0")
+
+	res, err := e.NetworkLatency(context.Background(), "default", "app=web", domain.NetemDelay{LatencyMs: 100}, "eth0", fullBlastRadius)
+	require.NoError(t, err)
+	_, err = res.RollbackFn(context.Background())
+	require.NoError(t, err)
+
+	res, err = e.CPUStress(context.Background(), "default", "app=web", 1, 30, StressOptions{}, fullBlastRadius)
+	require.NoError(t, err)
+	_, err = res.RollbackFn(context.Background())
+	require.NoError(t, err)
+
+	got := commands()
+	require.Len(t, got, 5)
+	assert.Equal(t, "sudo -n /usr/sbin/tc qdisc add dev eth0 root netem delay 100ms", strings.Join(got[0], " "))
+	assert.Equal(t, "sudo -n /usr/sbin/tc qdisc del dev eth0 root", strings.Join(got[1], " "))
+	assert.Equal(t, []string{"sudo", "-n", "sh", "-c", "command -v /opt/bin/stress-ng >/dev/null || exit 127"}, got[2])
+	assert.Equal(t, []string{"sudo", "-n", "/opt/bin/stress-ng", "--cpu", "1"}, got[3][:5])
+	assert.Equal(t, []string{"sudo", "-n", "/usr/bin/pkill", "-f", "stress-n[g]"}, got[4])
+}
+
+func TestSelfExcludingPattern(t *testing.T) {
+	assert.Equal(t, "stress-n[g]", selfExcludingPattern("stress-ng"))
+	assert.Equal(t, `stress-ng\.stati[c]`, selfExcludingPattern("stress-ng.static"))
+	assert.Equal(t, "", selfExcludingPattern(""))
+}
+
+func TestSelfExcludingRegex(t *testing.T) {
+	assert.Equal(t, "[n]ginx: worker", selfExcludingRegex("nginx: worker"))
+	assert.Equal(t, "[-]-workers=4", selfExcludingRegex("--workers=4"))
+	assert.Equal(t, "^java .*Main", selfExcludingRegex("^java .*Main"), "anchored patterns cannot match the wrapper")
+	assert.Equal(t, `\.venv/bin/gunicorn`, selfExcludingRegex(`\.venv/bin/gunicorn`))
+	assert.Equal(t, "", selfExcludingRegex(""))
+}
+
+func TestProcessKillGuardsAgainstWrapper(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	commands := recordExec(e, "eth0")
+	e.SetExecCommands(ExecCommands{Prefix: []string{"sudo", "-n"}})
+
+	res, err := e.ProcessKill(context.Background(), "default", "app=web", "-jar app.jar", "TERM", fullBlastRadius)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"sudo", "-n", "pkill", "-TERM", "-f", "--", "[-]jar app.jar"}}, commands())
+	assert.Equal(t, "-jar app.jar", res.Result["process_pattern"], "the result reports the pattern as given")
+}
+
+func TestProcessKillSendsSignal(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	commands := recordExec(e, "eth0")
+
+	res, err := e.ProcessKill(context.Background(), "default", "app=web", "nginx: worker", "KILL",
+		&domain.ExperimentConfig{Name: "kill", Safety: domain.SafetyConfig{MaxBlastRadius: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web-1"}, res.Result["pods"])
+	assert.Equal(t, [][]string{{"pkill", "-KILL", "-f", "--", "[n]ginx: worker"}}, commands())
+
+	undo, err := res.RollbackFn(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, undo, "note")
+}
+
+func TestExecuteChaosProcessKillRejectsCatastrophicPattern(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	commands := recordExec(e, "eth0")
+	runner := NewRunner(e, nil, safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(), safety.NewSnapshotManager(nil), nil, nil, "")
+
+	for _, p := range []map[string]any{
+		nil,
+		{"process_pattern": ".*"},
+		{"process_pattern": "nginx", "signal": "SEGV"},
+	} {
+		_, err := runner.executeChaos(context.Background(), &domain.ExperimentConfig{
+			Name: "kill", ChaosType: domain.ChaosTypeProcessKill, Parameters: p,
+			Safety: domain.SafetyConfig{MaxBlastRadius: 1},
+		})
+		assert.ErrorIs(t, err, domain.ErrInvalidConfig, "%v", p)
+	}
+	assert.Empty(t, commands())
+}
+
+var fullBlastRadius = &domain.ExperimentConfig{Safety: domain.SafetyConfig{MaxBlastRadius: 1}}
+
+func podAnnotations(t *testing.T, e *K8sEngine, name string) map[string]string {
+	t.Helper()
+	pod, err := e.clientset.CoreV1().Pods("default").Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return pod.Annotations
+}
+
+func TestExecChaosAnnotatesPodsUntilRollback(t *testing.T) {
+	pod := testPod("web-1", "default", map[string]string{"app": "web"})
+	pod.Annotations = map[string]string{"owner": "team-a"}
+	e := newTestK8sEngine(pod)
+	e.exec = func(ctx context.Context, _, podName string, _ []string) (string, error) {
+		// The annotation is in place before anything runs in the pod
+		pod, err := e.clientset.CoreV1().Pods("default").Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		if pod.Annotations[ExperimentAnnotation] != "exp00001" {
+			return "", errors.New("pod not annotated before injection")
+		}
+		return "", nil
+	}
+
+	ctx := WithExperimentID(context.Background(), "exp00001")
+	res, err := e.CPUStress(ctx, "default", "app=web", 1, 30, StressOptions{}, fullBlastRadius)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "team-a", ExperimentAnnotation: "exp00001"}, podAnnotations(t, e, "web-1"))
+
+	_, err = res.RollbackFn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "team-a"}, podAnnotations(t, e, "web-1"))
+}
+
+func TestExecChaosWithoutExperimentIDLeavesPodsUnannotated(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	recordExec(e, "eth0")
+
+	_, err := e.NetworkLoss(context.Background(), "default", "app=web", 10, "eth0", fullBlastRadius)
+	require.NoError(t, err)
+	assert.Empty(t, podAnnotations(t, e, "web-1"))
+}
+
+func TestAnnotatePodsRetriesOnConflict(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	var updates atomic.Int32
+	e.clientset.(*fake.Clientset).PrependReactor("update", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		// Lose the first write to a concurrent update, then let it through
+		if updates.Add(1) == 1 {
+			return true, nil, apierrors.NewConflict(corev1.Resource("pods"), "web-1", errors.New("object was modified"))
+		}
+		return false, nil, nil
+	})
+	pods, err := e.clientset.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+
+	unannotate := e.annotatePods(WithExperimentID(context.Background(), "exp00001"), "default", pods.Items)
+	assert.Equal(t, int32(2), updates.Load())
+	assert.Equal(t, "exp00001", podAnnotations(t, e, "web-1")[ExperimentAnnotation])
+
+	unannotate(context.Background())
+	assert.NotContains(t, podAnnotations(t, e, "web-1"), ExperimentAnnotation)
+}
+
+func TestUnannotateKeepsAnotherExperimentsAnnotation(t *testing.T) {
+	e := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	pods, err := e.clientset.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+
+	unannotate := e.annotatePods(WithExperimentID(context.Background(), "exp00001"), "default", pods.Items)
+	e.annotatePods(WithExperimentID(context.Background(), "exp00002"), "default", pods.Items)
+	unannotate(context.Background())
+	assert.Equal(t, "exp00002", podAnnotations(t, e, "web-1")[ExperimentAnnotation])
+}
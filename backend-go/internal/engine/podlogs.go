@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Bounds on the pod logs an experiment captures, so a chatty or large
+// target can't bloat results
+const (
+	podLogTailLines = 100
+	podLogMaxBytes  = 16 << 10
+	podLogMaxPods   = 10
+)
+
+// TargetPodNames returns the names of up to podLogMaxPods pods the
+// experiment targets in namespace
+func (e *K8sEngine) TargetPodNames(ctx context.Context, namespace, labelSelector string, cfg *domain.ExperimentConfig) ([]string, error) {
+	pods, _, err := e.listTargets(ctx, namespace, labelSelector, cfg)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, min(len(pods.Items), podLogMaxPods))
+	for _, p := range pods.Items[:min(len(pods.Items), podLogMaxPods)] {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+// CapturePodLogs reads the last podLogTailLines lines, at most
+// podLogMaxBytes, of each pod's first container. A non-zero since drops
+// lines written before it. A pod that can't be read, e.g. because it was
+// deleted, gets an entry with Error set instead of failing the capture.
+func (e *K8sEngine) CapturePodLogs(ctx context.Context, namespace string, pods []string, stage string, since time.Time) []domain.PodLogCapture {
+	captures := make([]domain.PodLogCapture, len(pods))
+	for i, name := range pods {
+		c := domain.PodLogCapture{Stage: stage, Namespace: namespace, Pod: name}
+		if err := e.readPodLogs(ctx, &c, since); err != nil {
+			c.Error = err.Error()
+		}
+		c.CapturedAt = time.Now().UTC()
+		captures[i] = c
+	}
+	return captures
+}
+
+// readPodLogs fills in c's container and logs
+func (e *K8sEngine) readPodLogs(ctx context.Context, c *domain.PodLogCapture, since time.Time) error {
+	pod, err := e.clientset.CoreV1().Pods(c.Namespace).Get(ctx, c.Pod, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("pod no longer exists")
+	}
+	if err != nil {
+		return fmt.Errorf("get pod: %w", err)
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return fmt.Errorf("pod has no containers")
+	}
+	c.Container = pod.Spec.Containers[0].Name
+
+	tail := int64(podLogTailLines)
+	limit := int64(podLogMaxBytes + 1)
+	opts := &corev1.PodLogOptions{Container: c.Container, TailLines: &tail, LimitBytes: &limit}
+	if !since.IsZero() {
+		opts.SinceTime = &metav1.Time{Time: since}
+	}
+	stream, err := e.clientset.CoreV1().Pods(c.Namespace).GetLogs(c.Pod, opts).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("get logs: %w", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(stream, limit))
+	if err != nil {
+		return fmt.Errorf("read logs: %w", err)
+	}
+	if len(data) > podLogMaxBytes {
+		data, c.Truncated = data[:podLogMaxBytes], true
+	}
+	c.Logs = string(data)
+	return nil
+}
+
+// capturePodLogs records a tail of the given target pods' logs on the
+// result and in the experiment's stored logs
+func (r *Runner) capturePodLogs(ctx context.Context, experimentID string, cfg domain.ExperimentConfig, result *domain.ExperimentResult, pods []string, stage string, since time.Time) {
+	if len(pods) == 0 {
+		return
+	}
+	captures := r.k8s.CapturePodLogs(ctx, *cfg.TargetNamespace, pods, stage, since)
+	result.PodLogs = append(result.PodLogs, captures...)
+	if r.queries == nil {
+		return
+	}
+	for _, c := range captures {
+		if err := r.queries.CreateExperimentPodLog(context.WithoutCancel(ctx), db.CreateExperimentPodLogParams{
+			ExperimentID: experimentID,
+			Stage:        c.Stage,
+			Namespace:    c.Namespace,
+			Pod:          c.Pod,
+			Container:    c.Container,
+			Logs:         c.Logs,
+			Truncated:    c.Truncated,
+			Error:        pgtype.Text{String: c.Error, Valid: c.Error != ""},
+			CapturedAt:   pgtype.Timestamptz{Time: c.CapturedAt, Valid: true},
+		}); err != nil {
+			log.Printf("Failed to store %s pod logs of %s for %s: %v", c.Stage, c.Pod, experimentID, err)
+		}
+	}
+}
+
+// podLogTargets returns the pods whose logs an experiment captures: its K8s
+// targets in TargetNamespace, resolved before injection. Dry-runs and
+// multi-namespace experiments capture none.
+func (r *Runner) podLogTargets(ctx context.Context, cfg domain.ExperimentConfig) []string {
+	if r.k8s == nil || cfg.Safety.DryRun || cfg.TargetNamespace == nil || len(cfg.TargetNamespaces) > 0 || !domain.IsK8sChaosType(cfg.ChaosType) {
+		return nil
+	}
+	pods, err := r.k8s.TargetPodNames(ctx, *cfg.TargetNamespace, domain.LabelSelectorString(cfg.TargetLabels), &cfg)
+	if err != nil {
+		log.Printf("Pod log capture skipped: %v", err)
+		return nil
+	}
+	return pods
+}
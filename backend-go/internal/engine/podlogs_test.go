@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func podWithContainer(name, namespace string, labels map[string]string) *corev1.Pod {
+	pod := testPod(name, namespace, labels)
+	pod.Spec.Containers = []corev1.Container{{Name: "app"}}
+	return pod
+}
+
+func TestCapturePodLogs(t *testing.T) {
+	k8s := newTestK8sEngine(podWithContainer("web-1", "default", nil))
+
+	captures := k8s.CapturePodLogs(context.Background(), "default", []string{"web-1", "gone-1"}, domain.PodLogsBeforeInjection, time.Now())
+	require.Len(t, captures, 2)
+
+	assert.Equal(t, "web-1", captures[0].Pod)
+	assert.Equal(t, "app", captures[0].Container)
+	assert.Equal(t, domain.PodLogsBeforeInjection, captures[0].Stage)
+	// The fake clientset serves a fixed body
+	assert.Equal(t, "fake logs", captures[0].Logs)
+	assert.False(t, captures[0].Truncated)
+	assert.Empty(t, captures[0].Error)
+	assert.False(t, captures[0].CapturedAt.IsZero())
+
+	assert.Equal(t, "gone-1", captures[1].Pod)
+	assert.Equal(t, "pod no longer exists", captures[1].Error)
+	assert.Empty(t, captures[1].Logs)
+}
+
+func TestTargetPodNamesIsBounded(t *testing.T) {
+	var objects []runtime.Object
+	for i := range podLogMaxPods + 5 {
+		objects = append(objects, podWithContainer(fmt.Sprintf("web-%d", i), "default", map[string]string{"app": "web"}))
+	}
+	k8s := newTestK8sEngine(objects...)
+
+	cfg := holdConfig("default")
+	names, err := k8s.TargetPodNames(context.Background(), "default", "app=web", &cfg)
+	require.NoError(t, err)
+	assert.Len(t, names, podLogMaxPods)
+}
+
+func TestRunCapturesPodLogsAroundInjection(t *testing.T) {
+	k8s := newTestK8sEngine(podWithContainer("web-1", "default", map[string]string{"app": "web"}))
+	runner := newHoldRunner(k8s)
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Safety.MaxBlastRadius = 1.0
+	cfg.Parameters = nil
+
+	result, err := runner.Run(context.Background(), "pod-logs", cfg)
+	require.NoError(t, err)
+	require.Len(t, result.PodLogs, 2)
+
+	before, after := result.PodLogs[0], result.PodLogs[1]
+	assert.Equal(t, domain.PodLogsBeforeInjection, before.Stage)
+	assert.Equal(t, "fake logs", before.Logs)
+	// The pod was deleted by the injection and is only restored on rollback
+	assert.Equal(t, domain.PodLogsAfterObservation, after.Stage)
+	assert.Equal(t, "web-1", after.Pod)
+	assert.Equal(t, "pod no longer exists", after.Error)
+}
+
+func TestRunSkipsPodLogsOnDryRun(t *testing.T) {
+	k8s := newTestK8sEngine(podWithContainer("web-1", "default", map[string]string{"app": "web"}))
+	runner := newHoldRunner(k8s)
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Safety.MaxBlastRadius = 1.0
+	cfg.Safety.DryRun = true
+
+	result, err := runner.Run(context.Background(), "pod-logs-dry", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusCompleted, result.Status)
+	assert.Nil(t, result.Error, "the preview passed the guardrails")
+	assert.Equal(t, true, result.InjectionResult["dry_run"])
+	assert.Empty(t, result.PodLogs)
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// orphanedError is recorded on experiments reaped by ReconcileOrphaned
+const orphanedError = "orphaned: experiment outlived its timeout without completing (process restarted?)"
+
+// ReconcileResult describes one orphaned experiment found at startup
+type ReconcileResult struct {
+	ExperimentID string                  `json:"experiment_id"`
+	ChaosType    domain.ChaosType        `json:"chaos_type"`
+	Rollback     []safety.RollbackResult `json:"rollback"`
+	Drift        []map[string]any        `json:"drift"`
+	Error        string                  `json:"error,omitempty"`
+}
+
+// ReconcileOrphaned is a dead-man's switch for experiments whose process died
+// mid-run. Rollback functions live in memory, so after a restart an
+// experiment still marked running with no owner can never finish or roll
+// back. Every running experiment older than its safety timeout is marked
+// failed, its persisted rollback actions are reconstructed and executed, and
+// its persisted snapshot is compared against the live cluster so any
+// leftover chaos (e.g. pods that could not be restored) is recorded in
+// rollback_result for an operator to act on. Experiments this process is
+// still running are never reaped. Call it at startup, before serving
+// requests, and then through ReconcilePeriodically so runs that were still
+// inside their timeout at startup are reaped once they outlive it.
+func (r *Runner) ReconcileOrphaned(ctx context.Context) ([]ReconcileResult, error) {
+	if r.queries == nil {
+		return nil, nil
+	}
+
+	running, err := r.queries.ListExperimentsByStatus(ctx, string(domain.StatusRunning))
+	if err != nil {
+		return nil, fmt.Errorf("list running experiments: %w", err)
+	}
+
+	now := time.Now().UTC()
+	results := []ReconcileResult{}
+	for _, rec := range running {
+		if r.IsRunning(rec.ID) {
+			continue
+		}
+		var cfg domain.ExperimentConfig
+		if err := json.Unmarshal(rec.Config, &cfg); err != nil {
+			log.Printf("Reconcile: unreadable config for %s: %v", rec.ID, err)
+		}
+		if rec.StartedAt.Valid && now.Sub(rec.StartedAt.Time) < experimentTimeout(cfg) {
+			continue
+		}
+
+		res := ReconcileResult{ExperimentID: rec.ID, ChaosType: cfg.ChaosType}
+		res.Rollback = r.replayRollbacks(ctx, rec.ID)
+		res.Drift = r.orphanDrift(ctx, rec.ID)
+		rollbackJSON, err := json.Marshal(map[string]any{
+			"reconciled_at": now.Format(time.RFC3339),
+			"rollbacks":     res.Rollback,
+			"drift":         res.Drift,
+			"note":          "rollbacks were replayed from persisted actions; restore any remaining drift manually",
+		})
+		if err != nil {
+			rollbackJSON = []byte("{}")
+		}
+
+		if err := r.queries.UpdateExperiment(ctx, db.UpdateExperimentParams{
+			ID:              rec.ID,
+			Status:          string(domain.StatusFailed),
+			Phase:           rec.Phase,
+			CompletedAt:     pgtype.Timestamptz{Time: now, Valid: true},
+			SteadyState:     rec.SteadyState,
+			Hypothesis:      rec.Hypothesis,
+			InjectionResult: rec.InjectionResult,
+			Observations:    rec.Observations,
+			RollbackResult:  rollbackJSON,
+			Error:           pgtype.Text{String: orphanedError, Valid: true},
+			AiInsights:      rec.AiInsights,
+			PhaseTimings:    rec.PhaseTimings,
+		}); err != nil {
+			res.Error = err.Error()
+			log.Printf("Reconcile: failed to mark %s failed: %v", rec.ID, err)
+		} else {
+			log.Printf("Reconcile: marked orphaned experiment %s failed (%d rollbacks replayed, %d drift actions)",
+				rec.ID, len(res.Rollback), len(res.Drift))
+		}
+		if r.persistHook != nil {
+			r.persistHook(rec.ID)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// ReconcilePeriodically runs ReconcileOrphaned every interval until ctx is
+// done
+func (r *Runner) ReconcilePeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		orphans, err := r.ReconcileOrphaned(ctx)
+		if err != nil {
+			log.Printf("Reconciliation failed: %v", err)
+		} else if len(orphans) > 0 {
+			log.Printf("Reconciliation marked %d orphaned experiment(s) failed", len(orphans))
+		}
+	}
+}
+
+// replayRollbacks reconstructs and executes, newest first, the rollback
+// actions an orphaned experiment left in the database. Successful ones are
+// deleted; failed ones are kept for another attempt.
+func (r *Runner) replayRollbacks(ctx context.Context, experimentID string) []safety.RollbackResult {
+	results := []safety.RollbackResult{}
+	actions, err := r.queries.ListRollbackActions(ctx, experimentID)
+	if err != nil {
+		log.Printf("Reconcile: failed to load rollback actions for %s: %v", experimentID, err)
+		return results
+	}
+
+	for i := len(actions) - 1; i >= 0; i-- {
+		row := actions[i]
+		res := safety.RollbackResult{Description: row.Description, Status: "failed"}
+		fn, err := ReconstructRollback(domain.RollbackAction{
+			ChaosType: domain.ChaosType(row.ChaosType),
+			Data:      row.Data,
+		}, r.k8s, r.aws)
+		if err == nil {
+			rbCtx, cancel := context.WithTimeout(ctx, safety.DefaultRollbackTimeout)
+			res.Result, err = fn(rbCtx)
+			cancel()
+		}
+		if err != nil {
+			res.Error = err.Error()
+			log.Printf("Reconcile: rollback %s for %s failed: %v", row.Description, experimentID, err)
+		} else {
+			res.Status = "success"
+			if err := r.queries.DeleteRollbackAction(ctx, row.ID); err != nil {
+				log.Printf("Reconcile: failed to delete rollback action %d: %v", row.ID, err)
+			}
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// orphanDrift compares the latest persisted K8s snapshot of an experiment
+// with the current state of its namespace
+func (r *Runner) orphanDrift(ctx context.Context, experimentID string) []map[string]any {
+	drift := []map[string]any{}
+	if r.k8s == nil {
+		return drift
+	}
+
+	snapshots, err := r.queries.GetSnapshotsByExperiment(ctx, experimentID)
+	if err != nil {
+		log.Printf("Reconcile: failed to load snapshots for %s: %v", experimentID, err)
+		return drift
+	}
+	for _, snap := range snapshots {
+		if snap.Type != "k8s" || !snap.Namespace.Valid {
+			continue
+		}
+		var data map[string]any
+		if err := json.Unmarshal(snap.Data, &data); err != nil {
+			log.Printf("Reconcile: unreadable snapshot for %s: %v", experimentID, err)
+			continue
+		}
+		current, err := r.k8s.GetSteadyState(ctx, snap.Namespace.String)
+		if err != nil {
+			log.Printf("Reconcile: failed to read %s for %s: %v", snap.Namespace.String, experimentID, err)
+			continue
+		}
+		// Snapshots are ordered newest first; the latest one is enough
+		return r.snapshotMgr.DetectDrift(data, current)
+	}
+	return drift
+}
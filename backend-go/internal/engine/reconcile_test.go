@@ -0,0 +1,237 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeDB is an in-memory DBTX fake serving fixed experiment and snapshot
+// rows, storing rollback actions and recording UpdateExperiment calls
+type fakeDB struct {
+	experiments [][]any
+	snapshots   [][]any
+	actions     []db.RollbackAction
+	updates     [][]any
+}
+
+func (d *fakeDB) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	switch {
+	case strings.Contains(sql, "UpdateExperiment"):
+		d.updates = append(d.updates, args)
+	case strings.Contains(sql, "DeleteRollbackAction"):
+		d.actions = slices.DeleteFunc(d.actions, func(a db.RollbackAction) bool { return a.ID == args[0].(int32) })
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+func (d *fakeDB) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	switch {
+	case strings.Contains(sql, "ListExperimentsByStatus"):
+		return &fakeRows{rows: d.experiments}, nil
+	case strings.Contains(sql, "ListRollbackActions"):
+		rows := [][]any{}
+		for _, a := range d.actions {
+			if a.ExperimentID == args[0].(string) {
+				rows = append(rows, rollbackActionValues(a))
+			}
+		}
+		return &fakeRows{rows: rows}, nil
+	}
+	return &fakeRows{rows: d.snapshots}, nil
+}
+
+func (d *fakeDB) QueryRow(_ context.Context, _ string, args ...interface{}) pgx.Row {
+	// CreateRollbackAction is the only QueryRow these tests issue
+	a := db.RollbackAction{
+		ID:           int32(len(d.actions) + 1),
+		ExperimentID: args[0].(string),
+		ChaosType:    args[1].(string),
+		Description:  args[2].(string),
+		Data:         args[3].(json.RawMessage),
+		CreatedAt:    pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	d.actions = append(d.actions, a)
+	return &fakeRows{rows: [][]any{rollbackActionValues(a)}, pos: 1}
+}
+
+func rollbackActionValues(a db.RollbackAction) []any {
+	return []any{a.ID, a.ExperimentID, a.ChaosType, a.Description, a.Data, a.CreatedAt}
+}
+
+// fakeRows scans preset values into destinations by reflection
+type fakeRows struct {
+	rows [][]any
+	pos  int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return r.rows[r.pos-1], nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	r.pos++
+	return r.pos <= len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	for i, v := range r.rows[r.pos-1] {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+	}
+	return nil
+}
+
+func experimentRow(id string, startedAt time.Time) []any {
+	cfg, _ := json.Marshal(domain.ExperimentConfig{
+		Name:            "orphan",
+		ChaosType:       domain.ChaosTypePodDelete,
+		TargetNamespace: strPtr("default"),
+		Safety:          domain.DefaultSafetyConfig(),
+	})
+	return []any{
+		id, json.RawMessage(cfg), string(domain.StatusRunning), string(domain.PhaseInject),
+		pgtype.Timestamptz{Time: startedAt, Valid: true}, pgtype.Timestamptz{},
+		[]byte(nil), pgtype.Text{}, []byte(nil), []byte(nil), []byte(nil), pgtype.Text{},
+		[]byte(nil), []byte(nil), pgtype.Text{},
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestReconcileOrphanedMarksStaleRunsFailed(t *testing.T) {
+	snapshot, _ := json.Marshal(map[string]any{
+		"type":      "k8s",
+		"namespace": "default",
+		"resources": map[string]any{
+			"pods": []any{
+				map[string]any{"name": "web-1", "phase": "Running"},
+				map[string]any{"name": "web-2", "phase": "Running"},
+			},
+		},
+	})
+	fake := &fakeDB{
+		experiments: [][]any{
+			experimentRow("stale", time.Now().Add(-time.Hour)),
+			experimentRow("fresh", time.Now()),
+		},
+		snapshots: [][]any{{
+			int32(1), "stale", "k8s", pgtype.Text{String: "default", Valid: true},
+			json.RawMessage(snapshot), pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		}},
+	}
+
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	runner := NewRunner(k8s, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		db.New(fake), nil, "",
+	)
+	var invalidated []string
+	runner.SetPersistHook(func(id string) { invalidated = append(invalidated, id) })
+
+	results, err := runner.ReconcileOrphaned(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "stale", results[0].ExperimentID)
+	require.Len(t, results[0].Drift, 1)
+	assert.Equal(t, "web-2", results[0].Drift[0]["name"])
+	assert.Equal(t, []string{"stale"}, invalidated)
+
+	require.Len(t, fake.updates, 1)
+	args := fake.updates[0]
+	assert.Equal(t, "stale", args[0])
+	assert.Equal(t, string(domain.StatusFailed), args[1])
+	assert.Equal(t, orphanedError, args[9].(pgtype.Text).String)
+
+	var rollback map[string]any
+	require.NoError(t, json.Unmarshal(args[8].([]byte), &rollback))
+	assert.Len(t, rollback["drift"], 1)
+}
+
+func TestReconcileOrphanedSkipsLiveRuns(t *testing.T) {
+	fake := &fakeDB{experiments: [][]any{
+		experimentRow("live", time.Now().Add(-time.Hour)),
+		experimentRow("dead", time.Now().Add(-time.Hour)),
+	}}
+	runner := NewRunner(nil, nil, safety.NewEmergencyStopManager(), safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil), db.New(fake), nil, "")
+	_, cancel := context.WithCancelCause(context.Background())
+	defer runner.track("live", cancel)()
+
+	results, err := runner.ReconcileOrphaned(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "dead", results[0].ExperimentID)
+}
+
+func TestReconcilePeriodicallyReapsRunsThatOutliveTheirTimeout(t *testing.T) {
+	fake := &fakeDB{experiments: [][]any{experimentRow("stale", time.Now().Add(-time.Hour))}}
+	runner := NewRunner(nil, nil, safety.NewEmergencyStopManager(), safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil), db.New(fake), nil, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	runner.ReconcilePeriodically(ctx, 10*time.Millisecond)
+
+	require.NotEmpty(t, fake.updates)
+	assert.Equal(t, "stale", fake.updates[0][0])
+}
+
+func TestReconcileOrphanedWithoutDB(t *testing.T) {
+	runner := newHoldRunner(nil)
+	results, err := runner.ReconcileOrphaned(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestReconcileOrphanedReplaysPersistedRollbacks(t *testing.T) {
+	ctx := context.Background()
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	res, err := k8s.PodDelete(ctx, "default", "app=web", nil, &domain.ExperimentConfig{
+		Name:      "kill-web",
+		ChaosType: domain.ChaosTypePodDelete,
+		Safety:    domain.SafetyConfig{MaxBlastRadius: 1},
+	})
+	require.NoError(t, err)
+
+	// The previous process persisted the rollback and died before running it
+	fake := &fakeDB{experiments: [][]any{experimentRow("stale", time.Now().Add(-time.Hour))}}
+	crashed := safety.NewRollbackManager()
+	crashed.SetQueries(db.New(fake))
+	crashed.PushAction("stale", res.RollbackFn, "pod_delete", res.Rollback)
+
+	runner := NewRunner(k8s, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		db.New(fake), nil, "",
+	)
+	results, err := runner.ReconcileOrphaned(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Rollback, 1)
+	assert.Equal(t, "success", results[0].Rollback[0].Status)
+	assert.Empty(t, fake.actions)
+
+	_, err = k8s.clientset.CoreV1().Pods("default").Get(ctx, "web-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RetentionCleaner periodically deletes finished experiments, with their
+// snapshots, probe and analysis results, events and pod logs, once they are
+// older than the retention period. Running and pending experiments are never
+// deleted, however old.
+type RetentionCleaner struct {
+	queries   db.Querier
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+	// deleteHook is called with the ID of every deleted experiment
+	deleteHook func(experimentID string)
+}
+
+// NewRetentionCleaner creates a cleaner that keeps experiments for
+// retention and sweeps every interval. A retention of 0 keeps them forever.
+func NewRetentionCleaner(queries db.Querier, retention, interval time.Duration) *RetentionCleaner {
+	return &RetentionCleaner{queries: queries, retention: retention, interval: interval, now: time.Now}
+}
+
+// SetDeleteHook registers a callback invoked for each experiment a sweep
+// deletes, e.g. to drop it from a read cache
+func (c *RetentionCleaner) SetDeleteHook(fn func(experimentID string)) {
+	c.deleteHook = fn
+}
+
+// cutoff is the time before which finished experiments are deleted
+func (c *RetentionCleaner) cutoff() time.Time {
+	return c.now().Add(-c.retention)
+}
+
+// Sweep deletes the experiments that finished before the cutoff and returns
+// their IDs
+func (c *RetentionCleaner) Sweep(ctx context.Context) ([]string, error) {
+	if c.queries == nil || c.retention <= 0 {
+		return nil, nil
+	}
+	deleted, err := c.queries.DeleteExperimentsOlderThan(ctx, pgtype.Timestamptz{Time: c.cutoff(), Valid: true})
+	if c.deleteHook != nil {
+		for _, id := range deleted {
+			c.deleteHook(id)
+		}
+	}
+	return deleted, err
+}
+
+// Run sweeps once right away, then every interval until ctx is done
+func (c *RetentionCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		deleted, err := c.Sweep(ctx)
+		if err != nil {
+			log.Printf("Retention sweep failed: %v", err)
+		} else if len(deleted) > 0 {
+			log.Printf("Retention sweep deleted %d experiment(s) older than %s", len(deleted), c.retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
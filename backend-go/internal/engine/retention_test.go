@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// retentionQuerier records the cutoffs it is asked to delete before. Any
+// other Querier method panics on the nil embed.
+type retentionQuerier struct {
+	db.Querier
+	cutoffs []time.Time
+}
+
+func (q *retentionQuerier) DeleteExperimentsOlderThan(_ context.Context, cutoff pgtype.Timestamptz) ([]string, error) {
+	q.cutoffs = append(q.cutoffs, cutoff.Time)
+	return []string{"old00001"}, nil
+}
+
+func TestRetentionCleanerCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	q := &retentionQuerier{}
+	c := NewRetentionCleaner(q, 30*24*time.Hour, time.Hour)
+	c.now = func() time.Time { return now }
+
+	deleted, err := c.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old00001"}, deleted)
+	require.Len(t, q.cutoffs, 1)
+	assert.Equal(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), q.cutoffs[0])
+}
+
+func TestRetentionCleanerReportsDeletedExperiments(t *testing.T) {
+	c := NewRetentionCleaner(&retentionQuerier{}, time.Hour, time.Hour)
+	var forgotten []string
+	c.SetDeleteHook(func(id string) { forgotten = append(forgotten, id) })
+
+	_, err := c.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old00001"}, forgotten)
+}
+
+func TestRetentionCleanerZeroKeepsForever(t *testing.T) {
+	q := &retentionQuerier{}
+	deleted, err := NewRetentionCleaner(q, 0, time.Hour).Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+	assert.Empty(t, q.cutoffs)
+}
+
+func TestRetentionCleanerRunStopsWithContext(t *testing.T) {
+	q := &retentionQuerier{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewRetentionCleaner(q, time.Hour, time.Hour).Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
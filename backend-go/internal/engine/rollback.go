@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podDeleteRollback is the persisted form of a pod_delete rollback
+type podDeleteRollback struct {
+	Namespace string       `json:"namespace"`
+	Pods      []corev1.Pod `json:"pods"`
+}
+
+// ec2StopRollback is the persisted form of an ec2_stop rollback
+type ec2StopRollback struct {
+	InstanceIDs []string `json:"instance_ids"`
+}
+
+// routeBlackholeRollback is the persisted form of a route_blackhole rollback
+type routeBlackholeRollback struct {
+	RouteTableID      string  `json:"route_table_id"`
+	DestinationCIDR   string  `json:"destination_cidr"`
+	OriginalGatewayID *string `json:"original_gateway_id,omitempty"`
+}
+
+// lambdaThrottleRollback is the persisted form of a lambda_throttle
+// rollback; a nil ReservedConcurrency means the function had no limit
+type lambdaThrottleRollback struct {
+	FunctionName        string `json:"function_name"`
+	ReservedConcurrency *int32 `json:"reserved_concurrency,omitempty"`
+}
+
+// subnetIsolateRollback is the persisted form of a subnet_isolate rollback.
+// AssociationID is the association created by the isolation, not the
+// original one, which AWS retires on replacement.
+type subnetIsolateRollback struct {
+	SubnetID      string `json:"subnet_id"`
+	AssociationID string `json:"association_id"`
+	OriginalACLID string `json:"original_acl_id"`
+}
+
+// newRollbackAction serializes data as the rollback of chaosType. A nil
+// action only means the rollback can't survive a restart, so marshal
+// failures are logged rather than returned.
+func newRollbackAction(chaosType domain.ChaosType, data any) *domain.RollbackAction {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Failed to serialize %s rollback: %v", chaosType, err)
+		return nil
+	}
+	return &domain.RollbackAction{ChaosType: chaosType, Data: raw}
+}
+
+// ReconstructRollback rebuilds the RollbackFunc described by a persisted
+// RollbackAction, using the engine the chaos type was injected with
+func ReconstructRollback(action domain.RollbackAction, k8s *K8sEngine, aws *AwsEngine) (domain.RollbackFunc, error) {
+	switch action.ChaosType {
+	case domain.ChaosTypePodDelete:
+		var data podDeleteRollback
+		if err := json.Unmarshal(action.Data, &data); err != nil {
+			return nil, fmt.Errorf("decode %s rollback: %w", action.ChaosType, err)
+		}
+		if k8s == nil {
+			return nil, fmt.Errorf("K8s engine not available")
+		}
+		return buildPodRollback(k8s.clientset, data.Namespace, data.Pods, k8s.podReadyWait), nil
+
+	case domain.ChaosTypeEC2Stop:
+		var data ec2StopRollback
+		if err := json.Unmarshal(action.Data, &data); err != nil {
+			return nil, fmt.Errorf("decode %s rollback: %w", action.ChaosType, err)
+		}
+		if aws == nil {
+			return nil, fmt.Errorf("AWS engine not available")
+		}
+		return aws.startInstancesRollback(data.InstanceIDs), nil
+
+	case domain.ChaosTypeRouteBlackhole:
+		var data routeBlackholeRollback
+		if err := json.Unmarshal(action.Data, &data); err != nil {
+			return nil, fmt.Errorf("decode %s rollback: %w", action.ChaosType, err)
+		}
+		if aws == nil {
+			return nil, fmt.Errorf("AWS engine not available")
+		}
+		return aws.restoreRouteRollback(data.RouteTableID, data.DestinationCIDR, data.OriginalGatewayID), nil
+
+	case domain.ChaosTypeLambdaThrottle:
+		var data lambdaThrottleRollback
+		if err := json.Unmarshal(action.Data, &data); err != nil {
+			return nil, fmt.Errorf("decode %s rollback: %w", action.ChaosType, err)
+		}
+		if aws == nil {
+			return nil, fmt.Errorf("AWS engine not available")
+		}
+		return aws.restoreConcurrencyRollback(data.FunctionName, data.ReservedConcurrency), nil
+
+	case domain.ChaosTypeSubnetIsolate:
+		var data subnetIsolateRollback
+		if err := json.Unmarshal(action.Data, &data); err != nil {
+			return nil, fmt.Errorf("decode %s rollback: %w", action.ChaosType, err)
+		}
+		if aws == nil {
+			return nil, fmt.Errorf("AWS engine not available")
+		}
+		return aws.restoreACLRollback(data.SubnetID, data.AssociationID, data.OriginalACLID), nil
+	}
+	return nil, fmt.Errorf("rollback for %s cannot be reconstructed", action.ChaosType)
+}
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeEC2 serves the EC2 query API's instance start/stop actions and records
+// the actions and instance IDs it was called with
+type fakeEC2 struct {
+	mu      sync.Mutex
+	actions []string
+	ids     []string
+}
+
+func (f *fakeEC2) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	action := r.Form.Get("Action")
+	f.mu.Lock()
+	f.actions = append(f.actions, action)
+	f.ids = append(f.ids, r.Form.Get("InstanceId.1"))
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/xml")
+	switch action {
+	case "StopInstances", "StartInstances":
+		_, _ = w.Write([]byte(`<` + action + `Response><instancesSet/></` + action + `Response>`))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (f *fakeEC2) calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.actions...)
+}
+
+func newTestEC2Engine(t *testing.T, fake *fakeEC2) *AwsEngine {
+	t.Helper()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	return &AwsEngine{
+		ec2Client: ec2.New(ec2.Options{
+			Region:       "us-east-1",
+			BaseEndpoint: aws.String(srv.URL),
+			Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+		}),
+		esm: safety.NewEmergencyStopManager(),
+	}
+}
+
+// reloadRollback reads the persisted actions of an experiment back from the
+// database, as a restarted process would, and reconstructs the newest one
+func reloadRollback(t *testing.T, fake *fakeDB, experimentID string, k8s *K8sEngine, aws *AwsEngine) domain.RollbackFunc {
+	t.Helper()
+	rows, err := db.New(fake).ListRollbackActions(context.Background(), experimentID)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	fn, err := ReconstructRollback(domain.RollbackAction{
+		ChaosType: domain.ChaosType(rows[0].ChaosType),
+		Data:      rows[0].Data,
+	}, k8s, aws)
+	require.NoError(t, err)
+	return fn
+}
+
+func TestRollbackActionRoundTripPodDelete(t *testing.T) {
+	ctx := context.Background()
+	e := newTestK8sEngine(
+		testPod("web-1", "default", map[string]string{"app": "web"}),
+		testPod("api-1", "default", map[string]string{"app": "api"}),
+	)
+	fake := &fakeDB{}
+	rm := safety.NewRollbackManager()
+	rm.SetQueries(db.New(fake))
+
+	cfg := &domain.ExperimentConfig{
+		Name:      "kill-web",
+		ChaosType: domain.ChaosTypePodDelete,
+		Safety:    domain.SafetyConfig{MaxBlastRadius: 0.5},
+	}
+	res, err := e.PodDelete(ctx, "default", "app=web", nil, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, res.Rollback)
+	rm.PushAction("exp-1", res.RollbackFn, "pod_delete", res.Rollback)
+
+	_, err = e.clientset.CoreV1().Pods("default").Get(ctx, "web-1", metav1.GetOptions{})
+	require.Error(t, err, "pod should be deleted")
+
+	// Simulate a restart: the in-memory stack is gone, only the DB row remains
+	fn := reloadRollback(t, fake, "exp-1", e, nil)
+	out, err := fn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, out["recreated"])
+
+	pod, err := e.clientset.CoreV1().Pods("default").Get(ctx, "web-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "web", pod.Labels["app"])
+}
+
+func TestRollbackActionRoundTripEC2Stop(t *testing.T) {
+	ec2Fake := &fakeEC2{}
+	e := newTestEC2Engine(t, ec2Fake)
+	fake := &fakeDB{}
+	rm := safety.NewRollbackManager()
+	rm.SetQueries(db.New(fake))
+
+	res, err := e.StopEC2(context.Background(), []string{"i-0abc"}, false)
+	require.NoError(t, err)
+	rm.PushAction("exp-2", res.RollbackFn, "ec2_stop", res.Rollback)
+
+	fn := reloadRollback(t, fake, "exp-2", nil, e)
+	out, err := fn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"i-0abc"}, out["started"])
+	assert.Equal(t, []string{"StopInstances", "StartInstances"}, ec2Fake.calls())
+	assert.Equal(t, []string{"i-0abc", "i-0abc"}, ec2Fake.ids)
+}
+
+func TestRollbackManagerDeletesActionAfterSuccess(t *testing.T) {
+	ec2Fake := &fakeEC2{}
+	e := newTestEC2Engine(t, ec2Fake)
+	fake := &fakeDB{}
+	rm := safety.NewRollbackManager()
+	rm.SetQueries(db.New(fake))
+
+	res, err := e.StopEC2(context.Background(), []string{"i-0abc"}, false)
+	require.NoError(t, err)
+	rm.PushAction("exp-3", res.RollbackFn, "ec2_stop", res.Rollback)
+	require.Len(t, fake.actions, 1)
+
+	results := rm.Rollback(context.Background(), "exp-3")
+	require.Len(t, results, 1)
+	assert.Equal(t, "success", results[0].Status)
+	assert.Empty(t, fake.actions, "persisted action should be removed after a successful rollback")
+}
+
+func TestReconstructRollbackUnsupportedType(t *testing.T) {
+	_, err := ReconstructRollback(domain.RollbackAction{ChaosType: domain.ChaosTypeCPUStress}, nil, nil)
+	assert.Error(t, err)
+}
@@ -4,16 +4,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/chaosduck/backend-go/internal/db"
 	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/observability"
+	"github.com/chaosduck/backend-go/internal/params"
 	"github.com/chaosduck/backend-go/internal/probe"
 	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -25,9 +37,28 @@ type Runner struct {
 	esm         *safety.EmergencyStopManager
 	rollbackMgr *safety.RollbackManager
 	snapshotMgr *safety.SnapshotManager
-	queries     *db.Queries
+	freezeMgr   *safety.NamespaceFreezeManager
+	allowed     map[domain.ChaosType]bool
+	safeMode    bool
+	queries     db.Querier
+	metrics     *observability.Metrics
+	persistHook func(experimentID string)
 	aiBaseURL   string
 	aiClient    *http.Client
+	aiTimeouts  AITimeouts
+	aiToken     string
+	persist     PersistRetry
+	// probeCertDir is where probes' ca_file, cert_file and key_file live
+	probeCertDir string
+	// healthLoopHook observes each started HealthCheckLoop, for tests
+	healthLoopHook func(*safety.HealthCheckLoop)
+	// running holds the cancel func of each experiment Run is executing
+	runningMu sync.Mutex
+	running   map[string]context.CancelCauseFunc
+	// inflight counts Run calls past the draining check; draining, set by
+	// Drain, turns new ones away
+	inflight sync.WaitGroup
+	draining bool
 }
 
 // NewRunner creates a new experiment runner
@@ -37,7 +68,8 @@ func NewRunner(
 	esm *safety.EmergencyStopManager,
 	rollbackMgr *safety.RollbackManager,
 	snapshotMgr *safety.SnapshotManager,
-	queries *db.Queries,
+	queries db.Querier,
+	metrics *observability.Metrics,
 	aiBaseURL string,
 ) *Runner {
 	return &Runner{
@@ -47,27 +79,271 @@ func NewRunner(
 		rollbackMgr: rollbackMgr,
 		snapshotMgr: snapshotMgr,
 		queries:     queries,
+		metrics:     metrics,
 		aiBaseURL:   aiBaseURL,
-		aiClient:    &http.Client{Timeout: 30 * time.Second},
+		aiClient:    &http.Client{},
+		aiTimeouts:  DefaultAITimeouts(),
+		persist:     DefaultPersistRetry(),
+		running:     make(map[string]context.CancelCauseFunc),
 	}
 }
 
+// SetAITimeouts sets how long AI service calls may take
+func (r *Runner) SetAITimeouts(t AITimeouts) {
+	r.aiTimeouts = t
+}
+
+// SetAIToken sets the bearer token sent to the AI service. Empty sends no
+// Authorization header.
+func (r *Runner) SetAIToken(token string) {
+	r.aiToken = token
+}
+
+// SetProbeCertDir sets the directory probes' ca_file, cert_file and
+// key_file are read from. Empty rejects file references, leaving inline PEM.
+func (r *Runner) SetProbeCertDir(dir string) {
+	r.probeCertDir = dir
+}
+
+// SetPersistRetry sets how persistResult retries failed writes and where it
+// spills results it could not store
+func (r *Runner) SetPersistRetry(p PersistRetry) {
+	if p.Attempts < 1 {
+		p.Attempts = 1
+	}
+	if p.SpillDir == "" {
+		p.SpillDir = DefaultPersistRetry().SpillDir
+	}
+	r.persist = p
+}
+
+// SetFreezeManager makes Run refuse experiments targeting frozen namespaces
+func (r *Runner) SetFreezeManager(fm *safety.NamespaceFreezeManager) {
+	r.freezeMgr = fm
+}
+
+// checkFrozen returns ErrNamespaceFrozen if any target namespace is frozen
+func (r *Runner) checkFrozen(cfg domain.ExperimentConfig) error {
+	if r.freezeMgr == nil {
+		return nil
+	}
+	for _, ns := range targetNamespaces(cfg) {
+		if err := r.freezeMgr.CheckNamespace(ns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetAllowedChaosTypes restricts Run to the given chaos types; an empty list
+// allows every type. Unknown entries are logged and ignored, so a list with
+// no known types allows nothing.
+func (r *Runner) SetAllowedChaosTypes(types []string) {
+	if len(types) == 0 {
+		r.allowed = nil
+		return
+	}
+	r.allowed = make(map[domain.ChaosType]bool, len(types))
+	for _, t := range types {
+		ct := domain.ChaosType(t)
+		if !domain.IsKnownChaosType(ct) {
+			log.Printf("Warning: ignoring unknown chaos type %q in allowlist", t)
+			continue
+		}
+		r.allowed[ct] = true
+	}
+}
+
+// checkAllowed returns ErrChaosTypeNotAllowed if the chaos type, or that of
+// any step, is outside the allowlist
+func (r *Runner) checkAllowed(cfg domain.ExperimentConfig) error {
+	if r.allowed == nil {
+		return nil
+	}
+	if !r.allowed[cfg.ChaosType] {
+		return fmt.Errorf("%w: %s", domain.ErrChaosTypeNotAllowed, cfg.ChaosType)
+	}
+	for _, step := range cfg.Steps {
+		if !r.allowed[step.ChaosType] {
+			return fmt.Errorf("%w: %s", domain.ErrChaosTypeNotAllowed, step.ChaosType)
+		}
+	}
+	return nil
+}
+
+// SetSafeMode forces every run to dry-run when on, so nothing is ever
+// injected whatever the config asks for
+func (r *Runner) SetSafeMode(on bool) {
+	r.safeMode = on
+}
+
+// SetPersistHook registers a callback invoked after an experiment record is
+// written, used to invalidate read caches
+func (r *Runner) SetPersistHook(fn func(experimentID string)) {
+	r.persistHook = fn
+}
+
 // Run executes the full 5-phase experiment lifecycle with timeout enforcement
 func (r *Runner) Run(ctx context.Context, experimentID string, cfg domain.ExperimentConfig) (*domain.ExperimentResult, error) {
+	result, err := r.run(ctx, experimentID, cfg)
+	if reason := safetyBlockReason(err); reason != "" && r.metrics != nil {
+		r.metrics.RecordSafetyBlock(reason)
+	}
+	return result, err
+}
+
+// safetyBlockReasons maps the guardrail errors to their
+// chaosduck_safety_blocks_total reason, checked in order with errors.Is
+var safetyBlockReasons = []struct {
+	err    error
+	reason string
+}{
+	{domain.ErrBlastRadiusExceeded, "blast_radius"},
+	{domain.ErrNamespaceConfirmation, "confirmation"},
+	{domain.ErrEmergencyStop, "emergency_stop"},
+	{domain.ErrNamespaceFrozen, "frozen_namespace"},
+	{domain.ErrChaosTypeNotAllowed, "chaos_type_not_allowed"},
+}
+
+// safetyBlockReason returns the reason a safety guardrail stopped an
+// experiment with err, or "" when err is nil or not a guardrail's
+func safetyBlockReason(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, b := range safetyBlockReasons {
+		if errors.Is(err, b.err) {
+			return b.reason
+		}
+	}
+	return ""
+}
+
+// AbortAll cancels every running experiment and rolls back all active
+// experiments. Unlike the emergency stop it latches nothing, so new
+// experiments can start as soon as it returns. It returns the IDs of the
+// experiments it cancelled and the rollback results per experiment.
+func (r *Runner) AbortAll(ctx context.Context) ([]string, map[string][]safety.RollbackResult) {
+	r.runningMu.Lock()
+	aborted := slices.Sorted(maps.Keys(r.running))
+	for _, cancel := range r.running {
+		cancel(domain.ErrExperimentAborted)
+	}
+	r.runningMu.Unlock()
+	return aborted, r.rollbackMgr.RollbackAll(context.WithoutCancel(ctx))
+}
+
+// Drain stops Run from starting experiments and waits up to grace for the
+// running ones to finish on their own. Those still running after grace are
+// aborted, so each rolls back its own injection, and Drain waits for them to
+// unwind until ctx is done. It returns the IDs of the experiments it aborted.
+func (r *Runner) Drain(ctx context.Context, grace time.Duration) []string {
+	r.runningMu.Lock()
+	r.draining = true
+	r.runningMu.Unlock()
+
+	// No Run can join inflight once draining is set, so Wait is safe here
+	done := make(chan struct{})
+	go func() {
+		r.inflight.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	r.runningMu.Lock()
+	aborted := slices.Sorted(maps.Keys(r.running))
+	for _, cancel := range r.running {
+		cancel(domain.ErrExperimentAborted)
+	}
+	r.runningMu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return aborted
+}
+
+// enter counts a new Run towards inflight, refusing it once Drain has begun
+func (r *Runner) enter() error {
+	r.runningMu.Lock()
+	defer r.runningMu.Unlock()
+	if r.draining {
+		return domain.ErrServerShuttingDown
+	}
+	r.inflight.Add(1)
+	return nil
+}
+
+// IsRunning reports whether Run is still executing the experiment
+func (r *Runner) IsRunning(experimentID string) bool {
+	r.runningMu.Lock()
+	defer r.runningMu.Unlock()
+	_, ok := r.running[experimentID]
+	return ok
+}
+
+// track registers a running experiment's cancel func with AbortAll and
+// returns the func that unregisters it
+func (r *Runner) track(experimentID string, cancel context.CancelCauseFunc) func() {
+	r.runningMu.Lock()
+	defer r.runningMu.Unlock()
+	r.running[experimentID] = cancel
+	return func() {
+		r.runningMu.Lock()
+		defer r.runningMu.Unlock()
+		delete(r.running, experimentID)
+	}
+}
+
+// abortCause returns domain.ErrExperimentAborted once AbortAll has
+// cancelled ctx, and nil otherwise
+func abortCause(ctx context.Context) error {
+	if cause := context.Cause(ctx); errors.Is(cause, domain.ErrExperimentAborted) {
+		return cause
+	}
+	return nil
+}
+
+// run is Run without the safety block accounting
+func (r *Runner) run(ctx context.Context, experimentID string, cfg domain.ExperimentConfig) (*domain.ExperimentResult, error) {
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer r.inflight.Done()
+	if r.safeMode {
+		cfg.Safety.DryRun = true
+	}
 	if err := r.esm.CheckEmergencyStop(); err != nil {
 		return nil, err
 	}
-
-	// Enforce timeout on the entire experiment lifecycle
-	timeoutSec := cfg.Safety.TimeoutSeconds
-	if timeoutSec < 1 {
-		timeoutSec = 30
+	if err := r.checkAllowed(cfg); err != nil {
+		return nil, err
 	}
-	if timeoutSec > 120 {
-		timeoutSec = 120
+	if err := r.checkFrozen(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.FieldSelector != nil {
+		if err := domain.ValidateFieldSelector(*cfg.FieldSelector); err != nil {
+			return nil, err
+		}
 	}
-	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSec)*time.Second)
+
+	// Enforce timeout on the entire experiment lifecycle
+	ctx, cancel := context.WithTimeout(ctx, experimentTimeout(cfg))
 	defer cancel()
+	ctx, abort := context.WithCancelCause(ctx)
+	defer abort(nil)
+	defer r.track(experimentID, abort)()
+	ctx = WithExperimentID(ctx, experimentID)
 
 	now := time.Now().UTC()
 	result := &domain.ExperimentResult{
@@ -76,13 +352,29 @@ func (r *Runner) Run(ctx context.Context, experimentID string, cfg domain.Experi
 		Status:       domain.StatusRunning,
 		Phase:        domain.PhaseSteadyState,
 		StartedAt:    &now,
+		SafeMode:     r.safeMode,
 	}
 	aiInsights := make(map[string]any)
+	clock := r.startPhaseClock(result)
+	r.recordEvent(ctx, experimentID, domain.EventStarted, "Experiment started", map[string]any{
+		"chaos_type": cfg.ChaosType, "phase": result.Phase, "dry_run": cfg.Safety.DryRun,
+	})
+	clock.onEnter = func(phase domain.ExperimentPhase) {
+		r.recordEvent(ctx, experimentID, domain.EventPhaseChanged, "Entered "+string(phase)+" phase", map[string]any{"phase": phase})
+	}
+	// Runs last, after any failure rollback below
+	defer func() {
+		data := map[string]any{"status": result.Status, "phase": result.Phase}
+		if result.Error != nil {
+			data["error"] = *result.Error
+		}
+		r.recordEvent(ctx, experimentID, domain.EventFinished, "Experiment "+string(result.Status), data)
+	}()
 
 	// Ensure rollback on panic or error
 	defer func() {
 		if result.Status == domain.StatusFailed {
-			r.rollbackMgr.Rollback(experimentID)
+			r.rollback(ctx, experimentID)
 		}
 	}()
 
@@ -94,11 +386,27 @@ func (r *Runner) Run(ctx context.Context, experimentID string, cfg domain.Experi
 	if cfg.TargetNamespace != nil && r.k8s != nil {
 		steadyState, err := r.k8s.GetSteadyState(ctx, *cfg.TargetNamespace)
 		if err != nil {
-			log.Printf("Steady state capture failed: %v", err)
+			observability.Logf(ctx, "Steady state capture failed: %v", err)
 		} else {
 			result.SteadyState = steadyState
 			if _, err := r.snapshotMgr.CaptureK8sSnapshot(ctx, experimentID, *cfg.TargetNamespace, steadyState); err != nil {
-				log.Printf("Failed to capture snapshot for %s: %v", experimentID, err)
+				observability.Logf(ctx, "Failed to capture snapshot for %s: %v", experimentID, err)
+			}
+		}
+	}
+	// AWS faults also capture the state of the resources they target. A
+	// cross-layer experiment keeps both snapshots, one per layer.
+	if resourceType, ids, ok := awsSteadyStateTarget(cfg); ok && r.aws != nil {
+		awsState, err := r.aws.GetSteadyState(ctx, resourceType, ids)
+		if err != nil {
+			observability.Logf(ctx, "AWS steady state capture failed: %v", err)
+		} else {
+			if result.SteadyState == nil {
+				result.SteadyState = map[string]any{}
+			}
+			result.SteadyState["aws"] = awsState
+			if _, err := r.snapshotMgr.CaptureAWSSnapshot(ctx, experimentID, resourceType, strings.Join(ids, ","), awsState); err != nil {
+				observability.Logf(ctx, "Failed to capture AWS snapshot for %s: %v", experimentID, err)
 			}
 		}
 	}
@@ -106,15 +414,16 @@ func (r *Runner) Run(ctx context.Context, experimentID string, cfg domain.Experi
 	// Execute SOT (Start of Test) probes
 	for _, p := range probes {
 		if p.Mode() == domain.ProbeModeSOT {
-			pr := probe.SafeExecute(ctx, p)
+			pr := r.runProbe(ctx, experimentID, p)
 			probeResults = append(probeResults, map[string]any{
 				"probe": pr.ProbeName, "type": pr.ProbeType, "passed": pr.Passed,
 			})
 			if !pr.Passed {
-				log.Printf("SOT probe %s failed, aborting experiment", pr.ProbeName)
+				observability.Logf(ctx, "SOT probe %s failed, aborting experiment", pr.ProbeName)
 				result.Status = domain.StatusFailed
 				errStr := fmt.Sprintf("SOT probe %s failed", pr.ProbeName)
 				result.Error = &errStr
+				clock.stop()
 				r.persistResult(ctx, experimentID, result)
 				return result, fmt.Errorf("%s", errStr)
 			}
@@ -123,79 +432,207 @@ func (r *Runner) Run(ctx context.Context, experimentID string, cfg domain.Experi
 
 	// AI: review steady state
 	if cfg.AIEnabled && result.SteadyState != nil {
-		if review, err := r.callAI("/review-steady-state", map[string]any{
+		if review, err := r.callAI(ctx, "/review-steady-state", map[string]any{
 			"steady_state": result.SteadyState,
 		}); err == nil {
 			aiInsights["steady_state_review"] = review
 		} else {
-			log.Printf("AI steady state review failed: %v", err)
+			observability.Logf(ctx, "AI steady state review failed: %v", err)
+		}
+	}
+
+	// Let the system settle after the SOT probes before injecting. The
+	// warmup is timed on its own, outside the steady state phase.
+	if warmupSeconds, _ := domain.WarmupSecondsParam.Get(cfg.Parameters); warmupSeconds > 0 {
+		clock.stop()
+		waited, err := r.warmup(ctx, warmupSeconds)
+		clock.record("warmup", waited)
+		if err != nil {
+			observability.Logf(ctx, "Experiment %s stopped during warmup: %v", experimentID, err)
+			switch {
+			case errors.Is(err, domain.ErrEmergencyStop):
+				result.Status = domain.StatusEmergencyStopped
+			case errors.Is(err, domain.ErrExperimentAborted):
+				result.Status = domain.StatusRolledBack
+			default:
+				result.Status = domain.StatusFailed
+			}
+			errStr := err.Error()
+			result.Error = &errStr
+			r.persistResult(ctx, experimentID, result)
+			return result, err
 		}
 	}
 
 	// Phase 2: Hypothesis
-	result.Phase = domain.PhaseHypothesis
+	clock.enter(domain.PhaseHypothesis)
 	if cfg.AIEnabled {
 		body := map[string]any{
 			"topology":   result.SteadyState,
 			"target":     cfg.Name,
 			"chaos_type": string(cfg.ChaosType),
 		}
-		if resp, err := r.callAI("/hypotheses", body); err == nil {
+		if resp, err := r.callAI(ctx, "/hypotheses", body); err == nil {
 			if h, ok := resp["hypothesis"].(string); ok {
 				result.Hypothesis = &h
 			}
 		} else {
-			log.Printf("AI hypothesis generation failed: %v", err)
+			observability.Logf(ctx, "AI hypothesis generation failed: %v", err)
 		}
 	}
 
 	// Safety: require confirmation for production namespaces
-	if cfg.TargetNamespace != nil {
-		if err := safety.RequireConfirmation(*cfg.TargetNamespace, "prod*", cfg.Safety.RequireConfirmation); err != nil {
+	for _, ns := range targetNamespaces(cfg) {
+		if err := safety.RequireConfirmation(ns, "prod*", cfg.Safety.RequireConfirmation); err != nil {
 			result.Status = domain.StatusFailed
 			errStr := err.Error()
 			result.Error = &errStr
+			clock.stop()
 			r.persistResult(ctx, experimentID, result)
 			return result, err
 		}
 	}
 
 	// Phase 3: Inject
-	result.Phase = domain.PhaseInject
-	chaosResult, err := r.executeChaos(ctx, &cfg)
-	if err != nil {
+	clock.enter(domain.PhaseInject)
+	logPods := r.podLogTargets(ctx, cfg)
+	r.capturePodLogs(ctx, experimentID, cfg, result, logPods, domain.PodLogsBeforeInjection, time.Time{})
+	injectedAt := time.Now()
+	chaosResult, err := r.inject(ctx, experimentID, &cfg)
+	if err != nil && !partiallyInjected(chaosResult) {
+		r.recordEvent(ctx, experimentID, domain.EventInjectionFailed, "Injection failed", map[string]any{"error": err.Error()})
 		result.Status = domain.StatusFailed
 		errStr := err.Error()
 		result.Error = &errStr
+		// Undo whatever was applied before the failure, including the
+		// steps of a chain that were already pushed
+		if chaosResult != nil && chaosResult.RollbackFn != nil {
+			r.rollbackMgr.PushAction(experimentID, chaosResult.RollbackFn, string(cfg.ChaosType), chaosResult.Rollback)
+		}
+		if r.rollbackMgr.StackSize(experimentID) > 0 {
+			if chaosResult != nil {
+				result.InjectionResult = chaosResult.Result
+			}
+			result.RollbackResult = r.verifiedRollback(ctx, experimentID, cfg)
+		}
+		clock.stop()
 		r.persistResult(ctx, experimentID, result)
 		return result, err
 	}
+	injected := map[string]any{"result": chaosResult.Result}
+	if err != nil {
+		observability.Logf(ctx, "Experiment %s partially injected: %v", experimentID, err)
+		injected["error"] = err.Error()
+	}
+	injectedMsg := "Injected " + string(cfg.ChaosType)
+	if len(cfg.Steps) > 0 {
+		injectedMsg = fmt.Sprintf("Injected %d chained steps", len(cfg.Steps))
+	}
+	r.recordEvent(ctx, experimentID, domain.EventInjected, injectedMsg, injected)
 	result.InjectionResult = chaosResult.Result
 
 	if chaosResult.RollbackFn != nil {
-		r.rollbackMgr.Push(experimentID, chaosResult.RollbackFn, string(cfg.ChaosType))
+		r.rollbackMgr.PushAction(experimentID, chaosResult.RollbackFn, string(cfg.ChaosType), chaosResult.Rollback)
+	}
+
+	// Safety: keep polling the SOT probes while the fault is in place. The
+	// deferred Stop runs on every return below, panics included, so the
+	// loop never outlives the run.
+	if loop := r.startHealthCheck(experimentID, cfg, probes); loop != nil {
+		defer loop.Stop()
+	}
+
+	// Safety: an emergency stop that raced the injection may already have
+	// drained the rollback stacks, so don't leave this one behind
+	if err := r.esm.CheckEmergencyStop(); err != nil {
+		observability.Logf(ctx, "Experiment %s: emergency stop during injection, rolling back", experimentID)
+		result.RollbackResult = r.verifiedRollback(ctx, experimentID, cfg)
+		result.Status = domain.StatusEmergencyStopped
+		errStr := err.Error()
+		result.Error = &errStr
+		clock.stop()
+		r.persistResult(ctx, experimentID, result)
+		return result, err
+	}
+
+	// An abort-all that raced the injection may likewise have missed it
+	if err := abortCause(ctx); err != nil {
+		observability.Logf(ctx, "Experiment %s: aborted during injection, rolling back", experimentID)
+		result.RollbackResult = r.verifiedRollback(ctx, experimentID, cfg)
+		result.Status = domain.StatusRolledBack
+		errStr := err.Error()
+		result.Error = &errStr
+		clock.stop()
+		r.persistResult(ctx, experimentID, result)
+		return result, err
+	}
+
+	// Safety: abort right away if the injection already broke the namespace
+	if reason := r.injectionHealthViolation(ctx, cfg); reason != "" {
+		observability.Logf(ctx, "Experiment %s aborted after injection: %s", experimentID, reason)
+		result.RollbackResult = r.verifiedRollback(ctx, experimentID, cfg)
+		result.Status = domain.StatusFailed
+		result.Error = &reason
+		result.Observations = map[string]any{"probe_results": probeResults}
+		clock.stop()
+		r.persistResult(ctx, experimentID, result)
+		return result, errors.New(reason)
 	}
 
 	// Execute ON_CHAOS probes
 	for _, p := range probes {
 		if p.Mode() == domain.ProbeModeOnChaos {
-			pr := probe.SafeExecute(ctx, p)
+			pr := r.runProbe(ctx, experimentID, p)
 			probeResults = append(probeResults, map[string]any{
 				"probe": pr.ProbeName, "type": pr.ProbeType, "passed": pr.Passed,
 			})
 		}
 	}
 
+	// Hold the fault in place while monitoring, if requested
+	holdSeconds, _ := domain.HoldSecondsParam.Get(cfg.Parameters)
+	var holdSummary map[string]any
+	if holdSeconds > 0 {
+		clock.enter(domain.PhaseObserve)
+		summary, holdErr := r.hold(ctx, experimentID, cfg, probes, holdSeconds, &probeResults)
+		holdSummary = summary
+		if holdErr != nil {
+			result.RollbackResult = r.verifiedRollback(ctx, experimentID, cfg)
+			result.Status = domain.StatusFailed
+			if errors.Is(holdErr, domain.ErrExperimentAborted) {
+				result.Status = domain.StatusRolledBack
+			}
+			errStr := holdErr.Error()
+			result.Error = &errStr
+			result.Observations = map[string]any{"hold": holdSummary, "probe_results": probeResults}
+			clock.stop()
+			r.persistResult(ctx, experimentID, result)
+			return result, holdErr
+		}
+	}
+
 	// Phase 4: Observe
-	result.Phase = domain.PhaseObserve
+	clock.enter(domain.PhaseObserve)
 	if cfg.TargetNamespace != nil && r.k8s != nil {
 		observations, err := r.k8s.GetSteadyState(ctx, *cfg.TargetNamespace)
 		if err != nil {
-			log.Printf("Observation capture failed: %v", err)
+			observability.Logf(ctx, "Observation capture failed: %v", err)
 		} else {
 			result.Observations = observations
 		}
 	}
+	r.capturePodLogs(ctx, experimentID, cfg, result, logPods, domain.PodLogsAfterObservation, injectedAt)
+
+	// Execute continuous probes against the observed state (already polled
+	// throughout a hold)
+	for _, p := range probes {
+		if p.Mode() == domain.ProbeModeContinuous && holdSeconds == 0 {
+			pr := r.runProbe(ctx, experimentID, p)
+			probeResults = append(probeResults, map[string]any{
+				"probe": pr.ProbeName, "type": pr.ProbeType, "passed": pr.Passed,
+			})
+		}
+	}
 
 	// AI: compare observations with steady state
 	if cfg.AIEnabled && result.Observations != nil {
@@ -204,65 +641,714 @@ func (r *Runner) Run(ctx context.Context, experimentID string, cfg domain.Experi
 			"observations": result.Observations,
 			"hypothesis":   result.Hypothesis,
 		}
-		if analysis, err := r.callAI("/compare-observations", body); err == nil {
+		if analysis, err := r.callAI(ctx, "/compare-observations", body); err == nil {
 			aiInsights["observation_analysis"] = analysis
 		} else {
-			log.Printf("AI observation analysis failed: %v", err)
+			observability.Logf(ctx, "AI observation analysis failed: %v", err)
+		}
+	}
+
+	// Execute EOT (End of Test) probes
+	for _, p := range probes {
+		if p.Mode() == domain.ProbeModeEOT {
+			pr := r.runProbe(ctx, experimentID, p)
+			probeResults = append(probeResults, map[string]any{
+				"probe": pr.ProbeName, "type": pr.ProbeType, "passed": pr.Passed,
+			})
+		}
+	}
+
+	// Phase 5: Rollback - always execute rollback to clean up injected faults
+	clock.enter(domain.PhaseRollback)
+	result.RollbackResult = r.verifiedRollback(ctx, experimentID, cfg)
+	result.Status = domain.StatusCompleted
+	completedAt := time.Now().UTC()
+	result.CompletedAt = &completedAt
+
+	// AI: verify recovery
+	if cfg.AIEnabled && result.SteadyState != nil && cfg.TargetNamespace != nil && r.k8s != nil {
+		postState, err := r.k8s.GetSteadyState(ctx, *cfg.TargetNamespace)
+		if err == nil {
+			body := map[string]any{
+				"original_state": result.SteadyState,
+				"current_state":  postState,
+			}
+			if recovery, err := r.callAI(ctx, "/verify-recovery", body); err == nil {
+				aiInsights["recovery_verification"] = recovery
+			} else {
+				observability.Logf(ctx, "AI recovery verification failed: %v", err)
+			}
+		}
+	}
+
+	if len(aiInsights) > 0 {
+		result.AIInsights = aiInsights
+	}
+	if len(probeResults) > 0 || holdSummary != nil {
+		if result.Observations == nil {
+			result.Observations = make(map[string]any)
+		}
+		if len(probeResults) > 0 {
+			result.Observations["probe_results"] = probeResults
+		}
+		if holdSummary != nil {
+			result.Observations["hold"] = holdSummary
+		}
+	}
+
+	clock.stop()
+	r.persistResult(ctx, experimentID, result)
+	return result, nil
+}
+
+// pausePollInterval is how often a warmup or step delay checks the
+// emergency stop
+const pausePollInterval = time.Second
+
+// warmup waits warmupSeconds before injection, returning how long it
+// waited. It takes at most half the time left before the experiment
+// timeout, so the rest of the run keeps its share, and ends early with an
+// error on an emergency stop, an abort-all or the context ending.
+func (r *Runner) warmup(ctx context.Context, warmupSeconds int) (time.Duration, error) {
+	return r.pause(ctx, time.Duration(warmupSeconds)*time.Second, "warmup")
+}
+
+// pause is warmup for any wait, such as a chained step's delay; what names
+// it in context errors
+func (r *Runner) pause(ctx context.Context, wait time.Duration, what string) (time.Duration, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		wait = max(min(wait, time.Until(deadline)/2), 0)
+	}
+	start := time.Now()
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	ticker := time.NewTicker(pausePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return time.Since(start), nil
+		case <-ctx.Done():
+			if err := abortCause(ctx); err != nil {
+				return time.Since(start), err
+			}
+			return time.Since(start), fmt.Errorf("%s: %w", what, ctx.Err())
+		case <-ticker.C:
+			if err := r.esm.CheckEmergencyStop(); err != nil {
+				return time.Since(start), err
+			}
+		}
+	}
+}
+
+// holdReserve is kept free at the end of the experiment timeout so the
+// observe and rollback phases still have time to run after a hold
+const holdReserve = 5 * time.Second
+
+// hold keeps the injected fault in place for up to holdSeconds, polling
+// continuous probes and the target namespace's steady state every
+// Safety.HealthCheckInterval. The hold is clamped to the experiment timeout
+// (minus holdReserve), so hold_seconds can never outlast timeout_seconds. It
+// returns an error, and the caller rolls back early, when pods_healthy_ratio
+// drops below min_healthy_ratio or continuous probes fail
+// Safety.HealthCheckFailureThreshold polls in a row.
+func (r *Runner) hold(ctx context.Context, experimentID string, cfg domain.ExperimentConfig, probes []probe.Probe, holdSeconds int, probeResults *[]map[string]any) (map[string]any, error) {
+	holdFor := time.Duration(holdSeconds) * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		if limit := time.Until(deadline) - holdReserve; holdFor > limit {
+			holdFor = max(limit, 0)
+		}
+	}
+	minRatio, _ := domain.MinHealthyRatioParam.Get(cfg.Parameters)
+	interval := time.Duration(max(cfg.Safety.HealthCheckInterval, 1)) * time.Second
+	threshold := max(cfg.Safety.HealthCheckFailureThreshold, 1)
+
+	summary := map[string]any{
+		"requested_seconds": holdSeconds,
+		"min_healthy_ratio": minRatio,
+		"polls":             0,
+		"aborted":           false,
+	}
+	start := time.Now()
+	defer func() { summary["held_seconds"] = time.Since(start).Seconds() }()
+
+	timer := time.NewTimer(holdFor)
+	defer timer.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	consecutiveFailures := 0
+	for {
+		select {
+		case <-timer.C:
+			return summary, nil
+		case <-ctx.Done():
+			if err := abortCause(ctx); err != nil {
+				summary["aborted"] = true
+				summary["reason"] = err.Error()
+				return summary, err
+			}
+			return summary, nil
+		case <-ticker.C:
+		}
+
+		if err := r.esm.CheckEmergencyStop(); err != nil {
+			summary["aborted"] = true
+			summary["reason"] = err.Error()
+			return summary, err
+		}
+		summary["polls"] = summary["polls"].(int) + 1
+
+		if cfg.TargetNamespace != nil && r.k8s != nil {
+			state, err := r.k8s.GetSteadyState(ctx, *cfg.TargetNamespace)
+			if err != nil {
+				observability.Logf(ctx, "Hold steady state capture failed for %s: %v", experimentID, err)
+			} else if ratio, ok := state["pods_healthy_ratio"].(float64); ok {
+				summary["last_healthy_ratio"] = ratio
+				if ratio < minRatio {
+					reason := fmt.Sprintf("hold aborted: pods_healthy_ratio %.2f below %.2f", ratio, minRatio)
+					summary["aborted"] = true
+					summary["reason"] = reason
+					return summary, errors.New(reason)
+				}
+			}
+		}
+
+		allPassed := true
+		for _, p := range probes {
+			if p.Mode() != domain.ProbeModeContinuous {
+				continue
+			}
+			pr := r.runProbe(ctx, experimentID, p)
+			*probeResults = append(*probeResults, map[string]any{
+				"probe": pr.ProbeName, "type": pr.ProbeType, "passed": pr.Passed,
+			})
+			allPassed = allPassed && pr.Passed
+		}
+		if allPassed {
+			consecutiveFailures = 0
+			continue
+		}
+		consecutiveFailures++
+		if consecutiveFailures >= threshold {
+			reason := fmt.Sprintf("hold aborted: continuous probes failed %d consecutive polls", consecutiveFailures)
+			summary["aborted"] = true
+			summary["reason"] = reason
+			return summary, errors.New(reason)
+		}
+	}
+}
+
+// injectionHealthViolation re-captures the target namespace right after
+// injection and returns an abort reason when pods_healthy_ratio is already
+// below abort_on_healthy_ratio_below. It returns "" when the guard is unset
+// or the state can't be read.
+func (r *Runner) injectionHealthViolation(ctx context.Context, cfg domain.ExperimentConfig) string {
+	threshold, _ := domain.AbortHealthyRatioParam.Get(cfg.Parameters)
+	if threshold <= 0 || cfg.TargetNamespace == nil || r.k8s == nil {
+		return ""
+	}
+	state, err := r.k8s.GetSteadyState(ctx, *cfg.TargetNamespace)
+	if err != nil {
+		observability.Logf(ctx, "Post-injection steady state capture failed: %v", err)
+		return ""
+	}
+	ratio, ok := state["pods_healthy_ratio"].(float64)
+	if !ok || ratio >= threshold {
+		return ""
+	}
+	return fmt.Sprintf("aborted after injection: pods_healthy_ratio %.2f below %.2f", ratio, threshold)
+}
+
+// phaseClock accumulates the time a run spends in each lifecycle phase into
+// result.PhaseTimings and the phase duration histogram
+type phaseClock struct {
+	result  *domain.ExperimentResult
+	metrics *observability.Metrics
+	current domain.ExperimentPhase
+	started time.Time
+	// onEnter, when set, is called on every phase change
+	onEnter func(domain.ExperimentPhase)
+}
+
+func (r *Runner) startPhaseClock(result *domain.ExperimentResult) *phaseClock {
+	result.PhaseTimings = make(map[string]float64)
+	return &phaseClock{result: result, metrics: r.metrics, current: result.Phase, started: time.Now()}
+}
+
+// enter closes the current phase and starts timing the next one
+func (c *phaseClock) enter(phase domain.ExperimentPhase) {
+	c.result.Phase = phase
+	if phase == c.current {
+		return
+	}
+	c.stop()
+	c.current = phase
+	c.started = time.Now()
+	if c.onEnter != nil {
+		c.onEnter(phase)
+	}
+}
+
+// stop closes the current phase, if any
+func (c *phaseClock) stop() {
+	if c.current == "" {
+		return
+	}
+	seconds := time.Since(c.started).Seconds()
+	c.result.PhaseTimings[string(c.current)] += seconds
+	if c.metrics != nil {
+		c.metrics.RecordPhaseDuration(string(c.current), seconds)
+	}
+	c.current = ""
+}
+
+// record adds time spent outside any phase, e.g. a warmup, to the phase
+// timings under name
+func (c *phaseClock) record(name string, d time.Duration) {
+	c.result.PhaseTimings[name] += d.Seconds()
+	if c.metrics != nil {
+		c.metrics.RecordPhaseDuration(name, d.Seconds())
+	}
+}
+
+// rollbackResultMap keys rollback results by execution order
+func rollbackResultMap(results []safety.RollbackResult) map[string]any {
+	if len(results) == 0 {
+		return nil
+	}
+	rbMap := make(map[string]any, len(results))
+	for i, rr := range results {
+		rbMap[fmt.Sprintf("rollback_%d", i)] = rr
+	}
+	return rbMap
+}
+
+// DryRun resolves the experiment's targets through the engines' dry-run paths
+// without mutating anything. Safety violations a real run would hit (blast
+// radius, namespace confirmation, missing targets) are collected and returned
+// instead of aborting the preview. The preview is persisted under
+// experimentID, or a new ID when that one is taken, so it can later be
+// promoted to a real run; the result carries the ID it was stored under.
+func (r *Runner) DryRun(ctx context.Context, experimentID string, cfg domain.ExperimentConfig) (*domain.ExperimentResult, []error) {
+	cfg.Safety.DryRun = true
+
+	now := time.Now().UTC()
+	result := &domain.ExperimentResult{
+		ExperimentID: experimentID,
+		Config:       cfg,
+		Status:       domain.StatusCompleted,
+		Phase:        domain.PhaseInject,
+		StartedAt:    &now,
+	}
+
+	var errs []error
+	if err := r.esm.CheckEmergencyStop(); err != nil {
+		errs = append(errs, err)
+	} else {
+		ctx, cancel := context.WithTimeout(ctx, experimentTimeout(cfg))
+		defer cancel()
+
+		if err := r.checkAllowed(cfg); err != nil {
+			errs = append(errs, err)
+		}
+		if err := r.checkFrozen(cfg); err != nil {
+			errs = append(errs, err)
+		}
+		for _, ns := range targetNamespaces(cfg) {
+			if err := safety.RequireConfirmation(ns, "prod*", cfg.Safety.RequireConfirmation); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		chaosResult, err := r.inject(ctx, experimentID, &cfg)
+		if chaosResult != nil {
+			result.InjectionResult = chaosResult.Result
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		result.Status = domain.StatusFailed
+		errStr := errors.Join(errs...).Error()
+		result.Error = &errStr
+	}
+	completedAt := time.Now().UTC()
+	result.CompletedAt = &completedAt
+
+	// Keep the preview so it can be promoted to a real run unchanged. It
+	// must never overwrite another experiment's record, so an ID that is
+	// already taken is replaced.
+	for attempt := 1; ; attempt++ {
+		err := r.savePreview(ctx, result)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errPreviewIDTaken) || attempt >= dryRunIDAttempts {
+			errs = append(errs, fmt.Errorf("preview not stored: %w", err))
+			result.ExperimentID = ""
+			break
+		}
+		result.ExperimentID = NewDryRunID()
+	}
+	return result, errs
+}
+
+// experimentTimeout returns the configured experiment timeout clamped to 1-120s,
+// defaulting to 30s when unset
+func experimentTimeout(cfg domain.ExperimentConfig) time.Duration {
+	timeoutSec := cfg.Safety.TimeoutSeconds
+	if timeoutSec < 1 {
+		timeoutSec = 30
+	}
+	if timeoutSec > 120 {
+		timeoutSec = 120
+	}
+	return time.Duration(timeoutSec) * time.Second
+}
+
+// targetNamespaces returns every namespace an experiment targets
+func targetNamespaces(cfg domain.ExperimentConfig) []string {
+	if len(cfg.TargetNamespaces) > 0 {
+		return cfg.TargetNamespaces
+	}
+	if cfg.TargetNamespace != nil {
+		return []string{*cfg.TargetNamespace}
+	}
+	return nil
+}
+
+// inject executes the chaos action once, or across every namespace in
+// TargetNamespaces
+func (r *Runner) inject(ctx context.Context, experimentID string, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+	if len(cfg.Steps) > 0 {
+		return r.injectSteps(ctx, experimentID, cfg)
+	}
+	if len(cfg.TargetNamespaces) == 0 {
+		return r.executeChaos(ctx, cfg)
+	}
+	return r.fanOut(ctx, cfg)
+}
+
+// injectSteps injects a chained experiment's steps in order, waiting each
+// step's delay first (skipped in a dry run). Every step's rollback is pushed as soon as it is
+// injected, so the chain unwinds in reverse order. The first failing step,
+// emergency stop or abort ends the chain with an error; the steps before it
+// stay on the rollback stack for the caller to undo. The result has the shape
+//
+//	{"action": "steps", "steps": [{"step": 1, "chaos_type": "...", "result": {...}, "error": "..."}]}
+func (r *Runner) injectSteps(ctx context.Context, experimentID string, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+	steps := make([]map[string]any, 0, len(cfg.Steps))
+	chain := &domain.ChaosResult{Result: map[string]any{"action": "steps", "steps": steps}}
+	fail := func(entry map[string]any, err error) (*domain.ChaosResult, error) {
+		entry["error"] = err.Error()
+		chain.Result["steps"] = append(steps, entry)
+		return chain, err
+	}
+
+	for i, step := range cfg.Steps {
+		entry := map[string]any{"step": i + 1, "chaos_type": string(step.ChaosType)}
+		if step.DelaySeconds > 0 && !cfg.Safety.DryRun {
+			if _, err := r.pause(ctx, time.Duration(step.DelaySeconds)*time.Second, "step delay"); err != nil {
+				return fail(entry, fmt.Errorf("step %d (%s): %w", i+1, step.ChaosType, err))
+			}
+		}
+		if err := r.esm.CheckEmergencyStop(); err != nil {
+			return fail(entry, err)
+		}
+		if err := abortCause(ctx); err != nil {
+			return fail(entry, err)
+		}
+
+		stepCfg := cfg.StepConfig(i)
+		res, err := r.inject(ctx, experimentID, &stepCfg)
+		if res != nil {
+			entry["result"] = res.Result
+			if res.RollbackFn != nil {
+				r.rollbackMgr.PushAction(experimentID, res.RollbackFn, fmt.Sprintf("step %d: %s", i+1, step.ChaosType), res.Rollback)
+			}
+		}
+		// A partially fanned-out step still counts as failed: later steps
+		// would compound a fault that is not in place everywhere
+		if err != nil {
+			return fail(entry, fmt.Errorf("step %d (%s): %w", i+1, step.ChaosType, err))
+		}
+		steps = append(steps, entry)
+		chain.Result["steps"] = steps
+		observability.Logf(ctx, "Experiment %s: injected step %d/%d (%s)", experimentID, i+1, len(cfg.Steps), step.ChaosType)
+	}
+	return chain, nil
+}
+
+// fanOut injects into every namespace in TargetNamespaces concurrently. Each
+// namespace gets its own blast radius check, and a failure in one does not
+// stop the others. The result has the shape
+//
+//	{"action": "<chaos_type>",
+//	 "namespaces": {"<ns>": {"status": "injected"|"failed", "result": {...}, "error": "..."}},
+//	 "succeeded": [...], "failed": [...]}
+//
+// and the rollback function undoes every successful namespace. The returned
+// error joins the per-namespace failures.
+func (r *Runner) fanOut(ctx context.Context, cfg *domain.ExperimentConfig) (*domain.ChaosResult, error) {
+	type outcome struct {
+		res *domain.ChaosResult
+		err error
+	}
+	outcomes := make([]outcome, len(cfg.TargetNamespaces))
+
+	var wg sync.WaitGroup
+	for i, ns := range cfg.TargetNamespaces {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nsCfg := *cfg
+			nsCfg.TargetNamespace = &ns
+			nsCfg.TargetNamespaces = nil
+			res, err := r.executeChaos(ctx, &nsCfg)
+			outcomes[i] = outcome{res: res, err: err}
+		}()
+	}
+	wg.Wait()
+
+	perNamespace := make(map[string]any, len(outcomes))
+	succeeded := []string{}
+	failed := []string{}
+	var errs []error
+	var rollbacks []namespaceRollback
+	for i, o := range outcomes {
+		ns := cfg.TargetNamespaces[i]
+		entry := map[string]any{"status": "injected"}
+		if o.res != nil {
+			entry["result"] = o.res.Result
+		}
+		if o.err != nil {
+			entry["status"] = "failed"
+			entry["error"] = o.err.Error()
+			failed = append(failed, ns)
+			errs = append(errs, fmt.Errorf("namespace %s: %w", ns, o.err))
+		} else {
+			succeeded = append(succeeded, ns)
+		}
+		// A failed namespace can still have partially applied changes to undo
+		if o.res != nil && o.res.RollbackFn != nil {
+			rollbacks = append(rollbacks, namespaceRollback{namespace: ns, fn: o.res.RollbackFn})
+		}
+		perNamespace[ns] = entry
+	}
+
+	result := &domain.ChaosResult{
+		Result: map[string]any{
+			"action":     string(cfg.ChaosType),
+			"namespaces": perNamespace,
+			"succeeded":  succeeded,
+			"failed":     failed,
+		},
+	}
+	if len(rollbacks) > 0 {
+		result.RollbackFn = rollbackNamespaces(rollbacks)
+	}
+	return result, errors.Join(errs...)
+}
+
+// rollback runs an experiment's rollback stack. It is detached from ctx's
+// cancellation so an experiment that hit its timeout still gets cleaned up;
+// the rollback manager bounds each rollback on its own.
+func (r *Runner) rollback(ctx context.Context, experimentID string) []safety.RollbackResult {
+	results := r.rollbackMgr.Rollback(context.WithoutCancel(ctx), experimentID)
+	for _, rr := range results {
+		data := map[string]any{"status": rr.Status, "result": rr.Result}
+		if rr.Error != "" {
+			data["error"] = rr.Error
+		}
+		r.recordEvent(ctx, experimentID, domain.EventRollback, "Rollback "+rr.Status+": "+rr.Description, data)
+	}
+	return results
+}
+
+// verifiedRollback runs the rollback and, when cfg.Safety.VerifyRollback is
+// set, adds the drift still present afterwards as "residual_drift"
+func (r *Runner) verifiedRollback(ctx context.Context, experimentID string, cfg domain.ExperimentConfig) map[string]any {
+	rbMap := rollbackResultMap(r.rollback(ctx, experimentID))
+	if !cfg.Safety.VerifyRollback {
+		return rbMap
+	}
+	drift, err := r.VerifyRollback(ctx, experimentID)
+	if err != nil {
+		observability.Logf(ctx, "Rollback verification for %s skipped: %v", experimentID, err)
+		return rbMap
+	}
+	if rbMap == nil {
+		rbMap = make(map[string]any, 1)
+	}
+	rbMap["residual_drift"] = drift
+	return rbMap
+}
+
+// VerifyRollback re-captures the steady state of the namespace and the AWS
+// resources snapshotted before injection and returns the drift the snapshot
+// manager still finds, e.g. pods that never came back or an instance left
+// stopped. It is empty once the system has recovered. A cross-layer
+// experiment is verified against both snapshots; a layer that cannot be
+// re-captured is logged and skipped, and only when no layer could be
+// verified is an error returned.
+func (r *Runner) VerifyRollback(ctx context.Context, experimentID string) ([]map[string]any, error) {
+	snapshots := r.snapshotMgr.GetSnapshots(experimentID)
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshot found for experiment %s", experimentID)
+	}
+	drift := []map[string]any{}
+	var errs []error
+	for _, layer := range slices.Sorted(maps.Keys(snapshots)) {
+		var layerDrift []map[string]any
+		var err error
+		if layer == "aws" {
+			layerDrift, err = r.verifyAWSRollback(ctx, experimentID, snapshots[layer])
+		} else {
+			layerDrift, err = r.verifyK8sRollback(ctx, experimentID, snapshots[layer])
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
+		drift = append(drift, layerDrift...)
+	}
+	if len(errs) == len(snapshots) {
+		return nil, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		observability.Logf(ctx, "Rollback verification for %s is partial: %v", experimentID, err)
+	}
+	return drift, nil
+}
+
+// verifyK8sRollback re-captures the steady state of the namespace in
+// snapshot and returns the drift from it
+func (r *Runner) verifyK8sRollback(ctx context.Context, experimentID string, snapshot map[string]any) ([]map[string]any, error) {
+	namespace, _ := snapshot["namespace"].(string)
+	if snapshot["type"] != "k8s" || namespace == "" {
+		return nil, fmt.Errorf("experiment %s has no Kubernetes snapshot to verify against", experimentID)
+	}
+	if r.k8s == nil {
+		return nil, fmt.Errorf("kubernetes engine not available")
+	}
+
+	current, err := r.k8s.GetSteadyState(context.WithoutCancel(ctx), namespace)
+	if err != nil {
+		return nil, fmt.Errorf("capture steady state: %w", err)
+	}
+	drift := r.snapshotMgr.DetectDrift(snapshot, current)
+	if len(drift) > 0 {
+		observability.Logf(ctx, "Rollback of %s left %d residual drift(s) in %s", experimentID, len(drift), namespace)
+	}
+	return drift, nil
+}
+
+// verifyAWSRollback re-captures the state of the AWS resources in snapshot
+// and returns the drift from it
+func (r *Runner) verifyAWSRollback(ctx context.Context, experimentID string, snapshot map[string]any) ([]map[string]any, error) {
+	if r.aws == nil {
+		return nil, fmt.Errorf("aws engine not available")
+	}
+	resourceType, _ := snapshot["resource_type"].(string)
+	resourceID, _ := snapshot["resource_id"].(string)
+	current, err := r.aws.GetSteadyState(context.WithoutCancel(ctx), resourceType, strings.Split(resourceID, ","))
+	if err != nil {
+		return nil, fmt.Errorf("capture AWS steady state: %w", err)
+	}
+	drift := r.snapshotMgr.DetectDrift(snapshot, current)
+	if len(drift) > 0 {
+		observability.Logf(ctx, "Rollback of %s left %d residual drift(s) in %s %s", experimentID, len(drift), resourceType, resourceID)
 	}
+	return drift, nil
+}
 
-	// Execute EOT (End of Test) probes
-	for _, p := range probes {
-		if p.Mode() == domain.ProbeModeEOT {
-			pr := probe.SafeExecute(ctx, p)
-			probeResults = append(probeResults, map[string]any{
-				"probe": pr.ProbeName, "type": pr.ProbeType, "passed": pr.Passed,
-			})
-		}
+// awsSteadyStateTarget returns the AWS resources whose state is captured
+// before cfg's fault: the instances of ec2_stop, the cluster of rds_failover
+// and the DB instance of rds_reboot. ok is false for other chaos types and
+// when the parameters are invalid, which injection reports.
+//
+// route_blackhole, lambda_throttle and subnet_isolate take no AWS baseline:
+// GetSteadyState cannot describe route tables, function concurrency or
+// network ACL associations yet, so their rollbacks are verified only on the
+// Kubernetes side of a cross-layer experiment.
+func awsSteadyStateTarget(cfg domain.ExperimentConfig) (resourceType string, ids []string, ok bool) {
+	switch cfg.ChaosType {
+	case domain.ChaosTypeEC2Stop:
+		instanceIDs, err := params.GetStringSliceRequired(cfg.Parameters, "instance_ids")
+		return AWSResourceEC2, instanceIDs, err == nil
+	case domain.ChaosTypeRDSFailover:
+		id, err := params.GetStringRequired(cfg.Parameters, "db_cluster_id")
+		return AWSResourceRDSCluster, []string{id}, err == nil
+	case domain.ChaosTypeRDSReboot:
+		id, err := params.GetStringRequired(cfg.Parameters, "db_instance_id")
+		return AWSResourceRDSInstance, []string{id}, err == nil
 	}
+	return "", nil, false
+}
 
-	// Phase 5: Rollback - always execute rollback to clean up injected faults
-	result.Phase = domain.PhaseRollback
-	rollbackResults := r.rollbackMgr.Rollback(experimentID)
-	if len(rollbackResults) > 0 {
-		rbMap := make(map[string]any)
-		for i, rr := range rollbackResults {
-			rbMap[fmt.Sprintf("rollback_%d", i)] = rr
-		}
-		result.RollbackResult = rbMap
+// recordEvent appends an entry to the experiment's audit timeline. It is
+// detached from ctx's cancellation so a run that timed out still records
+// how it ended.
+func (r *Runner) recordEvent(ctx context.Context, experimentID string, eventType domain.ExperimentEventType, message string, data map[string]any) {
+	if r.queries == nil {
+		return
 	}
-	result.Status = domain.StatusCompleted
-	completedAt := time.Now().UTC()
-	result.CompletedAt = &completedAt
+	if data == nil {
+		data = map[string]any{}
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		observability.Logf(ctx, "Failed to marshal %s event for %s: %v", eventType, experimentID, err)
+		dataJSON = []byte("{}")
+	}
+	if err := r.queries.CreateExperimentEvent(context.WithoutCancel(ctx), db.CreateExperimentEventParams{
+		ExperimentID: experimentID,
+		EventType:    string(eventType),
+		Message:      message,
+		Data:         dataJSON,
+		OccurredAt:   pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+	}); err != nil {
+		observability.Logf(ctx, "Failed to record %s event for %s: %v", eventType, experimentID, err)
+	}
+}
 
-	// AI: verify recovery
-	if cfg.AIEnabled && result.SteadyState != nil && cfg.TargetNamespace != nil && r.k8s != nil {
-		postState, err := r.k8s.GetSteadyState(ctx, *cfg.TargetNamespace)
-		if err == nil {
-			body := map[string]any{
-				"original_state": result.SteadyState,
-				"current_state":  postState,
-			}
-			if recovery, err := r.callAI("/verify-recovery", body); err == nil {
-				aiInsights["recovery_verification"] = recovery
-			} else {
-				log.Printf("AI recovery verification failed: %v", err)
+type namespaceRollback struct {
+	namespace string
+	fn        domain.RollbackFunc
+}
+
+// rollbackNamespaces combines per-namespace rollbacks into one function that
+// runs them all in reverse order and reports each namespace's outcome
+func rollbackNamespaces(rollbacks []namespaceRollback) domain.RollbackFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		results := make(map[string]any, len(rollbacks))
+		var errs []error
+		for i := len(rollbacks) - 1; i >= 0; i-- {
+			rb := rollbacks[i]
+			res, err := rb.fn(ctx)
+			if err != nil {
+				results[rb.namespace] = map[string]any{"error": err.Error()}
+				errs = append(errs, fmt.Errorf("namespace %s: %w", rb.namespace, err))
+				continue
 			}
+			results[rb.namespace] = res
 		}
+		return map[string]any{"namespaces": results}, errors.Join(errs...)
 	}
+}
 
-	if len(aiInsights) > 0 {
-		result.AIInsights = aiInsights
-	}
-	if len(probeResults) > 0 {
-		if result.Observations == nil {
-			result.Observations = make(map[string]any)
-		}
-		result.Observations["probe_results"] = probeResults
+// partiallyInjected reports whether a fan-out injection succeeded in at
+// least one namespace
+func partiallyInjected(res *domain.ChaosResult) bool {
+	if res == nil {
+		return false
 	}
-
-	r.persistResult(ctx, experimentID, result)
-	return result, nil
+	succeeded, _ := res.Result["succeeded"].([]string)
+	return len(succeeded) > 0
 }
 
 // executeChaos routes to the appropriate chaos function based on type
@@ -279,163 +1365,434 @@ func (r *Runner) executeChaos(ctx context.Context, cfg *domain.ExperimentConfig)
 		if r.k8s == nil {
 			return nil, fmt.Errorf("k8s engine not available")
 		}
-		return r.k8s.PodDelete(ctx, namespace, labelSelector, cfg)
+		gracePeriod, err := domain.PodDeleteGracePeriod(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		return r.k8s.PodDelete(ctx, namespace, labelSelector, gracePeriod, cfg)
+
+	case domain.ChaosTypePodEvict:
+		if r.k8s == nil {
+			return nil, fmt.Errorf("k8s engine not available")
+		}
+		return r.k8s.PodEvict(ctx, namespace, labelSelector, cfg)
 
 	case domain.ChaosTypeNetworkLatency:
 		if r.k8s == nil {
 			return nil, fmt.Errorf("k8s engine not available")
 		}
-		latencyMs := 100
-		if v, ok := cfg.Parameters["latency_ms"]; ok {
-			if f, ok := v.(float64); ok {
-				latencyMs = int(f)
-			}
+		delay, err := domain.NetworkDelay(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
 		}
-		if latencyMs < 1 || latencyMs > 60000 {
-			return nil, fmt.Errorf("latency_ms must be 1-60000, got %d", latencyMs)
+		iface, err := domain.NetworkInterface(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
 		}
-		return r.k8s.NetworkLatency(ctx, namespace, labelSelector, latencyMs, cfg)
+		return r.k8s.NetworkLatency(ctx, namespace, labelSelector, delay, iface, cfg)
 
 	case domain.ChaosTypeNetworkLoss:
 		if r.k8s == nil {
 			return nil, fmt.Errorf("k8s engine not available")
 		}
-		lossPercent := 10
-		if v, ok := cfg.Parameters["loss_percent"]; ok {
-			if f, ok := v.(float64); ok {
-				lossPercent = int(f)
-			}
+		lossPercent, err := domain.LossPercentParam.Get(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		iface, err := domain.NetworkInterface(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		return r.k8s.NetworkLoss(ctx, namespace, labelSelector, lossPercent, iface, cfg)
+
+	case domain.ChaosTypeNetworkFault:
+		if r.k8s == nil {
+			return nil, fmt.Errorf("k8s engine not available")
+		}
+		fault, err := domain.NetworkFault(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
 		}
-		if lossPercent < 1 || lossPercent > 100 {
-			return nil, fmt.Errorf("loss_percent must be 1-100, got %d", lossPercent)
+		iface, err := domain.NetworkInterface(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
 		}
-		return r.k8s.NetworkLoss(ctx, namespace, labelSelector, lossPercent, cfg)
+		return r.k8s.NetworkFault(ctx, namespace, labelSelector, fault, iface, cfg)
 
 	case domain.ChaosTypeCPUStress:
 		if r.k8s == nil {
 			return nil, fmt.Errorf("k8s engine not available")
 		}
-		cores := 1
-		if v, ok := cfg.Parameters["cores"]; ok {
-			if f, ok := v.(float64); ok {
-				cores = int(f)
-			}
+		cores, err := domain.CoresParam.Get(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
 		}
-		if cores < 1 || cores > 64 {
-			return nil, fmt.Errorf("cores must be 1-64, got %d", cores)
+		opts, err := stressOptions(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
 		}
-		return r.k8s.CPUStress(ctx, namespace, labelSelector, cores, cfg.Safety.TimeoutSeconds, cfg)
+		return r.k8s.CPUStress(ctx, namespace, labelSelector, cores, cfg.Safety.TimeoutSeconds, opts, cfg)
 
 	case domain.ChaosTypeMemoryStress:
 		if r.k8s == nil {
 			return nil, fmt.Errorf("k8s engine not available")
 		}
-		memBytes := "256M"
-		if v, ok := cfg.Parameters["memory_bytes"]; ok {
-			if s, ok := v.(string); ok {
-				memBytes = s
-			}
+		memBytes, err := params.GetString(cfg.Parameters, "memory_bytes", domain.DefaultMemoryBytes)
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		vmWorkers, err := domain.VMWorkersParam.Get(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		opts, err := stressOptions(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		return r.k8s.MemoryStress(ctx, namespace, labelSelector, memBytes, vmWorkers, cfg.Safety.TimeoutSeconds, opts, cfg)
+
+	case domain.ChaosTypeClockSkew:
+		if r.k8s == nil {
+			return nil, fmt.Errorf("k8s engine not available")
+		}
+		offset, err := domain.ClockOffsetParam.Get(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		return r.k8s.ClockSkew(ctx, namespace, labelSelector, offset, cfg)
+
+	case domain.ChaosTypeProcessKill:
+		if r.k8s == nil {
+			return nil, fmt.Errorf("k8s engine not available")
 		}
-		return r.k8s.MemoryStress(ctx, namespace, labelSelector, memBytes, cfg.Safety.TimeoutSeconds, cfg)
+		pattern, err := domain.ProcessPattern(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		signal, err := domain.ProcessSignal(cfg.Parameters)
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		return r.k8s.ProcessKill(ctx, namespace, labelSelector, pattern, signal, cfg)
 
 	// AWS chaos types
 	case domain.ChaosTypeEC2Stop:
 		if r.aws == nil {
 			return nil, fmt.Errorf("aws engine not available")
 		}
-		ids := extractStringSlice(cfg.Parameters, "instance_ids")
+		ids, err := params.GetStringSliceRequired(cfg.Parameters, "instance_ids")
+		if err != nil {
+			return nil, invalidParam(err)
+		}
 		return r.aws.StopEC2(ctx, ids, cfg.Safety.DryRun)
 
 	case domain.ChaosTypeRDSFailover:
 		if r.aws == nil {
 			return nil, fmt.Errorf("aws engine not available")
 		}
-		clusterID, _ := cfg.Parameters["db_cluster_id"].(string)
+		clusterID, err := params.GetStringRequired(cfg.Parameters, "db_cluster_id")
+		if err != nil {
+			return nil, invalidParam(err)
+		}
 		return r.aws.FailoverRDS(ctx, clusterID, cfg.Safety.DryRun)
 
+	case domain.ChaosTypeRDSReboot:
+		if r.aws == nil {
+			return nil, fmt.Errorf("aws engine not available")
+		}
+		instanceID, err := params.GetStringRequired(cfg.Parameters, "db_instance_id")
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		forceFailover, err := params.GetBool(cfg.Parameters, "force_failover", false)
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		return r.aws.RebootRDSInstance(ctx, instanceID, forceFailover, cfg.Safety.DryRun)
+
 	case domain.ChaosTypeRouteBlackhole:
 		if r.aws == nil {
 			return nil, fmt.Errorf("aws engine not available")
 		}
-		rtID, _ := cfg.Parameters["route_table_id"].(string)
-		cidr, _ := cfg.Parameters["destination_cidr"].(string)
+		rtID, err := params.GetStringRequired(cfg.Parameters, "route_table_id")
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		cidr, err := params.GetStringRequired(cfg.Parameters, "destination_cidr")
+		if err != nil {
+			return nil, invalidParam(err)
+		}
 		return r.aws.BlackholeRoute(ctx, rtID, cidr, cfg.Safety.DryRun)
 
+	case domain.ChaosTypeLambdaThrottle:
+		if r.aws == nil {
+			return nil, fmt.Errorf("aws engine not available")
+		}
+		functionName, err := params.GetStringRequired(cfg.Parameters, "function_name")
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		return r.aws.ThrottleLambda(ctx, functionName, cfg.Safety.DryRun)
+
+	case domain.ChaosTypeSubnetIsolate:
+		if r.aws == nil {
+			return nil, fmt.Errorf("aws engine not available")
+		}
+		subnetID, err := params.GetStringRequired(cfg.Parameters, "subnet_id")
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		denyACLID, err := params.GetStringRequired(cfg.Parameters, "deny_acl_id")
+		if err != nil {
+			return nil, invalidParam(err)
+		}
+		return r.aws.IsolateSubnet(ctx, subnetID, denyACLID, cfg.Safety.DryRun)
+
 	default:
 		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownChaosType, cfg.ChaosType)
 	}
 }
 
+// runProbe executes a probe, stores its result as a probe_results row and
+// counts it in the probe metrics
+func (r *Runner) runProbe(ctx context.Context, experimentID string, p probe.Probe) *probe.ProbeResult {
+	pr := probe.SafeExecute(ctx, p)
+
+	outcome := "failed"
+	if pr.Passed {
+		outcome = "passed"
+	}
+	event := map[string]any{"probe": pr.ProbeName, "type": pr.ProbeType, "mode": pr.Mode, "passed": pr.Passed}
+	if pr.Error != nil {
+		event["error"] = *pr.Error
+	}
+	r.recordEvent(ctx, experimentID, domain.EventProbe, fmt.Sprintf("%s probe %s %s", pr.Mode, pr.ProbeName, outcome), event)
+
+	if r.metrics != nil {
+		r.metrics.RecordProbeResult(pr.ProbeType, pr.Passed)
+	}
+
+	if r.queries != nil {
+		detail := make(map[string]any, len(pr.Detail)+1)
+		for k, v := range pr.Detail {
+			detail[k] = v
+		}
+		if pr.Error != nil {
+			detail["error"] = *pr.Error
+		}
+		detailJSON, err := json.Marshal(detail)
+		if err != nil {
+			observability.Logf(ctx, "Failed to marshal probe %s detail: %v", pr.ProbeName, err)
+			detailJSON = []byte("{}")
+		}
+		if _, err := r.queries.CreateProbeResult(ctx, db.CreateProbeResultParams{
+			ExperimentID: experimentID,
+			ProbeName:    pr.ProbeName,
+			ProbeType:    pr.ProbeType,
+			Mode:         string(pr.Mode),
+			Detail:       detailJSON,
+			Passed:       pr.Passed,
+			ExecutedAt:   pgtype.Timestamptz{Time: pr.ExecutedAt, Valid: true},
+		}); err != nil {
+			observability.Logf(ctx, "Failed to persist probe result %s for %s: %v", pr.ProbeName, experimentID, err)
+		}
+	}
+
+	return pr
+}
+
 func (r *Runner) persistResult(ctx context.Context, experimentID string, result *domain.ExperimentResult) {
 	if r.queries == nil {
 		return
 	}
+	if r.persistHook != nil {
+		defer r.persistHook(experimentID)
+	}
+
+	create, update := experimentRecord(ctx, experimentID, result)
+	write := func(ctx context.Context) error {
+		// Create the record if it does not exist yet (an error here means it
+		// already does), then fill in the rest of the result
+		_, _ = r.queries.CreateExperiment(ctx, create)
+		return r.queries.UpdateExperiment(ctx, update)
+	}
+
+	// The chaos has already run, so a timed-out or cancelled run must still
+	// record how it ended
+	if err := r.persist.do(context.WithoutCancel(ctx), experimentID, write); err != nil {
+		observability.Logf(ctx, "Failed to update experiment %s after %d attempts: %v", experimentID, r.persist.Attempts, err)
+		r.spillResult(experimentID, result)
+	}
+}
+
+// previewSaveTimeout bounds the single write that stores a dry-run preview
+const previewSaveTimeout = 5 * time.Second
+
+// DryRunIDPrefix marks the IDs of persisted dry-run previews
+const DryRunIDPrefix = "dry-"
+
+// dryRunIDAttempts bounds how many IDs DryRun tries when storing a preview
+const dryRunIDAttempts = 3
+
+// NewDryRunID returns a random dry-run preview ID: the prefix plus 16 hex
+// digits
+func NewDryRunID() string {
+	return DryRunIDPrefix + strings.ReplaceAll(uuid.NewString(), "-", "")[:16]
+}
+
+// errPreviewIDTaken is returned by savePreview when another experiment
+// already holds the preview's ID
+var errPreviewIDTaken = errors.New("experiment ID already taken")
+
+// pgUniqueViolation is PostgreSQL's unique_violation SQLSTATE
+const pgUniqueViolation = "23505"
+
+// savePreview stores a dry-run preview as a new record with one best-effort
+// write. Nothing was injected, so unlike persistResult it neither retries
+// nor spills: a preview that could not be stored just cannot be promoted,
+// and its error is returned so the caller does not hand out the ID. It
+// returns errPreviewIDTaken when the preview's ID is already in use.
+func (r *Runner) savePreview(ctx context.Context, result *domain.ExperimentResult) error {
+	if r.queries == nil {
+		return nil
+	}
+	if r.persistHook != nil {
+		defer r.persistHook(result.ExperimentID)
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), previewSaveTimeout)
+	defer cancel()
+
+	create, update := experimentRecord(ctx, result.ExperimentID, result)
+	if _, err := r.queries.CreateExperiment(ctx, create); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return errPreviewIDTaken
+		}
+		observability.Logf(ctx, "Failed to store dry run %s: %v", result.ExperimentID, err)
+		return err
+	}
+	if err := r.queries.UpdateExperiment(ctx, update); err != nil {
+		observability.Logf(ctx, "Failed to store dry run %s: %v", result.ExperimentID, err)
+		return err
+	}
+	return nil
+}
 
+// experimentRecord builds the create and update parameters that store
+// result under experimentID
+func experimentRecord(ctx context.Context, experimentID string, result *domain.ExperimentResult) (db.CreateExperimentParams, db.UpdateExperimentParams) {
 	marshalOrEmpty := func(v any) []byte {
 		b, err := json.Marshal(v)
 		if err != nil {
-			log.Printf("Failed to marshal field for experiment %s: %v", experimentID, err)
+			observability.Logf(ctx, "Failed to marshal field for experiment %s: %v", experimentID, err)
 			return []byte("{}")
 		}
 		return b
 	}
-	configJSON := marshalOrEmpty(result.Config)
-	steadyJSON := marshalOrEmpty(result.SteadyState)
-	injJSON := marshalOrEmpty(result.InjectionResult)
-	obsJSON := marshalOrEmpty(result.Observations)
-	rbJSON := marshalOrEmpty(result.RollbackResult)
-	aiJSON := marshalOrEmpty(result.AIInsights)
 
-	var completedAt pgtype.Timestamptz
+	var startedAt, completedAt pgtype.Timestamptz
+	if result.StartedAt != nil {
+		startedAt = pgtype.Timestamptz{Time: *result.StartedAt, Valid: true}
+	}
 	if result.CompletedAt != nil {
 		completedAt = pgtype.Timestamptz{Time: *result.CompletedAt, Valid: true}
 	}
-
-	// Create or update the experiment record
-	var startedAt pgtype.Timestamptz
-	if result.StartedAt != nil {
-		startedAt = pgtype.Timestamptz{Time: *result.StartedAt, Valid: true}
+	var hypothesis pgtype.Text
+	if result.Hypothesis != nil {
+		hypothesis = pgtype.Text{String: *result.Hypothesis, Valid: true}
+	}
+	var errText pgtype.Text
+	if result.Error != nil {
+		errText = pgtype.Text{String: *result.Error, Valid: true}
 	}
 
-	_, err := r.queries.CreateExperiment(ctx, db.CreateExperimentParams{
+	create := db.CreateExperimentParams{
 		ID:        experimentID,
-		Config:    configJSON,
+		Config:    marshalOrEmpty(result.Config),
 		Status:    string(result.Status),
 		Phase:     string(result.Phase),
 		StartedAt: startedAt,
-	})
-	if err != nil {
-		// Already exists, update instead
-		var hypothesis pgtype.Text
-		if result.Hypothesis != nil {
-			hypothesis = pgtype.Text{String: *result.Hypothesis, Valid: true}
-		}
-		var errText pgtype.Text
-		if result.Error != nil {
-			errText = pgtype.Text{String: *result.Error, Valid: true}
-		}
-
-		if err := r.queries.UpdateExperiment(ctx, db.UpdateExperimentParams{
-			ID:              experimentID,
-			Status:          string(result.Status),
-			Phase:           string(result.Phase),
-			CompletedAt:     completedAt,
-			SteadyState:     steadyJSON,
-			Hypothesis:      hypothesis,
-			InjectionResult: injJSON,
-			Observations:    obsJSON,
-			RollbackResult:  rbJSON,
-			Error:           errText,
-			AiInsights:      aiJSON,
-		}); err != nil {
-			log.Printf("Failed to update experiment %s: %v", experimentID, err)
+	}
+	update := db.UpdateExperimentParams{
+		ID:              experimentID,
+		Status:          string(result.Status),
+		Phase:           string(result.Phase),
+		CompletedAt:     completedAt,
+		SteadyState:     marshalOrEmpty(result.SteadyState),
+		Hypothesis:      hypothesis,
+		InjectionResult: marshalOrEmpty(result.InjectionResult),
+		Observations:    marshalOrEmpty(result.Observations),
+		RollbackResult:  marshalOrEmpty(result.RollbackResult),
+		Error:           errText,
+		AiInsights:      marshalOrEmpty(result.AIInsights),
+		PhaseTimings:    marshalOrEmpty(result.PhaseTimings),
+	}
+	return create, update
+}
+
+// PersistRetry bounds how hard persistResult tries to store an experiment's
+// state before spilling it to a local file
+type PersistRetry struct {
+	// Attempts is the total number of tries
+	Attempts int
+	// Backoff is the wait after the first failure; it doubles after each
+	// further one
+	Backoff time.Duration
+	// SpillDir receives <experiment_id>.json when every attempt failed
+	SpillDir string
+}
+
+// DefaultPersistRetry makes three attempts within about 600ms and spills to
+// the system temp directory
+func DefaultPersistRetry() PersistRetry {
+	return PersistRetry{
+		Attempts: 3,
+		Backoff:  200 * time.Millisecond,
+		SpillDir: filepath.Join(os.TempDir(), "chaosduck-spill"),
+	}
+}
+
+// do calls write until it succeeds or the attempts run out, returning the
+// last error
+func (p PersistRetry) do(ctx context.Context, experimentID string, write func(context.Context) error) error {
+	backoff := p.Backoff
+	for attempt := 1; ; attempt++ {
+		err := write(ctx)
+		if err == nil || attempt >= p.Attempts {
+			return err
 		}
+		observability.Logf(ctx, "Persist experiment %s failed (attempt %d/%d), retrying in %s: %v", experimentID, attempt, p.Attempts, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// spillResult writes a result the database would not take to SpillDir, so
+// the outcome (in particular whether rollback succeeded) survives for an
+// operator to recover
+func (r *Runner) spillResult(experimentID string, result *domain.ExperimentResult) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		log.Printf("Failed to marshal experiment %s for spilling: %v", experimentID, err)
+		return
+	}
+	if err := os.MkdirAll(r.persist.SpillDir, 0o700); err != nil {
+		log.Printf("Failed to create spill dir for experiment %s: %v", experimentID, err)
+		return
+	}
+	path := filepath.Join(r.persist.SpillDir, filepath.Base(experimentID)+".json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		log.Printf("Failed to spill experiment %s: %v", experimentID, err)
+		return
 	}
+	log.Printf("Spilled experiment %s result to %s", experimentID, path)
 }
 
 // callAI sends a JSON POST to the AI microservice and returns the response.
 // Returns nil, error if the AI service is unavailable or returns an error.
-func (r *Runner) callAI(path string, body any) (map[string]any, error) {
+// The call is bounded by the AI timeout for path and by ctx, so it can't
+// outlive the experiment.
+func (r *Runner) callAI(ctx context.Context, path string, body any) (map[string]any, error) {
 	if r.aiBaseURL == "" {
 		return nil, fmt.Errorf("AI service URL not configured")
 	}
@@ -445,11 +1802,21 @@ func (r *Runner) callAI(path string, body any) (map[string]any, error) {
 		return nil, fmt.Errorf("marshal body: %w", err)
 	}
 
-	resp, err := r.aiClient.Post(
-		r.aiBaseURL+path,
-		"application/json",
-		bytes.NewReader(jsonBody),
-	)
+	ctx, cancel := context.WithTimeout(ctx, r.aiTimeouts.For(path))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", r.aiBaseURL+path, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("create AI request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.aiToken != "" {
+		req.Header.Set("Authorization", "Bearer "+r.aiToken)
+	}
+	if id := observability.RequestID(ctx); id != "" {
+		req.Header.Set(observability.RequestIDHeader, id)
+	}
+
+	resp, err := r.aiClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("AI request failed: %w", err)
 	}
@@ -486,9 +1853,38 @@ func (r *Runner) buildProbes(cfg domain.ExperimentConfig) []probe.Probe {
 				status = int(v)
 			}
 			bodyPattern, _ := pc.Properties["body_pattern"].(string)
+			retries := 0
+			if v, ok := pc.Properties["retries"].(float64); ok {
+				retries = int(v)
+			}
+			var retryInterval time.Duration
+			if v, ok := pc.Properties["retry_interval_ms"].(float64); ok {
+				retryInterval = time.Duration(v) * time.Millisecond
+			}
+			headers, err := params.GetStringMap(pc.Properties, "headers")
+			if err != nil {
+				log.Printf("Failed to create HTTP probe %s: %v", pc.Name, err)
+				continue
+			}
+			body, _ := pc.Properties["body"].(string)
+			followRedirects, err := params.GetBool(pc.Properties, "follow_redirects", true)
+			if err != nil {
+				log.Printf("Failed to create HTTP probe %s: %v", pc.Name, err)
+				continue
+			}
+			timeout, err := probeTimeout(pc)
+			if err != nil {
+				log.Printf("Failed to create HTTP probe %s: %v", pc.Name, err)
+				continue
+			}
 			hp, err := probe.NewHTTPProbe(probe.HTTPProbeConfig{
 				Name: pc.Name, Mode: pc.Mode, URL: url, Method: method,
 				ExpectedStatus: status, BodyPattern: bodyPattern,
+				Headers: headers, Body: body,
+				Retries: retries, RetryInterval: retryInterval,
+				Timeout:         timeout,
+				FollowRedirects: followRedirects,
+				TLS:             r.probeTLS(pc.Properties),
 			})
 			if err != nil {
 				log.Printf("Failed to create HTTP probe %s: %v", pc.Name, err)
@@ -501,8 +1897,14 @@ func (r *Runner) buildProbes(cfg domain.ExperimentConfig) []probe.Probe {
 			if v, ok := pc.Properties["expected_exit_code"].(float64); ok {
 				exitCode = int(v)
 			}
+			timeout, err := probeTimeout(pc)
+			if err != nil {
+				log.Printf("Failed to create Cmd probe %s: %v", pc.Name, err)
+				continue
+			}
 			p = probe.NewCmdProbe(probe.CmdProbeConfig{
 				Name: pc.Name, Mode: pc.Mode, Command: command, ExpectedExitCode: exitCode,
+				Timeout: timeout,
 			})
 		case domain.ProbeTypeK8s:
 			if r.k8s == nil {
@@ -512,9 +1914,20 @@ func (r *Runner) buildProbes(cfg domain.ExperimentConfig) []probe.Probe {
 			ns, _ := pc.Properties["namespace"].(string)
 			kind, _ := pc.Properties["resource_kind"].(string)
 			name, _ := pc.Properties["resource_name"].(string)
+			minReplicas, _ := pc.Properties["min_replicas"].(float64)
+			// expected_value, e.g. a service's minimum ready endpoints, may
+			// be given as a JSON number or a string
+			var expected string
+			switch v := pc.Properties["expected_value"].(type) {
+			case string:
+				expected = v
+			case float64:
+				expected = strconv.FormatFloat(v, 'f', -1, 64)
+			}
 			p = probe.NewK8sProbe(probe.K8sProbeConfig{
 				Name: pc.Name, Mode: pc.Mode, Clientset: r.k8s.Clientset(),
 				Namespace: ns, ResourceKind: kind, ResourceName: name,
+				ExpectedValue: expected, MinReplicas: int32(minReplicas),
 			})
 		case domain.ProbeTypePrometheus:
 			endpoint, _ := pc.Properties["endpoint"].(string)
@@ -524,9 +1937,62 @@ func (r *Runner) buildProbes(cfg domain.ExperimentConfig) []probe.Probe {
 			if v, ok := pc.Properties["threshold"].(float64); ok {
 				threshold = v
 			}
-			p = probe.NewPromProbe(probe.PromProbeConfig{
+			username, _ := pc.Properties["username"].(string)
+			aggregation, _ := pc.Properties["aggregation"].(string)
+			var queryRange time.Duration
+			if v, ok := pc.Properties["range_seconds"].(float64); ok {
+				queryRange = time.Duration(v * float64(time.Second))
+			}
+			step := 0
+			if v, ok := pc.Properties["step_seconds"].(float64); ok {
+				step = int(v)
+			}
+			timeout, err := probeTimeout(pc)
+			if err != nil {
+				log.Printf("Failed to create Prometheus probe %s: %v", pc.Name, err)
+				continue
+			}
+			evalTime, err := domain.PromProbeTime(pc.Properties)
+			if err != nil {
+				log.Printf("Failed to create Prometheus probe %s: %v", pc.Name, err)
+				continue
+			}
+			queryTimeoutMs, err := domain.PromQueryTimeoutMsParam.Get(pc.Properties)
+			if err != nil {
+				log.Printf("Failed to create Prometheus probe %s: %v", pc.Name, err)
+				continue
+			}
+			pp, err := probe.NewPromProbe(probe.PromProbeConfig{
 				Name: pc.Name, Mode: pc.Mode, Endpoint: endpoint,
 				Query: query, Comparator: comparator, Threshold: threshold,
+				BearerToken:  secretProperty(pc.Properties, "bearer_token"),
+				Username:     username,
+				Password:     secretProperty(pc.Properties, "password"),
+				TLS:          r.probeTLS(pc.Properties),
+				Range:        queryRange,
+				StepSeconds:  step,
+				Aggregation:  aggregation,
+				Timeout:      timeout,
+				Time:         evalTime,
+				QueryTimeout: time.Duration(queryTimeoutMs) * time.Millisecond,
+			})
+			if err != nil {
+				log.Printf("Failed to create Prometheus probe %s: %v", pc.Name, err)
+				continue
+			}
+			p = pp
+		case domain.ProbeTypeAlertmanager:
+			endpoint, _ := pc.Properties["endpoint"].(string)
+			alertName, _ := pc.Properties["alert_name"].(string)
+			expectFiring, _ := pc.Properties["expect_firing"].(bool)
+			matchLabels, err := params.GetStringMap(pc.Properties, "match_labels")
+			if err != nil {
+				log.Printf("Failed to create Alertmanager probe %s: %v", pc.Name, err)
+				continue
+			}
+			p = probe.NewAlertmanagerProbe(probe.AlertmanagerProbeConfig{
+				Name: pc.Name, Mode: pc.Mode, Endpoint: endpoint,
+				AlertName: alertName, MatchLabels: matchLabels, ExpectFiring: expectFiring,
 			})
 		default:
 			log.Printf("Unknown probe type: %s", pc.Type)
@@ -537,23 +2003,72 @@ func (r *Runner) buildProbes(cfg domain.ExperimentConfig) []probe.Probe {
 	return probes
 }
 
-func extractStringSlice(params map[string]any, key string) []string {
-	v, ok := params[key]
-	if !ok {
-		return nil
+// secretEnvPrefix restricts which environment variables a probe may read
+// credentials from, so a config can't exfiltrate e.g. DATABASE_URL
+const secretEnvPrefix = "CHAOSDUCK_SECRET_"
+
+// probeTimeout reads a probe's timeout_ms property; 0 keeps the probe type's
+// default timeout
+func probeTimeout(pc domain.ProbeConfig) (time.Duration, error) {
+	ms, err := domain.ProbeTimeoutMsParam.Get(pc.Properties)
+	return time.Duration(ms) * time.Millisecond, err
+}
+
+// secretProperty reads a probe credential inline from key or, to keep
+// secrets out of the stored config, from the environment variable named by
+// key+"_env" (which must start with secretEnvPrefix)
+func secretProperty(props map[string]any, key string) string {
+	if v, ok := props[key].(string); ok && v != "" {
+		return v
 	}
-	switch val := v.(type) {
-	case []string:
-		return val
-	case []any:
-		result := make([]string, 0, len(val))
-		for _, item := range val {
-			if s, ok := item.(string); ok {
-				result = append(result, s)
-			}
-		}
-		return result
-	default:
-		return nil
+	name, _ := props[key+"_env"].(string)
+	if name == "" {
+		return ""
+	}
+	if !strings.HasPrefix(name, secretEnvPrefix) {
+		log.Printf("Ignoring %s_env %q: only %s* variables may be referenced", key, name, secretEnvPrefix)
+		return ""
+	}
+	return os.Getenv(name)
+}
+
+// probeTLS reads the TLS properties shared by HTTP and Prometheus probes:
+// insecure_skip_verify, inline PEM in ca_cert, cert and key (key may come
+// from key_env instead), and file names under the probe certificate
+// directory in ca_file, cert_file and key_file
+func (r *Runner) probeTLS(props map[string]any) probe.TLSConfig {
+	insecure, _ := props["insecure_skip_verify"].(bool)
+	caCert, _ := props["ca_cert"].(string)
+	caFile, _ := props["ca_file"].(string)
+	cert, _ := props["cert"].(string)
+	certFile, _ := props["cert_file"].(string)
+	keyFile, _ := props["key_file"].(string)
+	return probe.TLSConfig{
+		InsecureSkipVerify: insecure,
+		CACert:             caCert,
+		CAFile:             caFile,
+		Cert:               cert,
+		CertFile:           certFile,
+		Key:                secretProperty(props, "key"),
+		KeyFile:            keyFile,
+		CertDir:            r.probeCertDir,
 	}
 }
+
+// stressOptions reads how cpu_stress and memory_stress reach their pods
+func stressOptions(m map[string]any) (StressOptions, error) {
+	shellFallback, err := domain.ShellFallback(m)
+	if err != nil {
+		return StressOptions{}, err
+	}
+	image, err := domain.EphemeralImage(m)
+	if err != nil {
+		return StressOptions{}, err
+	}
+	return StressOptions{ShellFallback: shellFallback, EphemeralImage: image}, nil
+}
+
+// invalidParam tags a parameter parsing error as a config validation failure
+func invalidParam(err error) error {
+	return fmt.Errorf("%w: %v", domain.ErrInvalidConfig, err)
+}
@@ -1,70 +1,39 @@
 package engine
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/observability"
+	"github.com/chaosduck/backend-go/internal/probe"
 	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
-func TestExtractStringSliceFromStringSlice(t *testing.T) {
-	params := map[string]any{
-		"instance_ids": []string{"i-111", "i-222", "i-333"},
-	}
-	result := extractStringSlice(params, "instance_ids")
-	assert.Equal(t, []string{"i-111", "i-222", "i-333"}, result)
-}
-
-func TestExtractStringSliceFromAnySlice(t *testing.T) {
-	params := map[string]any{
-		"instance_ids": []any{"i-aaa", "i-bbb"},
-	}
-	result := extractStringSlice(params, "instance_ids")
-	assert.Equal(t, []string{"i-aaa", "i-bbb"}, result)
-}
-
-func TestExtractStringSliceFromAnySliceMixed(t *testing.T) {
-	params := map[string]any{
-		"items": []any{"valid", 42, "also-valid"},
-	}
-	result := extractStringSlice(params, "items")
-	// Non-string items should be skipped
-	assert.Equal(t, []string{"valid", "also-valid"}, result)
-}
-
-func TestExtractStringSliceMissingKey(t *testing.T) {
-	params := map[string]any{
-		"other": "value",
-	}
-	result := extractStringSlice(params, "instance_ids")
-	assert.Nil(t, result)
-}
-
-func TestExtractStringSliceWrongType(t *testing.T) {
-	params := map[string]any{
-		"instance_ids": "not-a-slice",
-	}
-	result := extractStringSlice(params, "instance_ids")
-	assert.Nil(t, result)
-}
-
-func TestExtractStringSliceEmptySlice(t *testing.T) {
-	params := map[string]any{
-		"instance_ids": []string{},
-	}
-	result := extractStringSlice(params, "instance_ids")
-	assert.Equal(t, []string{}, result)
-}
-
-func TestExtractStringSliceNilMap(t *testing.T) {
-	result := extractStringSlice(nil, "key")
-	assert.Nil(t, result)
-}
-
 func TestCallAISuccess(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "/review-steady-state", r.URL.Path)
@@ -86,10 +55,10 @@ func TestCallAISuccess(t *testing.T) {
 		safety.NewEmergencyStopManager(),
 		safety.NewRollbackManager(),
 		safety.NewSnapshotManager(nil),
-		nil, srv.URL,
+		nil, nil, srv.URL,
 	)
 
-	result, err := runner.callAI("/review-steady-state", map[string]any{
+	result, err := runner.callAI(context.Background(), "/review-steady-state", map[string]any{
 		"steady_state": map[string]any{"pods": 3},
 	})
 	require.NoError(t, err)
@@ -107,10 +76,10 @@ func TestCallAIServiceError(t *testing.T) {
 		safety.NewEmergencyStopManager(),
 		safety.NewRollbackManager(),
 		safety.NewSnapshotManager(nil),
-		nil, srv.URL,
+		nil, nil, srv.URL,
 	)
 
-	_, err := runner.callAI("/analyze", map[string]any{})
+	_, err := runner.callAI(context.Background(), "/analyze", map[string]any{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "500")
 }
@@ -120,22 +89,1201 @@ func TestCallAINoURL(t *testing.T) {
 		safety.NewEmergencyStopManager(),
 		safety.NewRollbackManager(),
 		safety.NewSnapshotManager(nil),
-		nil, "",
+		nil, nil, "",
 	)
 
-	_, err := runner.callAI("/analyze", map[string]any{})
+	_, err := runner.callAI(context.Background(), "/analyze", map[string]any{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not configured")
 }
 
+func TestCallAIRespectsContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	runner := NewRunner(nil, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, nil, srv.URL,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := runner.callAI(ctx, "/report", map[string]any{})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestCallAIUsesConfiguredTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	runner := NewRunner(nil, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, nil, srv.URL,
+	)
+	runner.SetAITimeouts(AITimeouts{Request: 50 * time.Millisecond, Long: time.Minute})
+
+	start := time.Now()
+	_, err := runner.callAI(context.Background(), "/hypotheses", map[string]any{})
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestAITimeoutsFor(t *testing.T) {
+	timeouts := AITimeouts{Request: 5 * time.Second, Long: 20 * time.Second}
+	assert.Equal(t, 5*time.Second, timeouts.For("/hypotheses"))
+	assert.Equal(t, 20*time.Second, timeouts.For("/report"))
+	assert.Equal(t, 20*time.Second, timeouts.For("/analyze"))
+
+	// Unset values fall back rather than disabling the timeout
+	assert.Equal(t, DefaultAIRequestTimeout, AITimeouts{}.For("/report"))
+}
+
+func TestCallAISendsBearerToken(t *testing.T) {
+	var auth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	runner := NewRunner(nil, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, nil, srv.URL,
+	)
+	_, err := runner.callAI(context.Background(), "/hypotheses", map[string]any{})
+	require.NoError(t, err)
+
+	runner.SetAIToken("s3cret")
+	_, err = runner.callAI(context.Background(), "/hypotheses", map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "Bearer s3cret"}, auth)
+}
+
 func TestCallAIConnectionRefused(t *testing.T) {
 	runner := NewRunner(nil, nil,
 		safety.NewEmergencyStopManager(),
 		safety.NewRollbackManager(),
 		safety.NewSnapshotManager(nil),
-		nil, "http://127.0.0.1:1",
+		nil, nil, "http://127.0.0.1:1",
 	)
 
-	_, err := runner.callAI("/analyze", map[string]any{})
+	_, err := runner.callAI(context.Background(), "/analyze", map[string]any{})
+	assert.Error(t, err)
+}
+
+func TestBuildProbesHTTPHeadersAndBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Api-Key") != "secret" || string(body) != `{"q":1}` {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	r := &Runner{}
+	probes := r.buildProbes(domain.ExperimentConfig{
+		Probes: []domain.ProbeConfig{{
+			Name: "auth-post",
+			Type: domain.ProbeTypeHTTP,
+			Mode: domain.ProbeModeSOT,
+			Properties: map[string]any{
+				"url":     srv.URL,
+				"method":  "POST",
+				"headers": map[string]any{"X-Api-Key": "secret"},
+				"body":    `{"q":1}`,
+			},
+		}},
+	})
+	require.Len(t, probes, 1)
+
+	result, err := probes[0].Execute(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+}
+
+func TestBuildProbesHTTPFollowRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		properties map[string]any
+		wantStatus int
+	}{
+		{"default follows", map[string]any{"url": srv.URL + "/old"}, http.StatusOK},
+		{"disabled", map[string]any{"url": srv.URL + "/old", "follow_redirects": false}, http.StatusMovedPermanently},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Runner{}
+			probes := r.buildProbes(domain.ExperimentConfig{
+				Probes: []domain.ProbeConfig{{
+					Name: "redirect", Type: domain.ProbeTypeHTTP, Mode: domain.ProbeModeSOT,
+					Properties: tt.properties,
+				}},
+			})
+			require.Len(t, probes, 1)
+
+			result, err := probes[0].Execute(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, result.Detail["status_code"])
+		})
+	}
+}
+
+func TestBuildProbesRejectsInvalidHeaders(t *testing.T) {
+	r := &Runner{}
+	probes := r.buildProbes(domain.ExperimentConfig{
+		Probes: []domain.ProbeConfig{{
+			Name: "bad-headers",
+			Type: domain.ProbeTypeHTTP,
+			Mode: domain.ProbeModeSOT,
+			Properties: map[string]any{
+				"url":     "http://example.invalid",
+				"headers": map[string]any{"X-Count": float64(3)},
+			},
+		}},
+	})
+	assert.Empty(t, probes)
+}
+
+func TestBuildProbesAppliesTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	r := &Runner{}
+	probes := r.buildProbes(domain.ExperimentConfig{
+		Probes: []domain.ProbeConfig{
+			{Name: "http-fast", Type: domain.ProbeTypeHTTP, Mode: domain.ProbeModeSOT,
+				Properties: map[string]any{"url": srv.URL, "timeout_ms": float64(50)}},
+			{Name: "http-default", Type: domain.ProbeTypeHTTP, Mode: domain.ProbeModeSOT,
+				Properties: map[string]any{"url": srv.URL}},
+			{Name: "cmd", Type: domain.ProbeTypeCmd, Mode: domain.ProbeModeSOT,
+				Properties: map[string]any{"command": "sleep 2", "timeout_ms": float64(50)}},
+			{Name: "prom", Type: domain.ProbeTypePrometheus, Mode: domain.ProbeModeSOT,
+				Properties: map[string]any{"endpoint": srv.URL, "query": "up", "comparator": ">", "timeout_ms": float64(50)}},
+		},
+	})
+	require.Len(t, probes, 4)
+
+	ctx := context.Background()
+	result, err := probes[0].Execute(ctx)
+	assert.True(t, err != nil || !result.Passed, "a 50ms timeout gives up on a 300ms response")
+
+	result, err = probes[1].Execute(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Passed, "the default timeout waits for it")
+
+	result, err = probes[2].Execute(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, result.Error)
+	assert.Contains(t, *result.Error, "timed out after 50ms")
+
+	_, err = probes[3].Execute(ctx)
 	assert.Error(t, err)
 }
+
+func TestBuildProbesRejectsInvalidTimeout(t *testing.T) {
+	r := &Runner{}
+	for _, timeout := range []any{float64(0), float64(600000), "5s"} {
+		probes := r.buildProbes(domain.ExperimentConfig{
+			Probes: []domain.ProbeConfig{{
+				Name: "bad-timeout", Type: domain.ProbeTypeCmd, Mode: domain.ProbeModeSOT,
+				Properties: map[string]any{"command": "true", "timeout_ms": timeout},
+			}},
+		})
+		assert.Empty(t, probes, "timeout_ms %v", timeout)
+	}
+}
+
+func TestRunRecordsProbeMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := observability.NewMetricsWithRegistry(reg)
+	runner := NewRunner(nil, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, metrics, "",
+	)
+
+	// The SOT probe runs before injection, which fails without a K8s engine
+	_, err := runner.Run(context.Background(), "probe-metrics", domain.ExperimentConfig{
+		Name:      "metrics",
+		ChaosType: domain.ChaosTypePodDelete,
+		Safety:    domain.DefaultSafetyConfig(),
+		Probes: []domain.ProbeConfig{{
+			Name:       "always-ok",
+			Type:       domain.ProbeTypeCmd,
+			Mode:       domain.ProbeModeSOT,
+			Properties: map[string]any{"command": "true"},
+		}},
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ProbeResultsTotal.WithLabelValues("cmd", "true")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.ProbeResultsTotal.WithLabelValues("cmd", "false")))
+}
+
+func TestRunCountsSafetyBlocks(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := observability.NewMetricsWithRegistry(reg)
+	k8s := newTestK8sEngine(
+		testPod("web-1", "default", map[string]string{"app": "web"}),
+		testPod("web-2", "default", map[string]string{"app": "web"}),
+	)
+	runner := NewRunner(k8s, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, metrics, "",
+	)
+
+	// Both pods match, so the blast radius is 1.0 against a 0.5 limit
+	cfg := holdConfig("default")
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Safety.MaxBlastRadius = 0.5
+	_, err := runner.Run(context.Background(), "blast-blocked", cfg)
+	require.ErrorIs(t, err, domain.ErrBlastRadiusExceeded)
+
+	runner.esm.Trigger()
+	_, err = runner.Run(context.Background(), "estop-blocked", cfg)
+	require.ErrorIs(t, err, domain.ErrEmergencyStop)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.SafetyBlocksTotal.WithLabelValues("blast_radius")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.SafetyBlocksTotal.WithLabelValues("emergency_stop")))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	found := false
+	for _, mf := range families {
+		found = found || mf.GetName() == "chaosduck_safety_blocks_total"
+	}
+	assert.True(t, found, "chaosduck_safety_blocks_total is scraped from the registry")
+}
+
+func TestSafetyBlockReason(t *testing.T) {
+	assert.Equal(t, "confirmation", safetyBlockReason(fmt.Errorf("ns prod: %w", domain.ErrNamespaceConfirmation)))
+	assert.Equal(t, "frozen_namespace", safetyBlockReason(domain.ErrNamespaceFrozen))
+	assert.Empty(t, safetyBlockReason(errors.New("injection failed")))
+	assert.Empty(t, safetyBlockReason(nil))
+}
+
+func newHoldRunner(k8s *K8sEngine) *Runner {
+	return NewRunner(k8s, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, nil, "",
+	)
+}
+
+func holdConfig(namespace string) domain.ExperimentConfig {
+	safetyCfg := domain.DefaultSafetyConfig()
+	safetyCfg.HealthCheckInterval = 1
+	return domain.ExperimentConfig{
+		Name:            "hold",
+		ChaosType:       domain.ChaosTypePodDelete,
+		TargetNamespace: &namespace,
+		Parameters:      map[string]any{"min_healthy_ratio": 0.5},
+		Safety:          safetyCfg,
+	}
+}
+
+func TestHoldAbortsWhenHealthyRatioDrops(t *testing.T) {
+	pending := testPod("web-2", "default", nil)
+	pending.Status.Phase = corev1.PodPending
+	failed := testPod("web-3", "default", nil)
+	failed.Status.Phase = corev1.PodFailed
+	k8s := newTestK8sEngine(testPod("web-1", "default", nil), pending, failed)
+	runner := newHoldRunner(k8s)
+
+	var probeResults []map[string]any
+	summary, err := runner.hold(context.Background(), "hold-abort", holdConfig("default"), nil, 30, &probeResults)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pods_healthy_ratio")
+	assert.Equal(t, true, summary["aborted"])
+	assert.Equal(t, 1, summary["polls"])
+	assert.Less(t, summary["held_seconds"].(float64), 30.0)
+}
+
+func TestHoldRunsForDuration(t *testing.T) {
+	k8s := newTestK8sEngine(testPod("web-1", "default", nil))
+	runner := newHoldRunner(k8s)
+
+	var probeResults []map[string]any
+	summary, err := runner.hold(context.Background(), "hold-ok", holdConfig("default"), nil, 2, &probeResults)
+	require.NoError(t, err)
+	assert.Equal(t, false, summary["aborted"])
+	assert.GreaterOrEqual(t, summary["held_seconds"].(float64), 2.0)
+	assert.Equal(t, 1.0, summary["last_healthy_ratio"])
+}
+
+func TestHoldBoundedByTimeout(t *testing.T) {
+	runner := newHoldRunner(nil)
+	ctx, cancel := context.WithTimeout(context.Background(), holdReserve+time.Second)
+	defer cancel()
+
+	var probeResults []map[string]any
+	start := time.Now()
+	_, err := runner.hold(ctx, "hold-clamped", holdConfig("default"), nil, 60, &probeResults)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestRunAbortsWhenInjectionDropsHealthyRatio(t *testing.T) {
+	// One targeted running pod and one untargeted pending pod: deleting the
+	// target leaves nothing running
+	bystander := testPod("batch-1", "default", nil)
+	bystander.Status.Phase = corev1.PodPending
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}), bystander)
+	runner := newHoldRunner(k8s)
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Safety.MaxBlastRadius = 1.0
+	cfg.Parameters = map[string]any{"abort_on_healthy_ratio_below": 0.4}
+
+	result, err := runner.Run(context.Background(), "abort-after-inject", cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pods_healthy_ratio 0.00 below 0.40")
+	assert.Equal(t, domain.StatusFailed, result.Status)
+	require.NotNil(t, result.RollbackResult)
+	assert.Nil(t, result.Observations["hold"])
+
+	// The deleted pod was restored by the rollback
+	_, err = k8s.Clientset().CoreV1().Pods("default").Get(context.Background(), "web-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, runner.rollbackMgr.StackSize("abort-after-inject"))
+}
+
+func TestRunRollsBackWhenEmergencyStopRacesInjection(t *testing.T) {
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	runner := newHoldRunner(k8s)
+	// The stop lands while the pod is being deleted, after the pre-injection checks
+	k8s.clientset.(*fake.Clientset).PrependReactor("delete", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		runner.esm.Trigger()
+		return false, nil, nil
+	})
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Safety.MaxBlastRadius = 1.0
+
+	result, err := runner.Run(context.Background(), "estop-race", cfg)
+	require.ErrorIs(t, err, domain.ErrEmergencyStop)
+	assert.Equal(t, domain.StatusEmergencyStopped, result.Status)
+	assert.NotNil(t, result.RollbackResult)
+	assert.Zero(t, runner.rollbackMgr.StackSize("estop-race"))
+
+	_, err = k8s.Clientset().CoreV1().Pods("default").Get(context.Background(), "web-1", metav1.GetOptions{})
+	require.NoError(t, err)
+}
+
+func TestAbortAllRollsBackWithoutLatchingStop(t *testing.T) {
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	runner := newHoldRunner(k8s)
+	// The abort lands while the pod is being deleted
+	var aborted []string
+	k8s.clientset.(*fake.Clientset).PrependReactor("delete", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if aborted == nil {
+			aborted, _ = runner.AbortAll(context.Background())
+		}
+		return false, nil, nil
+	})
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Safety.MaxBlastRadius = 1.0
+
+	result, err := runner.Run(context.Background(), "abort-all", cfg)
+	require.ErrorIs(t, err, domain.ErrExperimentAborted)
+	assert.Equal(t, []string{"abort-all"}, aborted)
+	assert.Equal(t, domain.StatusRolledBack, result.Status)
+	assert.NotNil(t, result.RollbackResult)
+	assert.Zero(t, runner.rollbackMgr.StackSize("abort-all"))
+
+	_, err = k8s.Clientset().CoreV1().Pods("default").Get(context.Background(), "web-1", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// Nothing latched: the next experiment runs
+	assert.False(t, runner.esm.IsTriggered())
+	result, err = runner.Run(context.Background(), "after-abort", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusCompleted, result.Status)
+}
+
+func TestAbortAllCancelsHold(t *testing.T) {
+	runner := newHoldRunner(nil)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer runner.track("held", cancel)()
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		runner.AbortAll(context.Background())
+	}()
+	var probeResults []map[string]any
+	summary, err := runner.hold(ctx, "held", holdConfig("default"), nil, 30, &probeResults)
+	require.ErrorIs(t, err, domain.ErrExperimentAborted)
+	assert.Equal(t, true, summary["aborted"])
+	assert.Less(t, summary["held_seconds"].(float64), 30.0)
+}
+
+// drainConfig holds a pod_delete on web-1 for holdSeconds while the
+// untargeted web-2 and web-3 keep the namespace healthy
+func drainConfig(holdSeconds int) (*K8sEngine, domain.ExperimentConfig) {
+	k8s := newTestK8sEngine(
+		testPod("web-1", "default", map[string]string{"app": "web"}),
+		testPod("web-2", "default", nil),
+		testPod("web-3", "default", nil),
+	)
+	cfg := holdConfig("default")
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Safety.MaxBlastRadius = 1.0
+	cfg.Parameters["hold_seconds"] = holdSeconds
+	return k8s, cfg
+}
+
+func TestDrainWaitsForRunningExperiments(t *testing.T) {
+	k8s, cfg := drainConfig(1)
+	runner := newHoldRunner(k8s)
+
+	type outcome struct {
+		result *domain.ExperimentResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := runner.Run(context.Background(), "drain-finish", cfg)
+		done <- outcome{result, err}
+	}()
+	require.Eventually(t, func() bool { return runner.IsRunning("drain-finish") }, 5*time.Second, 10*time.Millisecond)
+
+	aborted := runner.Drain(context.Background(), 10*time.Second)
+	assert.Empty(t, aborted, "the run finishes within the grace period")
+	out := <-done
+	require.NoError(t, out.err)
+	assert.Equal(t, domain.StatusCompleted, out.result.Status)
+
+	_, err := runner.Run(context.Background(), "after-drain", cfg)
+	assert.ErrorIs(t, err, domain.ErrServerShuttingDown)
+}
+
+func TestDrainAbortsRunsPastTheGracePeriod(t *testing.T) {
+	k8s, cfg := drainConfig(30)
+	runner := newHoldRunner(k8s)
+
+	type outcome struct {
+		result *domain.ExperimentResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := runner.Run(context.Background(), "drain-abort", cfg)
+		done <- outcome{result, err}
+	}()
+	// Shut down once the pod is deleted and the run is holding
+	require.Eventually(t, func() bool { return runner.rollbackMgr.StackSize("drain-abort") > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	start := time.Now()
+	aborted := runner.Drain(context.Background(), 50*time.Millisecond)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.Equal(t, []string{"drain-abort"}, aborted)
+
+	// The run rolled itself back before Drain returned, leaving nothing for
+	// the shutdown's RollbackAll
+	out := <-done
+	require.ErrorIs(t, out.err, domain.ErrExperimentAborted)
+	assert.Equal(t, domain.StatusRolledBack, out.result.Status)
+	assert.Zero(t, runner.rollbackMgr.StackSize("drain-abort"))
+	_, err := k8s.Clientset().CoreV1().Pods("default").Get(context.Background(), "web-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestRunIgnoresUnsetAbortThreshold(t *testing.T) {
+	bystander := testPod("batch-1", "default", nil)
+	bystander.Status.Phase = corev1.PodPending
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}), bystander)
+	runner := newHoldRunner(k8s)
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Safety.MaxBlastRadius = 1.0
+	cfg.Parameters = nil
+
+	result, err := runner.Run(context.Background(), "no-abort", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusCompleted, result.Status)
+}
+
+func TestRunStopsHealthCheckLoop(t *testing.T) {
+	sotProbe := []domain.ProbeConfig{{
+		Name:       "always-ok",
+		Type:       domain.ProbeTypeCmd,
+		Mode:       domain.ProbeModeSOT,
+		Properties: map[string]any{"command": "true"},
+	}}
+
+	tests := []struct {
+		name       string
+		parameters map[string]any
+		wantErr    bool
+	}{
+		{"completed", nil, false},
+		// Aborted right after injection, on the error path
+		{"aborted", map[string]any{"abort_on_healthy_ratio_below": 0.4}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bystander := testPod("batch-1", "default", nil)
+			bystander.Status.Phase = corev1.PodPending
+			k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}), bystander)
+			runner := newHoldRunner(k8s)
+			var loops []*safety.HealthCheckLoop
+			runner.healthLoopHook = func(l *safety.HealthCheckLoop) { loops = append(loops, l) }
+
+			cfg := holdConfig("default")
+			cfg.TargetLabels = map[string]string{"app": "web"}
+			cfg.Safety.MaxBlastRadius = 1.0
+			cfg.Parameters = tt.parameters
+			cfg.Probes = sotProbe
+
+			_, err := runner.Run(context.Background(), "healthcheck-"+tt.name, cfg)
+			assert.Equal(t, tt.wantErr, err != nil)
+			require.Len(t, loops, 1)
+			assert.False(t, loops[0].IsRunning())
+		})
+	}
+}
+
+func TestRunSkipsHealthCheckWithoutSOTProbes(t *testing.T) {
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	runner := newHoldRunner(k8s)
+	started := false
+	runner.healthLoopHook = func(*safety.HealthCheckLoop) { started = true }
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Safety.MaxBlastRadius = 1.0
+
+	_, err := runner.Run(context.Background(), "no-healthcheck", cfg)
+	require.NoError(t, err)
+	assert.False(t, started)
+}
+
+func TestRunRecordsPhaseTimings(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := observability.NewMetricsWithRegistry(reg)
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	runner := NewRunner(k8s, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		nil, metrics, "",
+	)
+
+	namespace := "default"
+	safetyCfg := domain.DefaultSafetyConfig()
+	safetyCfg.MaxBlastRadius = 1.0
+	result, err := runner.Run(context.Background(), "phase-timings", domain.ExperimentConfig{
+		Name:            "timings",
+		ChaosType:       domain.ChaosTypePodDelete,
+		TargetNamespace: &namespace,
+		TargetLabels:    map[string]string{"app": "web"},
+		Safety:          safetyCfg,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusCompleted, result.Status)
+
+	phases := []domain.ExperimentPhase{
+		domain.PhaseSteadyState, domain.PhaseHypothesis, domain.PhaseInject,
+		domain.PhaseObserve, domain.PhaseRollback,
+	}
+	require.Len(t, result.PhaseTimings, len(phases))
+	for _, phase := range phases {
+		assert.Contains(t, result.PhaseTimings, string(phase))
+		assert.GreaterOrEqual(t, result.PhaseTimings[string(phase)], 0.0)
+	}
+	assert.Equal(t, len(phases), testutil.CollectAndCount(metrics.PhaseDurationSeconds))
+}
+
+func TestRunHonorsWarmup(t *testing.T) {
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	runner := newHoldRunner(k8s)
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Safety.MaxBlastRadius = 1.0
+	cfg.Parameters = map[string]any{"warmup_seconds": float64(1)}
+
+	start := time.Now()
+	result, err := runner.Run(context.Background(), "warmup", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusCompleted, result.Status)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.GreaterOrEqual(t, result.PhaseTimings["warmup"], 1.0)
+	// The warmup isn't counted as steady state
+	assert.Less(t, result.PhaseTimings[string(domain.PhaseSteadyState)], 1.0)
+}
+
+func TestWarmupStopsEarly(t *testing.T) {
+	t.Run("emergency stop", func(t *testing.T) {
+		runner := newHoldRunner(nil)
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			runner.esm.Trigger()
+		}()
+		waited, err := runner.warmup(context.Background(), 30)
+		require.ErrorIs(t, err, domain.ErrEmergencyStop)
+		assert.Less(t, waited, 3*time.Second)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		runner := newHoldRunner(nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+		waited, err := runner.warmup(ctx, 30)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, waited, time.Second)
+	})
+
+	t.Run("bounded by timeout", func(t *testing.T) {
+		runner := newHoldRunner(nil)
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		waited, err := runner.warmup(ctx, 30)
+		require.NoError(t, err)
+		assert.Less(t, waited, 1500*time.Millisecond)
+	})
+}
+
+func multiNamespaceConfig(namespaces ...string) domain.ExperimentConfig {
+	return domain.ExperimentConfig{
+		Name:             "fan-out",
+		ChaosType:        domain.ChaosTypePodDelete,
+		TargetNamespaces: namespaces,
+		TargetLabels:     map[string]string{"app": "web"},
+		Safety:           domain.DefaultSafetyConfig(),
+	}
+}
+
+func TestRunFansOutAcrossNamespaces(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	other := map[string]string{"app": "other"}
+	k8s := newTestK8sEngine(
+		// team-a: 1 of 4 pods targeted, within the 0.3 blast radius
+		testPod("web-1", "team-a", web),
+		testPod("other-1", "team-a", other),
+		testPod("other-2", "team-a", other),
+		testPod("other-3", "team-a", other),
+		// team-b: 1 of 1 pods targeted, over the blast radius
+		testPod("web-1", "team-b", web),
+	)
+	runner := newHoldRunner(k8s)
+
+	result, err := runner.Run(context.Background(), "fan-out", multiNamespaceConfig("team-a", "team-b"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusCompleted, result.Status)
+
+	inj := result.InjectionResult
+	assert.Equal(t, []string{"team-a"}, inj["succeeded"])
+	assert.Equal(t, []string{"team-b"}, inj["failed"])
+	namespaces := inj["namespaces"].(map[string]any)
+	assert.Equal(t, "injected", namespaces["team-a"].(map[string]any)["status"])
+	teamB := namespaces["team-b"].(map[string]any)
+	assert.Equal(t, "failed", teamB["status"])
+	assert.Contains(t, teamB["error"], "blast radius")
+
+	// The team-a pod was deleted and then restored by the single rollback entry
+	require.Len(t, result.RollbackResult, 1)
+	_, err = k8s.clientset.CoreV1().Pods("team-a").Get(context.Background(), "web-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestRunFanOutFailsWhenEveryNamespaceFails(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	k8s := newTestK8sEngine(testPod("web-1", "team-a", web), testPod("web-1", "team-b", web))
+	runner := newHoldRunner(k8s)
+
+	result, err := runner.Run(context.Background(), "fan-out-fail", multiNamespaceConfig("team-a", "team-b"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "namespace team-a")
+	assert.Contains(t, err.Error(), "namespace team-b")
+	assert.Equal(t, domain.StatusFailed, result.Status)
+}
+
+func TestRunRollsBackPartialInjection(t *testing.T) {
+	ctx := context.Background()
+	web := map[string]string{"app": "web"}
+	k8s := newTestK8sEngine(testPod("web-1", "default", web), testPod("web-2", "default", web))
+	k8s.clientset.(*fake.Clientset).PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.DeleteAction).GetName() == "web-2" {
+			return true, nil, errors.New("forbidden")
+		}
+		return false, nil, nil
+	})
+	runner := newHoldRunner(k8s)
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = web
+	cfg.Parameters = nil
+	cfg.Safety.MaxBlastRadius = 1
+
+	result, err := runner.Run(ctx, "partial", cfg)
+	require.Error(t, err)
+	assert.Equal(t, domain.StatusFailed, result.Status)
+	require.NotNil(t, result.RollbackResult, "the deleted pod should be rolled back")
+
+	_, err = k8s.clientset.CoreV1().Pods("default").Get(ctx, "web-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func chainedConfig(steps ...domain.ChaosStep) domain.ExperimentConfig {
+	cfg := holdConfig("default")
+	cfg.ChaosType = steps[0].ChaosType
+	cfg.TargetLabels = map[string]string{"app": "web"}
+	cfg.Parameters = nil
+	cfg.Safety.MaxBlastRadius = 1
+	cfg.Steps = steps
+	return cfg
+}
+
+func TestRunChainsStepsAndUnwindsInReverse(t *testing.T) {
+	ctx := context.Background()
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	commands := recordExec(k8s, "eth0")
+	runner := newHoldRunner(k8s)
+
+	cfg := chainedConfig(
+		domain.ChaosStep{ChaosType: domain.ChaosTypeNetworkLatency, Parameters: map[string]any{"latency_ms": 100}},
+		domain.ChaosStep{ChaosType: domain.ChaosTypePodDelete},
+	)
+	result, err := runner.Run(ctx, "chain", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusCompleted, result.Status)
+
+	assert.Equal(t, "steps", result.InjectionResult["action"])
+	steps := result.InjectionResult["steps"].([]map[string]any)
+	require.Len(t, steps, 2)
+	assert.Equal(t, "network_latency", steps[0]["chaos_type"])
+	assert.Equal(t, "pod_delete", steps[1]["chaos_type"])
+
+	// The last step is undone first
+	require.Len(t, result.RollbackResult, 2)
+	assert.Equal(t, "step 2: pod_delete", result.RollbackResult["rollback_0"].(safety.RollbackResult).Description)
+	assert.Equal(t, "step 1: network_latency", result.RollbackResult["rollback_1"].(safety.RollbackResult).Description)
+	cmds := commands()
+	assert.Equal(t, []string{"tc", "qdisc", "del", "dev", "eth0", "root"}, cmds[len(cmds)-1])
+	_, err = k8s.clientset.CoreV1().Pods("default").Get(ctx, "web-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestRunRollsBackEarlierStepsWhenAStepFails(t *testing.T) {
+	ctx := context.Background()
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	recordExec(k8s, "eth0")
+	runner := newHoldRunner(k8s)
+
+	cfg := chainedConfig(
+		domain.ChaosStep{ChaosType: domain.ChaosTypePodDelete},
+		domain.ChaosStep{ChaosType: domain.ChaosTypeNetworkLatency, Parameters: map[string]any{"latency_ms": -5}},
+	)
+	result, err := runner.Run(ctx, "chain-fail", cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "step 2 (network_latency)")
+	assert.Equal(t, domain.StatusFailed, result.Status)
+	require.Len(t, result.RollbackResult, 1, "step 1 should be rolled back")
+
+	_, err = k8s.clientset.CoreV1().Pods("default").Get(ctx, "web-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Zero(t, runner.rollbackMgr.StackSize("chain-fail"))
+}
+
+func TestRunVerifiesRollbackReportsMissingPod(t *testing.T) {
+	ctx := context.Background()
+	web := map[string]string{"app": "web"}
+	k8s := newTestK8sEngine(testPod("web-1", "default", web), testPod("api-1", "default", nil))
+	// The rollback cannot recreate the deleted pod
+	k8s.clientset.(*fake.Clientset).PrependReactor("create", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("admission webhook denied the request")
+	})
+	runner := newHoldRunner(k8s)
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = web
+	cfg.Parameters = nil
+	cfg.Safety.MaxBlastRadius = 1
+	cfg.Safety.VerifyRollback = true
+
+	result, err := runner.Run(ctx, "verify-missing", cfg)
+	require.NoError(t, err)
+	drift, ok := result.RollbackResult["residual_drift"].([]map[string]any)
+	require.True(t, ok, "residual_drift missing from %v", result.RollbackResult)
+	require.Len(t, drift, 1)
+	assert.Equal(t, "pod_missing", drift[0]["action"])
+	assert.Equal(t, "web-1", drift[0]["name"])
+}
+
+func TestRunVerifiesCleanRollback(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	k8s := newTestK8sEngine(testPod("web-1", "default", web))
+	runner := newHoldRunner(k8s)
+
+	cfg := holdConfig("default")
+	cfg.TargetLabels = web
+	cfg.Parameters = nil
+	cfg.Safety.MaxBlastRadius = 1
+
+	result, err := runner.Run(context.Background(), "unverified", cfg)
+	require.NoError(t, err)
+	assert.NotContains(t, result.RollbackResult, "residual_drift", "verification is opt-in")
+
+	cfg.Safety.VerifyRollback = true
+	result, err = runner.Run(context.Background(), "verify-clean", cfg)
+	require.NoError(t, err)
+	assert.Empty(t, result.RollbackResult["residual_drift"])
+	assert.Contains(t, result.RollbackResult, "residual_drift")
+
+	_, err = runner.VerifyRollback(context.Background(), "never-ran")
+	assert.Error(t, err)
+}
+
+func TestSecretPropertyReadsPrefixedEnv(t *testing.T) {
+	t.Setenv("CHAOSDUCK_SECRET_PROM_TOKEN", "from-env")
+	t.Setenv("DATABASE_URL", "postgres://secret")
+
+	assert.Equal(t, "inline", secretProperty(map[string]any{"bearer_token": "inline"}, "bearer_token"))
+	assert.Equal(t, "from-env", secretProperty(map[string]any{"bearer_token_env": "CHAOSDUCK_SECRET_PROM_TOKEN"}, "bearer_token"))
+	assert.Empty(t, secretProperty(map[string]any{"bearer_token_env": "DATABASE_URL"}, "bearer_token"))
+	assert.Empty(t, secretProperty(map[string]any{}, "bearer_token"))
+}
+
+func TestProbeTLSReadsSharedProperties(t *testing.T) {
+	t.Setenv("CHAOSDUCK_SECRET_PROBE_KEY", "key-pem")
+	runner := newHoldRunner(nil)
+	runner.SetProbeCertDir("/etc/chaosduck/certs")
+
+	got := runner.probeTLS(map[string]any{
+		"insecure_skip_verify": true,
+		"ca_file":              "ca.pem",
+		"cert":                 "cert-pem",
+		"key_env":              "CHAOSDUCK_SECRET_PROBE_KEY",
+	})
+	assert.Equal(t, probe.TLSConfig{
+		InsecureSkipVerify: true,
+		CAFile:             "ca.pem",
+		Cert:               "cert-pem",
+		Key:                "key-pem",
+		CertDir:            "/etc/chaosduck/certs",
+	}, got)
+}
+
+// eventDB is a DBTX fake that keeps the audit events a run records and
+// answers every other query with no rows
+type eventDB struct {
+	mu     sync.Mutex
+	events []db.CreateExperimentEventParams
+}
+
+func (d *eventDB) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if strings.Contains(sql, "name: CreateExperimentEvent") {
+		d.mu.Lock()
+		d.events = append(d.events, db.CreateExperimentEventParams{
+			ExperimentID: args[0].(string),
+			EventType:    args[1].(string),
+			Message:      args[2].(string),
+			Data:         args[3].(json.RawMessage),
+			OccurredAt:   args[4].(pgtype.Timestamptz),
+		})
+		d.mu.Unlock()
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+func (d *eventDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+func (d *eventDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return noRow{}
+}
+
+type noRow struct{}
+
+func (noRow) Scan(...any) error { return pgx.ErrNoRows }
+
+func TestRunRecordsEventTimeline(t *testing.T) {
+	store := &eventDB{}
+	k8s := newTestK8sEngine(testPod("web-1", "default", map[string]string{"app": "web"}))
+	runner := NewRunner(k8s, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		db.New(store), nil, "",
+	)
+
+	namespace := "default"
+	safetyCfg := domain.DefaultSafetyConfig()
+	safetyCfg.MaxBlastRadius = 1.0
+	result, err := runner.Run(context.Background(), "timeline", domain.ExperimentConfig{
+		Name:            "timeline",
+		ChaosType:       domain.ChaosTypePodDelete,
+		TargetNamespace: &namespace,
+		TargetLabels:    map[string]string{"app": "web"},
+		Safety:          safetyCfg,
+		Probes: []domain.ProbeConfig{{
+			Name:       "noop",
+			Type:       domain.ProbeTypeCmd,
+			Mode:       domain.ProbeModeSOT,
+			Properties: map[string]any{"command": "true"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusCompleted, result.Status)
+
+	var got []string
+	for _, ev := range store.events {
+		assert.Equal(t, "timeline", ev.ExperimentID)
+		label := ev.EventType
+		if ev.EventType == string(domain.EventPhaseChanged) {
+			var data map[string]any
+			require.NoError(t, json.Unmarshal(ev.Data, &data))
+			label += ":" + data["phase"].(string)
+		}
+		got = append(got, label)
+	}
+	assert.Equal(t, []string{
+		"started",
+		"probe",
+		"phase_changed:hypothesis",
+		"phase_changed:inject",
+		"injected",
+		"phase_changed:observe",
+		"phase_changed:rollback",
+		"rollback",
+		"finished",
+	}, got)
+
+	for i := 1; i < len(store.events); i++ {
+		assert.False(t, store.events[i].OccurredAt.Time.Before(store.events[i-1].OccurredAt.Time))
+	}
+	last := store.events[len(store.events)-1]
+	assert.JSONEq(t, `{"status":"completed","phase":"rollback"}`, string(last.Data))
+}
+
+// flakyDB is a DBTX fake whose UpdateExperiment fails a set number of times
+// before succeeding
+type flakyDB struct {
+	eventDB
+	failures int
+	updates  int
+}
+
+func (d *flakyDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	// Like pgx, refuse to write on a cancelled context
+	if err := ctx.Err(); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	if strings.Contains(sql, "name: UpdateExperiment ") {
+		d.updates++
+		if d.updates <= d.failures {
+			return pgconn.CommandTag{}, errors.New("connection reset by peer")
+		}
+	}
+	return d.eventDB.Exec(ctx, sql, args...)
+}
+
+func persistRunner(t *testing.T, store *flakyDB) (*Runner, string) {
+	spillDir := t.TempDir()
+	runner := NewRunner(nil, nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		db.New(store), nil, "",
+	)
+	runner.SetPersistRetry(PersistRetry{Attempts: 3, Backoff: time.Millisecond, SpillDir: spillDir})
+	return runner, spillDir
+}
+
+func finishedResult(id string) *domain.ExperimentResult {
+	return &domain.ExperimentResult{
+		ExperimentID:   id,
+		Status:         domain.StatusCompleted,
+		Phase:          domain.PhaseRollback,
+		RollbackResult: map[string]any{"rollback_0": map[string]any{"status": "success"}},
+	}
+}
+
+func TestPersistResultRetriesTransientFailures(t *testing.T) {
+	store := &flakyDB{failures: 2}
+	runner, spillDir := persistRunner(t, store)
+
+	runner.persistResult(context.Background(), "flaky001", finishedResult("flaky001"))
+
+	assert.Equal(t, 3, store.updates)
+	entries, err := os.ReadDir(spillDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a result that was eventually stored must not be spilled")
+}
+
+func TestPersistResultSpillsAfterLastAttempt(t *testing.T) {
+	store := &flakyDB{failures: 5}
+	runner, spillDir := persistRunner(t, store)
+
+	runner.persistResult(context.Background(), "flaky002", finishedResult("flaky002"))
+
+	assert.Equal(t, 3, store.updates)
+	data, err := os.ReadFile(filepath.Join(spillDir, "flaky002.json"))
+	require.NoError(t, err)
+	var spilled domain.ExperimentResult
+	require.NoError(t, json.Unmarshal(data, &spilled))
+	assert.Equal(t, domain.StatusCompleted, spilled.Status)
+	assert.Equal(t, "success", spilled.RollbackResult["rollback_0"].(map[string]any)["status"])
+}
+
+func TestPersistResultSurvivesCancelledContext(t *testing.T) {
+	store := &flakyDB{}
+	runner, spillDir := persistRunner(t, store)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runner.persistResult(ctx, "timeout1", finishedResult("timeout1"))
+
+	assert.Equal(t, 1, store.updates)
+	entries, err := os.ReadDir(spillDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// previewDB is a DBTX fake that keeps experiment configs by ID. Like
+// PostgreSQL, creating a record whose ID exists fails with a unique
+// violation; with down set, every write fails.
+type previewDB struct {
+	eventDB
+	down    bool
+	configs map[string]json.RawMessage
+	creates int
+	updates int
+}
+
+func (d *previewDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if strings.Contains(sql, "name: UpdateExperiment ") {
+		d.updates++
+		if d.down {
+			return pgconn.CommandTag{}, errors.New("connection refused")
+		}
+	}
+	return d.eventDB.Exec(ctx, sql, args...)
+}
+
+func (d *previewDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if !strings.Contains(sql, "name: CreateExperiment ") {
+		return d.eventDB.QueryRow(ctx, sql, args...)
+	}
+	d.creates++
+	id := args[0].(string)
+	switch {
+	case d.down:
+		return errRow{errors.New("connection refused")}
+	case d.configs[id] != nil:
+		return errRow{&pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint"}}
+	}
+	d.configs[id] = args[1].(json.RawMessage)
+	return errRow{}
+}
+
+// errRow is a row whose Scan returns err
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+func previewRunner(t *testing.T, store *previewDB) (*Runner, string) {
+	spillDir := t.TempDir()
+	runner := NewRunner(newTestK8sEngine(), nil,
+		safety.NewEmergencyStopManager(),
+		safety.NewRollbackManager(),
+		safety.NewSnapshotManager(nil),
+		db.New(store), nil, "",
+	)
+	// A retried write would sleep at least a second
+	runner.SetPersistRetry(PersistRetry{Attempts: 3, Backoff: time.Second, SpillDir: spillDir})
+	return runner, spillDir
+}
+
+func TestDryRunSavesPreviewOnceWithoutSpilling(t *testing.T) {
+	store := &previewDB{down: true, configs: map[string]json.RawMessage{}}
+	runner, spillDir := previewRunner(t, store)
+
+	start := time.Now()
+	result, errs := runner.DryRun(context.Background(), "dry-down", domain.ExperimentConfig{
+		Name: "preview", ChaosType: domain.ChaosTypePodDelete,
+	})
+
+	require.NotNil(t, result)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, 1, store.creates, "a preview is written once")
+	assert.Empty(t, result.ExperimentID, "an unstored preview has no ID to promote")
+	require.NotEmpty(t, errs)
+	assert.ErrorContains(t, errors.Join(errs...), "preview not stored: connection refused")
+	assert.Zero(t, store.updates)
+	entries, err := os.ReadDir(spillDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a preview is never spilled")
+}
+
+func TestDryRunRetriesTakenPreviewID(t *testing.T) {
+	store := &previewDB{configs: map[string]json.RawMessage{
+		"dry-0001": json.RawMessage(`{"name":"earlier-preview"}`),
+	}}
+	runner, _ := previewRunner(t, store)
+
+	result, _ := runner.DryRun(context.Background(), "dry-0001", domain.ExperimentConfig{
+		Name: "later-preview", ChaosType: domain.ChaosTypePodDelete,
+	})
+
+	assert.NotEqual(t, "dry-0001", result.ExperimentID)
+	assert.True(t, strings.HasPrefix(result.ExperimentID, DryRunIDPrefix))
+	assert.Len(t, result.ExperimentID, len(DryRunIDPrefix)+16)
+	assert.JSONEq(t, `{"name":"earlier-preview"}`, string(store.configs["dry-0001"]), "the earlier preview is untouched")
+	var stored domain.ExperimentConfig
+	require.NoError(t, json.Unmarshal(store.configs[result.ExperimentID], &stored))
+	assert.Equal(t, "later-preview", stored.Name)
+	assert.Equal(t, 1, store.updates)
+}
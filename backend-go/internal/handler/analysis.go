@@ -2,35 +2,59 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/engine"
+	"github.com/chaosduck/backend-go/internal/observability"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // AnalysisHandler proxies AI analysis requests to the Python AI microservice
 type AnalysisHandler struct {
-	queries      *db.Queries
+	queries      db.Querier
 	aiServiceURL string
 	httpClient   *http.Client
+	timeouts     engine.AITimeouts
+	token        string
+	metrics      *observability.Metrics
+	scoreWeights domain.ResilienceWeights
 }
 
 // NewAnalysisHandler creates a new AnalysisHandler
-func NewAnalysisHandler(queries *db.Queries, aiServiceURL string) *AnalysisHandler {
+func NewAnalysisHandler(queries db.Querier, aiServiceURL string, timeouts engine.AITimeouts, metrics *observability.Metrics) *AnalysisHandler {
 	return &AnalysisHandler{
 		queries:      queries,
 		aiServiceURL: aiServiceURL,
-		httpClient:   &http.Client{Timeout: 60 * time.Second},
+		httpClient:   &http.Client{},
+		timeouts:     timeouts,
+		metrics:      metrics,
+		scoreWeights: domain.DefaultResilienceWeights(),
 	}
 }
 
+// SetAIToken sets the bearer token sent to the AI service. Empty sends no
+// Authorization header.
+func (h *AnalysisHandler) SetAIToken(token string) {
+	h.token = token
+}
+
+// SetResilienceWeights sets the category weights of the resilience score
+// computed locally when the AI service is unavailable
+func (h *AnalysisHandler) SetResilienceWeights(w domain.ResilienceWeights) {
+	h.scoreWeights = w
+}
+
 // AnalyzeExperiment proxies to AI service for experiment analysis
 func (h *AnalysisHandler) AnalyzeExperiment(c *gin.Context) {
 	if h.queries == nil {
@@ -53,7 +77,7 @@ func (h *AnalysisHandler) AnalyzeExperiment(c *gin.Context) {
 		"observations":    result.Observations,
 	}
 
-	resp, err := h.proxyToAI("/analyze", body)
+	resp, err := h.proxyToAI(c.Request.Context(), "/analyze", body)
 	if err != nil {
 		c.JSON(http.StatusBadGateway, gin.H{"detail": fmt.Sprintf("AI service error: %v", err)})
 		return
@@ -74,7 +98,9 @@ func (h *AnalysisHandler) AnalyzeExperiment(c *gin.Context) {
 			Recommendations: recsJSON,
 			ResilienceScore: pgtype.Float8{Float64: resilienceScore, Valid: true},
 		}); err != nil {
-			log.Printf("Failed to persist analysis result: %v", err)
+			observability.Logf(c.Request.Context(), "Failed to persist analysis result: %v", err)
+		} else {
+			h.metrics.RecordAnalysis(severity, resilienceScore)
 		}
 	}
 
@@ -89,7 +115,7 @@ func (h *AnalysisHandler) GenerateHypotheses(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.proxyToAI("/hypotheses", body)
+	resp, err := h.proxyToAI(c.Request.Context(), "/hypotheses", body)
 	if err != nil {
 		c.JSON(http.StatusBadGateway, gin.H{"detail": fmt.Sprintf("AI service error: %v", err)})
 		return
@@ -97,7 +123,9 @@ func (h *AnalysisHandler) GenerateHypotheses(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
-// CalculateResilienceScore proxies to AI service
+// CalculateResilienceScore proxies to AI service. When the AI service
+// fails, the score is computed locally from the stored experiments and
+// labeled with source "local".
 func (h *AnalysisHandler) CalculateResilienceScore(c *gin.Context) {
 	var body map[string]any
 	if err := c.ShouldBindJSON(&body); err != nil {
@@ -105,14 +133,77 @@ func (h *AnalysisHandler) CalculateResilienceScore(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.proxyToAI("/resilience-score", body)
+	resp, err := h.proxyToAI(c.Request.Context(), "/resilience-score", body)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{"detail": fmt.Sprintf("AI service error: %v", err)})
+		score, localErr := h.localResilienceScore(c.Request.Context(), body)
+		if localErr != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"detail": fmt.Sprintf("AI service error: %v; local score unavailable: %v", err, localErr)})
+			return
+		}
+		observability.Logf(c.Request.Context(), "AI resilience score failed, computed locally: %v", err)
+		c.JSON(http.StatusOK, score)
 		return
 	}
 	c.JSON(http.StatusOK, resp)
 }
 
+// localScoreMaxExperiments caps how many of the most recent stored
+// experiments a local resilience score covers when none are named
+const localScoreMaxExperiments = 50
+
+// localResilienceScore scores the stored experiments named in the request's
+// "experiments" list, by ID or by objects carrying experiment_id, or else
+// the most recent ones. Unknown IDs are skipped.
+func (h *AnalysisHandler) localResilienceScore(ctx context.Context, body map[string]any) (domain.ResilienceScore, error) {
+	if h.queries == nil {
+		return domain.ResilienceScore{}, errors.New("database not available")
+	}
+
+	var records []db.Experiment
+	if ids := requestedExperimentIDs(body); len(ids) > 0 {
+		for _, id := range ids {
+			rec, err := h.queries.GetExperiment(ctx, id)
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return domain.ResilienceScore{}, err
+			}
+			records = append(records, rec)
+		}
+	} else {
+		recent, err := h.queries.ListExperiments(ctx)
+		if err != nil {
+			return domain.ResilienceScore{}, err
+		}
+		records = recent[:min(len(recent), localScoreMaxExperiments)]
+	}
+
+	results := make([]domain.ExperimentResult, 0, len(records))
+	for _, rec := range records {
+		results = append(results, recordToResult(rec))
+	}
+	return domain.ScoreResilience(results, h.scoreWeights), nil
+}
+
+// requestedExperimentIDs returns the experiment IDs in a resilience score
+// request's "experiments" list
+func requestedExperimentIDs(body map[string]any) []string {
+	list, _ := body["experiments"].([]any)
+	ids := make([]string, 0, len(list))
+	for _, e := range list {
+		switch v := e.(type) {
+		case string:
+			ids = append(ids, v)
+		case map[string]any:
+			if id, _ := v["experiment_id"].(string); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
 // GenerateReport proxies to AI service
 func (h *AnalysisHandler) GenerateReport(c *gin.Context) {
 	var body map[string]any
@@ -121,7 +212,7 @@ func (h *AnalysisHandler) GenerateReport(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.proxyToAI("/report", body)
+	resp, err := h.proxyToAI(c.Request.Context(), "/report", body)
 	if err != nil {
 		c.JSON(http.StatusBadGateway, gin.H{"detail": fmt.Sprintf("AI service error: %v", err)})
 		return
@@ -137,7 +228,7 @@ func (h *AnalysisHandler) GenerateExperiments(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.proxyToAI("/generate-experiments", body)
+	resp, err := h.proxyToAI(c.Request.Context(), "/generate-experiments", body)
 	if err != nil {
 		c.JSON(http.StatusBadGateway, gin.H{"detail": fmt.Sprintf("AI service error: %v", err)})
 		return
@@ -159,7 +250,7 @@ func (h *AnalysisHandler) NLExperiment(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.proxyToAI("/nl-experiment", body)
+	resp, err := h.proxyToAI(c.Request.Context(), "/nl-experiment", body)
 	if err != nil {
 		c.JSON(http.StatusBadGateway, gin.H{"detail": fmt.Sprintf("AI service error: %v", err)})
 		return
@@ -255,7 +346,7 @@ func (h *AnalysisHandler) ResilienceTrendSummary(c *gin.Context) {
 	}
 
 	body := map[string]any{"experiments": experimentsData}
-	resp, err := h.proxyToAI("/resilience-score", body)
+	resp, err := h.proxyToAI(c.Request.Context(), "/resilience-score", body)
 	if err != nil {
 		c.JSON(http.StatusBadGateway, gin.H{"detail": fmt.Sprintf("AI service error: %v", err)})
 		return
@@ -268,18 +359,45 @@ func (h *AnalysisHandler) ResilienceTrendSummary(c *gin.Context) {
 	})
 }
 
-// proxyToAI sends a JSON POST request to the AI microservice
-func (h *AnalysisHandler) proxyToAI(path string, body any) (map[string]any, error) {
+// proxyToAI sends a JSON POST request to the AI microservice, bounded by the
+// AI timeout for path and by ctx
+func (h *AnalysisHandler) proxyToAI(ctx context.Context, path string, body any) (map[string]any, error) {
+	respBody, err := h.postAI(ctx, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+
+	return result, nil
+}
+
+// postAI sends a JSON POST request to the AI microservice and returns the
+// raw response body
+func (h *AnalysisHandler) postAI(ctx context.Context, path string, body any) ([]byte, error) {
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("marshal body: %w", err)
 	}
 
-	resp, err := h.httpClient.Post(
-		h.aiServiceURL+path,
-		"application/json",
-		bytes.NewReader(jsonBody),
-	)
+	ctx, cancel := context.WithTimeout(ctx, h.timeouts.For(path))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", h.aiServiceURL+path, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+	if id := observability.RequestID(ctx); id != "" {
+		req.Header.Set(observability.RequestIDHeader, id)
+	}
+
+	resp, err := h.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -294,10 +412,5 @@ func (h *AnalysisHandler) proxyToAI(path string, body any) (map[string]any, erro
 		return nil, fmt.Errorf("AI service returned %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	var result map[string]any
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("parse response: %w", err)
-	}
-
-	return result, nil
+	return respBody, nil
 }
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/engine"
+	"github.com/chaosduck/backend-go/internal/observability"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// analysisStore extends experimentStore with CreateAnalysisResult
+type analysisStore struct {
+	*experimentStore
+	analyses []db.CreateAnalysisResultParams
+}
+
+func (s *analysisStore) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if !strings.Contains(sql, "name: CreateAnalysisResult") {
+		return s.experimentStore.QueryRow(ctx, sql, args...)
+	}
+	arg := db.CreateAnalysisResultParams{
+		ExperimentID:    args[0].(string),
+		Severity:        args[1].(string),
+		RootCause:       args[2].(string),
+		Confidence:      args[3].(float64),
+		Recommendations: args[4].(json.RawMessage),
+		ResilienceScore: args[5].(pgtype.Float8),
+	}
+	s.analyses = append(s.analyses, arg)
+	return &fakeRows{rows: [][]any{{
+		int32(len(s.analyses)), arg.ExperimentID, arg.Severity, arg.RootCause, arg.Confidence,
+		arg.Recommendations, arg.ResilienceScore, pgtype.Timestamptz{},
+	}}, pos: 1}
+}
+
+func TestAnalyzeExperimentRecordsSeverityMetric(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/analyze", r.URL.Path)
+		_, _ = w.Write([]byte(`{"severity":"SEV1","root_cause":"no replicas","confidence":0.9,
+			"recommendations":[],"resilience_score":42}`))
+	}))
+	defer ai.Close()
+
+	store := &analysisStore{experimentStore: &experimentStore{rows: map[string]db.Experiment{}}}
+	seedExperiment(t, store.experimentStore, "exp00001", domain.StatusCompleted)
+	reg := prometheus.NewRegistry()
+	metrics := observability.NewMetricsWithRegistry(reg)
+	h := NewAnalysisHandler(db.New(store), ai.URL, engine.DefaultAITimeouts(), metrics)
+	r := gin.New()
+	r.POST("/analysis/experiment/:experiment_id", h.AnalyzeExperiment)
+
+	w := postJSON(r, "/analysis/experiment/exp00001", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, store.analyses, 1)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.AnalysisSeverityTotal.WithLabelValues("SEV1")))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	var observed bool
+	for _, mf := range families {
+		if mf.GetName() == "chaosduck_resilience_score" {
+			hist := mf.GetMetric()[0].GetHistogram()
+			assert.Equal(t, uint64(1), hist.GetSampleCount())
+			assert.Equal(t, 42.0, hist.GetSampleSum())
+			observed = true
+		}
+	}
+	assert.True(t, observed, "resilience score histogram not gathered")
+}
+
+func TestAnalysisProxySendsBearerToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var auth string
+	ai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"hypotheses":[]}`))
+	}))
+	defer ai.Close()
+
+	h := NewAnalysisHandler(nil, ai.URL, engine.DefaultAITimeouts(), nil)
+	h.SetAIToken("s3cret")
+	r := gin.New()
+	r.POST("/analysis/hypotheses", h.GenerateHypotheses)
+
+	w := postJSON(r, "/analysis/hypotheses", `{"topology":{}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "Bearer s3cret", auth)
+}
+
+func TestAnalysisProxyForwardsRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var forwarded string
+	ai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get(observability.RequestIDHeader)
+		_, _ = w.Write([]byte(`{"hypotheses":[]}`))
+	}))
+	defer ai.Close()
+
+	h := NewAnalysisHandler(nil, ai.URL, engine.DefaultAITimeouts(), nil)
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.POST("/analysis/hypotheses", h.GenerateHypotheses)
+
+	w := postJSON(r, "/analysis/hypotheses", `{"topology":{}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotEmpty(t, forwarded)
+	assert.Equal(t, w.Header().Get(observability.RequestIDHeader), forwarded)
+}
+
+func TestResilienceScoreFallsBackToLocalScore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ai.Close()
+
+	q := &fakeQuerier{experiments: map[string]db.Experiment{
+		"exp00001": {ID: "exp00001", Status: string(domain.StatusCompleted),
+			Observations:   []byte(`{"probe_results":[{"probe":"web","passed":true},{"probe":"api","passed":false}]}`),
+			RollbackResult: []byte(`{"rollback_0":{"status":"success"}}`)},
+		"exp00002": {ID: "exp00002", Status: string(domain.StatusFailed)},
+	}}
+	h := NewAnalysisHandler(q, ai.URL, engine.DefaultAITimeouts(), nil)
+	h.SetResilienceWeights(domain.ResilienceWeights{ProbePassRate: 1})
+	r := gin.New()
+	r.POST("/analysis/resilience-score", h.CalculateResilienceScore)
+
+	w := postJSON(r, "/analysis/resilience-score", `{"experiments":["exp00001",{"experiment_id":"missing1"}]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var score domain.ResilienceScore
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &score))
+	assert.Equal(t, domain.ResilienceScoreSourceLocal, score.Source)
+	assert.Equal(t, 50.0, score.Overall)
+	assert.Equal(t, 50.0, score.Categories[domain.ResilienceProbePassRate])
+	assert.Equal(t, 100.0, score.Categories[domain.ResilienceRollbackSuccess])
+	require.NotNil(t, score.Details)
+	assert.Contains(t, *score.Details, "Computed locally from 1 experiment(s)")
+
+	// Without a list, the stored experiments are scored
+	w = postJSON(r, "/analysis/resilience-score", `{}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var all domain.ResilienceScore
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &all))
+	require.NotNil(t, all.Details)
+	assert.Contains(t, *all.Details, "Computed locally from 2 experiment(s)")
+	assert.Equal(t, 0.0, all.Categories[domain.ResilienceHypothesisAdherence], "neither run held its hypothesis")
+
+	// Without a database there is nothing to fall back on
+	h = NewAnalysisHandler(nil, ai.URL, engine.DefaultAITimeouts(), nil)
+	r = gin.New()
+	r.POST("/analysis/resilience-score", h.CalculateResilienceScore)
+	w = postJSON(r, "/analysis/resilience-score", `{}`)
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/chaosduck/backend-go/internal/db"
@@ -14,109 +18,213 @@ import (
 	"github.com/chaosduck/backend-go/internal/safety"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// experimentCacheTTL bounds how stale a polled experiment record can be; it
+// matches the SSE tick so concurrent streams share one query per tick
+const experimentCacheTTL = time.Second
+
+// ExperimentRunner executes experiments; *engine.Runner implements it
+type ExperimentRunner interface {
+	Run(ctx context.Context, experimentID string, cfg domain.ExperimentConfig) (*domain.ExperimentResult, error)
+	DryRun(ctx context.Context, experimentID string, cfg domain.ExperimentConfig) (*domain.ExperimentResult, []error)
+	SetPersistHook(fn func(experimentID string))
+	VerifyRollback(ctx context.Context, experimentID string) ([]map[string]any, error)
+	AbortAll(ctx context.Context) ([]string, map[string][]safety.RollbackResult)
+}
+
 // ChaosHandler handles chaos experiment endpoints
 type ChaosHandler struct {
-	runner      *engine.Runner
-	queries     *db.Queries
+	runner      ExperimentRunner
+	queries     db.Querier
+	experiments *db.ExperimentCache
 	esm         *safety.EmergencyStopManager
 	rollbackMgr *safety.RollbackManager
 	metrics     *observability.Metrics
+	queue       *experimentQueue
+	safeMode    bool
 }
 
 // NewChaosHandler creates a new ChaosHandler
 func NewChaosHandler(
-	runner *engine.Runner,
-	queries *db.Queries,
+	runner ExperimentRunner,
+	queries db.Querier,
 	esm *safety.EmergencyStopManager,
 	rollbackMgr *safety.RollbackManager,
 	metrics *observability.Metrics,
 ) *ChaosHandler {
-	return &ChaosHandler{
+	h := &ChaosHandler{
 		runner:      runner,
 		queries:     queries,
 		esm:         esm,
 		rollbackMgr: rollbackMgr,
 		metrics:     metrics,
 	}
+	if queries != nil {
+		h.experiments = db.NewExperimentCache(queries, experimentCacheTTL)
+		runner.SetPersistHook(h.experiments.Invalidate)
+	}
+	return h
+}
+
+// InvalidateExperiment drops an experiment from the read cache, e.g. once
+// retention has deleted it
+func (h *ChaosHandler) InvalidateExperiment(experimentID string) {
+	if h.experiments != nil {
+		h.experiments.Invalidate(experimentID)
+	}
+}
+
+// EnableSafeMode forces every experiment started through the handler to
+// dry-run and marks its result with safe_mode
+func (h *ChaosHandler) EnableSafeMode() {
+	h.safeMode = true
+}
+
+// SafeMode reports whether safe mode is on
+func (h *ChaosHandler) SafeMode() bool {
+	return h.safeMode
 }
 
 // CreateExperiment creates and runs a chaos experiment
 func (h *ChaosHandler) CreateExperiment(c *gin.Context) {
 	if h.esm.IsTriggered() {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"detail": "Emergency stop is active"})
+		respondError(c, http.StatusServiceUnavailable, CodeEmergencyStop, "Emergency stop is active")
 		return
 	}
 
 	var cfg domain.ExperimentConfig
 	if err := c.ShouldBindJSON(&cfg); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"detail": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
-	// Fill in zero-value safety fields with defaults
-	defaults := domain.DefaultSafetyConfig()
-	if cfg.Safety.TimeoutSeconds == 0 {
-		cfg.Safety.TimeoutSeconds = defaults.TimeoutSeconds
-	}
-	if cfg.Safety.MaxBlastRadius == 0 {
-		cfg.Safety.MaxBlastRadius = defaults.MaxBlastRadius
-	}
-	if cfg.Safety.HealthCheckInterval == 0 {
-		cfg.Safety.HealthCheckInterval = defaults.HealthCheckInterval
-	}
-	if cfg.Safety.HealthCheckFailureThreshold == 0 {
-		cfg.Safety.HealthCheckFailureThreshold = defaults.HealthCheckFailureThreshold
+	applySafetyDefaults(&cfg)
+	h.runExperiment(c, cfg, nil)
+}
+
+// runExperiment starts the experiment and writes the result as the response
+func (h *ChaosHandler) runExperiment(c *gin.Context, cfg domain.ExperimentConfig, rerunOf *string) {
+	status, result, err := h.startExperiment(c.Request.Context(), cfg, rerunOf)
+	if err != nil {
+		respondDomainError(c, err)
+		return
 	}
+	c.JSON(status, result)
+}
 
-	experimentID := uuid.New().String()[:8]
+// startExperiment persists the initial record and runs the experiment,
+// returning 200 with its result. In sequential mode the experiment is queued
+// instead and the pending record is returned with 202. rerunOf links a rerun
+// to the experiment it repeats; it is nil otherwise.
+func (h *ChaosHandler) startExperiment(ctx context.Context, cfg domain.ExperimentConfig, rerunOf *string) (int, *domain.ExperimentResult, error) {
+	// Safe mode is deployment-wide, so the caller cannot opt back out of it
+	if h.safeMode {
+		cfg.Safety.DryRun = true
+	}
 	now := time.Now().UTC()
+	initial := domain.ExperimentResult{
+		ExperimentID: uuid.New().String()[:8],
+		Config:       cfg,
+		Status:       domain.StatusRunning,
+		Phase:        domain.PhaseSteadyState,
+		StartedAt:    &now,
+		RerunOf:      rerunOf,
+		SafeMode:     h.safeMode,
+	}
 
-	// Persist initial record
-	if h.queries != nil {
-		configJSON, err := json.Marshal(cfg)
+	if h.queue != nil {
+		initial.Status = domain.StatusPending
+		err := h.queue.enqueue(queuedExperiment{id: initial.ExperimentID, cfg: cfg, requestID: observability.RequestID(ctx)}, func() {
+			h.persistInitial(ctx, initial)
+		})
 		if err != nil {
-			log.Printf("Failed to marshal config for experiment %s: %v", experimentID, err)
-			configJSON = []byte("{}")
-		}
-		if _, err := h.queries.CreateExperiment(c.Request.Context(), db.CreateExperimentParams{
-			ID:     experimentID,
-			Config: configJSON,
-			Status: string(domain.StatusRunning),
-			Phase:  string(domain.PhaseSteadyState),
-			StartedAt: pgtype.Timestamptz{
-				Time:  now,
-				Valid: true,
-			},
-		}); err != nil {
-			log.Printf("Failed to persist experiment %s: %v", experimentID, err)
+			return 0, nil, err
 		}
+		return http.StatusAccepted, &initial, nil
+	}
+
+	h.persistInitial(ctx, initial)
+	result, err := h.execute(ctx, initial.ExperimentID, cfg)
+	if err != nil {
+		return 0, nil, err
+	}
+	result.RerunOf = rerunOf
+	result.SafeMode = h.safeMode
+	return http.StatusOK, result, nil
+}
+
+// persistInitial writes the experiment record before it runs
+func (h *ChaosHandler) persistInitial(ctx context.Context, initial domain.ExperimentResult) {
+	if h.queries == nil {
+		return
+	}
+	configJSON, err := json.Marshal(initial.Config)
+	if err != nil {
+		observability.Logf(ctx, "Failed to marshal config for experiment %s: %v", initial.ExperimentID, err)
+		configJSON = []byte("{}")
+	}
+	var rerunOf pgtype.Text
+	if initial.RerunOf != nil {
+		rerunOf = pgtype.Text{String: *initial.RerunOf, Valid: true}
+	}
+	if _, err := h.queries.CreateExperiment(ctx, db.CreateExperimentParams{
+		ID:     initial.ExperimentID,
+		Config: configJSON,
+		Status: string(initial.Status),
+		Phase:  string(initial.Phase),
+		StartedAt: pgtype.Timestamptz{
+			Time:  *initial.StartedAt,
+			Valid: true,
+		},
+		RerunOf: rerunOf,
+	}); err != nil {
+		observability.Logf(ctx, "Failed to persist experiment %s: %v", initial.ExperimentID, err)
 	}
+}
 
+// execute runs the experiment and records its metrics
+func (h *ChaosHandler) execute(ctx context.Context, experimentID string, cfg domain.ExperimentConfig) (*domain.ExperimentResult, error) {
+	start := time.Now()
 	h.metrics.RecordExperimentStart()
 
-	result, err := h.runner.Run(c.Request.Context(), experimentID, cfg)
+	result, err := h.runner.Run(ctx, experimentID, cfg)
+	duration := time.Since(start).Seconds()
 	if err != nil {
-		duration := time.Since(now).Seconds()
 		h.metrics.RecordExperimentEnd(string(cfg.ChaosType), "failed", duration)
-		c.JSON(http.StatusInternalServerError, gin.H{"detail": err.Error()})
-		return
+		return nil, err
 	}
-
-	duration := time.Since(now).Seconds()
 	h.metrics.RecordExperimentEnd(string(cfg.ChaosType), string(result.Status), duration)
-	c.JSON(http.StatusOK, result)
+	return result, nil
 }
 
-// ListExperiments returns all experiments
+// ListExperiments returns all experiments, optionally only those started
+// within ?since=&until= (RFC 3339, inclusive) and those whose config names
+// ?owner=
 func (h *ChaosHandler) ListExperiments(c *gin.Context) {
 	if h.queries == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"detail": "Database not available"})
 		return
 	}
-	records, err := h.queries.ListExperiments(c.Request.Context())
+	between, err := parseTimeRange(c.Query("since"), c.Query("until"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"detail": err.Error()})
+		return
+	}
+
+	filter := db.ListExperimentsFilteredParams{Since: between.Since, Until: between.Until}
+	if owner := c.Query("owner"); owner != "" {
+		filter.Owner = pgtype.Text{String: owner, Valid: true}
+	}
+
+	var records []db.Experiment
+	if filter.Since.Valid || filter.Until.Valid || filter.Owner.Valid {
+		records, err = h.queries.ListExperimentsFiltered(c.Request.Context(), filter)
+	} else {
+		records, err = h.queries.ListExperiments(c.Request.Context())
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"detail": err.Error()})
 		return
@@ -129,6 +237,29 @@ func (h *ChaosHandler) ListExperiments(c *gin.Context) {
 	c.JSON(http.StatusOK, results)
 }
 
+// parseTimeRange parses optional RFC 3339 since/until bounds
+func parseTimeRange(since, until string) (db.ListExperimentsFilteredParams, error) {
+	var r db.ListExperimentsFilteredParams
+	if since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return r, fmt.Errorf("invalid since %q: must be an RFC 3339 timestamp", since)
+		}
+		r.Since = pgtype.Timestamptz{Time: t, Valid: true}
+	}
+	if until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return r, fmt.Errorf("invalid until %q: must be an RFC 3339 timestamp", until)
+		}
+		r.Until = pgtype.Timestamptz{Time: t, Valid: true}
+	}
+	if r.Since.Valid && r.Until.Valid && r.Since.Time.After(r.Until.Time) {
+		return r, fmt.Errorf("since must not be after until")
+	}
+	return r, nil
+}
+
 // GetExperiment returns a specific experiment
 func (h *ChaosHandler) GetExperiment(c *gin.Context) {
 	if h.queries == nil {
@@ -146,6 +277,132 @@ func (h *ChaosHandler) GetExperiment(c *gin.Context) {
 	c.JSON(http.StatusOK, recordToResult(rec))
 }
 
+// UpdateExperimentConfig replaces the stored config of an experiment that
+// has not started yet. A queued experiment picks the new config up when its
+// turn comes; running and finished experiments answer 409.
+func (h *ChaosHandler) UpdateExperimentConfig(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+	experimentID := c.Param("experiment_id")
+
+	var cfg domain.ExperimentConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	applySafetyDefaults(&cfg)
+	if errs := domain.ValidateConfig(cfg); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, validateResponse{Valid: false, Errors: errs})
+		return
+	}
+	if h.safeMode {
+		cfg.Safety.DryRun = true
+	}
+
+	rec, err := h.queries.GetExperiment(c.Request.Context(), experimentID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, CodeExperimentNotFound, fmt.Sprintf("Experiment %s not found", experimentID))
+		return
+	}
+	if domain.ExperimentStatus(rec.Status) != domain.StatusPending {
+		respondError(c, http.StatusConflict, CodeExperimentNotEditable,
+			fmt.Sprintf("experiment %s is %s; only pending experiments can be edited", experimentID, rec.Status))
+		return
+	}
+
+	configJSON, err := json.Marshal(cfg)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	// The update only matches while the record is still pending, so an
+	// experiment the queue started after the check above is not rewritten
+	updated, err := h.experiments.UpdateExperimentConfig(c.Request.Context(), db.UpdateExperimentConfigParams{
+		ID:     experimentID,
+		Config: configJSON,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(c, http.StatusConflict, CodeExperimentNotEditable,
+			fmt.Sprintf("experiment %s started before the update; only pending experiments can be edited", experimentID))
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, recordToResult(updated))
+}
+
+// ListProbeResults returns the probe results recorded for an experiment
+func (h *ChaosHandler) ListProbeResults(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+	experimentID := c.Param("experiment_id")
+
+	if _, err := h.queries.GetExperiment(c.Request.Context(), experimentID); err != nil {
+		respondError(c, http.StatusNotFound, CodeExperimentNotFound, fmt.Sprintf("Experiment %s not found", experimentID))
+		return
+	}
+
+	results, err := h.queries.ListProbeResultsByExperiment(c.Request.Context(), experimentID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// ListExperimentEvents returns an experiment's audit timeline, oldest first
+func (h *ChaosHandler) ListExperimentEvents(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+	experimentID := c.Param("experiment_id")
+
+	if _, err := h.queries.GetExperiment(c.Request.Context(), experimentID); err != nil {
+		respondError(c, http.StatusNotFound, CodeExperimentNotFound, fmt.Sprintf("Experiment %s not found", experimentID))
+		return
+	}
+
+	events, err := h.queries.ListExperimentEvents(c.Request.Context(), experimentID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// ListExperimentPodLogs returns the target pod logs an experiment captured
+// before injection and after observation, oldest first
+func (h *ChaosHandler) ListExperimentPodLogs(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+	experimentID := c.Param("experiment_id")
+
+	if _, err := h.queries.GetExperiment(c.Request.Context(), experimentID); err != nil {
+		respondError(c, http.StatusNotFound, CodeExperimentNotFound, fmt.Sprintf("Experiment %s not found", experimentID))
+		return
+	}
+
+	logs, err := h.queries.ListExperimentPodLogs(c.Request.Context(), experimentID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
 // RollbackExperiment triggers rollback for a specific experiment
 func (h *ChaosHandler) RollbackExperiment(c *gin.Context) {
 	experimentID := c.Param("experiment_id")
@@ -158,31 +415,224 @@ func (h *ChaosHandler) RollbackExperiment(c *gin.Context) {
 		}
 	}
 
-	results := h.rollbackMgr.Rollback(experimentID)
+	results := h.rollbackMgr.Rollback(context.WithoutCancel(c.Request.Context()), experimentID)
 	if h.queries != nil {
-		if err := h.queries.UpdateExperimentStatus(c.Request.Context(), db.UpdateExperimentStatusParams{
+		if err := h.experiments.UpdateExperimentStatus(c.Request.Context(), db.UpdateExperimentStatusParams{
 			ID:     experimentID,
 			Status: string(domain.StatusRolledBack),
 		}); err != nil {
-			log.Printf("Failed to update experiment status: %v", err)
+			observability.Logf(c.Request.Context(), "Failed to update experiment status: %v", err)
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"experiment_id":    experimentID,
 		"rollback_results": results,
+	}
+	if c.Query("verify") == "true" {
+		drift, err := h.runner.VerifyRollback(context.WithoutCancel(c.Request.Context()), experimentID)
+		if err != nil {
+			resp["verify_error"] = err.Error()
+		} else {
+			resp["residual_drift"] = drift
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AbortAll cancels every running experiment and rolls back all active
+// experiments without triggering the emergency stop, so new experiments
+// may start straight away
+func (h *ChaosHandler) AbortAll(c *gin.Context) {
+	aborted, results := h.runner.AbortAll(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{
+		"status":           "aborted",
+		"aborted":          aborted,
+		"rollback_results": results,
+		"emergency_stop":   h.esm.IsTriggered(),
 	})
 }
 
-// DryRun executes a dry-run chaos experiment
+// rollbackStatusResponse summarizes the persisted rollback results of an
+// experiment, one entry per rollback action in execution order
+type rollbackStatusResponse struct {
+	ExperimentID string                  `json:"experiment_id"`
+	Status       domain.ExperimentStatus `json:"status"`
+	Total        int                     `json:"total"`
+	Succeeded    int                     `json:"succeeded"`
+	Failed       int                     `json:"failed"`
+	Results      []safety.RollbackResult `json:"results"`
+	// ResidualDrift is what verification still found after the rollback
+	ResidualDrift []any `json:"residual_drift,omitempty"`
+}
+
+// GetRollbackStatus returns the per-action outcome of an experiment's
+// persisted rollback
+func (h *ChaosHandler) GetRollbackStatus(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+	experimentID := c.Param("experiment_id")
+
+	rec, err := h.queries.GetExperiment(c.Request.Context(), experimentID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, CodeExperimentNotFound, fmt.Sprintf("Experiment %s not found", experimentID))
+		return
+	}
+	result := recordToResult(rec)
+	results := domain.PersistedRollbackResults(result.RollbackResult)
+	if len(results) == 0 {
+		respondError(c, http.StatusNotFound, CodeRollbackResultNotFound,
+			fmt.Sprintf("No rollback result recorded for experiment %s", experimentID))
+		return
+	}
+
+	resp := rollbackStatusResponse{
+		ExperimentID: experimentID,
+		Status:       result.Status,
+		Total:        len(results),
+		Results:      results,
+	}
+	resp.ResidualDrift = domain.PersistedResidualDrift(result.RollbackResult)
+	for _, r := range results {
+		if r.Status == "success" {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// dryRunResponse is the experiment result of a dry run plus a preview of the
+// targets a real run would affect and the errors it would hit
+type dryRunResponse struct {
+	domain.ExperimentResult
+	WouldAffect map[string]any `json:"would_affect"`
+	Errors      []string       `json:"errors"`
+}
+
+// DryRun previews a chaos experiment: targets are resolved through the engines'
+// read-only paths and safety checks are evaluated, but nothing is mutated. The
+// preview is stored under its dry- ID for PromoteDryRun.
 func (h *ChaosHandler) DryRun(c *gin.Context) {
 	var cfg domain.ExperimentConfig
 	if err := c.ShouldBindJSON(&cfg); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"detail": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	cfg.Safety.DryRun = true
+	applySafetyDefaults(&cfg)
+
+	experimentID := engine.NewDryRunID()
+	result, errs := h.runner.DryRun(c.Request.Context(), experimentID, cfg)
+
+	resp := dryRunResponse{
+		ExperimentResult: *result,
+		WouldAffect:      map[string]any{"count": 0, "names": []string{}},
+		Errors:           make([]string, 0, len(errs)),
+	}
+	if wa, ok := result.InjectionResult["would_affect"].(map[string]any); ok {
+		resp.WouldAffect = wa
+	}
+	for _, err := range errs {
+		resp.Errors = append(resp.Errors, err.Error())
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PromoteDryRun runs the exact config of a stored dry-run preview for real,
+// so the run cannot drift from what was previewed
+func (h *ChaosHandler) PromoteDryRun(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+	dryID := c.Param("experiment_id")
+	if !strings.HasPrefix(dryID, engine.DryRunIDPrefix) {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest,
+			fmt.Sprintf("experiment %s is not a dry run; only dry-run previews can be promoted", dryID))
+		return
+	}
+
+	rec, err := h.queries.GetExperiment(c.Request.Context(), dryID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(c, http.StatusNotFound, CodeExperimentNotFound, fmt.Sprintf("Dry run %s not found", dryID))
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	preview := recordToResult(rec)
+	if preview.Status == domain.StatusFailed {
+		msg := fmt.Sprintf("dry run %s reported errors; fix the config and preview it again", dryID)
+		if preview.Error != nil {
+			msg += ": " + *preview.Error
+		}
+		respondError(c, http.StatusUnprocessableEntity, CodeInvalidConfig, msg)
+		return
+	}
+
+	cfg := preview.Config
+	cfg.Safety.DryRun = false
+	h.runExperiment(c, cfg, nil)
+}
+
+// RerunExperiment runs the stored config of a finished experiment again
+// under a new ID linked to the original through rerun_of
+func (h *ChaosHandler) RerunExperiment(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+	experimentID := c.Param("experiment_id")
+	if strings.HasPrefix(experimentID, engine.DryRunIDPrefix) {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest,
+			fmt.Sprintf("experiment %s is a dry run; promote it instead", experimentID))
+		return
+	}
+
+	rec, err := h.queries.GetExperiment(c.Request.Context(), experimentID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, CodeExperimentNotFound, fmt.Sprintf("Experiment %s not found", experimentID))
+		return
+	}
+	original := recordToResult(rec)
+	if original.Status == domain.StatusPending || original.Status == domain.StatusRunning {
+		respondError(c, http.StatusConflict, CodeExperimentRunning,
+			fmt.Sprintf("experiment %s is still %s", experimentID, original.Status))
+		return
+	}
+
+	h.runExperiment(c, original.Config, &experimentID)
+}
+
+// validateResponse is returned by the config validation endpoint
+type validateResponse struct {
+	Valid  bool                     `json:"valid"`
+	Errors []domain.ValidationError `json:"errors"`
+}
+
+// ValidateExperiment checks an experiment config without executing it.
+// Struct binding tags are skipped so every problem is reported at once.
+func (h *ChaosHandler) ValidateExperiment(c *gin.Context) {
+	var cfg domain.ExperimentConfig
+	if err := json.NewDecoder(c.Request.Body).Decode(&cfg); err != nil {
+		respondBindError(c, fmt.Errorf("invalid JSON: %w", err))
+		return
+	}
+	applySafetyDefaults(&cfg)
+
+	errs := domain.ValidateConfig(cfg)
+	c.JSON(http.StatusOK, validateResponse{Valid: len(errs) == 0, Errors: errs})
+}
+
+// applySafetyDefaults fills in zero-value safety fields with defaults
+func applySafetyDefaults(cfg *domain.ExperimentConfig) {
 	defaults := domain.DefaultSafetyConfig()
 	if cfg.Safety.TimeoutSeconds == 0 {
 		cfg.Safety.TimeoutSeconds = defaults.TimeoutSeconds
@@ -190,19 +640,12 @@ func (h *ChaosHandler) DryRun(c *gin.Context) {
 	if cfg.Safety.MaxBlastRadius == 0 {
 		cfg.Safety.MaxBlastRadius = defaults.MaxBlastRadius
 	}
-
-	experimentID := "dry-" + uuid.New().String()[:8]
-	now := time.Now().UTC()
-
-	result := domain.ExperimentResult{
-		ExperimentID: experimentID,
-		Config:       cfg,
-		Status:       domain.StatusCompleted,
-		StartedAt:    &now,
-		CompletedAt:  &now,
+	if cfg.Safety.HealthCheckInterval == 0 {
+		cfg.Safety.HealthCheckInterval = defaults.HealthCheckInterval
+	}
+	if cfg.Safety.HealthCheckFailureThreshold == 0 {
+		cfg.Safety.HealthCheckFailureThreshold = defaults.HealthCheckFailureThreshold
 	}
-
-	c.JSON(http.StatusOK, result)
 }
 
 // recordToResult converts a DB record to domain ExperimentResult
@@ -224,6 +667,9 @@ func recordToResult(rec db.Experiment) domain.ExperimentResult {
 		t := rec.StartedAt.Time
 		result.StartedAt = &t
 	}
+	if rec.RerunOf.Valid {
+		result.RerunOf = &rec.RerunOf.String
+	}
 	if rec.CompletedAt.Valid {
 		t := rec.CompletedAt.Time
 		result.CompletedAt = &t
@@ -269,6 +715,13 @@ func recordToResult(rec db.Experiment) domain.ExperimentResult {
 		}
 		result.AIInsights = ai
 	}
+	if len(rec.PhaseTimings) > 0 {
+		var pt map[string]float64
+		if err := json.Unmarshal(rec.PhaseTimings, &pt); err != nil {
+			log.Printf("Failed to unmarshal phase_timings for experiment %s: %v", rec.ID, err)
+		}
+		result.PhaseTimings = pt
+	}
 
 	return result
 }
@@ -281,14 +734,50 @@ var terminalStatuses = map[domain.ExperimentStatus]bool{
 	domain.StatusEmergencyStopped: true,
 }
 
-// sendSSE writes a single SSE event to the response writer
-func sendSSE(c *gin.Context, event string, data any) {
+// sseRetryMillis is the reconnect delay the stream asks browsers to use
+const sseRetryMillis = 3000
+
+// phaseOrder ranks phases in lifecycle order
+var phaseOrder = map[domain.ExperimentPhase]int{
+	domain.PhaseSteadyState: 1,
+	domain.PhaseHypothesis:  2,
+	domain.PhaseInject:      3,
+	domain.PhaseObserve:     4,
+	domain.PhaseRollback:    5,
+}
+
+// sseEventID identifies an experiment state for Last-Event-ID resumption.
+// Phases only move forward and a terminal status is final, so the ID grows
+// with every state change the stream reports.
+func sseEventID(r domain.ExperimentResult) int {
+	rank := 1
+	switch {
+	case r.Status == domain.StatusPending:
+		rank = 0
+	case terminalStatuses[r.Status]:
+		rank = 2
+	}
+	return phaseOrder[r.Phase]*3 + rank
+}
+
+// lastEventID reads the Last-Event-ID a reconnecting client sends; -1 when
+// absent or not one of ours
+func lastEventID(c *gin.Context) int {
+	id, err := strconv.Atoi(c.GetHeader("Last-Event-ID"))
+	if err != nil {
+		return -1
+	}
+	return id
+}
+
+// sendSSE writes a single SSE event with the given id to the response writer
+func sendSSE(c *gin.Context, id int, event string, data any) {
 	j, err := json.Marshal(data)
 	if err != nil {
-		log.Printf("SSE marshal error: %v", err)
+		observability.Logf(c.Request.Context(), "SSE marshal error: %v", err)
 		return
 	}
-	_, _ = fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, j)
+	_, _ = fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", id, event, j)
 	if f, ok := c.Writer.(http.Flusher); ok {
 		f.Flush()
 	}
@@ -303,12 +792,20 @@ func (h *ChaosHandler) StreamExperiment(c *gin.Context) {
 	experimentID := c.Param("experiment_id")
 
 	// Fetch initial state (also verifies experiment exists)
-	rec, err := h.queries.GetExperiment(c.Request.Context(), experimentID)
+	rec, err := h.experiments.GetExperiment(c.Request.Context(), experimentID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"detail": "Experiment not found"})
 		return
 	}
 
+	// The stream outlives the server's WriteTimeout, which would otherwise
+	// cut it off mid-experiment; it ends when the experiment finishes or the
+	// client goes away
+	err = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		observability.Logf(c.Request.Context(), "SSE %s: clear write deadline: %v", experimentID, err)
+	}
+
 	// Set SSE headers
 	c.Writer.Header().Set("Content-Type", "text/event-stream")
 	c.Writer.Header().Set("Cache-Control", "no-cache")
@@ -316,14 +813,12 @@ func (h *ChaosHandler) StreamExperiment(c *gin.Context) {
 	c.Writer.Header().Set("X-Accel-Buffering", "no")
 	c.Status(http.StatusOK)
 
-	// Send initial state immediately
-	result := recordToResult(rec)
-	lastStatus := string(result.Status)
-	lastPhase := string(result.Phase)
-	sendSSE(c, "experiment", result)
+	// A reconnecting client already has every state up to Last-Event-ID
+	lastID := lastEventID(c)
+	_, _ = fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetryMillis)
 
-	if terminalStatuses[result.Status] {
-		sendSSE(c, "done", gin.H{"status": result.Status})
+	// Send initial state immediately
+	if sendNewState(c, recordToResult(rec), &lastID) {
 		return
 	}
 
@@ -335,30 +830,34 @@ func (h *ChaosHandler) StreamExperiment(c *gin.Context) {
 	for {
 		select {
 		case <-maxTimeout:
-			sendSSE(c, "timeout", gin.H{"message": "stream max timeout reached"})
+			sendSSE(c, lastID, "timeout", gin.H{"message": "stream max timeout reached"})
 			return
 		case <-c.Request.Context().Done():
 			return
 		case <-ticker.C:
-			rec, err := h.queries.GetExperiment(c.Request.Context(), experimentID)
+			rec, err := h.experiments.GetExperiment(c.Request.Context(), experimentID)
 			if err != nil {
 				continue
 			}
-			result := recordToResult(rec)
-			currentStatus := string(result.Status)
-			currentPhase := string(result.Phase)
-
-			// Only send when state changes
-			if currentStatus != lastStatus || currentPhase != lastPhase {
-				lastStatus = currentStatus
-				lastPhase = currentPhase
-				sendSSE(c, "experiment", result)
-
-				if terminalStatuses[result.Status] {
-					sendSSE(c, "done", gin.H{"status": result.Status})
-					return
-				}
+			if sendNewState(c, recordToResult(rec), &lastID) {
+				return
 			}
 		}
 	}
 }
+
+// sendNewState sends result when it is newer than the client's *lastID,
+// followed by "done" once it is terminal. It reports whether the stream is
+// finished.
+func sendNewState(c *gin.Context, result domain.ExperimentResult, lastID *int) bool {
+	id := sseEventID(result)
+	if id > *lastID {
+		*lastID = id
+		sendSSE(c, id, "experiment", result)
+	}
+	if terminalStatuses[result.Status] {
+		sendSSE(c, id, "done", gin.H{"status": result.Status})
+		return true
+	}
+	return false
+}
@@ -1,25 +1,43 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/chaosduck/backend-go/internal/db"
 	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/engine"
 	"github.com/chaosduck/backend-go/internal/observability"
 	"github.com/chaosduck/backend-go/internal/safety"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func setupTestRouter() (*gin.Engine, *ChaosHandler) {
 	gin.SetMode(gin.TestMode)
-	metrics := observability.NewMetrics()
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
 	esm := safety.NewEmergencyStopManager()
 	rollbackMgr := safety.NewRollbackManager()
-	h := NewChaosHandler(nil, nil, esm, rollbackMgr, metrics)
+	runner := engine.NewRunner(nil, nil, esm, rollbackMgr, safety.NewSnapshotManager(nil), nil, metrics, "")
+	h := NewChaosHandler(runner, nil, esm, rollbackMgr, metrics)
 	r := gin.New()
 	return r, h
 }
@@ -45,10 +63,10 @@ func TestSendSSE(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 
 	data := map[string]string{"status": "running"}
-	sendSSE(c, "experiment", data)
+	sendSSE(c, 7, "experiment", data)
 
 	body := w.Body.String()
-	assert.Contains(t, body, "event: experiment\n")
+	assert.Contains(t, body, "id: 7\nevent: experiment\n")
 	assert.Contains(t, body, `"status":"running"`)
 	assert.Contains(t, body, "\n\n")
 }
@@ -61,3 +79,990 @@ func TestTerminalStatuses(t *testing.T) {
 	assert.False(t, terminalStatuses[domain.StatusRunning])
 	assert.False(t, terminalStatuses[domain.StatusPending])
 }
+
+func TestDryRun_NoDB(t *testing.T) {
+	r, h := setupTestRouter()
+	r.POST("/dry-run", h.DryRun)
+
+	body := `{"name":"preview","chaos_type":"pod_delete","target_labels":{"app":"web"},
+		"safety":{"timeout_seconds":30,"max_blast_radius":0.3,"health_check_interval":10,"health_check_failure_threshold":3}}`
+	req := httptest.NewRequest("POST", "/dry-run", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Contains(t, resp["experiment_id"], "dry-")
+	assert.Equal(t, "failed", resp["status"])
+	wouldAffect := resp["would_affect"].(map[string]any)
+	assert.Equal(t, float64(0), wouldAffect["count"])
+	errs := resp["errors"].([]any)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "k8s engine not available")
+}
+
+func TestValidateExperiment(t *testing.T) {
+	r, h := setupTestRouter()
+	r.POST("/validate", h.ValidateExperiment)
+
+	tests := []struct {
+		name       string
+		body       string
+		valid      bool
+		wantFields []string
+	}{
+		{
+			name:  "valid pod delete",
+			body:  `{"name":"ok","chaos_type":"pod_delete","target_labels":{"app":"web"}}`,
+			valid: true,
+		},
+		{
+			name:       "unknown type and missing name",
+			body:       `{"chaos_type":"disk_fill"}`,
+			wantFields: []string{"name", "chaos_type"},
+		},
+		{
+			name:       "route blackhole missing params",
+			body:       `{"name":"rb","chaos_type":"route_blackhole","parameters":{"destination_cidr":"10.0.0.0/8"}}`,
+			wantFields: []string{"parameters.route_table_id"},
+		},
+		{
+			name:       "latency and safety out of range",
+			body:       `{"name":"lat","chaos_type":"network_latency","parameters":{"latency_ms":90000},"safety":{"timeout_seconds":500}}`,
+			wantFields: []string{"parameters.latency_ms", "safety.timeout_seconds"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/validate", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp struct {
+				Valid  bool `json:"valid"`
+				Errors []struct {
+					Field string `json:"field"`
+				} `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.valid, resp.Valid)
+			fields := make([]string, 0, len(resp.Errors))
+			for _, e := range resp.Errors {
+				fields = append(fields, e.Field)
+			}
+			assert.ElementsMatch(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestValidateExperiment_InvalidJSON(t *testing.T) {
+	r, h := setupTestRouter()
+	r.POST("/validate", h.ValidateExperiment)
+
+	req := httptest.NewRequest("POST", "/validate", strings.NewReader("{not json"))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListProbeResults_NoDB(t *testing.T) {
+	r, h := setupTestRouter()
+	r.GET("/experiments/:experiment_id/probes", h.ListProbeResults)
+
+	req := httptest.NewRequest("GET", "/experiments/test123/probes", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"database_unavailable"`)
+}
+
+func TestListProbeResultsUnknownExperiment(t *testing.T) {
+	r := setupQuerierRouter(&fakeQuerier{experiments: map[string]db.Experiment{}})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/missing1/probes", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"experiment_not_found"`)
+}
+
+func TestListExperimentEvents_NoDB(t *testing.T) {
+	r, h := setupTestRouter()
+	r.GET("/experiments/:experiment_id/events", h.ListExperimentEvents)
+
+	req := httptest.NewRequest("GET", "/experiments/test123/events", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestGetRollbackStatus_NoDB(t *testing.T) {
+	r, h := setupTestRouter()
+	r.GET("/experiments/:experiment_id/rollback-status", h.GetRollbackStatus)
+
+	req := httptest.NewRequest("GET", "/experiments/abc/rollback-status", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+// experimentsDB is a DBTX fake serving experiment rows. ListExperimentsFiltered
+// applies the query's inclusive started_at bounds and owner match.
+type experimentsDB struct {
+	started map[string]time.Time
+	// owners holds the config owner of experiments that have one
+	owners map[string]string
+}
+
+func (d *experimentsDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (d *experimentsDB) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	rows := [][]any{}
+	for id, startedAt := range d.started {
+		owner, hasOwner := d.owners[id]
+		if strings.Contains(sql, "ListExperimentsFiltered") {
+			since, until, want := args[0].(pgtype.Timestamptz), args[1].(pgtype.Timestamptz), args[2].(pgtype.Text)
+			if (since.Valid && startedAt.Before(since.Time)) || (until.Valid && startedAt.After(until.Time)) {
+				continue
+			}
+			if want.Valid && (!hasOwner || owner != want.String) {
+				continue
+			}
+		}
+		row := experimentRow(id, startedAt)
+		if hasOwner {
+			row[1] = json.RawMessage(fmt.Sprintf(`{"owner":%q}`, owner))
+		}
+		rows = append(rows, row)
+	}
+	return &fakeRows{rows: rows}, nil
+}
+
+// QueryRow serves GetExperiment
+func (d *experimentsDB) QueryRow(_ context.Context, _ string, args ...interface{}) pgx.Row {
+	startedAt, ok := d.started[args[0].(string)]
+	if !ok {
+		return &fakeRows{}
+	}
+	return &fakeRows{rows: [][]any{experimentRow(args[0].(string), startedAt)}, pos: 1}
+}
+
+// experimentRow is a completed experiment's 14 experiments columns
+func experimentRow(id string, startedAt time.Time) []any {
+	return []any{
+		id, json.RawMessage(`{}`), string(domain.StatusCompleted), string(domain.PhaseRollback),
+		pgtype.Timestamptz{Time: startedAt, Valid: true}, pgtype.Timestamptz{},
+		[]byte(nil), pgtype.Text{}, []byte(nil), []byte(nil), []byte(nil), pgtype.Text{},
+		[]byte(nil), []byte(nil), pgtype.Text{},
+	}
+}
+
+// fakeRows scans preset values into destinations by reflection
+type fakeRows struct {
+	rows [][]any
+	pos  int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return r.rows[r.pos-1], nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	r.pos++
+	return r.pos <= len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if r.pos < 1 || r.pos > len(r.rows) {
+		return pgx.ErrNoRows
+	}
+	for i, v := range r.rows[r.pos-1] {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+	}
+	return nil
+}
+
+func setupDBRouter(fake db.DBTX) (*gin.Engine, *ChaosHandler) {
+	gin.SetMode(gin.TestMode)
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	esm := safety.NewEmergencyStopManager()
+	rollbackMgr := safety.NewRollbackManager()
+	queries := db.New(fake)
+	runner := engine.NewRunner(nil, nil, esm, rollbackMgr, safety.NewSnapshotManager(nil), queries, metrics, "")
+	return gin.New(), NewChaosHandler(runner, queries, esm, rollbackMgr, metrics)
+}
+
+func TestListExperimentsTimeRange(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r, h := setupDBRouter(&experimentsDB{started: map[string]time.Time{
+		"before": base.Add(-time.Hour),
+		"start":  base,
+		"middle": base.Add(30 * time.Minute),
+		"end":    base.Add(time.Hour),
+		"after":  base.Add(2 * time.Hour),
+	}})
+	r.GET("/experiments", h.ListExperiments)
+
+	list := func(query string) (int, []string) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments"+query, nil))
+		var results []domain.ExperimentResult
+		_ = json.Unmarshal(w.Body.Bytes(), &results)
+		ids := []string{}
+		for _, res := range results {
+			ids = append(ids, res.ExperimentID)
+		}
+		return w.Code, ids
+	}
+
+	code, ids := list("")
+	require.Equal(t, http.StatusOK, code)
+	assert.Len(t, ids, 5)
+
+	// Both bounds are inclusive
+	code, ids = list("?since=2026-03-01T12:00:00Z&until=2026-03-01T13:00:00Z")
+	require.Equal(t, http.StatusOK, code)
+	assert.ElementsMatch(t, []string{"start", "middle", "end"}, ids)
+
+	code, ids = list("?since=2026-03-01T13:00:00Z")
+	require.Equal(t, http.StatusOK, code)
+	assert.ElementsMatch(t, []string{"end", "after"}, ids)
+
+	code, ids = list("?until=2026-03-01T11:00:00%2B00:00")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"before"}, ids)
+
+	code, ids = list("?since=2026-01-01T00:00:00Z&until=2026-01-31T00:00:00Z")
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, ids)
+}
+
+func TestListExperimentsInvalidTimeRange(t *testing.T) {
+	r, h := setupDBRouter(&experimentsDB{})
+	r.GET("/experiments", h.ListExperiments)
+
+	for _, query := range []string{
+		"?since=yesterday",
+		"?until=2026-03-01",
+		"?since=2026-03-02T00:00:00Z&until=2026-03-01T00:00:00Z",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestListExperimentsOwnerWithinTimeRange(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r, h := setupDBRouter(&experimentsDB{
+		started: map[string]time.Time{
+			"payearly": base.Add(-time.Hour),
+			"pay00001": base,
+			"pay00002": base.Add(time.Hour),
+			"web00001": base.Add(30 * time.Minute),
+			"none0001": base.Add(45 * time.Minute),
+		},
+		owners: map[string]string{
+			"payearly": "payments@example.com",
+			"pay00001": "payments@example.com",
+			"pay00002": "payments@example.com",
+			"web00001": "@web-team",
+		},
+	})
+	r.GET("/experiments", h.ListExperiments)
+
+	list := func(query string) []string {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var results []domain.ExperimentResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+		ids := []string{}
+		for _, res := range results {
+			ids = append(ids, res.ExperimentID)
+		}
+		return ids
+	}
+
+	assert.ElementsMatch(t, []string{"payearly", "pay00001", "pay00002"}, list("?owner=payments@example.com"))
+	assert.ElementsMatch(t, []string{"pay00001", "pay00002"},
+		list("?owner=payments@example.com&since=2026-03-01T12:00:00Z"))
+	assert.Equal(t, []string{"pay00001"},
+		list("?owner=payments@example.com&since=2026-03-01T12:00:00Z&until=2026-03-01T12:30:00Z"))
+	assert.Equal(t, []string{"web00001"}, list("?owner=%40web-team&until=2026-03-01T13:00:00Z"))
+	assert.Empty(t, list("?owner=%40web-team&since=2026-03-01T12:31:00Z"))
+	assert.Len(t, list("?since=2026-03-01T12:00:00Z"), 4, "without an owner every experiment in range is listed")
+}
+
+func TestStreamExperimentEventIDs(t *testing.T) {
+	r, h := setupDBRouter(&experimentsDB{started: map[string]time.Time{"exp-1": time.Now()}})
+	r.GET("/experiments/:experiment_id/stream", h.StreamExperiment)
+
+	stream := func(lastEventID string) string {
+		req := httptest.NewRequest("GET", "/experiments/exp-1/stream", nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	// completed in the rollback phase
+	id := sseEventID(domain.ExperimentResult{Status: domain.StatusCompleted, Phase: domain.PhaseRollback})
+
+	body := stream("")
+	assert.True(t, strings.HasPrefix(body, "retry: 3000\n\n"))
+	assert.Contains(t, body, fmt.Sprintf("id: %d\nevent: experiment\n", id))
+	assert.Contains(t, body, fmt.Sprintf("id: %d\nevent: done\n", id))
+
+	// Reconnecting after the final state only gets the done marker
+	body = stream(strconv.Itoa(id))
+	assert.NotContains(t, body, "event: experiment")
+	assert.Contains(t, body, "event: done")
+
+	// An older ID, or one that is not ours, replays the state
+	assert.Contains(t, stream(strconv.Itoa(id-1)), "event: experiment")
+	assert.Contains(t, stream("garbage"), "event: experiment")
+}
+
+func TestStreamExperimentNotFound(t *testing.T) {
+	r, h := setupDBRouter(&experimentsDB{})
+	r.GET("/experiments/:experiment_id/stream", h.StreamExperiment)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/missing/stream", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSSEEventIDGrowsWithEveryStateChange(t *testing.T) {
+	states := []domain.ExperimentResult{
+		{Status: domain.StatusPending, Phase: domain.PhaseSteadyState},
+		{Status: domain.StatusRunning, Phase: domain.PhaseSteadyState},
+		{Status: domain.StatusRunning, Phase: domain.PhaseHypothesis},
+		{Status: domain.StatusRunning, Phase: domain.PhaseInject},
+		{Status: domain.StatusRunning, Phase: domain.PhaseObserve},
+		{Status: domain.StatusRunning, Phase: domain.PhaseRollback},
+		{Status: domain.StatusCompleted, Phase: domain.PhaseRollback},
+	}
+	for i := 1; i < len(states); i++ {
+		assert.Greater(t, sseEventID(states[i]), sseEventID(states[i-1]), "%+v", states[i])
+	}
+
+	// Failing during injection is still newer than the running inject state
+	assert.Greater(t,
+		sseEventID(domain.ExperimentResult{Status: domain.StatusFailed, Phase: domain.PhaseInject}),
+		sseEventID(domain.ExperimentResult{Status: domain.StatusRunning, Phase: domain.PhaseInject}))
+}
+
+// experimentStore is a DBTX fake that keeps the experiments written through
+// CreateExperiment and UpdateExperiment in memory
+type experimentStore struct {
+	mu   sync.Mutex
+	rows map[string]db.Experiment
+	// getErr, when set, fails every GetExperiment
+	getErr error
+}
+
+func (s *experimentStore) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if strings.Contains(sql, "name: UpdateExperiment :exec") {
+		e := s.rows[args[0].(string)]
+		e.Status, e.Phase = args[1].(string), args[2].(string)
+		e.CompletedAt = args[3].(pgtype.Timestamptz)
+		e.InjectionResult = args[6].([]byte)
+		e.Error = args[9].(pgtype.Text)
+		s.rows[e.ID] = e
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+func (s *experimentStore) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+func (s *experimentStore) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case strings.Contains(sql, "name: CreateExperiment"):
+		id := args[0].(string)
+		if _, ok := s.rows[id]; ok {
+			return &fakeRows{}
+		}
+		s.rows[id] = db.Experiment{
+			ID: id, Config: args[1].(json.RawMessage), Status: args[2].(string), Phase: args[3].(string),
+			StartedAt: args[4].(pgtype.Timestamptz), RerunOf: args[5].(pgtype.Text),
+		}
+		return &fakeRows{rows: [][]any{experimentColumns(s.rows[id])}, pos: 1}
+	case strings.Contains(sql, "name: GetExperiment"):
+		if s.getErr != nil {
+			return errRow{s.getErr}
+		}
+		if e, ok := s.rows[args[0].(string)]; ok {
+			return &fakeRows{rows: [][]any{experimentColumns(e)}, pos: 1}
+		}
+	}
+	return &fakeRows{}
+}
+
+// errRow is a row whose Scan returns err
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+func experimentColumns(e db.Experiment) []any {
+	return []any{
+		e.ID, e.Config, e.Status, e.Phase, e.StartedAt, e.CompletedAt, e.SteadyState, e.Hypothesis,
+		e.InjectionResult, e.Observations, e.RollbackResult, e.Error, e.AiInsights, e.PhaseTimings, e.RerunOf,
+	}
+}
+
+func setupPromoteRouter() (*gin.Engine, *experimentStore) {
+	gin.SetMode(gin.TestMode)
+	store := &experimentStore{rows: map[string]db.Experiment{}}
+	queries := db.New(store)
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	esm := safety.NewEmergencyStopManager()
+	rollbackMgr := safety.NewRollbackManager()
+	k8s := engine.NewK8sEngineWithClientset(fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop", Labels: map[string]string{"app": "web"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}), esm)
+	runner := engine.NewRunner(k8s, nil, esm, rollbackMgr, safety.NewSnapshotManager(nil), queries, metrics, "")
+	h := NewChaosHandler(runner, queries, esm, rollbackMgr, metrics)
+
+	r := gin.New()
+	r.POST("/dry-run", h.DryRun)
+	r.POST("/experiments/:experiment_id/promote", h.PromoteDryRun)
+	r.POST("/experiments/:experiment_id/rerun", h.RerunExperiment)
+	return r, store
+}
+
+// testSafetyJSON passes the safety config's binding rules
+const testSafetyJSON = `{"timeout_seconds":30,"health_check_interval":5,"health_check_failure_threshold":3,"max_blast_radius":1}`
+
+func postJSON(r *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", path, strings.NewReader(body)))
+	return w
+}
+
+func TestPromoteDryRunRunsPreviewedConfig(t *testing.T) {
+	r, store := setupPromoteRouter()
+
+	w := postJSON(r, "/dry-run", `{"name":"kill-web","chaos_type":"pod_delete","target_namespace":"shop",
+		"target_labels":{"app":"web"},"safety":`+testSafetyJSON+`}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var preview dryRunResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	require.True(t, strings.HasPrefix(preview.ExperimentID, "dry-"))
+	assert.Equal(t, 1, int(preview.WouldAffect["count"].(float64)))
+
+	stored, ok := store.rows[preview.ExperimentID]
+	require.True(t, ok, "dry run was not persisted")
+	assert.Equal(t, string(domain.StatusCompleted), stored.Status)
+
+	w = postJSON(r, "/experiments/"+preview.ExperimentID+"/promote", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var run domain.ExperimentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+	assert.NotEqual(t, preview.ExperimentID, run.ExperimentID)
+	assert.Equal(t, "kill-web", run.Config.Name)
+	assert.Equal(t, map[string]string{"app": "web"}, run.Config.TargetLabels)
+	assert.False(t, run.Config.Safety.DryRun)
+	assert.Equal(t, "pod_delete", run.InjectionResult["action"])
+}
+
+func TestPromoteDryRunErrors(t *testing.T) {
+	r, store := setupPromoteRouter()
+	store.rows["abc12345"] = db.Experiment{ID: "abc12345", Config: json.RawMessage(`{}`), Status: "completed"}
+
+	w := postJSON(r, "/experiments/abc12345/promote", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postJSON(r, "/experiments/dry-missing/promote", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// A preview that reported safety errors cannot be promoted
+	w = postJSON(r, "/dry-run", `{"name":"kill-web","chaos_type":"pod_delete","target_namespace":"prod-shop",
+		"target_labels":{"app":"web"},"safety":`+testSafetyJSON+`}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var preview dryRunResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	require.NotEmpty(t, preview.Errors)
+
+	w = postJSON(r, "/experiments/"+preview.ExperimentID+"/promote", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "requires confirmation")
+}
+
+func TestPromoteDryRunReportsDatabaseErrors(t *testing.T) {
+	r, store := setupPromoteRouter()
+	store.getErr = errors.New("connection refused")
+
+	w := postJSON(r, "/experiments/dry-0001/promote", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), CodeInternal)
+}
+
+// seedExperiment stores a pod_delete experiment against shop/app=web
+func seedExperiment(t *testing.T, store *experimentStore, id string, status domain.ExperimentStatus) {
+	t.Helper()
+	ns := "shop"
+	cfg, err := json.Marshal(domain.ExperimentConfig{
+		Name: "kill-web", ChaosType: domain.ChaosTypePodDelete, TargetNamespace: &ns,
+		TargetLabels: map[string]string{"app": "web"},
+		Safety:       domain.SafetyConfig{TimeoutSeconds: 30, MaxBlastRadius: 1},
+	})
+	require.NoError(t, err)
+	store.rows[id] = db.Experiment{
+		ID: id, Config: cfg, Status: string(status), Phase: string(domain.PhaseInject),
+		StartedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+}
+
+func TestRerunExperimentRunsStoredConfig(t *testing.T) {
+	r, store := setupPromoteRouter()
+	seedExperiment(t, store, "failed01", domain.StatusFailed)
+
+	w := postJSON(r, "/experiments/failed01/rerun", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result domain.ExperimentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.NotEqual(t, "failed01", result.ExperimentID)
+	require.NotNil(t, result.RerunOf)
+	assert.Equal(t, "failed01", *result.RerunOf)
+	assert.Equal(t, "kill-web", result.Config.Name)
+
+	rerun, ok := store.rows[result.ExperimentID]
+	require.True(t, ok)
+	assert.Equal(t, pgtype.Text{String: "failed01", Valid: true}, rerun.RerunOf)
+	assert.Equal(t, string(domain.StatusFailed), store.rows["failed01"].Status)
+}
+
+func TestRerunExperimentRejectsUnfinished(t *testing.T) {
+	r, store := setupPromoteRouter()
+	seedExperiment(t, store, "running1", domain.StatusRunning)
+	seedExperiment(t, store, "pending1", domain.StatusPending)
+
+	for _, id := range []string{"running1", "pending1"} {
+		w := postJSON(r, "/experiments/"+id+"/rerun", "")
+		assert.Equal(t, http.StatusConflict, w.Code, id)
+		assert.Contains(t, w.Body.String(), CodeExperimentRunning)
+	}
+	assert.Len(t, store.rows, 2)
+
+	w := postJSON(r, "/experiments/missing1/rerun", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSafeModeForcesDryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	esm := safety.NewEmergencyStopManager()
+	rollbackMgr := safety.NewRollbackManager()
+	cs := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop", Labels: map[string]string{"app": "web"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	})
+	runner := engine.NewRunner(engine.NewK8sEngineWithClientset(cs, esm), nil, esm, rollbackMgr, safety.NewSnapshotManager(nil), nil, metrics, "")
+	h := NewChaosHandler(runner, nil, esm, rollbackMgr, metrics)
+	h.EnableSafeMode()
+	r := gin.New()
+	r.POST("/experiments", h.CreateExperiment)
+
+	w := postJSON(r, "/experiments", `{"name":"kill-web","chaos_type":"pod_delete","target_namespace":"shop",
+		"target_labels":{"app":"web"},"safety":{"timeout_seconds":30,"health_check_interval":5,
+		"health_check_failure_threshold":3,"max_blast_radius":1,"dry_run":false}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result domain.ExperimentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.True(t, result.SafeMode)
+	assert.True(t, result.Config.Safety.DryRun)
+	assert.Equal(t, true, result.InjectionResult["dry_run"])
+	_, err := cs.CoreV1().Pods("shop").Get(context.Background(), "web-1", metav1.GetOptions{})
+	assert.NoError(t, err, "safe mode deleted a pod")
+}
+
+// fakeQuerier implements db.Querier in memory. Only the methods a test
+// relies on are overridden; calling any other one panics on the nil embed.
+type fakeQuerier struct {
+	db.Querier
+	experiments map[string]db.Experiment
+	events      []db.ExperimentEvent
+	probes      []db.ProbeResult
+	podLogs     []db.ExperimentPodLog
+}
+
+func (f *fakeQuerier) GetExperiment(_ context.Context, id string) (db.Experiment, error) {
+	rec, ok := f.experiments[id]
+	if !ok {
+		return db.Experiment{}, pgx.ErrNoRows
+	}
+	return rec, nil
+}
+
+func (f *fakeQuerier) ListExperiments(context.Context) ([]db.Experiment, error) {
+	out := make([]db.Experiment, 0, len(f.experiments))
+	for _, rec := range f.experiments {
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+func (f *fakeQuerier) ListExperimentsFiltered(_ context.Context, arg db.ListExperimentsFilteredParams) ([]db.Experiment, error) {
+	out := []db.Experiment{}
+	for _, rec := range f.experiments {
+		var cfg domain.ExperimentConfig
+		_ = json.Unmarshal(rec.Config, &cfg)
+		if arg.Owner.Valid && (cfg.Owner == nil || *cfg.Owner != arg.Owner.String) {
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+func (f *fakeQuerier) ListExperimentEvents(_ context.Context, experimentID string) ([]db.ExperimentEvent, error) {
+	out := []db.ExperimentEvent{}
+	for _, ev := range f.events {
+		if ev.ExperimentID == experimentID {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeQuerier) ListExperimentPodLogs(_ context.Context, experimentID string) ([]db.ExperimentPodLog, error) {
+	out := []db.ExperimentPodLog{}
+	for _, l := range f.podLogs {
+		if l.ExperimentID == experimentID {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeQuerier) UpdateExperimentConfig(_ context.Context, arg db.UpdateExperimentConfigParams) (db.Experiment, error) {
+	rec, ok := f.experiments[arg.ID]
+	if !ok || rec.Status != string(domain.StatusPending) {
+		return db.Experiment{}, pgx.ErrNoRows
+	}
+	rec.Config = arg.Config
+	f.experiments[arg.ID] = rec
+	return rec, nil
+}
+
+func (f *fakeQuerier) ListProbeResultsByExperiment(_ context.Context, experimentID string) ([]db.ProbeResult, error) {
+	out := []db.ProbeResult{}
+	for _, p := range f.probes {
+		if p.ExperimentID == experimentID {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func setupQuerierRouter(q db.Querier) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	h := NewChaosHandler(&stubRunner{}, q, safety.NewEmergencyStopManager(), safety.NewRollbackManager(), metrics)
+	r := gin.New()
+	r.GET("/experiments", h.ListExperiments)
+	r.GET("/experiments/:experiment_id", h.GetExperiment)
+	r.PUT("/experiments/:experiment_id", h.UpdateExperimentConfig)
+	r.GET("/experiments/:experiment_id/events", h.ListExperimentEvents)
+	r.GET("/experiments/:experiment_id/probes", h.ListProbeResults)
+	r.GET("/experiments/:experiment_id/logs", h.ListExperimentPodLogs)
+	r.GET("/experiments/:experiment_id/rollback-status", h.GetRollbackStatus)
+	r.GET("/experiments/:experiment_id/junit", h.ExperimentJUnit)
+	r.GET("/experiments/:experiment_id/report", h.ExperimentReport)
+	return r
+}
+
+func TestGetRollbackStatusReadsReconciledResults(t *testing.T) {
+	q := &fakeQuerier{experiments: map[string]db.Experiment{
+		"orphan01": {ID: "orphan01", Config: json.RawMessage(`{}`), Status: string(domain.StatusFailed),
+			RollbackResult: []byte(`{"reconciled_at":"2026-03-01T12:00:00Z",
+				"rollbacks":[{"description":"pod_delete","status":"success"}],
+				"drift":[{"action":"pod_missing"}]}`)},
+		"norback1": {ID: "norback1", Config: json.RawMessage(`{}`), Status: string(domain.StatusCompleted)},
+	}}
+	r := setupQuerierRouter(q)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/orphan01/rollback-status", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp rollbackStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, 1, resp.Succeeded)
+	assert.Len(t, resp.ResidualDrift, 1)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/norback1/rollback-status", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"rollback_result_not_found"`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/missing1/rollback-status", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"experiment_not_found"`)
+}
+
+func TestGetExperimentMapsStoredRecord(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	q := &fakeQuerier{experiments: map[string]db.Experiment{"abc12345": {
+		ID:             "abc12345",
+		Config:         json.RawMessage(`{"name":"kill-web","chaos_type":"pod_delete","target_labels":{"app":"web"},"safety":{}}`),
+		Status:         string(domain.StatusCompleted),
+		Phase:          string(domain.PhaseRollback),
+		StartedAt:      pgtype.Timestamptz{Time: started, Valid: true},
+		CompletedAt:    pgtype.Timestamptz{Time: started.Add(time.Minute), Valid: true},
+		Hypothesis:     pgtype.Text{String: "web survives", Valid: true},
+		RollbackResult: json.RawMessage(`{"rollback_0":{"status":"success"}}`),
+		PhaseTimings:   json.RawMessage(`{"inject":1.5}`),
+		RerunOf:        pgtype.Text{String: "orig0001", Valid: true},
+	}}}
+	r := setupQuerierRouter(q)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/abc12345", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result domain.ExperimentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+	assert.Equal(t, "kill-web", result.Config.Name)
+	assert.Equal(t, map[string]string{"app": "web"}, result.Config.TargetLabels)
+	assert.Equal(t, domain.StatusCompleted, result.Status)
+	require.NotNil(t, result.StartedAt)
+	assert.True(t, started.Equal(*result.StartedAt))
+	require.NotNil(t, result.CompletedAt)
+	assert.Equal(t, time.Minute, result.CompletedAt.Sub(*result.StartedAt))
+	assert.Equal(t, "web survives", *result.Hypothesis)
+	assert.Equal(t, "success", result.RollbackResult["rollback_0"].(map[string]any)["status"])
+	assert.Equal(t, 1.5, result.PhaseTimings["inject"])
+	assert.Equal(t, "orig0001", *result.RerunOf)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/missing1", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListAndGetExperimentsByOwner(t *testing.T) {
+	record := func(id, config string) db.Experiment {
+		return db.Experiment{
+			ID: id, Config: json.RawMessage(config),
+			Status: string(domain.StatusCompleted), Phase: string(domain.PhaseRollback),
+		}
+	}
+	q := &fakeQuerier{experiments: map[string]db.Experiment{
+		"pay00001": record("pay00001", `{"name":"a","chaos_type":"pod_delete","owner":"payments@example.com","safety":{}}`),
+		"pay00002": record("pay00002", `{"name":"b","chaos_type":"pod_delete","owner":"payments@example.com","safety":{}}`),
+		"web00001": record("web00001", `{"name":"c","chaos_type":"pod_delete","owner":"@web-team","safety":{}}`),
+		"none0001": record("none0001", `{"name":"d","chaos_type":"pod_delete","safety":{}}`),
+	}}
+	r := setupQuerierRouter(q)
+
+	list := func(query string) []string {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var results []domain.ExperimentResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+		ids := []string{}
+		for _, res := range results {
+			ids = append(ids, res.ExperimentID)
+		}
+		return ids
+	}
+	assert.Len(t, list(""), 4)
+	assert.ElementsMatch(t, []string{"pay00001", "pay00002"}, list("?owner=payments@example.com"))
+	assert.Equal(t, []string{"web00001"}, list("?owner=%40web-team"))
+	assert.Empty(t, list("?owner=nobody"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/web00001", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var result domain.ExperimentResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.NotNil(t, result.Config.Owner)
+	assert.Equal(t, "@web-team", *result.Config.Owner)
+}
+
+func TestListExperimentEventsReturnsTimeline(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	q := &fakeQuerier{
+		experiments: map[string]db.Experiment{"abc12345": {ID: "abc12345"}},
+		events: []db.ExperimentEvent{
+			{ID: 1, ExperimentID: "abc12345", EventType: "started", Message: "Experiment started",
+				Data: json.RawMessage(`{}`), OccurredAt: pgtype.Timestamptz{Time: at, Valid: true}},
+			{ID: 2, ExperimentID: "other001", EventType: "started", Message: "Experiment started",
+				Data: json.RawMessage(`{}`), OccurredAt: pgtype.Timestamptz{Time: at, Valid: true}},
+			{ID: 3, ExperimentID: "abc12345", EventType: "finished", Message: "Experiment completed",
+				Data: json.RawMessage(`{"status":"completed"}`), OccurredAt: pgtype.Timestamptz{Time: at.Add(time.Second), Valid: true}},
+		},
+	}
+	r := setupQuerierRouter(q)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/abc12345/events", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var events []db.ExperimentEvent
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+	require.Len(t, events, 2)
+	assert.Equal(t, "started", events[0].EventType)
+	assert.Equal(t, "finished", events[1].EventType)
+	assert.JSONEq(t, `{"status":"completed"}`, string(events[1].Data))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/missing1/events", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"experiment_not_found"`)
+}
+
+func TestListExperimentPodLogs(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	q := &fakeQuerier{
+		experiments: map[string]db.Experiment{"abc12345": {ID: "abc12345"}},
+		podLogs: []db.ExperimentPodLog{
+			{ID: 1, ExperimentID: "abc12345", Stage: "before", Namespace: "shop", Pod: "web-1", Container: "app",
+				Logs: "ready\n", CapturedAt: pgtype.Timestamptz{Time: at, Valid: true}},
+			{ID: 2, ExperimentID: "other001", Stage: "before", Namespace: "shop", Pod: "db-1", Container: "db",
+				Logs: "ok\n", CapturedAt: pgtype.Timestamptz{Time: at, Valid: true}},
+			{ID: 3, ExperimentID: "abc12345", Stage: "after", Namespace: "shop", Pod: "web-1",
+				Error: pgtype.Text{String: "pod no longer exists", Valid: true}, CapturedAt: pgtype.Timestamptz{Time: at.Add(time.Minute), Valid: true}},
+		},
+	}
+	r := setupQuerierRouter(q)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/abc12345/logs", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var logs []db.ExperimentPodLog
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &logs))
+	require.Len(t, logs, 2)
+	assert.Equal(t, "before", logs[0].Stage)
+	assert.Equal(t, "ready\n", logs[0].Logs)
+	assert.Equal(t, "after", logs[1].Stage)
+	assert.Equal(t, "pod no longer exists", logs[1].Error.String)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/missing1/logs", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"experiment_not_found"`)
+}
+
+func putJSON(r *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("PUT", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateExperimentConfigByStatus(t *testing.T) {
+	stored := json.RawMessage(`{"name":"old","chaos_type":"pod_delete","target_namespace":"shop","safety":{}}`)
+	update := `{"name":"new","chaos_type":"pod_delete","target_namespace":"shop","safety":` + testSafetyJSON + `}`
+
+	tests := []struct {
+		status domain.ExperimentStatus
+		want   int
+	}{
+		{domain.StatusPending, http.StatusOK},
+		{domain.StatusRunning, http.StatusConflict},
+		{domain.StatusCompleted, http.StatusConflict},
+		{domain.StatusFailed, http.StatusConflict},
+		{domain.StatusEmergencyStopped, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			q := &fakeQuerier{experiments: map[string]db.Experiment{"abc12345": {
+				ID: "abc12345", Config: stored, Status: string(tt.status), Phase: string(domain.PhaseSteadyState),
+			}}}
+			r := setupQuerierRouter(q)
+
+			w := putJSON(r, "/experiments/abc12345", update)
+			require.Equal(t, tt.want, w.Code, w.Body.String())
+			if tt.want != http.StatusOK {
+				var body struct {
+					Error struct {
+						Code string `json:"code"`
+					} `json:"error"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, CodeExperimentNotEditable, body.Error.Code)
+				assert.JSONEq(t, string(stored), string(q.experiments["abc12345"].Config))
+				return
+			}
+			var result domain.ExperimentResult
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			assert.Equal(t, "new", result.Config.Name)
+			assert.Equal(t, domain.StatusPending, result.Status)
+			var saved domain.ExperimentConfig
+			require.NoError(t, json.Unmarshal(q.experiments["abc12345"].Config, &saved))
+			assert.Equal(t, "new", saved.Name)
+		})
+	}
+}
+
+func TestUpdateExperimentConfigRejectsInvalidConfig(t *testing.T) {
+	q := &fakeQuerier{experiments: map[string]db.Experiment{"abc12345": {
+		ID: "abc12345", Config: json.RawMessage(`{}`), Status: string(domain.StatusPending),
+	}}}
+	r := setupQuerierRouter(q)
+
+	w := putJSON(r, "/experiments/abc12345", `{"name":"new","chaos_type":"network_latency","target_namespace":"shop","parameters":{"latency_ms":-5},"safety":`+testSafetyJSON+`}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var resp validateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Valid)
+	assert.NotEmpty(t, resp.Errors)
+	assert.JSONEq(t, `{}`, string(q.experiments["abc12345"].Config))
+
+	w = putJSON(r, "/experiments/missing1", `{"name":"new","chaos_type":"pod_delete","target_namespace":"shop","safety":`+testSafetyJSON+`}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRollbackExperimentVerifyReportsResidualDrift(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	runner := &stubRunner{drift: []map[string]any{{"action": "pod_missing", "name": "web-1", "status": "detected"}}}
+	h := NewChaosHandler(runner, nil, safety.NewEmergencyStopManager(), safety.NewRollbackManager(), metrics)
+	r := gin.New()
+	r.POST("/experiments/:experiment_id/rollback", h.RollbackExperiment)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/experiments/abc12345/rollback?verify=true", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body struct {
+		ResidualDrift []map[string]any `json:"residual_drift"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.ResidualDrift, 1)
+	assert.Equal(t, "web-1", body.ResidualDrift[0]["name"])
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/experiments/abc12345/rollback", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "residual_drift")
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// runSummary is what CompareExperiments diffs for a single experiment
+type runSummary struct {
+	result domain.ExperimentResult
+	// probes maps probe name to whether every execution of it passed
+	probes          map[string]bool
+	resilienceScore *float64
+}
+
+// metricDelta is a numeric value captured before and after chaos
+type metricDelta struct {
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Delta  float64 `json:"delta"`
+}
+
+// CompareExperiments diffs two experiment runs: steady-state degradation,
+// probe outcomes, duration and resilience score
+func (h *ChaosHandler) CompareExperiments(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+	idA, idB := c.Query("a"), c.Query("b")
+	if idA == "" || idB == "" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest, "Both 'a' and 'b' experiment IDs are required")
+		return
+	}
+
+	summaries := make([]runSummary, 0, 2)
+	for _, id := range []string{idA, idB} {
+		rec, err := h.queries.GetExperiment(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, http.StatusNotFound, CodeExperimentNotFound, fmt.Sprintf("Experiment %s not found", id))
+			return
+		}
+		summary, err := h.summarizeRun(c.Request.Context(), rec)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+		summaries = append(summaries, summary)
+	}
+
+	c.JSON(http.StatusOK, compareRuns(summaries[0], summaries[1]))
+}
+
+// summarizeRun loads the probe results and resilience score of an experiment
+func (h *ChaosHandler) summarizeRun(ctx context.Context, rec db.Experiment) (runSummary, error) {
+	summary := runSummary{result: recordToResult(rec), probes: map[string]bool{}}
+
+	probeRows, err := h.queries.ListProbeResultsByExperiment(ctx, rec.ID)
+	if err != nil {
+		return summary, fmt.Errorf("list probe results for %s: %w", rec.ID, err)
+	}
+	for _, pr := range probeRows {
+		passed, seen := summary.probes[pr.ProbeName]
+		summary.probes[pr.ProbeName] = pr.Passed && (passed || !seen)
+	}
+
+	// Prefer the score recorded with the run, then the latest analysis
+	if score, ok := summary.result.AIInsights["resilience_score"].(float64); ok {
+		summary.resilienceScore = &score
+	} else if analyses, err := h.queries.GetAnalysisResultsByExperiment(ctx, rec.ID); err == nil {
+		for _, a := range analyses {
+			if a.ResilienceScore.Valid {
+				score := a.ResilienceScore.Float64
+				summary.resilienceScore = &score
+				break
+			}
+		}
+	}
+	return summary, nil
+}
+
+// compareRuns builds the structured diff between run a and run b. Deltas
+// are b minus a, so a positive resilience_score delta means b did better.
+func compareRuns(a, b runSummary) map[string]any {
+	warnings := []string{}
+	if a.result.Config.ChaosType != b.result.Config.ChaosType {
+		warnings = append(warnings, fmt.Sprintf("experiments use different chaos types (%s vs %s); the comparison may not be meaningful",
+			a.result.Config.ChaosType, b.result.Config.ChaosType))
+	}
+
+	diff := map[string]any{
+		"a":              runOverview(a),
+		"b":              runOverview(b),
+		"status_changed": a.result.Status != b.result.Status,
+		"probes":         diffProbes(a.probes, b.probes),
+		"degradation":    diffDegradation(a.result, b.result),
+	}
+
+	durA, okA := runDuration(a.result)
+	durB, okB := runDuration(b.result)
+	if okA && okB {
+		diff["duration_seconds"] = map[string]float64{"a": durA, "b": durB, "delta": durB - durA}
+	} else {
+		warnings = append(warnings, "duration unavailable for an experiment that has not completed")
+	}
+
+	if a.resilienceScore != nil && b.resilienceScore != nil {
+		diff["resilience_score"] = map[string]float64{
+			"a": *a.resilienceScore, "b": *b.resilienceScore, "delta": *b.resilienceScore - *a.resilienceScore,
+		}
+	}
+
+	diff["warnings"] = warnings
+	return diff
+}
+
+func runOverview(s runSummary) map[string]any {
+	overview := map[string]any{
+		"experiment_id": s.result.ExperimentID,
+		"name":          s.result.Config.Name,
+		"chaos_type":    s.result.Config.ChaosType,
+		"status":        s.result.Status,
+	}
+	if d, ok := runDuration(s.result); ok {
+		overview["duration_seconds"] = d
+	}
+	if s.resilienceScore != nil {
+		overview["resilience_score"] = *s.resilienceScore
+	}
+	return overview
+}
+
+func runDuration(r domain.ExperimentResult) (float64, bool) {
+	if r.StartedAt == nil || r.CompletedAt == nil {
+		return 0, false
+	}
+	return r.CompletedAt.Sub(*r.StartedAt).Seconds(), true
+}
+
+// diffProbes classifies probes by how their outcome changed from a to b
+func diffProbes(a, b map[string]bool) map[string][]string {
+	diff := map[string][]string{
+		"fixed":         {},
+		"regressed":     {},
+		"still_passing": {},
+		"still_failing": {},
+		"only_in_a":     {},
+		"only_in_b":     {},
+	}
+	for name, passedA := range a {
+		passedB, ok := b[name]
+		switch {
+		case !ok:
+			diff["only_in_a"] = append(diff["only_in_a"], name)
+		case !passedA && passedB:
+			diff["fixed"] = append(diff["fixed"], name)
+		case passedA && !passedB:
+			diff["regressed"] = append(diff["regressed"], name)
+		case passedA:
+			diff["still_passing"] = append(diff["still_passing"], name)
+		default:
+			diff["still_failing"] = append(diff["still_failing"], name)
+		}
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			diff["only_in_b"] = append(diff["only_in_b"], name)
+		}
+	}
+	for _, names := range diff {
+		slices.Sort(names)
+	}
+	return diff
+}
+
+// diffDegradation compares, per numeric metric, how far each run moved
+// from its steady state. change is b's delta minus a's delta.
+func diffDegradation(a, b domain.ExperimentResult) map[string]any {
+	degA := numericDegradation(a.SteadyState, a.Observations)
+	degB := numericDegradation(b.SteadyState, b.Observations)
+
+	keys := make([]string, 0, len(degA)+len(degB))
+	for k := range degA {
+		keys = append(keys, k)
+	}
+	for k := range degB {
+		if _, ok := degA[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	diff := make(map[string]any, len(keys))
+	for _, k := range keys {
+		entry := map[string]any{}
+		deltaA, okA := degA[k]
+		deltaB, okB := degB[k]
+		if okA {
+			entry["a"] = deltaA
+		}
+		if okB {
+			entry["b"] = deltaB
+		}
+		if okA && okB {
+			entry["change"] = deltaB.Delta - deltaA.Delta
+		}
+		diff[k] = entry
+	}
+	return diff
+}
+
+// numericDegradation returns, for every numeric key present in both maps,
+// the value before and after chaos
+func numericDegradation(before, after map[string]any) map[string]metricDelta {
+	deltas := map[string]metricDelta{}
+	for k, bv := range before {
+		b, ok := toFloat(bv)
+		if !ok {
+			continue
+		}
+		a, ok := toFloat(after[k])
+		if !ok {
+			continue
+		}
+		deltas[k] = metricDelta{Before: b, After: a, Delta: a - b}
+	}
+	return deltas
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
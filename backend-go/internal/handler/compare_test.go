@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/observability"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareExperiments_NoDB(t *testing.T) {
+	r, h := setupTestRouter()
+	r.GET("/experiments/compare", h.CompareExperiments)
+
+	req := httptest.NewRequest("GET", "/experiments/compare?a=one&b=two", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"database_unavailable"`)
+}
+
+func TestCompareExperimentsErrorCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	q := &fakeQuerier{experiments: map[string]db.Experiment{"abc12345": {ID: "abc12345"}}}
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	h := NewChaosHandler(&stubRunner{}, q, safety.NewEmergencyStopManager(), safety.NewRollbackManager(), metrics)
+	r := gin.New()
+	r.GET("/experiments/compare", h.CompareExperiments)
+
+	for query, want := range map[string]struct {
+		status int
+		code   string
+	}{
+		"?a=abc12345":            {http.StatusBadRequest, CodeInvalidRequest},
+		"?a=missing1&b=abc12345": {http.StatusNotFound, CodeExperimentNotFound},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/compare"+query, nil))
+		assert.Equal(t, want.status, w.Code, query)
+		assert.Contains(t, w.Body.String(), `"code":"`+want.code+`"`, query)
+	}
+}
+
+func compareRun(id string, chaosType domain.ChaosType, ratio float64, dur time.Duration, probes map[string]bool, score *float64) runSummary {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(dur)
+	return runSummary{
+		result: domain.ExperimentResult{
+			ExperimentID: id,
+			Config:       domain.ExperimentConfig{Name: id, ChaosType: chaosType},
+			Status:       domain.StatusCompleted,
+			StartedAt:    &start,
+			CompletedAt:  &end,
+			SteadyState:  map[string]any{"pods_healthy_ratio": 1.0, "namespace": "default"},
+			Observations: map[string]any{"pods_healthy_ratio": ratio, "namespace": "default"},
+		},
+		probes:          probes,
+		resilienceScore: score,
+	}
+}
+
+func TestCompareRuns(t *testing.T) {
+	scoreA, scoreB := 60.0, 85.0
+	a := compareRun("a", domain.ChaosTypePodDelete, 0.5, 40*time.Second,
+		map[string]bool{"latency": false, "errors": true, "legacy": true}, &scoreA)
+	b := compareRun("b", domain.ChaosTypePodDelete, 0.9, 30*time.Second,
+		map[string]bool{"latency": true, "errors": false, "new": true}, &scoreB)
+
+	diff := compareRuns(a, b)
+
+	assert.Empty(t, diff["warnings"])
+	assert.Equal(t, false, diff["status_changed"])
+	assert.Equal(t, map[string]float64{"a": 40, "b": 30, "delta": -10}, diff["duration_seconds"])
+	assert.Equal(t, map[string]float64{"a": 60, "b": 85, "delta": 25}, diff["resilience_score"])
+
+	probes := diff["probes"].(map[string][]string)
+	assert.Equal(t, []string{"latency"}, probes["fixed"])
+	assert.Equal(t, []string{"errors"}, probes["regressed"])
+	assert.Equal(t, []string{"legacy"}, probes["only_in_a"])
+	assert.Equal(t, []string{"new"}, probes["only_in_b"])
+
+	degradation := diff["degradation"].(map[string]any)
+	require.Contains(t, degradation, "pods_healthy_ratio")
+	assert.NotContains(t, degradation, "namespace")
+	ratio := degradation["pods_healthy_ratio"].(map[string]any)
+	assert.InDelta(t, 0.4, ratio["change"], 1e-9)
+	assert.Equal(t, metricDelta{Before: 1, After: 0.5, Delta: -0.5}, ratio["a"])
+}
+
+func TestCompareRunsWarnsOnDifferentChaosTypes(t *testing.T) {
+	a := compareRun("a", domain.ChaosTypePodDelete, 1, time.Second, map[string]bool{}, nil)
+	b := compareRun("b", domain.ChaosTypeNetworkLatency, 1, time.Second, map[string]bool{}, nil)
+	b.result.CompletedAt = nil
+
+	diff := compareRuns(a, b)
+
+	warnings := diff["warnings"].([]string)
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "different chaos types")
+	assert.NotContains(t, diff, "duration_seconds")
+	assert.NotContains(t, diff, "resilience_score")
+}
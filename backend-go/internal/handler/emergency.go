@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+)
+
+// resetRequest must carry confirm=true so a stray POST can't clear the stop mid-incident
+type resetRequest struct {
+	Confirm bool `json:"confirm"`
+}
+
+// EmergencyStopStatus reports whether the emergency stop is active
+func EmergencyStopStatus(esm *safety.EmergencyStopManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"emergency_stop": esm.IsTriggered()})
+	}
+}
+
+// TriggerEmergencyStop activates the emergency stop
+func TriggerEmergencyStop(esm *safety.EmergencyStopManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		esm.Trigger()
+		c.JSON(http.StatusOK, gin.H{"status": "emergency_stop_triggered", "emergency_stop": true})
+	}
+}
+
+// ResetEmergencyStop clears the emergency stop once the caller confirms
+func ResetEmergencyStop(esm *safety.EmergencyStopManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req resetRequest
+		if err := c.ShouldBindJSON(&req); err != nil || !req.Confirm {
+			c.JSON(http.StatusBadRequest, gin.H{"detail": `Reset requires {"confirm": true}`})
+			return
+		}
+		esm.Reset()
+		c.JSON(http.StatusOK, gin.H{"status": "emergency_stop_reset", "emergency_stop": esm.IsTriggered()})
+	}
+}
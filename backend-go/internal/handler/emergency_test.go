@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupEmergencyRouter() (*gin.Engine, *safety.EmergencyStopManager) {
+	gin.SetMode(gin.TestMode)
+	esm := safety.NewEmergencyStopManager()
+	r := gin.New()
+	r.GET("/emergency-stop", EmergencyStopStatus(esm))
+	r.POST("/emergency-stop", TriggerEmergencyStop(esm))
+	r.POST("/emergency-stop/reset", ResetEmergencyStop(esm))
+	return r, esm
+}
+
+func doEmergencyRequest(t *testing.T, r *gin.Engine, method, path, body string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w.Code, resp
+}
+
+func TestEmergencyStopTriggerStatusReset(t *testing.T) {
+	r, esm := setupEmergencyRouter()
+
+	code, resp := doEmergencyRequest(t, r, "GET", "/emergency-stop", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, false, resp["emergency_stop"])
+
+	code, _ = doEmergencyRequest(t, r, "POST", "/emergency-stop", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, esm.IsTriggered())
+
+	_, resp = doEmergencyRequest(t, r, "GET", "/emergency-stop", "")
+	assert.Equal(t, true, resp["emergency_stop"])
+
+	code, resp = doEmergencyRequest(t, r, "POST", "/emergency-stop/reset", `{"confirm":true}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, false, resp["emergency_stop"])
+	assert.False(t, esm.IsTriggered())
+}
+
+func TestEmergencyStopResetRequiresConfirm(t *testing.T) {
+	r, esm := setupEmergencyRouter()
+	esm.Trigger()
+
+	for _, body := range []string{"", `{}`, `{"confirm":false}`} {
+		code, _ := doEmergencyRequest(t, r, "POST", "/emergency-stop/reset", body)
+		assert.Equal(t, http.StatusBadRequest, code, body)
+	}
+	assert.True(t, esm.IsTriggered())
+}
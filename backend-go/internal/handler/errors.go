@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// Stable machine-readable error codes returned in error responses
+const (
+	CodeInvalidRequest         = "invalid_request"
+	CodeRequestTooLarge        = "request_too_large"
+	CodeUnauthorized           = "unauthorized"
+	CodeInvalidConfig          = "invalid_config"
+	CodeInvalidTargetResource  = "invalid_target_resource"
+	CodeInvalidTargetWorkload  = "invalid_target_workload"
+	CodeUnknownChaosType       = "unknown_chaos_type"
+	CodeChaosTypeNotAllowed    = "chaos_type_not_allowed"
+	CodeBlastRadiusExceeded    = "blast_radius_exceeded"
+	CodeConfirmationRequired   = "namespace_confirmation_required"
+	CodeNamespaceFrozen        = "namespace_frozen"
+	CodeFreezeNotFound         = "freeze_not_found"
+	CodeSnapshotNotFound       = "snapshot_not_found"
+	CodeTemplateNotFound       = "template_not_found"
+	CodeRollbackResultNotFound = "rollback_result_not_found"
+	CodeInsufficientPrivileges = "insufficient_privileges"
+	CodeStressToolMissing      = "stress_tool_missing"
+	CodeEphemeralUnsupported   = "ephemeral_containers_unsupported"
+	CodeEmergencyStop          = "emergency_stop_active"
+	CodeExperimentAborted      = "experiment_aborted"
+	CodeExperimentNotFound     = "experiment_not_found"
+	CodeExperimentRunning      = "experiment_running"
+	CodeExperimentNotEditable  = "experiment_not_editable"
+	CodeTimeout                = "timeout"
+	CodeQueueFull              = "queue_full"
+	CodeQueueClosed            = "queue_closed"
+	CodeShuttingDown           = "shutting_down"
+	CodeAIServiceUnavailable   = "ai_service_unavailable"
+	CodeDatabaseUnavailable    = "database_unavailable"
+	CodeInternal               = "internal_error"
+)
+
+// errorMapping ties a domain sentinel error to its response status and code
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// domainErrors maps the domain sentinels, checked in order with errors.Is.
+// Requests the server refused for safety reasons are 422: the config is
+// well-formed but not allowed to run as given. Chaos types the deployment
+// forbids outright are 403. A failing AI service is an upstream failure
+// (502), not ours.
+var domainErrors = []errorMapping{
+	{domain.ErrInvalidConfig, http.StatusBadRequest, CodeInvalidConfig},
+	{domain.ErrInvalidTargetResource, http.StatusBadRequest, CodeInvalidTargetResource},
+	{domain.ErrInvalidTargetWorkload, http.StatusBadRequest, CodeInvalidTargetWorkload},
+	{domain.ErrUnknownChaosType, http.StatusBadRequest, CodeUnknownChaosType},
+	{domain.ErrChaosTypeNotAllowed, http.StatusForbidden, CodeChaosTypeNotAllowed},
+	{domain.ErrBlastRadiusExceeded, http.StatusUnprocessableEntity, CodeBlastRadiusExceeded},
+	{domain.ErrNamespaceConfirmation, http.StatusUnprocessableEntity, CodeConfirmationRequired},
+	{domain.ErrNamespaceFrozen, http.StatusUnprocessableEntity, CodeNamespaceFrozen},
+	{domain.ErrClockSkewNotPermitted, http.StatusUnprocessableEntity, CodeInsufficientPrivileges},
+	{domain.ErrStressToolMissing, http.StatusUnprocessableEntity, CodeStressToolMissing},
+	{domain.ErrEphemeralContainersUnsupported, http.StatusUnprocessableEntity, CodeEphemeralUnsupported},
+	{domain.ErrEmergencyStop, http.StatusServiceUnavailable, CodeEmergencyStop},
+	{domain.ErrExperimentAborted, http.StatusConflict, CodeExperimentAborted},
+	{domain.ErrExperimentNotFound, http.StatusNotFound, CodeExperimentNotFound},
+	{domain.ErrTimeout, http.StatusGatewayTimeout, CodeTimeout},
+	{domain.ErrQueueFull, http.StatusTooManyRequests, CodeQueueFull},
+	{domain.ErrQueueClosed, http.StatusServiceUnavailable, CodeQueueClosed},
+	{domain.ErrServerShuttingDown, http.StatusServiceUnavailable, CodeShuttingDown},
+	{domain.ErrAIServiceUnavailable, http.StatusBadGateway, CodeAIServiceUnavailable},
+}
+
+// respondError writes {"error": {"code", "message"}}. The message is also
+// kept under "detail", which existing clients read.
+func respondError(c *gin.Context, status int, code, message string) {
+	c.JSON(status, gin.H{
+		"error":  gin.H{"code": code, "message": message},
+		"detail": message,
+	})
+}
+
+// respondDomainError responds with the status and code mapped from err,
+// falling back to 500 for errors that match no domain sentinel
+func respondDomainError(c *gin.Context, err error) {
+	status, code := errorStatus(err)
+	respondError(c, status, code, err.Error())
+}
+
+// errorStatus returns the HTTP status and error code for err
+func errorStatus(err error) (int, string) {
+	for _, m := range domainErrors {
+		if errors.Is(err, m.err) {
+			return m.status, m.code
+		}
+	}
+	return http.StatusInternalServerError, CodeInternal
+}
+
+// respondBindError reports a request body that could not be decoded: 413
+// when it hit the body size limit, 400 otherwise
+func respondBindError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondError(c, http.StatusRequestEntityTooLarge, CodeRequestTooLarge,
+			fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+		return
+	}
+	respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/engine"
+	"github.com/chaosduck/backend-go/internal/observability"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestErrorStatusMapsDomainSentinels(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{domain.ErrInvalidConfig, http.StatusBadRequest, CodeInvalidConfig},
+		{domain.ErrInvalidTargetResource, http.StatusBadRequest, CodeInvalidTargetResource},
+		{domain.ErrUnknownChaosType, http.StatusBadRequest, CodeUnknownChaosType},
+		{domain.ErrChaosTypeNotAllowed, http.StatusForbidden, CodeChaosTypeNotAllowed},
+		{domain.ErrBlastRadiusExceeded, http.StatusUnprocessableEntity, CodeBlastRadiusExceeded},
+		{domain.ErrNamespaceConfirmation, http.StatusUnprocessableEntity, CodeConfirmationRequired},
+		{domain.ErrNamespaceFrozen, http.StatusUnprocessableEntity, CodeNamespaceFrozen},
+		{domain.ErrClockSkewNotPermitted, http.StatusUnprocessableEntity, CodeInsufficientPrivileges},
+		{domain.ErrStressToolMissing, http.StatusUnprocessableEntity, CodeStressToolMissing},
+		{domain.ErrEphemeralContainersUnsupported, http.StatusUnprocessableEntity, CodeEphemeralUnsupported},
+		{domain.ErrEmergencyStop, http.StatusServiceUnavailable, CodeEmergencyStop},
+		{domain.ErrExperimentAborted, http.StatusConflict, CodeExperimentAborted},
+		{domain.ErrExperimentNotFound, http.StatusNotFound, CodeExperimentNotFound},
+		{domain.ErrTimeout, http.StatusGatewayTimeout, CodeTimeout},
+		{domain.ErrQueueFull, http.StatusTooManyRequests, CodeQueueFull},
+		{domain.ErrQueueClosed, http.StatusServiceUnavailable, CodeQueueClosed},
+		{domain.ErrServerShuttingDown, http.StatusServiceUnavailable, CodeShuttingDown},
+		{domain.ErrAIServiceUnavailable, http.StatusBadGateway, CodeAIServiceUnavailable},
+		{errors.New("boom"), http.StatusInternalServerError, CodeInternal},
+	}
+	for _, tt := range tests {
+		status, code := errorStatus(tt.err)
+		assert.Equal(t, tt.status, status, tt.err.Error())
+		assert.Equal(t, tt.code, code, tt.err.Error())
+
+		// Wrapped and joined errors map the same way
+		status, code = errorStatus(errors.Join(errors.New("ns-a"), fmt.Errorf("ns-b: %w", tt.err)))
+		assert.Equal(t, tt.status, status, tt.err.Error())
+		assert.Equal(t, tt.code, code, tt.err.Error())
+	}
+}
+
+func TestRespondErrorShape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	respondDomainError(c, fmt.Errorf("pod delete: %w", domain.ErrBlastRadiusExceeded))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+		Detail string `json:"detail"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, CodeBlastRadiusExceeded, body.Error.Code)
+	assert.Equal(t, "pod delete: blast radius exceeded", body.Error.Message)
+	assert.Equal(t, body.Error.Message, body.Detail)
+}
+
+func TestCreateExperimentSafetyRefusalsAre422(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": "web"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	esm := safety.NewEmergencyStopManager()
+	rollbackMgr := safety.NewRollbackManager()
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	k8s := engine.NewK8sEngineWithClientset(fake.NewSimpleClientset(pod("web-1"), pod("web-2")), esm)
+	runner := engine.NewRunner(k8s, nil, esm, rollbackMgr, safety.NewSnapshotManager(nil), nil, metrics, "")
+	h := NewChaosHandler(runner, nil, esm, rollbackMgr, metrics)
+	r := gin.New()
+	r.POST("/experiments", h.CreateExperiment)
+
+	tests := []struct {
+		name, body, code string
+	}{
+		{"blast radius", `{"name":"x","chaos_type":"pod_delete","target_namespace":"shop","target_labels":{"app":"web"},
+			"safety":{"timeout_seconds":30,"health_check_interval":5,"health_check_failure_threshold":3,"max_blast_radius":0.3}}`,
+			CodeBlastRadiusExceeded},
+		{"confirmation", `{"name":"x","chaos_type":"pod_delete","target_namespace":"prod-shop",
+			"safety":{"timeout_seconds":30,"health_check_interval":5,"health_check_failure_threshold":3,"max_blast_radius":0.3}}`,
+			CodeConfirmationRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("POST", "/experiments", strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+			var body struct {
+				Error struct{ Code string } `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.code, body.Error.Code)
+		})
+	}
+}
+
+// stubRunner fails every run with err and reports drift when asked to
+// verify a rollback
+type stubRunner struct {
+	err   error
+	drift []map[string]any
+}
+
+func (s *stubRunner) Run(context.Context, string, domain.ExperimentConfig) (*domain.ExperimentResult, error) {
+	return nil, s.err
+}
+
+func (s *stubRunner) DryRun(context.Context, string, domain.ExperimentConfig) (*domain.ExperimentResult, []error) {
+	return nil, []error{s.err}
+}
+
+func (s *stubRunner) SetPersistHook(func(string)) {}
+
+func (s *stubRunner) VerifyRollback(context.Context, string) ([]map[string]any, error) {
+	return s.drift, nil
+}
+
+func (s *stubRunner) AbortAll(context.Context) ([]string, map[string][]safety.RollbackResult) {
+	return nil, nil
+}
+
+func TestCreateExperimentMapsRunnerErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := `{"name":"x","chaos_type":"pod_delete","target_namespace":"shop",
+		"safety":{"timeout_seconds":30,"health_check_interval":5,"health_check_failure_threshold":3,"max_blast_radius":0.3}}`
+
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{fmt.Errorf("pod delete: %w", domain.ErrBlastRadiusExceeded), http.StatusUnprocessableEntity},
+		{domain.ErrNamespaceConfirmation, http.StatusUnprocessableEntity},
+		{fmt.Errorf("%w: %s", domain.ErrUnknownChaosType, "fog"), http.StatusBadRequest},
+		{fmt.Errorf("%w: latency_ms out of range", domain.ErrInvalidConfig), http.StatusBadRequest},
+		{domain.ErrEmergencyStop, http.StatusServiceUnavailable},
+		{fmt.Errorf("hypothesis: %w", domain.ErrAIServiceUnavailable), http.StatusBadGateway},
+		{errors.New("k8s engine not available"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+		h := NewChaosHandler(&stubRunner{err: tt.err}, nil, safety.NewEmergencyStopManager(), safety.NewRollbackManager(), metrics)
+		r := gin.New()
+		r.POST("/experiments", h.CreateExperiment)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/experiments", strings.NewReader(body)))
+		assert.Equal(t, tt.status, w.Code, tt.err.Error())
+	}
+}
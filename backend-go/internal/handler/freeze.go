@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+)
+
+// freezeRequest names the namespace, or glob pattern, to freeze
+type freezeRequest struct {
+	Namespace string `json:"namespace" binding:"required"`
+	Reason    string `json:"reason"`
+}
+
+// ListNamespaceFreezes lists the namespace patterns frozen from chaos
+func ListNamespaceFreezes(fm *safety.NamespaceFreezeManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"freezes": fm.List()})
+	}
+}
+
+// FreezeNamespace freezes a namespace or glob pattern from chaos
+func FreezeNamespace(fm *safety.NamespaceFreezeManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req freezeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+		freeze, err := fm.Freeze(c.Request.Context(), req.Namespace, req.Reason)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidConfig) {
+				respondError(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+				return
+			}
+			respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, freeze)
+	}
+}
+
+// UnfreezeNamespace lifts the freeze on a namespace pattern. The pattern
+// must match the frozen one exactly.
+func UnfreezeNamespace(fm *safety.NamespaceFreezeManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pattern := c.Param("namespace")
+		ok, err := fm.Unfreeze(c.Request.Context(), pattern)
+		if err != nil {
+			respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, err.Error())
+			return
+		}
+		if !ok {
+			respondError(c, http.StatusNotFound, CodeFreezeNotFound, fmt.Sprintf("namespace %q is not frozen", pattern))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "unfrozen", "namespace": pattern})
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupFreezeRouter() (*gin.Engine, *safety.NamespaceFreezeManager) {
+	gin.SetMode(gin.TestMode)
+	fm := safety.NewNamespaceFreezeManager(nil)
+	r := gin.New()
+	r.GET("/api/safety/freeze", ListNamespaceFreezes(fm))
+	r.POST("/api/safety/freeze", FreezeNamespace(fm))
+	r.DELETE("/api/safety/freeze/:namespace", UnfreezeNamespace(fm))
+	return r, fm
+}
+
+func TestFreezeAndUnfreezeNamespace(t *testing.T) {
+	r, fm := setupFreezeRouter()
+
+	code, resp := doEmergencyRequest(t, r, "POST", "/api/safety/freeze", `{"namespace":"team-a-*","reason":"incident"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "team-a-*", resp["pattern"])
+	assert.True(t, fm.IsFrozen("team-a-api"))
+
+	_, resp = doEmergencyRequest(t, r, "GET", "/api/safety/freeze", "")
+	assert.Len(t, resp["freezes"], 1)
+
+	code, _ = doEmergencyRequest(t, r, "DELETE", "/api/safety/freeze/team-a-%2A", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, fm.IsFrozen("team-a-api"))
+
+	code, resp = doEmergencyRequest(t, r, "DELETE", "/api/safety/freeze/team-a-%2A", "")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, CodeFreezeNotFound, resp["error"].(map[string]any)["code"])
+}
+
+func TestFreezeNamespaceRejectsBadPattern(t *testing.T) {
+	r, fm := setupFreezeRouter()
+
+	for _, body := range []string{`{}`, `{"namespace":"team-[a"}`} {
+		code, resp := doEmergencyRequest(t, r, "POST", "/api/safety/freeze", body)
+		assert.Equal(t, http.StatusBadRequest, code, body)
+		assert.Equal(t, CodeInvalidRequest, resp["error"].(map[string]any)["code"])
+	}
+	assert.Empty(t, fm.List())
+}
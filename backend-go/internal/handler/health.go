@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/engine"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// readinessTimeout bounds each dependency check
+const readinessTimeout = 2 * time.Second
+
+// errNotConfigured marks a dependency the server was started without. It is
+// reported but doesn't fail readiness, since the server runs degraded
+// without it by design.
+var errNotConfigured = errors.New("not configured")
+
+// dependencyCheck probes a single dependency
+type dependencyCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// HealthHandler serves the readiness probe
+type HealthHandler struct {
+	checks  []dependencyCheck
+	timeout time.Duration
+}
+
+// NewHealthHandler creates a HealthHandler checking the database, both
+// engines and the AI service
+func NewHealthHandler(pool *pgxpool.Pool, k8s *engine.K8sEngine, aws *engine.AwsEngine, aiServiceURL string) *HealthHandler {
+	httpClient := &http.Client{}
+	return &HealthHandler{
+		timeout: readinessTimeout,
+		checks: []dependencyCheck{
+			{name: "database", check: func(ctx context.Context) error {
+				if pool == nil {
+					return errors.New("connection pool not available")
+				}
+				return pool.Ping(ctx)
+			}},
+			{name: "k8s", check: func(ctx context.Context) error {
+				if k8s == nil {
+					return errNotConfigured
+				}
+				_, err := k8s.ServerVersion()
+				return err
+			}},
+			{name: "aws", check: func(ctx context.Context) error {
+				if aws == nil {
+					return errNotConfigured
+				}
+				return aws.Ping(ctx)
+			}},
+			{name: "ai_service", check: func(ctx context.Context) error {
+				if aiServiceURL == "" {
+					return errNotConfigured
+				}
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(aiServiceURL, "/")+"/health", nil)
+				if err != nil {
+					return err
+				}
+				resp, err := httpClient.Do(req)
+				if err != nil {
+					return err
+				}
+				resp.Body.Close()
+				if resp.StatusCode >= 400 {
+					return fmt.Errorf("status %d", resp.StatusCode)
+				}
+				return nil
+			}},
+		},
+	}
+}
+
+// Ready runs every dependency check concurrently, each bounded by the
+// handler's timeout, and returns 503 if any configured dependency is down
+func (h *HealthHandler) Ready(c *gin.Context) {
+	results := make(map[string]any, len(h.checks))
+	ready := true
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, dc := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := h.runCheck(c.Request.Context(), dc)
+
+			entry := map[string]any{"status": "ok", "latency_ms": time.Since(start).Milliseconds()}
+			switch {
+			case errors.Is(err, errNotConfigured):
+				entry = map[string]any{"status": "not_configured"}
+			case err != nil:
+				entry["status"] = "error"
+				entry["error"] = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[dc.name] = entry
+			if err != nil && !errors.Is(err, errNotConfigured) {
+				ready = false
+			}
+		}()
+	}
+	wg.Wait()
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not_ready", http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{"status": status, "checks": results})
+}
+
+// runCheck runs dc with a deadline. Checks that ignore their context (the
+// K8s discovery client has none) are abandoned when the deadline passes.
+func (h *HealthHandler) runCheck(ctx context.Context, dc dependencyCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- dc.check(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %s", h.timeout)
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/engine"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func serveReady(t *testing.T, h *HealthHandler) (int, map[string]any) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/health/ready", h.Ready)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w.Code, body
+}
+
+func TestReadyReportsEachDependency(t *testing.T) {
+	ai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ai.Close()
+
+	k8s := engine.NewK8sEngineWithClientset(fake.NewSimpleClientset(), safety.NewEmergencyStopManager())
+	code, body := serveReady(t, NewHealthHandler(nil, k8s, nil, ai.URL))
+
+	// No database pool means the server can't do its job
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not_ready", body["status"])
+
+	checks := body["checks"].(map[string]any)
+	assert.Equal(t, "error", checks["database"].(map[string]any)["status"])
+	assert.Equal(t, "ok", checks["k8s"].(map[string]any)["status"])
+	assert.Equal(t, "not_configured", checks["aws"].(map[string]any)["status"])
+	assert.Equal(t, "ok", checks["ai_service"].(map[string]any)["status"])
+}
+
+func TestReadyIgnoresUnconfiguredDependencies(t *testing.T) {
+	h := &HealthHandler{
+		timeout: time.Second,
+		checks: []dependencyCheck{
+			{name: "database", check: func(context.Context) error { return nil }},
+			{name: "aws", check: func(context.Context) error { return errNotConfigured }},
+		},
+	}
+	code, body := serveReady(t, h)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", body["status"])
+}
+
+func TestReadyTimesOutHungDependency(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	h := &HealthHandler{
+		timeout: 50 * time.Millisecond,
+		checks: []dependencyCheck{
+			// Ignores its context, like the K8s discovery client
+			{name: "k8s", check: func(context.Context) error { <-release; return nil }},
+		},
+	}
+
+	start := time.Now()
+	code, body := serveReady(t, h)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	k8s := body["checks"].(map[string]any)["k8s"].(map[string]any)
+	assert.Equal(t, "error", k8s["status"])
+	assert.Contains(t, k8s["error"], "timed out")
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// JUnit mapping: the experiment is a <testsuite>, every recorded probe
+// execution is a <testcase>, and the experiment's overall outcome is one
+// more <testcase> named "hypothesis". A failed SOT or EOT probe and a
+// failed experiment become <failure>; an emergency stop, which ends the run
+// without a verdict, becomes <error>. Continuous and on-chaos polls are
+// expected to fail now and then, so a failed poll is only noted in
+// <system-out>; when enough of them fail the experiment itself fails.
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	ID        string          `xml:"id,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr,omitempty"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// ExperimentJUnit renders a finished experiment as a JUnit XML report so CI
+// systems can ingest chaos outcomes as test results
+func (h *ChaosHandler) ExperimentJUnit(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+	experimentID := c.Param("experiment_id")
+
+	rec, err := h.queries.GetExperiment(c.Request.Context(), experimentID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, CodeExperimentNotFound, fmt.Sprintf("Experiment %s not found", experimentID))
+		return
+	}
+	result := recordToResult(rec)
+	if !terminalStatuses[result.Status] {
+		respondError(c, http.StatusConflict, CodeExperimentRunning,
+			fmt.Sprintf("experiment %s is still %s", experimentID, result.Status))
+		return
+	}
+
+	probes, err := h.queries.ListProbeResultsByExperiment(c.Request.Context(), experimentID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+
+	out, err := xml.MarshalIndent(buildJUnitSuite(result, probes), "", "  ")
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), out...))
+}
+
+// buildJUnitSuite maps an experiment and its probe results to a testsuite
+func buildJUnitSuite(result domain.ExperimentResult, probes []db.ProbeResult) junitTestSuite {
+	suite := junitTestSuite{Name: result.Config.Name, ID: result.ExperimentID}
+	if suite.Name == "" {
+		suite.Name = result.ExperimentID
+	}
+	if result.StartedAt != nil {
+		suite.Timestamp = result.StartedAt.UTC().Format("2006-01-02T15:04:05")
+		if result.CompletedAt != nil {
+			suite.Time = fmt.Sprintf("%.3f", result.CompletedAt.Sub(*result.StartedAt).Seconds())
+		}
+	}
+
+	for _, p := range probes {
+		tc := junitTestCase{Name: p.ProbeName, ClassName: "probe." + p.Mode}
+		if !p.Passed {
+			detail := probeFailureDetail(p.Detail)
+			switch domain.ProbeMode(p.Mode) {
+			case domain.ProbeModeSOT, domain.ProbeModeEOT:
+				tc.Failure = &junitProblem{
+					Message: fmt.Sprintf("%s probe %s failed", p.Mode, p.ProbeName),
+					Type:    p.ProbeType,
+					Body:    detail,
+				}
+			default:
+				tc.SystemOut = fmt.Sprintf("probe failed at %s: %s", p.ExecutedAt.Time.UTC().Format("2006-01-02T15:04:05Z"), detail)
+			}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	hypothesis := junitTestCase{Name: "hypothesis", ClassName: "experiment." + string(result.Config.ChaosType)}
+	if result.Hypothesis != nil {
+		hypothesis.SystemOut = *result.Hypothesis
+	}
+	reason := string(result.Status)
+	if result.Error != nil {
+		reason = *result.Error
+	}
+	switch result.Status {
+	case domain.StatusFailed:
+		hypothesis.Failure = &junitProblem{Message: reason, Type: string(result.Status)}
+	case domain.StatusEmergencyStopped:
+		hypothesis.Error = &junitProblem{Message: reason, Type: string(result.Status)}
+	}
+	suite.TestCases = append(suite.TestCases, hypothesis)
+
+	for _, tc := range suite.TestCases {
+		suite.Tests++
+		if tc.Failure != nil {
+			suite.Failures++
+		}
+		if tc.Error != nil {
+			suite.Errors++
+		}
+	}
+	return suite
+}
+
+// probeFailureDetail prefers the probe's error message and falls back to
+// the raw detail
+func probeFailureDetail(detail json.RawMessage) string {
+	var d map[string]any
+	if err := json.Unmarshal(detail, &d); err == nil {
+		if msg, ok := d["error"].(string); ok && msg != "" {
+			return msg
+		}
+	}
+	return strings.TrimSpace(string(detail))
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExperimentJUnitMixedRun(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := pgtype.Timestamptz{Time: started, Valid: true}
+	q := &fakeQuerier{
+		experiments: map[string]db.Experiment{"abc12345": {
+			ID:          "abc12345",
+			Config:      json.RawMessage(`{"name":"kill-web","chaos_type":"pod_delete","safety":{}}`),
+			Status:      string(domain.StatusFailed),
+			Phase:       string(domain.PhaseRollback),
+			StartedAt:   at,
+			CompletedAt: pgtype.Timestamptz{Time: started.Add(90 * time.Second), Valid: true},
+			Error:       pgtype.Text{String: "EOT probe checkout failed", Valid: true},
+		}},
+		probes: []db.ProbeResult{
+			{ExperimentID: "abc12345", ProbeName: "web-up", ProbeType: "http", Mode: "sot", Passed: true,
+				Detail: json.RawMessage(`{"status_code":200}`), ExecutedAt: at},
+			{ExperimentID: "abc12345", ProbeName: "latency", ProbeType: "prometheus", Mode: "continuous", Passed: false,
+				Detail: json.RawMessage(`{"error":"p99 above 500ms"}`), ExecutedAt: at},
+			{ExperimentID: "abc12345", ProbeName: "checkout", ProbeType: "http", Mode: "eot", Passed: false,
+				Detail: json.RawMessage(`{"error":"status 503 <want 200>"}`), ExecutedAt: at},
+		},
+	}
+	r := setupQuerierRouter(q)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/abc12345/junit", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/xml")
+	assert.True(t, strings.HasPrefix(w.Body.String(), xml.Header))
+
+	// Walk every token so malformed output fails here rather than in CI
+	dec := xml.NewDecoder(strings.NewReader(w.Body.String()))
+	for {
+		_, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+	}
+
+	var suite junitTestSuite
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &suite))
+	assert.Equal(t, "kill-web", suite.Name)
+	assert.Equal(t, "abc12345", suite.ID)
+	assert.Equal(t, 4, suite.Tests)
+	assert.Equal(t, 2, suite.Failures)
+	assert.Equal(t, 0, suite.Errors)
+	assert.Equal(t, "90.000", suite.Time)
+	assert.Equal(t, "2026-03-01T12:00:00", suite.Timestamp)
+
+	require.Len(t, suite.TestCases, 4)
+	sot, continuous, eot, hypothesis := suite.TestCases[0], suite.TestCases[1], suite.TestCases[2], suite.TestCases[3]
+	assert.Equal(t, "probe.sot", sot.ClassName)
+	assert.Nil(t, sot.Failure)
+	assert.Nil(t, continuous.Failure)
+	assert.Contains(t, continuous.SystemOut, "p99 above 500ms")
+	require.NotNil(t, eot.Failure)
+	assert.Equal(t, "eot probe checkout failed", eot.Failure.Message)
+	assert.Equal(t, "status 503 <want 200>", eot.Failure.Body)
+	assert.Equal(t, "hypothesis", hypothesis.Name)
+	require.NotNil(t, hypothesis.Failure)
+	assert.Equal(t, "EOT probe checkout failed", hypothesis.Failure.Message)
+}
+
+func TestExperimentJUnitStatuses(t *testing.T) {
+	q := &fakeQuerier{experiments: map[string]db.Experiment{
+		"running1": {ID: "running1", Config: json.RawMessage(`{}`), Status: string(domain.StatusRunning)},
+		"stopped1": {ID: "stopped1", Config: json.RawMessage(`{}`), Status: string(domain.StatusEmergencyStopped)},
+	}}
+	r := setupQuerierRouter(q)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/running1/junit", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/missing1/junit", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/experiments/stopped1/junit", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var suite junitTestSuite
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &suite))
+	assert.Equal(t, "stopped1", suite.Name)
+	assert.Equal(t, 1, suite.Tests)
+	assert.Equal(t, 1, suite.Errors)
+	require.NotNil(t, suite.TestCases[0].Error)
+}
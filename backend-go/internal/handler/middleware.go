@@ -1,14 +1,57 @@
 package handler
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/chaosduck/backend-go/internal/observability"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// requestIDKey is the gin context key holding the request ID
+const requestIDKey = "request_id"
+
+// maxRequestIDLen bounds an incoming X-Request-ID. Longer IDs, and IDs with
+// characters outside printable ASCII, are replaced with a fresh one so they
+// cannot forge log lines or bloat headers.
+const maxRequestIDLen = 128
+
+// RequestIDMiddleware tags each request with the X-Request-ID it arrived
+// with, or a new UUID. The ID is stored in the gin context and the request
+// context, where it prefixes log lines and is forwarded to the AI service,
+// and is echoed in the response header.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(observability.RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Set(requestIDKey, id)
+		c.Request = c.Request.WithContext(observability.WithRequestID(c.Request.Context(), id))
+		c.Header(observability.RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// validRequestID reports whether a client-supplied request ID can be used
+// as is
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
 // PrometheusMiddleware records HTTP request metrics
 func PrometheusMiddleware(metrics *observability.Metrics) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -25,13 +68,30 @@ func PrometheusMiddleware(metrics *observability.Metrics) gin.HandlerFunc {
 	}
 }
 
+// BodyLimitMiddleware caps request bodies at maxBytes. A declared
+// Content-Length over the limit is refused up front; chunked bodies fail
+// with 413 once the handler reads past it.
+func BodyLimitMiddleware(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			respondError(c, http.StatusRequestEntityTooLarge, CodeRequestTooLarge,
+				fmt.Sprintf("request body exceeds %d bytes", maxBytes))
+			c.Abort()
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
 // CORSMiddleware handles Cross-Origin Resource Sharing
 func CORSMiddleware(allowOrigin string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", allowOrigin)
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -41,6 +101,97 @@ func CORSMiddleware(allowOrigin string) gin.HandlerFunc {
 	}
 }
 
+// gzipMinSize is the smallest response body worth compressing
+const gzipMinSize = 1024
+
+// GzipMiddleware compresses responses for clients that accept gzip. Bodies
+// are buffered until they reach minSize, so small responses go out
+// uncompressed. Server-Sent Events are never compressed: compression would
+// hold events back until enough bytes accumulate.
+func GzipMiddleware(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") ||
+			c.Request.Method == "HEAD" {
+			c.Next()
+			return
+		}
+
+		gw := &gzipWriter{ResponseWriter: c.Writer, minSize: minSize}
+		c.Writer = gw
+		defer gw.finish()
+		c.Next()
+	}
+}
+
+// gzipWriter buffers the start of a response and switches to gzip once the
+// body passes minSize
+type gzipWriter struct {
+	gin.ResponseWriter
+	minSize     int
+	buf         bytes.Buffer
+	gz          *gzip.Writer
+	passthrough bool
+}
+
+func (w *gzipWriter) Write(p []byte) (int, error) {
+	switch {
+	case w.gz != nil:
+		return w.gz.Write(p)
+	case w.passthrough:
+		return w.ResponseWriter.Write(p)
+	case strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") ||
+		w.Header().Get("Content-Encoding") != "":
+		w.passthrough = true
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf.Write(p)
+	if w.buf.Len() >= w.minSize {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+		w.Header().Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+		if _, err := w.gz.Write(w.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		w.buf.Reset()
+	}
+	return len(p), nil
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends buffered data immediately; a handler that flushes before the
+// threshold is streaming, so the rest of its response stays uncompressed
+func (w *gzipWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	} else if !w.passthrough {
+		w.passthrough = true
+		w.flushBuffer()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipWriter) flushBuffer() {
+	if w.buf.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+// finish writes whatever is left once the handler returns
+func (w *gzipWriter) finish() {
+	if w.gz != nil {
+		_ = w.gz.Close()
+		return
+	}
+	w.flushBuffer()
+}
+
 // normalizePath replaces dynamic path segments with placeholders
 func normalizePath(path string) string {
 	parts := strings.Split(strings.Trim(path, "/"), "/")
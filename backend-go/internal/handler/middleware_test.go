@@ -1,9 +1,18 @@
 package handler
 
 import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/chaosduck/backend-go/internal/observability"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNormalizePath(t *testing.T) {
@@ -53,3 +62,129 @@ func TestIsShortID(t *testing.T) {
 		})
 	}
 }
+
+func setupGzipRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(GzipMiddleware(gzipMinSize))
+	r.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"items": strings.Repeat("node,", 1000)})
+	})
+	r.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	r.GET("/stream", func(c *gin.Context) {
+		c.Writer.Header().Set("Content-Type", "text/event-stream")
+		c.Status(http.StatusOK)
+		for i := 0; i < 100; i++ {
+			sendSSE(c, 1, "experiment", gin.H{"payload": strings.Repeat("x", 50)})
+		}
+	})
+	return r
+}
+
+func gzipRequest(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGzipMiddlewareCompressesLargeResponse(t *testing.T) {
+	w := gzipRequest(setupGzipRouter(), "/large")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Less(t, w.Body.Len(), 1000)
+
+	zr, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"items":"node,node,`)
+}
+
+func TestGzipMiddlewareSkipsSmallResponse(t *testing.T) {
+	w := gzipRequest(setupGzipRouter(), "/small")
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestGzipMiddlewareSkipsSSE(t *testing.T) {
+	w := gzipRequest(setupGzipRouter(), "/stream")
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "id: 1\nevent: experiment\n"))
+}
+
+func TestGzipMiddlewareRespectsAcceptEncoding(t *testing.T) {
+	r := setupGzipRouter()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/large", nil))
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Body.String(), `"items"`)
+}
+
+func TestBodyLimitMiddlewareRejectsOversizedBodies(t *testing.T) {
+	r, h := setupTestRouter()
+	r.POST("/experiments", BodyLimitMiddleware(1024), h.CreateExperiment)
+
+	oversized := `{"name":"` + strings.Repeat("x", 4096) + `","chaos_type":"pod_delete"}`
+
+	// Declared Content-Length: refused before the handler runs
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/experiments", strings.NewReader(oversized)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"request_too_large"`)
+
+	// Chunked body of unknown length: cut off while the handler decodes it
+	req := httptest.NewRequest("POST", "/experiments", io.MultiReader(strings.NewReader(oversized)))
+	req.ContentLength = -1
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	// Small bodies pass through to normal validation
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/experiments", strings.NewReader(`{"name":"x"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var seen string
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.GET("/ping", func(c *gin.Context) {
+		seen = observability.RequestID(c.Request.Context())
+		assert.Equal(t, seen, c.GetString(requestIDKey))
+		c.Status(http.StatusOK)
+	})
+
+	// Generated when the client sends none
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/ping", nil))
+	generated := w.Header().Get(observability.RequestIDHeader)
+	_, err := uuid.Parse(generated)
+	require.NoError(t, err)
+	assert.Equal(t, generated, seen)
+
+	// Echoed when the client sends one
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.Header.Set(observability.RequestIDHeader, "client-abc-123")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "client-abc-123", w.Header().Get(observability.RequestIDHeader))
+	assert.Equal(t, "client-abc-123", seen)
+
+	// Replaced when it could forge log lines
+	req = httptest.NewRequest("GET", "/ping", nil)
+	req.Header.Set(observability.RequestIDHeader, "bad id")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.NotEqual(t, "bad id", w.Header().Get(observability.RequestIDHeader))
+	assert.NotEmpty(t, w.Header().Get(observability.RequestIDHeader))
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+)
+
+// nlRunRequest is the body of POST /api/chaos/nl-run. Confirm stands in for
+// require_confirmation, which is never taken from the AI's output.
+type nlRunRequest struct {
+	Text     string         `json:"text" binding:"required"`
+	Topology map[string]any `json:"topology,omitempty"`
+	Confirm  bool           `json:"confirm"`
+}
+
+// nlRunResponse pairs the config the AI interpreted with the experiment it
+// ran, so the caller can check what was actually executed
+type nlRunResponse struct {
+	Config domain.ExperimentConfig  `json:"config"`
+	Result *domain.ExperimentResult `json:"result,omitempty"`
+}
+
+// NLRun turns a natural language description into an experiment config via
+// the AI service, validates it and runs it. An experiment aimed at a
+// production namespace is not run until the caller resends the request with
+// confirm set, after reviewing the interpreted config returned by the 422.
+func NLRun(analysis *AnalysisHandler, chaos *ChaosHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if chaos.esm.IsTriggered() {
+			respondError(c, http.StatusServiceUnavailable, CodeEmergencyStop, "Emergency stop is active")
+			return
+		}
+
+		var req nlRunRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+
+		raw, err := analysis.postAI(c.Request.Context(), "/nl-experiment", gin.H{"text": req.Text, "topology": req.Topology})
+		if err != nil {
+			respondError(c, http.StatusBadGateway, CodeAIServiceUnavailable, fmt.Sprintf("AI service error: %v", err))
+			return
+		}
+
+		var cfg domain.ExperimentConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":       gin.H{"code": CodeInvalidConfig, "message": "AI returned an unusable experiment config"},
+				"detail":      fmt.Sprintf("decode AI config: %v", err),
+				"ai_response": string(raw),
+			})
+			return
+		}
+		cfg.Safety.RequireConfirmation = req.Confirm
+		if chaos.safeMode {
+			cfg.Safety.DryRun = true
+		}
+		applySafetyDefaults(&cfg)
+
+		if errs := domain.ValidateConfig(cfg); len(errs) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"valid":       false,
+				"errors":      errs,
+				"config":      cfg,
+				"ai_response": string(raw),
+			})
+			return
+		}
+
+		for _, ns := range configNamespaces(cfg) {
+			if err := safety.RequireConfirmation(ns, "prod*", req.Confirm); err != nil {
+				chaos.metrics.RecordSafetyBlock("confirmation")
+				status, code := errorStatus(err)
+				c.JSON(status, gin.H{
+					"error":  gin.H{"code": code, "message": err.Error()},
+					"detail": fmt.Sprintf("namespace %s requires confirmation; resend with confirm=true to run this config", ns),
+					"config": cfg,
+				})
+				return
+			}
+		}
+
+		status, result, err := chaos.startExperiment(c.Request.Context(), cfg, nil)
+		if err != nil {
+			respondDomainError(c, err)
+			return
+		}
+		c.JSON(status, nlRunResponse{Config: cfg, Result: result})
+	}
+}
+
+// configNamespaces lists every namespace the config targets
+func configNamespaces(cfg domain.ExperimentConfig) []string {
+	namespaces := append([]string{}, cfg.TargetNamespaces...)
+	if cfg.TargetNamespace != nil && *cfg.TargetNamespace != "" {
+		namespaces = append(namespaces, *cfg.TargetNamespace)
+	}
+	return namespaces
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/engine"
+	"github.com/chaosduck/backend-go/internal/observability"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capturingRunner completes every experiment and keeps the configs it ran
+type capturingRunner struct {
+	stubRunner
+	ran []domain.ExperimentConfig
+}
+
+func (r *capturingRunner) Run(_ context.Context, id string, cfg domain.ExperimentConfig) (*domain.ExperimentResult, error) {
+	r.ran = append(r.ran, cfg)
+	return &domain.ExperimentResult{ExperimentID: id, Config: cfg, Status: domain.StatusCompleted}, nil
+}
+
+// setupNLRunRouter serves /nl-run against an AI stub that answers
+// /nl-experiment with aiResponse
+func setupNLRunRouter(t *testing.T, aiResponse string) (*gin.Engine, *capturingRunner) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/nl-experiment", r.URL.Path)
+		_, _ = w.Write([]byte(aiResponse))
+	}))
+	t.Cleanup(ai.Close)
+
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	runner := &capturingRunner{}
+	chaos := NewChaosHandler(runner, nil, safety.NewEmergencyStopManager(), safety.NewRollbackManager(), metrics)
+	analysis := NewAnalysisHandler(nil, ai.URL, engine.DefaultAITimeouts(), metrics)
+	r := gin.New()
+	r.POST("/nl-run", NLRun(analysis, chaos))
+	return r, runner
+}
+
+func TestNLRunRunsInterpretedConfig(t *testing.T) {
+	r, runner := setupNLRunRouter(t, `{"name":"kill-web","chaos_type":"pod_delete",
+		"target_namespace":"shop","target_labels":{"app":"web"},
+		"description":"delete one web pod","safety":{"require_confirmation":true}}`)
+
+	w := postJSON(r, "/nl-run", `{"text":"kill a web pod in shop"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp nlRunResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, domain.ChaosTypePodDelete, resp.Config.ChaosType)
+	require.NotNil(t, resp.Result)
+	assert.Equal(t, domain.StatusCompleted, resp.Result.Status)
+
+	require.Len(t, runner.ran, 1)
+	assert.Equal(t, map[string]string{"app": "web"}, runner.ran[0].TargetLabels)
+	// The AI cannot confirm on the caller's behalf
+	assert.False(t, runner.ran[0].Safety.RequireConfirmation)
+	assert.Equal(t, domain.DefaultSafetyConfig().TimeoutSeconds, runner.ran[0].Safety.TimeoutSeconds)
+}
+
+func TestNLRunProductionNeedsConfirmation(t *testing.T) {
+	r, runner := setupNLRunRouter(t, `{"name":"kill-api","chaos_type":"pod_delete",
+		"target_namespace":"prod-api","safety":{"require_confirmation":true}}`)
+
+	w := postJSON(r, "/nl-run", `{"text":"kill an api pod in production"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, CodeConfirmationRequired, body["error"].(map[string]any)["code"])
+	assert.Equal(t, "prod-api", body["config"].(map[string]any)["target_namespace"])
+	assert.Empty(t, runner.ran)
+
+	w = postJSON(r, "/nl-run", `{"text":"kill an api pod in production","confirm":true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, runner.ran, 1)
+	assert.True(t, runner.ran[0].Safety.RequireConfirmation)
+}
+
+func TestNLRunMalformedAIOutput(t *testing.T) {
+	r, runner := setupNLRunRouter(t, `{"name":"oops","chaos_type":["pod_delete"]}`)
+
+	w := postJSON(r, "/nl-run", `{"text":"break something"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, `{"name":"oops","chaos_type":["pod_delete"]}`, body["ai_response"])
+	assert.Empty(t, runner.ran)
+}
+
+func TestNLRunRejectsInvalidAIConfig(t *testing.T) {
+	r, runner := setupNLRunRouter(t, `{"name":"fog","chaos_type":"fog_machine"}`)
+
+	w := postJSON(r, "/nl-run", `{"text":"fill the cluster with fog"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, false, body["valid"])
+	assert.NotEmpty(t, body["errors"])
+	assert.Empty(t, runner.ran)
+}
+
+func TestNLRunRequiresText(t *testing.T) {
+	r, _ := setupNLRunRouter(t, `{}`)
+
+	w := postJSON(r, "/nl-run", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
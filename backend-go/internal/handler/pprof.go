@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenAuthMiddleware admits only requests carrying
+// "Authorization: Bearer <token>". An empty token admits nothing, so a
+// route guarded by it can never be opened by a missing setting.
+func TokenAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			respondError(c, http.StatusUnauthorized, CodeUnauthorized, "missing or invalid bearer token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// MountPprof serves the net/http/pprof handlers under /debug/pprof behind
+// TokenAuthMiddleware. Profiles can stall the process and expose its
+// internals, so the caller only mounts them when ENABLE_PPROF is set.
+func MountPprof(r *gin.Engine, token string) {
+	g := r.Group("/debug/pprof", TokenAuthMiddleware(token))
+	g.GET("/", gin.WrapF(pprof.Index))
+	g.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	g.GET("/profile", gin.WrapF(pprof.Profile))
+	g.GET("/symbol", gin.WrapF(pprof.Symbol))
+	g.POST("/symbol", gin.WrapF(pprof.Symbol))
+	g.GET("/trace", gin.WrapF(pprof.Trace))
+	// Named runtime profiles: goroutine, heap, allocs, block, mutex, threadcreate
+	g.GET("/:profile", func(c *gin.Context) {
+		pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+	})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupPprofRouter(token string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	MountPprof(r, token)
+	return r
+}
+
+func pprofRequest(r *gin.Engine, path, auth string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPprofRequiresToken(t *testing.T) {
+	r := setupPprofRouter("s3cret")
+
+	w := pprofRequest(r, "/debug/pprof/goroutine", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"unauthorized"`)
+
+	w = pprofRequest(r, "/debug/pprof/goroutine", "Bearer wrong")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestPprofServesProfiles(t *testing.T) {
+	r := setupPprofRouter("s3cret")
+
+	w := pprofRequest(r, "/debug/pprof/", "Bearer s3cret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "goroutine")
+
+	w = pprofRequest(r, "/debug/pprof/goroutine?debug=1", "Bearer s3cret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "goroutine profile:")
+}
+
+func TestPprofEmptyTokenRefusesEverything(t *testing.T) {
+	r := setupPprofRouter("")
+
+	w := pprofRequest(r, "/debug/pprof/heap", "Bearer ")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/observability"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// queuedExperiment is an experiment accepted in sequential mode and waiting
+// for its turn
+type queuedExperiment struct {
+	id  string
+	cfg domain.ExperimentConfig
+	// requestID is the ID of the request that queued it, carried into its run
+	requestID string
+}
+
+// experimentQueue runs experiments strictly one at a time in FIFO order on
+// a single worker goroutine
+type experimentQueue struct {
+	mu      sync.Mutex
+	items   chan queuedExperiment
+	closed  bool
+	done    chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	run     func(ctx context.Context, e queuedExperiment)
+	discard func(e queuedExperiment)
+}
+
+// newExperimentQueue starts a worker that calls run for each queued
+// experiment. Experiments still queued when the queue is drained are passed
+// to discard instead.
+func newExperimentQueue(maxDepth int, run func(context.Context, queuedExperiment), discard func(queuedExperiment)) *experimentQueue {
+	if maxDepth < 1 {
+		maxDepth = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &experimentQueue{
+		items:   make(chan queuedExperiment, maxDepth),
+		done:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+		run:     run,
+		discard: discard,
+	}
+	go q.work()
+	return q
+}
+
+// enqueue adds e to the back of the queue without blocking. accepted runs
+// once a slot is reserved and before the worker can see e, so the pending
+// record exists before the experiment starts.
+func (q *experimentQueue) enqueue(e queuedExperiment, accepted func()) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return domain.ErrQueueClosed
+	}
+	// Only enqueue sends, under mu, so a free slot stays free until the send
+	if len(q.items) == cap(q.items) {
+		return domain.ErrQueueFull
+	}
+	accepted()
+	q.items <- e
+	return nil
+}
+
+// work runs queued experiments until the queue is closed, then discards
+// whatever is left
+func (q *experimentQueue) work() {
+	defer close(q.done)
+	for e := range q.items {
+		if q.isClosed() {
+			q.discard(e)
+			continue
+		}
+		q.run(q.ctx, e)
+	}
+}
+
+func (q *experimentQueue) isClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+// drain stops accepting experiments, discards the queued ones and waits for
+// the running one to finish. If ctx ends first the running experiment is
+// cancelled.
+func (q *experimentQueue) drain(ctx context.Context) {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.items)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+	case <-ctx.Done():
+		q.cancel()
+		<-q.done
+	}
+	q.cancel()
+}
+
+// EnableSequentialMode routes new experiments through a FIFO queue run by a
+// single worker, so experiments never overlap. CreateExperiment then
+// answers 202 with the pending experiment and a full queue answers 429.
+func (h *ChaosHandler) EnableSequentialMode(maxDepth int) {
+	h.queue = newExperimentQueue(maxDepth, h.runQueued, h.discardQueued)
+}
+
+// DrainQueue stops sequential mode from accepting experiments, marks the
+// queued ones failed and waits for the running one. It is a no-op outside
+// sequential mode.
+func (h *ChaosHandler) DrainQueue(ctx context.Context) {
+	if h.queue != nil {
+		h.queue.drain(ctx)
+	}
+}
+
+// runQueued runs an experiment taken off the queue. A run refused before it
+// produced a result is marked failed here; otherwise the runner has already
+// persisted the outcome.
+func (h *ChaosHandler) runQueued(ctx context.Context, e queuedExperiment) {
+	if e.requestID != "" {
+		ctx = observability.WithRequestID(ctx, e.requestID)
+	}
+	// Flip the record to running now so streams see the transition before
+	// the runner's first write
+	if h.queries != nil {
+		if err := h.experiments.UpdateExperimentStatus(ctx, db.UpdateExperimentStatusParams{
+			ID:     e.id,
+			Status: string(domain.StatusRunning),
+		}); err != nil {
+			observability.Logf(ctx, "Failed to mark experiment %s running: %v", e.id, err)
+		}
+	}
+	result, err := h.execute(ctx, e.id, h.queuedConfig(ctx, e))
+	if err != nil {
+		observability.Logf(ctx, "Queued experiment %s failed: %v", e.id, err)
+		if result == nil {
+			h.markFailed(e.id, err.Error())
+		}
+	}
+}
+
+// queuedConfig returns the config to run e with. The stored config wins
+// because it may have been edited while e waited; the record is already
+// running here, so it cannot change underneath the run.
+func (h *ChaosHandler) queuedConfig(ctx context.Context, e queuedExperiment) domain.ExperimentConfig {
+	if h.queries == nil {
+		return e.cfg
+	}
+	rec, err := h.queries.GetExperiment(ctx, e.id)
+	if err != nil {
+		observability.Logf(ctx, "Failed to reload config of experiment %s, running it as queued: %v", e.id, err)
+		return e.cfg
+	}
+	var cfg domain.ExperimentConfig
+	if err := json.Unmarshal(rec.Config, &cfg); err != nil {
+		observability.Logf(ctx, "Failed to decode stored config of experiment %s, running it as queued: %v", e.id, err)
+		return e.cfg
+	}
+	return cfg
+}
+
+// discardQueued fails an experiment that never got its turn
+func (h *ChaosHandler) discardQueued(e queuedExperiment) {
+	log.Printf("Discarding queued experiment %s: server shutting down", e.id)
+	h.markFailed(e.id, domain.ErrQueueClosed.Error())
+}
+
+// markFailed records a failure for an experiment the runner never
+// persisted, so it does not stay pending forever
+func (h *ChaosHandler) markFailed(experimentID, reason string) {
+	if h.queries == nil {
+		return
+	}
+	if err := h.experiments.UpdateExperiment(context.Background(), db.UpdateExperimentParams{
+		ID:          experimentID,
+		Status:      string(domain.StatusFailed),
+		Phase:       string(domain.PhaseSteadyState),
+		CompletedAt: pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+		Error:       pgtype.Text{String: reason, Valid: true},
+	}); err != nil {
+		log.Printf("Failed to mark experiment %s failed: %v", experimentID, err)
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/observability"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedRunner blocks every run until released and records the run order
+// and how many runs overlapped
+type gatedRunner struct {
+	mu        sync.Mutex
+	order     []string
+	active    int
+	maxActive int
+	started   chan string
+	release   chan struct{}
+}
+
+func newGatedRunner() *gatedRunner {
+	return &gatedRunner{started: make(chan string, 10), release: make(chan struct{})}
+}
+
+func (g *gatedRunner) Run(ctx context.Context, id string, cfg domain.ExperimentConfig) (*domain.ExperimentResult, error) {
+	g.mu.Lock()
+	g.order = append(g.order, cfg.Name)
+	g.active++
+	g.maxActive = max(g.maxActive, g.active)
+	g.mu.Unlock()
+	g.started <- cfg.Name
+
+	defer func() {
+		g.mu.Lock()
+		g.active--
+		g.mu.Unlock()
+	}()
+	select {
+	case <-g.release:
+		return &domain.ExperimentResult{ExperimentID: id, Status: domain.StatusCompleted}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (g *gatedRunner) DryRun(context.Context, string, domain.ExperimentConfig) (*domain.ExperimentResult, []error) {
+	return nil, nil
+}
+
+func (g *gatedRunner) SetPersistHook(func(string)) {}
+
+func (g *gatedRunner) VerifyRollback(context.Context, string) ([]map[string]any, error) {
+	return nil, nil
+}
+
+func (g *gatedRunner) AbortAll(context.Context) ([]string, map[string][]safety.RollbackResult) {
+	return nil, nil
+}
+
+func (g *gatedRunner) waitStarted(t *testing.T, name string) {
+	t.Helper()
+	select {
+	case got := <-g.started:
+		require.Equal(t, name, got)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("%s did not start", name)
+	}
+}
+
+func setupQueueRouter(runner ExperimentRunner, maxDepth int) (*gin.Engine, *ChaosHandler) {
+	gin.SetMode(gin.TestMode)
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	h := NewChaosHandler(runner, nil, safety.NewEmergencyStopManager(), safety.NewRollbackManager(), metrics)
+	h.EnableSequentialMode(maxDepth)
+	r := gin.New()
+	r.POST("/experiments", h.CreateExperiment)
+	return r, h
+}
+
+func queueExperiment(t *testing.T, r *gin.Engine, name string) int {
+	t.Helper()
+	w := postJSON(r, "/experiments", `{"name":"`+name+`","chaos_type":"pod_delete","target_namespace":"shop","safety":`+testSafetyJSON+`}`)
+	if w.Code == http.StatusAccepted {
+		var result domain.ExperimentResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, domain.StatusPending, result.Status)
+		assert.NotEmpty(t, result.ExperimentID)
+	}
+	return w.Code
+}
+
+func TestSequentialModeRunsInOrderWithoutOverlap(t *testing.T) {
+	runner := newGatedRunner()
+	r, h := setupQueueRouter(runner, 2)
+	defer h.DrainQueue(context.Background())
+
+	require.Equal(t, http.StatusAccepted, queueExperiment(t, r, "first"))
+	runner.waitStarted(t, "first")
+	require.Equal(t, http.StatusAccepted, queueExperiment(t, r, "second"))
+	require.Equal(t, http.StatusAccepted, queueExperiment(t, r, "third"))
+
+	// first is running and two are waiting, so the queue is full
+	assert.Equal(t, http.StatusTooManyRequests, queueExperiment(t, r, "fourth"))
+
+	runner.release <- struct{}{}
+	runner.waitStarted(t, "second")
+	runner.release <- struct{}{}
+	runner.waitStarted(t, "third")
+	runner.release <- struct{}{}
+
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	assert.Equal(t, []string{"first", "second", "third"}, runner.order)
+	assert.Equal(t, 1, runner.maxActive)
+}
+
+func TestDrainQueueDiscardsWaitingExperiments(t *testing.T) {
+	runner := newGatedRunner()
+	r, h := setupQueueRouter(runner, 2)
+
+	require.Equal(t, http.StatusAccepted, queueExperiment(t, r, "running"))
+	runner.waitStarted(t, "running")
+	require.Equal(t, http.StatusAccepted, queueExperiment(t, r, "waiting"))
+
+	drained := make(chan struct{})
+	go func() {
+		h.DrainQueue(context.Background())
+		close(drained)
+	}()
+
+	// The running experiment is waited for, not cancelled
+	select {
+	case <-drained:
+		t.Fatal("drain returned while an experiment was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	runner.release <- struct{}{}
+	<-drained
+
+	assert.Equal(t, http.StatusServiceUnavailable, queueExperiment(t, r, "late"))
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	assert.Equal(t, []string{"running"}, runner.order)
+}
+
+func TestDrainQueueCancelsRunningExperimentAtDeadline(t *testing.T) {
+	runner := newGatedRunner()
+	r, h := setupQueueRouter(runner, 1)
+
+	require.Equal(t, http.StatusAccepted, queueExperiment(t, r, "stuck"))
+	runner.waitStarted(t, "stuck")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	h.DrainQueue(ctx)
+
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	assert.Equal(t, 0, runner.active)
+}
+
+func TestQueuedExperimentRunsEditedConfig(t *testing.T) {
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	q := &fakeQuerier{experiments: map[string]db.Experiment{"abc12345": {
+		ID: "abc12345", Config: json.RawMessage(`{"name":"edited","chaos_type":"pod_delete"}`),
+	}}}
+	h := NewChaosHandler(&stubRunner{}, q, safety.NewEmergencyStopManager(), safety.NewRollbackManager(), metrics)
+
+	queued := domain.ExperimentConfig{Name: "original", ChaosType: domain.ChaosTypePodDelete}
+	assert.Equal(t, "edited", h.queuedConfig(context.Background(), queuedExperiment{id: "abc12345", cfg: queued}).Name)
+	assert.Equal(t, "original", h.queuedConfig(context.Background(), queuedExperiment{id: "missing1", cfg: queued}).Name)
+}
@@ -0,0 +1,371 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// reportPhases is the order phases run in, used to lay out phase timings
+var reportPhases = []domain.ExperimentPhase{
+	domain.PhaseSteadyState, domain.PhaseHypothesis, domain.PhaseInject, domain.PhaseObserve, domain.PhaseRollback,
+}
+
+// reportRow is one key/value line of a report table
+type reportRow struct {
+	Key   string
+	Value string
+}
+
+// reportProbe is one probe execution in the report's probe table
+type reportProbe struct {
+	Name   string
+	Type   string
+	Mode   string
+	Passed bool
+	At     string
+	Detail string
+}
+
+// reportView is an experiment flattened for the report templates. Sections
+// the experiment never produced are left empty and the templates skip them.
+type reportView struct {
+	ID           string
+	Name         string
+	ChaosType    string
+	Status       string
+	Phase        string
+	StartedAt    string
+	CompletedAt  string
+	Duration     string
+	Description  string
+	Hypothesis   string
+	Error        string
+	Config       []reportRow
+	Phases       []reportRow
+	SteadyState  []reportRow
+	Observations []reportRow
+	Probes       []reportProbe
+	Rollback     []reportRow
+	AIInsights   []reportRow
+}
+
+const markdownReport = `# Chaos experiment report: {{.Name}}
+
+| Field | Value |
+| --- | --- |
+| Experiment ID | {{cell .ID}} |
+| Chaos type | {{cell .ChaosType}} |
+| Status | {{cell .Status}} |
+| Last phase | {{cell .Phase}} |
+{{- if .StartedAt}}
+| Started | {{.StartedAt}} |
+{{- end}}
+{{- if .CompletedAt}}
+| Completed | {{.CompletedAt}} |
+{{- end}}
+{{- if .Duration}}
+| Duration | {{.Duration}} |
+{{- end}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+{{- if .Error}}
+> **Error:** {{.Error}}
+{{end}}
+## Configuration
+{{template "rows" .Config}}
+## Phases
+{{if .Phases}}{{template "rows" .Phases}}{{else}}
+No phase timings recorded.
+{{end}}
+## Hypothesis
+{{if .Hypothesis}}
+{{.Hypothesis}}
+{{else}}
+No hypothesis recorded.
+{{end}}
+## Steady state
+{{if .SteadyState}}{{template "rows" .SteadyState}}{{else}}
+No steady state captured.
+{{end}}
+## Observations
+{{if .Observations}}{{template "rows" .Observations}}{{else}}
+No observations recorded.
+{{end}}
+## Probes
+{{if .Probes}}
+| Probe | Type | Mode | Result | Executed | Detail |
+| --- | --- | --- | --- | --- | --- |
+{{- range .Probes}}
+| {{cell .Name}} | {{cell .Type}} | {{cell .Mode}} | {{if .Passed}}passed{{else}}failed{{end}} | {{.At}} | {{cell .Detail}} |
+{{- end}}
+{{else}}
+No probes ran.
+{{end}}
+## Rollback
+{{if .Rollback}}{{template "rows" .Rollback}}{{else}}
+No rollback recorded.
+{{end}}
+{{- if .AIInsights}}
+## AI insights
+{{template "rows" .AIInsights}}
+{{- end}}
+{{- define "rows"}}
+| Key | Value |
+| --- | --- |
+{{- range .}}
+| {{cell .Key}} | {{cell .Value}} |
+{{- end}}
+{{end}}`
+
+const htmlReport = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Chaos experiment report: {{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2rem; }
+table { border-collapse: collapse; margin-bottom: 1rem; }
+th, td { border: 1px solid #ccc; padding: 0.25rem 0.5rem; text-align: left; vertical-align: top; }
+td pre { margin: 0; white-space: pre-wrap; }
+.passed { color: #1a7f37; }
+.failed { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Chaos experiment report: {{.Name}}</h1>
+<table>
+<tr><th>Experiment ID</th><td>{{.ID}}</td></tr>
+<tr><th>Chaos type</th><td>{{.ChaosType}}</td></tr>
+<tr><th>Status</th><td>{{.Status}}</td></tr>
+<tr><th>Last phase</th><td>{{.Phase}}</td></tr>
+{{- if .StartedAt}}<tr><th>Started</th><td>{{.StartedAt}}</td></tr>{{end}}
+{{- if .CompletedAt}}<tr><th>Completed</th><td>{{.CompletedAt}}</td></tr>{{end}}
+{{- if .Duration}}<tr><th>Duration</th><td>{{.Duration}}</td></tr>{{end}}
+</table>
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+{{- if .Error}}
+<p class="failed"><strong>Error:</strong> {{.Error}}</p>
+{{- end}}
+<h2>Configuration</h2>
+{{template "rows" .Config}}
+<h2>Phases</h2>
+{{if .Phases}}{{template "rows" .Phases}}{{else}}<p>No phase timings recorded.</p>{{end}}
+<h2>Hypothesis</h2>
+{{if .Hypothesis}}<p>{{.Hypothesis}}</p>{{else}}<p>No hypothesis recorded.</p>{{end}}
+<h2>Steady state</h2>
+{{if .SteadyState}}{{template "rows" .SteadyState}}{{else}}<p>No steady state captured.</p>{{end}}
+<h2>Observations</h2>
+{{if .Observations}}{{template "rows" .Observations}}{{else}}<p>No observations recorded.</p>{{end}}
+<h2>Probes</h2>
+{{- if .Probes}}
+<table>
+<tr><th>Probe</th><th>Type</th><th>Mode</th><th>Result</th><th>Executed</th><th>Detail</th></tr>
+{{- range .Probes}}
+<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Mode}}</td>{{if .Passed}}<td class="passed">passed</td>{{else}}<td class="failed">failed</td>{{end}}<td>{{.At}}</td><td><pre>{{.Detail}}</pre></td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No probes ran.</p>
+{{- end}}
+<h2>Rollback</h2>
+{{if .Rollback}}{{template "rows" .Rollback}}{{else}}<p>No rollback recorded.</p>{{end}}
+{{- if .AIInsights}}
+<h2>AI insights</h2>
+{{template "rows" .AIInsights}}
+{{- end}}
+</body>
+</html>
+{{- define "rows"}}
+<table>
+<tr><th>Key</th><th>Value</th></tr>
+{{- range .}}
+<tr><td>{{.Key}}</td><td><pre>{{.Value}}</pre></td></tr>
+{{- end}}
+</table>
+{{- end}}
+`
+
+var (
+	markdownReportTmpl = template.Must(template.New("report.md").Funcs(template.FuncMap{"cell": markdownCell}).Parse(markdownReport))
+	htmlReportTmpl     = htmltemplate.Must(htmltemplate.New("report.html").Parse(htmlReport))
+)
+
+// ExperimentReport renders a stored experiment as a Markdown (?format=md,
+// the default) or HTML (?format=html) document. It is built locally from the
+// stored result, so it works without the AI service.
+func (h *ChaosHandler) ExperimentReport(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+	format := c.DefaultQuery("format", "md")
+	if format != "md" && format != "html" {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest,
+			fmt.Sprintf("unsupported format %q: must be md or html", format))
+		return
+	}
+	experimentID := c.Param("experiment_id")
+
+	rec, err := h.queries.GetExperiment(c.Request.Context(), experimentID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, CodeExperimentNotFound, fmt.Sprintf("Experiment %s not found", experimentID))
+		return
+	}
+	probes, err := h.queries.ListProbeResultsByExperiment(c.Request.Context(), experimentID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+
+	view := buildReportView(recordToResult(rec), probes)
+	var buf bytes.Buffer
+	contentType := "text/markdown; charset=utf-8"
+	if format == "html" {
+		contentType = "text/html; charset=utf-8"
+		err = htmlReportTmpl.Execute(&buf, view)
+	} else {
+		err = markdownReportTmpl.Execute(&buf, view)
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// buildReportView flattens an experiment and its probe results for the
+// report templates
+func buildReportView(result domain.ExperimentResult, probes []db.ProbeResult) reportView {
+	cfg := result.Config
+	v := reportView{
+		ID:        result.ExperimentID,
+		Name:      cfg.Name,
+		ChaosType: string(cfg.ChaosType),
+		Status:    string(result.Status),
+		Phase:     string(result.Phase),
+	}
+	if v.Name == "" {
+		v.Name = result.ExperimentID
+	}
+	if result.StartedAt != nil {
+		v.StartedAt = result.StartedAt.UTC().Format(time.RFC3339)
+		if result.CompletedAt != nil {
+			v.Duration = result.CompletedAt.Sub(*result.StartedAt).Round(time.Millisecond).String()
+		}
+	}
+	if result.CompletedAt != nil {
+		v.CompletedAt = result.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	if cfg.Description != nil {
+		v.Description = *cfg.Description
+	}
+	if result.Hypothesis != nil {
+		v.Hypothesis = *result.Hypothesis
+	}
+	if result.Error != nil {
+		v.Error = *result.Error
+	}
+
+	if namespaces := targetNamespaceList(cfg); len(namespaces) > 0 {
+		v.Config = append(v.Config, reportRow{"Target namespaces", strings.Join(namespaces, ", ")})
+	}
+	if len(cfg.TargetLabels) > 0 {
+		v.Config = append(v.Config, reportRow{"Target labels", reportValue(cfg.TargetLabels)})
+	}
+	if cfg.TargetResource != nil {
+		v.Config = append(v.Config, reportRow{"Target resource", *cfg.TargetResource})
+	}
+	if cfg.TargetWorkload != nil {
+		v.Config = append(v.Config, reportRow{"Target workload", *cfg.TargetWorkload})
+	}
+	if cfg.FieldSelector != nil {
+		v.Config = append(v.Config, reportRow{"Field selector", *cfg.FieldSelector})
+	}
+	if len(cfg.Parameters) > 0 {
+		v.Config = append(v.Config, reportRow{"Parameters", reportValue(cfg.Parameters)})
+	}
+	v.Config = append(v.Config,
+		reportRow{"Dry run", fmt.Sprint(cfg.Safety.DryRun)},
+		reportRow{"Timeout", fmt.Sprintf("%ds", cfg.Safety.TimeoutSeconds)},
+		reportRow{"Max blast radius", fmt.Sprintf("%.0f%%", cfg.Safety.MaxBlastRadius*100)},
+	)
+	if result.SafeMode {
+		v.Config = append(v.Config, reportRow{"Safe mode", "true"})
+	}
+
+	for _, phase := range reportPhases {
+		if seconds, ok := result.PhaseTimings[string(phase)]; ok {
+			v.Phases = append(v.Phases, reportRow{string(phase), fmt.Sprintf("%.3fs", seconds)})
+		}
+	}
+	v.SteadyState = reportRows(result.SteadyState)
+	v.Observations = reportRows(result.Observations)
+	v.Rollback = reportRows(result.RollbackResult)
+	v.AIInsights = reportRows(result.AIInsights)
+
+	for _, p := range probes {
+		v.Probes = append(v.Probes, reportProbe{
+			Name:   p.ProbeName,
+			Type:   p.ProbeType,
+			Mode:   p.Mode,
+			Passed: p.Passed,
+			At:     p.ExecutedAt.Time.UTC().Format(time.RFC3339),
+			Detail: probeFailureDetail(p.Detail),
+		})
+	}
+	return v
+}
+
+// targetNamespaceList returns the namespaces an experiment targets
+func targetNamespaceList(cfg domain.ExperimentConfig) []string {
+	if len(cfg.TargetNamespaces) > 0 {
+		return cfg.TargetNamespaces
+	}
+	if cfg.TargetNamespace != nil {
+		return []string{*cfg.TargetNamespace}
+	}
+	return nil
+}
+
+// reportRows turns a result section into rows sorted by key
+func reportRows(m map[string]any) []reportRow {
+	rows := make([]reportRow, 0, len(m))
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		rows = append(rows, reportRow{k, reportValue(m[k])})
+	}
+	return rows
+}
+
+// reportValue formats a value for a table cell; anything that is not a
+// plain string is rendered as compact JSON
+func reportValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(out)
+}
+
+// markdownCell keeps a value inside a single Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\n", " ")), " ")
+}
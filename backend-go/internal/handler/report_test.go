@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reportQuerier() *fakeQuerier {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := pgtype.Timestamptz{Time: started, Valid: true}
+	return &fakeQuerier{
+		experiments: map[string]db.Experiment{
+			"abc12345": {
+				ID:             "abc12345",
+				Config:         json.RawMessage(`{"name":"kill-web","chaos_type":"pod_delete","target_namespace":"shop","target_labels":{"app":"web"},"safety":{"timeout_seconds":120,"max_blast_radius":0.5}}`),
+				Status:         string(domain.StatusCompleted),
+				Phase:          string(domain.PhaseRollback),
+				StartedAt:      at,
+				CompletedAt:    pgtype.Timestamptz{Time: started.Add(90 * time.Second), Valid: true},
+				SteadyState:    json.RawMessage(`{"pods_healthy_ratio":1}`),
+				Hypothesis:     pgtype.Text{String: "web keeps serving <200ms>", Valid: true},
+				Observations:   json.RawMessage(`{"hold":{"polls":3}}`),
+				RollbackResult: json.RawMessage(`{"rollback_0":{"status":"success"}}`),
+				AiInsights:     json.RawMessage(`{"recovery_verification":"recovered | fully"}`),
+				PhaseTimings:   json.RawMessage(`{"inject":1.5,"steady_state":0.25}`),
+			},
+			"bare0001": {
+				ID:     "bare0001",
+				Config: json.RawMessage(`{"chaos_type":"pod_delete","safety":{}}`),
+				Status: string(domain.StatusFailed),
+				Phase:  string(domain.PhaseSteadyState),
+				Error:  pgtype.Text{String: "SOT probe web-up failed", Valid: true},
+			},
+		},
+		probes: []db.ProbeResult{
+			{ExperimentID: "abc12345", ProbeName: "web-up", ProbeType: "http", Mode: "sot", Passed: true,
+				Detail: json.RawMessage(`{"status_code":200}`), ExecutedAt: at},
+			{ExperimentID: "abc12345", ProbeName: "checkout", ProbeType: "http", Mode: "eot", Passed: false,
+				Detail: json.RawMessage(`{"error":"status 503"}`), ExecutedAt: at},
+		},
+	}
+}
+
+func getReport(t *testing.T, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	setupQuerierRouter(reportQuerier()).ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w
+}
+
+func TestExperimentReportMarkdown(t *testing.T) {
+	w := getReport(t, "/experiments/abc12345/report")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/markdown")
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"# Chaos experiment report: kill-web",
+		"| Status | completed |",
+		"| Duration | 1m30s |",
+		"## Configuration",
+		"| Target namespaces | shop |",
+		`| Target labels | {"app":"web"} |`,
+		"| Max blast radius | 50% |",
+		"## Phases",
+		"| steady_state | 0.250s |",
+		"| inject | 1.500s |",
+		"## Hypothesis\n\nweb keeps serving <200ms>",
+		"## Steady state",
+		"| pods_healthy_ratio | 1 |",
+		"## Observations",
+		`| hold | {"polls":3} |`,
+		"## Probes",
+		"| web-up | http | sot | passed |",
+		"| checkout | http | eot | failed | 2026-03-01T12:00:00Z | status 503 |",
+		"## Rollback",
+		`| rollback_0 | {"status":"success"} |`,
+		"## AI insights",
+		`| recovery_verification | recovered \| fully |`,
+	} {
+		assert.Contains(t, body, want)
+	}
+	// Phases are listed in the order they run, not alphabetically
+	assert.Less(t, strings.Index(body, "| steady_state |"), strings.Index(body, "| inject |"))
+}
+
+func TestExperimentReportMissingSections(t *testing.T) {
+	w := getReport(t, "/experiments/bare0001/report?format=md")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	body := w.Body.String()
+	assert.Contains(t, body, "# Chaos experiment report: bare0001")
+	assert.Contains(t, body, "> **Error:** SOT probe web-up failed")
+	assert.Contains(t, body, "No phase timings recorded.")
+	assert.Contains(t, body, "No hypothesis recorded.")
+	assert.Contains(t, body, "No steady state captured.")
+	assert.Contains(t, body, "No observations recorded.")
+	assert.Contains(t, body, "No probes ran.")
+	assert.Contains(t, body, "No rollback recorded.")
+	assert.NotContains(t, body, "## AI insights")
+	assert.NotContains(t, body, "| Duration |")
+}
+
+func TestExperimentReportHTML(t *testing.T) {
+	w := getReport(t, "/experiments/abc12345/report?format=html")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+
+	body := w.Body.String()
+	assert.Contains(t, body, "<h1>Chaos experiment report: kill-web</h1>")
+	assert.Contains(t, body, "<h2>Probes</h2>")
+	assert.Contains(t, body, `<td class="failed">failed</td>`)
+	assert.Contains(t, body, "<h2>AI insights</h2>")
+	// Stored values are escaped, not injected as markup
+	assert.Contains(t, body, "web keeps serving &lt;200ms&gt;")
+	assert.NotContains(t, body, "<200ms>")
+}
+
+func TestExperimentReportErrors(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, getReport(t, "/experiments/abc12345/report?format=pdf").Code)
+	assert.Equal(t, http.StatusNotFound, getReport(t, "/experiments/missing1/report").Code)
+}
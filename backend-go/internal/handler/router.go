@@ -14,46 +14,81 @@ func SetupRouter(
 	chaos *ChaosHandler,
 	topology *TopologyHandler,
 	analysis *AnalysisHandler,
+	health *HealthHandler,
 	esm *safety.EmergencyStopManager,
+	freezes *safety.NamespaceFreezeManager,
+	snapshots *safety.SnapshotManager,
 	metrics *observability.Metrics,
 	corsOrigin string,
+	maxBodyBytes int64,
 ) *gin.Engine {
 	r := gin.New()
 	r.MaxMultipartMemory = 1 << 20 // 1 MB max body
 	r.Use(gin.Recovery())
+	r.Use(RequestIDMiddleware())
 	r.Use(CORSMiddleware(corsOrigin))
 	r.Use(PrometheusMiddleware(metrics))
+	r.Use(GzipMiddleware(gzipMinSize))
 
-	// Health check
+	// Health checks: /health is a cheap liveness check, /health/ready probes
+	// dependencies
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status":         "healthy",
 			"emergency_stop": esm.IsTriggered(),
+			"safe_mode":      chaos.SafeMode(),
 		})
 	})
+	r.GET("/health/ready", health.Ready)
 
 	// Prometheus metrics
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// Emergency stop
-	r.POST("/emergency-stop", func(c *gin.Context) {
-		esm.Trigger()
-		c.JSON(http.StatusOK, gin.H{"status": "emergency_stop_triggered"})
-	})
-	r.POST("/emergency-stop/reset", func(c *gin.Context) {
-		esm.Reset()
-		c.JSON(http.StatusOK, gin.H{"status": "emergency_stop_reset"})
-	})
+	r.GET("/emergency-stop", EmergencyStopStatus(esm))
+	r.POST("/emergency-stop", TriggerEmergencyStop(esm))
+	r.POST("/emergency-stop/reset", ResetEmergencyStop(esm))
+
+	// Namespace freezes
+	safetyGroup := r.Group("/api/safety", BodyLimitMiddleware(maxBodyBytes))
+	{
+		safetyGroup.GET("/freeze", ListNamespaceFreezes(freezes))
+		safetyGroup.POST("/freeze", FreezeNamespace(freezes))
+		safetyGroup.DELETE("/freeze/:namespace", UnfreezeNamespace(freezes))
+	}
+
+	// API description
+	r.GET("/api/openapi.json", OpenAPISpec(r))
 
-	// Chaos endpoints
-	chaosGroup := r.Group("/api/chaos")
+	// Chaos endpoints. Experiment configs are a few KB, so their bodies are
+	// capped well below anything that could tie up the server.
+	chaosGroup := r.Group("/api/chaos", BodyLimitMiddleware(maxBodyBytes))
 	{
 		chaosGroup.POST("/experiments", chaos.CreateExperiment)
 		chaosGroup.GET("/experiments", chaos.ListExperiments)
+		chaosGroup.GET("/experiments/compare", chaos.CompareExperiments)
 		chaosGroup.GET("/experiments/:experiment_id", chaos.GetExperiment)
+		chaosGroup.PUT("/experiments/:experiment_id", chaos.UpdateExperimentConfig)
 		chaosGroup.POST("/experiments/:experiment_id/rollback", chaos.RollbackExperiment)
+		chaosGroup.POST("/experiments/:experiment_id/promote", chaos.PromoteDryRun)
+		chaosGroup.POST("/experiments/:experiment_id/rerun", chaos.RerunExperiment)
+		chaosGroup.GET("/experiments/:experiment_id/rollback-status", chaos.GetRollbackStatus)
 		chaosGroup.GET("/experiments/:experiment_id/stream", chaos.StreamExperiment)
+		chaosGroup.GET("/experiments/:experiment_id/probes", chaos.ListProbeResults)
+		chaosGroup.GET("/experiments/:experiment_id/events", chaos.ListExperimentEvents)
+		chaosGroup.GET("/experiments/:experiment_id/logs", chaos.ListExperimentPodLogs)
+		chaosGroup.GET("/experiments/:experiment_id/junit", chaos.ExperimentJUnit)
+		chaosGroup.GET("/experiments/:experiment_id/report", chaos.ExperimentReport)
+		chaosGroup.POST("/abort-all", chaos.AbortAll)
+		chaosGroup.GET("/snapshots", ListSnapshots(snapshots))
+		chaosGroup.GET("/snapshots/:experiment_id", GetSnapshot(snapshots))
 		chaosGroup.POST("/dry-run", chaos.DryRun)
+		chaosGroup.POST("/validate", chaos.ValidateExperiment)
+		chaosGroup.POST("/nl-run", NLRun(analysis, chaos))
+		chaosGroup.GET("/schema", ExperimentSchema())
+		chaosGroup.GET("/templates", chaos.ListTemplates)
+		chaosGroup.POST("/templates", chaos.CreateTemplate)
+		chaosGroup.POST("/templates/:name/run", chaos.RunTemplate)
 	}
 
 	// Topology endpoints
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// experimentConfigRoutes accept an ExperimentConfig request body
+var experimentConfigRoutes = map[string]bool{
+	"POST /api/chaos/experiments": true,
+	"POST /api/chaos/dry-run":     true,
+	"POST /api/chaos/validate":    true,
+}
+
+// ExperimentSchema serves the JSON Schema for ExperimentConfig
+func ExperimentSchema() gin.HandlerFunc {
+	schema := domain.ExperimentConfigSchema()
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, schema)
+	}
+}
+
+// OpenAPISpec serves a minimal OpenAPI 3.1 document listing every route
+// registered on r. Paths are read from the router when requested so the
+// document can't drift from the real routes.
+func OpenAPISpec(r *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, buildOpenAPI(r.Routes()))
+	}
+}
+
+func buildOpenAPI(routes gin.RoutesInfo) map[string]any {
+	paths := map[string]any{}
+	for _, rt := range routes {
+		path, params := openAPIPath(rt.Path)
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			item = map[string]any{}
+			paths[path] = item
+		}
+
+		op := map[string]any{
+			"responses": map[string]any{
+				"default": map[string]any{"description": "JSON response"},
+			},
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		if experimentConfigRoutes[rt.Method+" "+rt.Path] {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/json": map[string]any{
+						"schema": map[string]any{"$ref": "#/components/schemas/ExperimentConfig"},
+					},
+				},
+			}
+		}
+		item[strings.ToLower(rt.Method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info":    map[string]any{"title": "ChaosDuck API", "version": "1.0.0"},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": map[string]any{"ExperimentConfig": domain.ExperimentConfigSchema()},
+		},
+	}
+}
+
+// openAPIPath converts gin's ":param" segments to "{param}" and returns the
+// matching path parameter objects
+func openAPIPath(path string) (string, []any) {
+	segments := strings.Split(path, "/")
+	var params []any
+	for i, seg := range segments {
+		name, ok := strings.CutPrefix(seg, ":")
+		if !ok {
+			continue
+		}
+		segments[i] = "{" + name + "}"
+		params = append(params, map[string]any{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+	return strings.Join(segments, "/"), params
+}
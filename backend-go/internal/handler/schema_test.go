@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExperimentSchemaEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/chaos/schema", ExperimentSchema())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/chaos/schema", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &schema))
+	assert.Equal(t, "ExperimentConfig", schema["title"])
+	assert.Contains(t, schema["properties"], "chaos_type")
+}
+
+func TestOpenAPISpecListsRoutes(t *testing.T) {
+	r, h := setupTestRouter()
+	r.GET("/api/openapi.json", OpenAPISpec(r))
+	r.POST("/api/chaos/validate", h.ValidateExperiment)
+	r.GET("/api/chaos/experiments/:experiment_id", h.GetExperiment)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var spec struct {
+		OpenAPI string                               `json:"openapi"`
+		Paths   map[string]map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "3.1.0", spec.OpenAPI)
+
+	validate := spec.Paths["/api/chaos/validate"]["post"]
+	require.NotNil(t, validate)
+	assert.Contains(t, validate, "requestBody")
+
+	get := spec.Paths["/api/chaos/experiments/{experiment_id}"]["get"]
+	require.NotNil(t, get)
+	params := get["parameters"].([]any)
+	assert.Equal(t, "experiment_id", params[0].(map[string]any)["name"])
+	assert.Contains(t, spec.Paths, "/api/openapi.json")
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+)
+
+// snapshotSummary is a listed snapshot without its captured resources
+type snapshotSummary struct {
+	ExperimentID string `json:"experiment_id"`
+	Type         string `json:"type"`
+	Namespace    string `json:"namespace,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	CapturedAt   string `json:"captured_at"`
+}
+
+// ListSnapshots lists the snapshots held in memory, newest first. Each entry
+// names what was captured; the captured state itself is served by
+// GetSnapshot.
+func ListSnapshots(sm *safety.SnapshotManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		snapshots := sm.ListSnapshots()
+		summaries := make([]snapshotSummary, 0, len(snapshots))
+		for id, snap := range snapshots {
+			s := snapshotSummary{ExperimentID: id}
+			s.Type, _ = snap["type"].(string)
+			s.Namespace, _ = snap["namespace"].(string)
+			s.ResourceType, _ = snap["resource_type"].(string)
+			s.ResourceID, _ = snap["resource_id"].(string)
+			s.CapturedAt, _ = snap["captured_at"].(string)
+			summaries = append(summaries, s)
+		}
+		// captured_at is RFC 3339 in UTC, so it sorts as a string
+		slices.SortFunc(summaries, func(a, b snapshotSummary) int {
+			return cmp.Or(cmp.Compare(b.CapturedAt, a.CapturedAt), cmp.Compare(a.ExperimentID, b.ExperimentID))
+		})
+		c.JSON(http.StatusOK, gin.H{"snapshots": summaries})
+	}
+}
+
+// GetSnapshot returns the full snapshot captured before an experiment ran,
+// from memory or, after a restart, from the database
+func GetSnapshot(sm *safety.SnapshotManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		experimentID := c.Param("experiment_id")
+		snap, ok, err := sm.LoadSnapshot(c.Request.Context(), experimentID)
+		if err != nil {
+			respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, err.Error())
+			return
+		}
+		if !ok {
+			respondError(c, http.StatusNotFound, CodeSnapshotNotFound, fmt.Sprintf("no snapshot for experiment %s", experimentID))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"experiment_id": experimentID, "snapshot": snap})
+	}
+}
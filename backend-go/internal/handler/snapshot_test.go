@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotQuerier serves persisted snapshots by experiment ID
+type snapshotQuerier struct {
+	db.Querier
+	snapshots map[string]db.Snapshot
+}
+
+func (q *snapshotQuerier) GetSnapshot(_ context.Context, experimentID string) (db.Snapshot, error) {
+	s, ok := q.snapshots[experimentID]
+	if !ok {
+		return db.Snapshot{}, pgx.ErrNoRows
+	}
+	return s, nil
+}
+
+func setupSnapshotRouter(sm *safety.SnapshotManager) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/chaos/snapshots", ListSnapshots(sm))
+	r.GET("/api/chaos/snapshots/:experiment_id", GetSnapshot(sm))
+	return r
+}
+
+func TestListAndGetSnapshots(t *testing.T) {
+	ctx := context.Background()
+	sm := safety.NewSnapshotManager(nil)
+	_, err := sm.CaptureK8sSnapshot(ctx, "exp00001", "shop", map[string]any{"pods": []any{map[string]any{"name": "web-1"}}})
+	require.NoError(t, err)
+	_, err = sm.CaptureAWSSnapshot(ctx, "exp00002", "ec2", "i-123", map[string]any{"state": "running"})
+	require.NoError(t, err)
+	r := setupSnapshotRouter(sm)
+
+	code, resp := doEmergencyRequest(t, r, "GET", "/api/chaos/snapshots", "")
+	assert.Equal(t, http.StatusOK, code)
+	list := resp["snapshots"].([]any)
+	require.Len(t, list, 2)
+	byID := map[string]map[string]any{}
+	for _, s := range list {
+		entry := s.(map[string]any)
+		byID[entry["experiment_id"].(string)] = entry
+		assert.NotContains(t, entry, "resources", "listing leaves out the captured state")
+	}
+	assert.Equal(t, "shop", byID["exp00001"]["namespace"])
+	assert.Equal(t, "i-123", byID["exp00002"]["resource_id"])
+
+	code, resp = doEmergencyRequest(t, r, "GET", "/api/chaos/snapshots/exp00001", "")
+	assert.Equal(t, http.StatusOK, code)
+	snap := resp["snapshot"].(map[string]any)
+	assert.Equal(t, "k8s", snap["type"])
+	assert.Len(t, snap["resources"].(map[string]any)["pods"], 1)
+
+	code, resp = doEmergencyRequest(t, r, "GET", "/api/chaos/snapshots/missing1", "")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, CodeSnapshotNotFound, resp["error"].(map[string]any)["code"])
+}
+
+func TestGetSnapshotFallsBackToDatabase(t *testing.T) {
+	data, _ := json.Marshal(map[string]any{"type": "k8s", "namespace": "shop", "resources": map[string]any{}})
+	q := &snapshotQuerier{snapshots: map[string]db.Snapshot{
+		"exp00003": {ExperimentID: "exp00003", Type: "k8s", Data: data},
+	}}
+	r := setupSnapshotRouter(safety.NewSnapshotManager(q))
+
+	code, resp := doEmergencyRequest(t, r, "GET", "/api/chaos/snapshots/exp00003", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "shop", resp["snapshot"].(map[string]any)["namespace"])
+
+	code, _ = doEmergencyRequest(t, r, "GET", "/api/chaos/snapshots/missing1", "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// templateRequest stores a reusable experiment config under a name. The
+// config may leave out values (e.g. instance_ids) that each run supplies.
+type templateRequest struct {
+	Name        string          `json:"name" binding:"required,max=100"`
+	Description *string         `json:"description,omitempty"`
+	Config      json.RawMessage `json:"config" binding:"required"`
+}
+
+// CreateTemplate stores or replaces an experiment template
+func (h *ChaosHandler) CreateTemplate(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+
+	var req templateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	var cfg domain.ExperimentConfig
+	if err := json.Unmarshal(req.Config, &cfg); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidConfig, fmt.Sprintf("invalid config: %v", err))
+		return
+	}
+	if !domain.IsKnownChaosType(cfg.ChaosType) {
+		respondError(c, http.StatusBadRequest, CodeUnknownChaosType, fmt.Sprintf("unknown chaos type %q", cfg.ChaosType))
+		return
+	}
+
+	var description pgtype.Text
+	if req.Description != nil {
+		description = pgtype.Text{String: *req.Description, Valid: true}
+	}
+	tmpl, err := h.queries.UpsertTemplate(c.Request.Context(), db.UpsertTemplateParams{
+		Name:        req.Name,
+		Config:      req.Config,
+		Description: description,
+	})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tmpl)
+}
+
+// ListTemplates returns all stored templates
+func (h *ChaosHandler) ListTemplates(c *gin.Context) {
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+
+	templates, err := h.queries.ListTemplates(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// RunTemplate instantiates a template with the overrides in the request body,
+// validates the merged config and runs it
+func (h *ChaosHandler) RunTemplate(c *gin.Context) {
+	if h.esm.IsTriggered() {
+		respondError(c, http.StatusServiceUnavailable, CodeEmergencyStop, "Emergency stop is active")
+		return
+	}
+	if h.queries == nil {
+		respondError(c, http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database not available")
+		return
+	}
+
+	var overrides map[string]any
+	if err := json.NewDecoder(c.Request.Body).Decode(&overrides); err != nil && !errors.Is(err, io.EOF) {
+		respondBindError(c, fmt.Errorf("invalid JSON: %w", err))
+		return
+	}
+
+	name := c.Param("name")
+	tmpl, err := h.queries.GetTemplate(c.Request.Context(), name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(c, http.StatusNotFound, CodeTemplateNotFound, fmt.Sprintf("Template %s not found", name))
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+
+	cfg, err := instantiateTemplate(tmpl.Config, overrides)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidConfig, err.Error())
+		return
+	}
+	applySafetyDefaults(&cfg)
+
+	if errs := domain.ValidateConfig(cfg); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, validateResponse{Valid: false, Errors: errs})
+		return
+	}
+
+	h.runExperiment(c, cfg, nil)
+}
+
+// instantiateTemplate decodes a stored template config and overlays overrides
+func instantiateTemplate(stored json.RawMessage, overrides map[string]any) (domain.ExperimentConfig, error) {
+	var base domain.ExperimentConfig
+	if err := json.Unmarshal(stored, &base); err != nil {
+		return domain.ExperimentConfig{}, fmt.Errorf("decode template: %w", err)
+	}
+	return domain.MergeConfig(base, overrides)
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/observability"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstantiateTemplate(t *testing.T) {
+	stored := json.RawMessage(`{"name":"blackhole-db","chaos_type":"route_blackhole",
+		"parameters":{"destination_cidr":"10.0.0.0/16"},
+		"safety":{"timeout_seconds":30,"max_blast_radius":0.3,"health_check_interval":10,"health_check_failure_threshold":3}}`)
+
+	cfg, err := instantiateTemplate(stored, nil)
+	require.NoError(t, err)
+	errs := domain.ValidateConfig(cfg)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "parameters.route_table_id", errs[0].Field)
+
+	cfg, err = instantiateTemplate(stored, map[string]any{
+		"parameters": map[string]any{"route_table_id": "rtb-123"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, domain.ValidateConfig(cfg))
+	assert.Equal(t, "10.0.0.0/16", cfg.Parameters["destination_cidr"])
+}
+
+func TestTemplates_NoDB(t *testing.T) {
+	r, h := setupTestRouter()
+	r.GET("/templates", h.ListTemplates)
+	r.POST("/templates", h.CreateTemplate)
+	r.POST("/templates/:name/run", h.RunTemplate)
+
+	for _, tc := range []struct{ method, path, body string }{
+		{"GET", "/templates", ""},
+		{"POST", "/templates", `{"name":"t","config":{"name":"t","chaos_type":"pod_delete"}}`},
+		{"POST", "/templates/t/run", `{}`},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, tc.path)
+		assert.Contains(t, w.Body.String(), `"code":"database_unavailable"`, tc.path)
+	}
+}
+
+func TestRunTemplate_EmergencyStop(t *testing.T) {
+	r, h := setupTestRouter()
+	r.POST("/templates/:name/run", h.RunTemplate)
+	h.esm.Trigger()
+
+	req := httptest.NewRequest("POST", "/templates/t/run", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "Emergency stop")
+	assert.Contains(t, w.Body.String(), `"code":"emergency_stop_active"`)
+}
+
+// templateQuerier serves GetTemplate from memory
+type templateQuerier struct {
+	fakeQuerier
+	templates map[string]db.Template
+}
+
+func (q *templateQuerier) GetTemplate(_ context.Context, name string) (db.Template, error) {
+	tmpl, ok := q.templates[name]
+	if !ok {
+		return db.Template{}, pgx.ErrNoRows
+	}
+	return tmpl, nil
+}
+
+func TestTemplateErrorCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	q := &templateQuerier{templates: map[string]db.Template{
+		"broken": {Name: "broken", Config: json.RawMessage(`{"name":"broken","chaos_type":"pod_delete"}`)},
+	}}
+	metrics := observability.NewMetricsWithRegistry(prometheus.NewRegistry())
+	h := NewChaosHandler(&stubRunner{}, q, safety.NewEmergencyStopManager(), safety.NewRollbackManager(), metrics)
+	r := gin.New()
+	r.POST("/templates", h.CreateTemplate)
+	r.POST("/templates/:name/run", h.RunTemplate)
+
+	for _, tc := range []struct {
+		path, body string
+		status     int
+		code       string
+	}{
+		{"/templates", `{"name":"t","config":[]}`, http.StatusBadRequest, CodeInvalidConfig},
+		{"/templates", `{"name":"t","config":{"name":"t","chaos_type":"nope"}}`, http.StatusBadRequest, CodeUnknownChaosType},
+		{"/templates/missing/run", `{}`, http.StatusNotFound, CodeTemplateNotFound},
+		{"/templates/broken/run", `{"parameters":"not-an-object"}`, http.StatusBadRequest, CodeInvalidConfig},
+	} {
+		req := httptest.NewRequest("POST", tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, tc.body)
+		assert.Contains(t, w.Body.String(), `"code":"`+tc.code+`"`, tc.body)
+	}
+}
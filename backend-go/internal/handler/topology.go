@@ -1,34 +1,140 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/chaosduck/backend-go/internal/domain"
 	"github.com/chaosduck/backend-go/internal/engine"
 	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+// maxCachedTopologies bounds how many scans a TopologyHandler caches
+const maxCachedTopologies = 256
+
 // TopologyHandler handles topology discovery endpoints
 type TopologyHandler struct {
 	k8s *engine.K8sEngine
 	aws *engine.AwsEngine
+
+	cacheTTL time.Duration
+	maxCache int
+	now      func() time.Time
+	mu       sync.Mutex
+	cache    map[string]cachedTopology
 }
 
-// NewTopologyHandler creates a new TopologyHandler
-func NewTopologyHandler(k8s *engine.K8sEngine, aws *engine.AwsEngine) *TopologyHandler {
-	return &TopologyHandler{k8s: k8s, aws: aws}
+type cachedTopology struct {
+	topo      *domain.InfraTopology
+	expiresAt time.Time
+}
+
+// NewTopologyHandler creates a new TopologyHandler. Scans are cached for
+// cacheTTL; a zero TTL disables caching. Once the cache is full, expired
+// scans are pruned and then the oldest is evicted.
+func NewTopologyHandler(k8s *engine.K8sEngine, aws *engine.AwsEngine, cacheTTL time.Duration) *TopologyHandler {
+	return &TopologyHandler{
+		k8s:      k8s,
+		aws:      aws,
+		cacheTTL: cacheTTL,
+		maxCache: maxCachedTopologies,
+		now:      time.Now,
+		cache:    make(map[string]cachedTopology),
+	}
+}
+
+// cachedScan returns the cached topology for key, or runs scan and caches
+// the result. refresh forces a new scan.
+func (h *TopologyHandler) cachedScan(key string, refresh bool, scan func() (*domain.InfraTopology, error)) (*domain.InfraTopology, error) {
+	h.mu.Lock()
+	entry, ok := h.cache[key]
+	h.mu.Unlock()
+	if ok && !refresh && h.now().Before(entry.expiresAt) {
+		return entry.topo, nil
+	}
+
+	topo, err := scan()
+	if err != nil {
+		return nil, err
+	}
+	if h.cacheTTL > 0 {
+		h.mu.Lock()
+		h.store(key, topo)
+		h.mu.Unlock()
+	}
+	return topo, nil
+}
+
+// store caches topo under key, making room first when the cache is full.
+// Callers hold h.mu.
+func (h *TopologyHandler) store(key string, topo *domain.InfraTopology) {
+	now := h.now()
+	if _, ok := h.cache[key]; !ok && len(h.cache) >= h.maxCache {
+		for k, e := range h.cache {
+			if !now.Before(e.expiresAt) {
+				delete(h.cache, k)
+			}
+		}
+		// Every entry shares the TTL, so the one expiring first is the oldest
+		for len(h.cache) >= h.maxCache {
+			oldest, oldestAt := "", time.Time{}
+			for k, e := range h.cache {
+				if oldest == "" || e.expiresAt.Before(oldestAt) {
+					oldest, oldestAt = k, e.expiresAt
+				}
+			}
+			delete(h.cache, oldest)
+		}
+	}
+	h.cache[key] = cachedTopology{topo: topo, expiresAt: now.Add(h.cacheTTL)}
+}
+
+// topologyNamespace reads ?namespace= (default "default"; empty scans every
+// namespace) and rejects values that cannot name a namespace, so arbitrary
+// strings never become cache keys
+func topologyNamespace(c *gin.Context) (string, bool) {
+	namespace := c.DefaultQuery("namespace", "default")
+	if namespace == "" {
+		return namespace, true
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest,
+			fmt.Sprintf("invalid namespace %q: %s", namespace, strings.Join(errs, "; ")))
+		return "", false
+	}
+	return namespace, true
+}
+
+func (h *TopologyHandler) k8sTopology(ctx context.Context, namespace string, refresh bool) (*domain.InfraTopology, error) {
+	return h.cachedScan("k8s/"+namespace, refresh, func() (*domain.InfraTopology, error) {
+		return h.k8s.GetTopology(ctx, namespace)
+	})
+}
+
+func (h *TopologyHandler) awsTopology(ctx context.Context, refresh bool) (*domain.InfraTopology, error) {
+	return h.cachedScan("aws", refresh, func() (*domain.InfraTopology, error) {
+		return h.aws.GetTopology(ctx)
+	})
 }
 
 // GetK8sTopology returns Kubernetes resource topology
 func (h *TopologyHandler) GetK8sTopology(c *gin.Context) {
-	namespace := c.DefaultQuery("namespace", "default")
+	namespace, ok := topologyNamespace(c)
+	if !ok {
+		return
+	}
 
 	if h.k8s == nil {
 		c.JSON(http.StatusOK, domain.InfraTopology{Nodes: []domain.TopologyNode{}, Edges: []domain.TopologyEdge{}})
 		return
 	}
 
-	topo, err := h.k8s.GetTopology(c.Request.Context(), namespace)
+	topo, err := h.k8sTopology(c.Request.Context(), namespace, c.Query("refresh") == "true")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"detail": err.Error()})
 		return
@@ -43,7 +149,7 @@ func (h *TopologyHandler) GetAWSTopology(c *gin.Context) {
 		return
 	}
 
-	topo, err := h.aws.GetTopology(c.Request.Context())
+	topo, err := h.awsTopology(c.Request.Context(), c.Query("refresh") == "true")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"detail": err.Error()})
 		return
@@ -51,31 +157,44 @@ func (h *TopologyHandler) GetAWSTopology(c *gin.Context) {
 	c.JSON(http.StatusOK, topo)
 }
 
-// GetCombinedTopology returns combined K8s + AWS topology
+// GetCombinedTopology returns combined K8s + AWS topology, linked by
+// node-to-instance runs_on edges. Its timestamp is the older of the two scans.
 func (h *TopologyHandler) GetCombinedTopology(c *gin.Context) {
-	namespace := c.DefaultQuery("namespace", "default")
+	namespace, ok := topologyNamespace(c)
+	if !ok {
+		return
+	}
+	refresh := c.Query("refresh") == "true"
 
 	combined := domain.InfraTopology{
 		Nodes: make([]domain.TopologyNode, 0),
 		Edges: make([]domain.TopologyEdge, 0),
 	}
 
+	var k8sTopo, awsTopo *domain.InfraTopology
 	if h.k8s != nil {
-		k8sTopo, err := h.k8s.GetTopology(c.Request.Context(), namespace)
+		var err error
+		k8sTopo, err = h.k8sTopology(c.Request.Context(), namespace, refresh)
 		if err == nil {
 			combined.Nodes = append(combined.Nodes, k8sTopo.Nodes...)
 			combined.Edges = append(combined.Edges, k8sTopo.Edges...)
+			combined.Timestamp = k8sTopo.Timestamp
 		}
 	}
 
 	if h.aws != nil {
-		awsTopo, err := h.aws.GetTopology(c.Request.Context())
+		var err error
+		awsTopo, err = h.awsTopology(c.Request.Context(), refresh)
 		if err == nil {
 			combined.Nodes = append(combined.Nodes, awsTopo.Nodes...)
 			combined.Edges = append(combined.Edges, awsTopo.Edges...)
+			combined.Timestamp = domain.OlderTimestamp(combined.Timestamp, awsTopo.Timestamp)
 		}
 	}
 
+	// Cross-layer edges: K8s nodes to the EC2 instances they run on
+	combined.Edges = append(combined.Edges, domain.LinkNodesToInstances(k8sTopo, awsTopo)...)
+
 	c.JSON(http.StatusOK, combined)
 }
 
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/chaosduck/backend-go/internal/engine"
+	"github.com/chaosduck/backend-go/internal/safety"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func setupTopologyRouter(ttl time.Duration) (*gin.Engine, *TopologyHandler, *fake.Clientset) {
+	gin.SetMode(gin.TestMode)
+	cs := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	})
+	h := NewTopologyHandler(engine.NewK8sEngineWithClientset(cs, safety.NewEmergencyStopManager()), nil, ttl)
+	r := gin.New()
+	r.GET("/api/topology/k8s", h.GetK8sTopology)
+	r.GET("/api/topology/combined", h.GetCombinedTopology)
+	return r, h, cs
+}
+
+// podLists counts how many times the handler scanned pods
+func podLists(cs *fake.Clientset) int {
+	n := 0
+	for _, a := range cs.Actions() {
+		if a.GetVerb() == "list" && a.GetResource().Resource == "pods" {
+			n++
+		}
+	}
+	return n
+}
+
+func getTopology(t *testing.T, r *gin.Engine, path string) domain.InfraTopology {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var topo domain.InfraTopology
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &topo))
+	return topo
+}
+
+func TestTopologyCacheHit(t *testing.T) {
+	r, _, cs := setupTopologyRouter(time.Minute)
+
+	first := getTopology(t, r, "/api/topology/k8s")
+	second := getTopology(t, r, "/api/topology/k8s")
+
+	assert.Equal(t, 1, podLists(cs))
+	require.NotNil(t, first.Timestamp)
+	assert.Equal(t, first.Timestamp, second.Timestamp)
+	assert.NotEmpty(t, first.Nodes)
+}
+
+func TestTopologyCacheMissAfterExpiry(t *testing.T) {
+	r, h, cs := setupTopologyRouter(time.Minute)
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	getTopology(t, r, "/api/topology/k8s")
+	now = now.Add(2 * time.Minute)
+	getTopology(t, r, "/api/topology/k8s")
+	// A different namespace is a separate cache entry
+	getTopology(t, r, "/api/topology/k8s?namespace=other")
+
+	assert.Equal(t, 3, podLists(cs))
+}
+
+func TestTopologyForcedRefresh(t *testing.T) {
+	r, _, cs := setupTopologyRouter(time.Minute)
+
+	getTopology(t, r, "/api/topology/k8s")
+	getTopology(t, r, "/api/topology/k8s?refresh=true")
+	getTopology(t, r, "/api/topology/combined")
+
+	assert.Equal(t, 2, podLists(cs))
+}
+
+func TestTopologyCacheIsBounded(t *testing.T) {
+	r, h, cs := setupTopologyRouter(time.Minute)
+	h.maxCache = 2
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	for _, ns := range []string{"one", "two", "three"} {
+		getTopology(t, r, "/api/topology/k8s?namespace="+ns)
+		now = now.Add(time.Second)
+	}
+	assert.Len(t, h.cache, 2)
+	assert.NotContains(t, h.cache, "k8s/one", "the oldest scan is evicted")
+
+	// Expired scans are pruned before anything live is evicted
+	now = now.Add(time.Minute)
+	getTopology(t, r, "/api/topology/k8s?namespace=four")
+	assert.Len(t, h.cache, 1)
+	assert.Equal(t, 4, podLists(cs))
+}
+
+func TestTopologyRejectsInvalidNamespace(t *testing.T) {
+	r, h, cs := setupTopologyRouter(time.Minute)
+
+	for _, path := range []string{
+		"/api/topology/k8s?namespace=Not_A_Namespace",
+		"/api/topology/combined?namespace=" + strings.Repeat("a", 64),
+		"/api/topology/k8s?namespace=..%2Fkube-system",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		assert.Contains(t, w.Body.String(), `"code":"invalid_request"`, path)
+	}
+	assert.Empty(t, h.cache)
+	assert.Zero(t, podLists(cs))
+}
+
+func TestCombinedTopologyTimestamp(t *testing.T) {
+	r, _, _ := setupTopologyRouter(time.Minute)
+
+	k8s := getTopology(t, r, "/api/topology/k8s")
+	combined := getTopology(t, r, "/api/topology/combined")
+
+	assert.Equal(t, k8s.Timestamp, combined.Timestamp)
+}
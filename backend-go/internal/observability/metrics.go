@@ -1,7 +1,11 @@
 package observability
 
 import (
+	"slices"
+	"strconv"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
@@ -10,51 +14,106 @@ type Metrics struct {
 	ExperimentsTotal          *prometheus.CounterVec
 	ExperimentDurationSeconds prometheus.Histogram
 	ActiveExperiments         prometheus.Gauge
+	PhaseDurationSeconds      *prometheus.HistogramVec
 	ProbeResultsTotal         *prometheus.CounterVec
 	RollbackTotal             *prometheus.CounterVec
 	HTTPRequestsTotal         *prometheus.CounterVec
 	HTTPRequestDuration       *prometheus.HistogramVec
+	AnalysisSeverityTotal     *prometheus.CounterVec
+	ResilienceScore           prometheus.Histogram
+	SafetyBlocksTotal         *prometheus.CounterVec
+}
+
+// analysisSeverities are the severities the AI service reports; anything
+// else is counted as "unknown" to keep the label set bounded
+var analysisSeverities = []string{"SEV1", "SEV2", "SEV3", "SEV4"}
+
+// DefaultExperimentDurationBuckets are the chaosduck_experiment_duration_seconds
+// bucket boundaries used when none are configured
+var DefaultExperimentDurationBuckets = []float64{1, 5, 10, 30, 60, 120}
+
+// NewMetrics registers and returns all metrics on the default registry.
+// durationBuckets sets the experiment duration histogram's boundaries;
+// nil uses DefaultExperimentDurationBuckets.
+func NewMetrics(durationBuckets []float64) *Metrics {
+	// The default registry ships a Go collector with only the basic go_*
+	// series; it is replaced by the fuller one NewMetricsWithRegistry adds
+	prometheus.Unregister(collectors.NewGoCollector())
+	return NewMetricsWithRegistry(prometheus.DefaultRegisterer, durationBuckets...)
 }
 
-// NewMetrics registers and returns all metrics
-func NewMetrics() *Metrics {
+// NewMetricsWithRegistry registers all metrics on reg, letting tests use an
+// isolated registry. Go runtime metrics (goroutines, heap, GC, scheduler) are
+// registered alongside, so leaked goroutines show up on /metrics. Without
+// durationBuckets the experiment duration histogram uses
+// DefaultExperimentDurationBuckets; they must be sorted ascending.
+func NewMetricsWithRegistry(reg prometheus.Registerer, durationBuckets ...float64) *Metrics {
+	if len(durationBuckets) == 0 {
+		durationBuckets = DefaultExperimentDurationBuckets
+	}
+	reg.MustRegister(collectors.NewGoCollector(
+		collectors.WithGoCollectorRuntimeMetrics(collectors.MetricsGC, collectors.MetricsMemory, collectors.MetricsScheduler),
+	))
+	f := promauto.With(reg)
 	return &Metrics{
-		ExperimentsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+		ExperimentsTotal: f.NewCounterVec(prometheus.CounterOpts{
 			Name: "chaosduck_experiments_total",
 			Help: "Total number of chaos experiments",
 		}, []string{"chaos_type", "status"}),
 
-		ExperimentDurationSeconds: promauto.NewHistogram(prometheus.HistogramOpts{
+		ExperimentDurationSeconds: f.NewHistogram(prometheus.HistogramOpts{
 			Name:    "chaosduck_experiment_duration_seconds",
 			Help:    "Duration of chaos experiments in seconds",
-			Buckets: []float64{1, 5, 10, 30, 60, 120},
+			Buckets: durationBuckets,
 		}),
 
-		ActiveExperiments: promauto.NewGauge(prometheus.GaugeOpts{
+		ActiveExperiments: f.NewGauge(prometheus.GaugeOpts{
 			Name: "chaosduck_active_experiments",
 			Help: "Number of currently running experiments",
 		}),
 
-		ProbeResultsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+		PhaseDurationSeconds: f.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "chaosduck_phase_duration_seconds",
+			Help:    "Duration of each experiment lifecycle phase in seconds",
+			Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120},
+		}, []string{"phase"}),
+
+		ProbeResultsTotal: f.NewCounterVec(prometheus.CounterOpts{
 			Name: "chaosduck_probe_results",
 			Help: "Total probe execution results",
 		}, []string{"probe_type", "passed"}),
 
-		RollbackTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+		RollbackTotal: f.NewCounterVec(prometheus.CounterOpts{
 			Name: "chaosduck_rollback_total",
 			Help: "Total number of rollbacks",
 		}, []string{"status"}),
 
-		HTTPRequestsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+		HTTPRequestsTotal: f.NewCounterVec(prometheus.CounterOpts{
 			Name: "chaosduck_http_requests_total",
 			Help: "Total HTTP requests",
 		}, []string{"method", "path", "status_code"}),
 
-		HTTPRequestDuration: promauto.NewHistogramVec(prometheus.HistogramOpts{
+		HTTPRequestDuration: f.NewHistogramVec(prometheus.HistogramOpts{
 			Name:    "chaosduck_http_request_duration_seconds",
 			Help:    "HTTP request duration in seconds",
 			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1.0, 5.0},
 		}, []string{"method", "path"}),
+
+		AnalysisSeverityTotal: f.NewCounterVec(prometheus.CounterOpts{
+			Name: "chaosduck_analysis_severity_total",
+			Help: "Total stored AI analyses by severity",
+		}, []string{"severity"}),
+
+		ResilienceScore: f.NewHistogram(prometheus.HistogramOpts{
+			Name:    "chaosduck_resilience_score",
+			Help:    "Resilience score (0-100) of stored AI analyses",
+			Buckets: []float64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100},
+		}),
+
+		SafetyBlocksTotal: f.NewCounterVec(prometheus.CounterOpts{
+			Name: "chaosduck_safety_blocks_total",
+			Help: "Total experiments stopped by a safety guardrail, by reason",
+		}, []string{"reason"}),
 	}
 }
 
@@ -70,7 +129,32 @@ func (m *Metrics) RecordExperimentEnd(chaosType, status string, duration float64
 	m.ExperimentDurationSeconds.Observe(duration)
 }
 
+// RecordPhaseDuration observes how long an experiment phase took
+func (m *Metrics) RecordPhaseDuration(phase string, seconds float64) {
+	m.PhaseDurationSeconds.WithLabelValues(phase).Observe(seconds)
+}
+
 // RecordRollback records a rollback event
 func (m *Metrics) RecordRollback(status string) {
 	m.RollbackTotal.WithLabelValues(status).Inc()
 }
+
+// RecordProbeResult counts an executed probe by type and outcome
+func (m *Metrics) RecordProbeResult(probeType string, passed bool) {
+	m.ProbeResultsTotal.WithLabelValues(probeType, strconv.FormatBool(passed)).Inc()
+}
+
+// RecordAnalysis counts a stored AI analysis by severity and observes its
+// resilience score
+func (m *Metrics) RecordAnalysis(severity string, resilienceScore float64) {
+	if !slices.Contains(analysisSeverities, severity) {
+		severity = "unknown"
+	}
+	m.AnalysisSeverityTotal.WithLabelValues(severity).Inc()
+	m.ResilienceScore.Observe(resilienceScore)
+}
+
+// RecordSafetyBlock counts an experiment a safety guardrail stopped
+func (m *Metrics) RecordSafetyBlock(reason string) {
+	m.SafetyBlocksTotal.WithLabelValues(reason).Inc()
+}
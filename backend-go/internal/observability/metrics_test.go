@@ -4,50 +4,13 @@ import (
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newTestMetrics(reg *prometheus.Registry) *Metrics {
-	f := promauto.With(reg)
-	return &Metrics{
-		ExperimentsTotal: f.NewCounterVec(prometheus.CounterOpts{
-			Name: "chaosduck_experiments_total",
-			Help: "Total number of chaos experiments",
-		}, []string{"chaos_type", "status"}),
-
-		ExperimentDurationSeconds: f.NewHistogram(prometheus.HistogramOpts{
-			Name:    "chaosduck_experiment_duration_seconds",
-			Help:    "Duration of chaos experiments in seconds",
-			Buckets: []float64{1, 5, 10, 30, 60, 120},
-		}),
-
-		ActiveExperiments: f.NewGauge(prometheus.GaugeOpts{
-			Name: "chaosduck_active_experiments",
-			Help: "Number of currently running experiments",
-		}),
-
-		ProbeResultsTotal: f.NewCounterVec(prometheus.CounterOpts{
-			Name: "chaosduck_probe_results",
-			Help: "Total probe execution results",
-		}, []string{"probe_type", "passed"}),
-
-		RollbackTotal: f.NewCounterVec(prometheus.CounterOpts{
-			Name: "chaosduck_rollback_total",
-			Help: "Total number of rollbacks",
-		}, []string{"status"}),
-
-		HTTPRequestsTotal: f.NewCounterVec(prometheus.CounterOpts{
-			Name: "chaosduck_http_requests_total",
-			Help: "Total HTTP requests",
-		}, []string{"method", "path", "status_code"}),
-
-		HTTPRequestDuration: f.NewHistogramVec(prometheus.HistogramOpts{
-			Name:    "chaosduck_http_request_duration_seconds",
-			Help:    "HTTP request duration in seconds",
-			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1.0, 5.0},
-		}, []string{"method", "path"}),
-	}
+	return NewMetricsWithRegistry(reg)
 }
 
 func TestNewMetricsFields(t *testing.T) {
@@ -61,6 +24,8 @@ func TestNewMetricsFields(t *testing.T) {
 	assert.NotNil(t, m.RollbackTotal)
 	assert.NotNil(t, m.HTTPRequestsTotal)
 	assert.NotNil(t, m.HTTPRequestDuration)
+	assert.NotNil(t, m.AnalysisSeverityTotal)
+	assert.NotNil(t, m.ResilienceScore)
 }
 
 func TestRecordExperimentLifecycle(t *testing.T) {
@@ -80,3 +45,91 @@ func TestRecordRollback(t *testing.T) {
 	m.RecordRollback("success")
 	m.RecordRollback("failed")
 }
+
+func TestRecordProbeResult(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := newTestMetrics(reg)
+
+	m.RecordProbeResult("http", true)
+	m.RecordProbeResult("http", true)
+	m.RecordProbeResult("k8s", false)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	counts := map[string]float64{}
+	for _, mf := range families {
+		if mf.GetName() != "chaosduck_probe_results" {
+			continue
+		}
+		for _, metric := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, lp := range metric.GetLabel() {
+				labels[lp.GetName()] = lp.GetValue()
+			}
+			counts[labels["probe_type"]+"/"+labels["passed"]] = metric.GetCounter().GetValue()
+		}
+	}
+	assert.Equal(t, 2.0, counts["http/true"])
+	assert.Equal(t, 1.0, counts["k8s/false"])
+}
+
+func TestRecordAnalysisBoundsSeverity(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := newTestMetrics(reg)
+
+	m.RecordAnalysis("SEV1", 35)
+	m.RecordAnalysis("catastrophic!!", 80)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.AnalysisSeverityTotal.WithLabelValues("SEV1")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.AnalysisSeverityTotal.WithLabelValues("unknown")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.AnalysisSeverityTotal))
+}
+
+func TestGoRuntimeMetricsRegistered(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	newTestMetrics(reg)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	names := map[string]bool{}
+	for _, mf := range families {
+		names[mf.GetName()] = true
+	}
+	assert.True(t, names["go_goroutines"])
+	assert.True(t, names["go_memstats_heap_alloc_bytes"])
+	assert.True(t, names["go_gc_duration_seconds"])
+}
+
+// durationBucketBounds returns the upper bounds of the experiment duration
+// histogram registered on reg
+func durationBucketBounds(t *testing.T, reg *prometheus.Registry) []float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, mf := range families {
+		if mf.GetName() != "chaosduck_experiment_duration_seconds" {
+			continue
+		}
+		var bounds []float64
+		for _, b := range mf.GetMetric()[0].GetHistogram().GetBucket() {
+			bounds = append(bounds, b.GetUpperBound())
+		}
+		return bounds
+	}
+	t.Fatal("experiment duration histogram not registered")
+	return nil
+}
+
+func TestExperimentDurationBuckets(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := newTestMetrics(reg)
+	m.RecordExperimentEnd("pod_delete", "completed", 5.0)
+	assert.Equal(t, DefaultExperimentDurationBuckets, durationBucketBounds(t, reg))
+
+	reg = prometheus.NewRegistry()
+	m = NewMetricsWithRegistry(reg, 0.5, 1, 90, 300)
+	m.RecordExperimentEnd("pod_delete", "completed", 95.0)
+	assert.Equal(t, []float64{0.5, 1, 90, 300}, durationBucketBounds(t, reg))
+}
@@ -0,0 +1,32 @@
+package observability
+
+import (
+	"context"
+	"log"
+)
+
+// RequestIDHeader carries a request's ID from the client through the API to
+// the AI service and back in the response
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Logf logs like log.Printf, prefixed with the request ID carried by ctx so
+// the line can be correlated with the request that caused it
+func Logf(ctx context.Context, format string, args ...any) {
+	if id := RequestID(ctx); id != "" {
+		format = "[request " + id + "] " + format
+	}
+	log.Printf(format, args...)
+}
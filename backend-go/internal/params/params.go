@@ -0,0 +1,170 @@
+// Package params provides typed accessors for the free-form chaos
+// parameters map decoded from experiment configs.
+package params
+
+import (
+	"fmt"
+	"math"
+)
+
+// Error describes an invalid or missing parameter
+type Error struct {
+	Key     string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Key + " " + e.Message
+}
+
+func invalid(key, format string, args ...any) error {
+	return &Error{Key: key, Message: fmt.Sprintf(format, args...)}
+}
+
+// GetIntInRange returns an integer parameter, def when absent, and an error
+// when the value is not a whole number or falls outside [min, max]
+func GetIntInRange(params map[string]any, key string, def, min, max int) (int, error) {
+	v, ok := params[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	var n int
+	switch val := v.(type) {
+	case float64:
+		if val != math.Trunc(val) {
+			return 0, invalid(key, "must be an integer, got %v", val)
+		}
+		n = int(val)
+	case int:
+		n = val
+	default:
+		return 0, invalid(key, "must be a number, got %T", v)
+	}
+	if n < min || n > max {
+		return 0, invalid(key, "must be %d-%d, got %d", min, max, n)
+	}
+	return n, nil
+}
+
+// GetFloatInRange returns a numeric parameter, def when absent, and an error
+// when the value is not a number or falls outside [min, max]
+func GetFloatInRange(params map[string]any, key string, def, min, max float64) (float64, error) {
+	v, ok := params[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	var f float64
+	switch val := v.(type) {
+	case float64:
+		f = val
+	case int:
+		f = float64(val)
+	default:
+		return 0, invalid(key, "must be a number, got %T", v)
+	}
+	if f < min || f > max {
+		return 0, invalid(key, "must be %g-%g, got %g", min, max, f)
+	}
+	return f, nil
+}
+
+// GetString returns a string parameter, def when absent, and an error when
+// the value is not a string
+func GetString(params map[string]any, key, def string) (string, error) {
+	v, ok := params[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", invalid(key, "must be a string, got %T", v)
+	}
+	return s, nil
+}
+
+// GetBool returns a boolean parameter, def when absent, and an error when the
+// value is not a boolean
+func GetBool(params map[string]any, key string, def bool) (bool, error) {
+	v, ok := params[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, invalid(key, "must be a boolean, got %T", v)
+	}
+	return b, nil
+}
+
+// GetStringRequired returns a non-empty string parameter
+func GetStringRequired(params map[string]any, key string) (string, error) {
+	s, err := GetString(params, key, "")
+	if err != nil {
+		return "", err
+	}
+	if s == "" {
+		return "", invalid(key, "is required")
+	}
+	return s, nil
+}
+
+// GetStringSlice returns a list-of-strings parameter, nil when absent
+func GetStringSlice(params map[string]any, key string) ([]string, error) {
+	v, ok := params[key]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	switch val := v.(type) {
+	case []string:
+		return val, nil
+	case []any:
+		result := make([]string, 0, len(val))
+		for i, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, invalid(key, "item %d must be a string, got %T", i, item)
+			}
+			result = append(result, s)
+		}
+		return result, nil
+	default:
+		return nil, invalid(key, "must be a list of strings, got %T", v)
+	}
+}
+
+// GetStringSliceRequired returns a non-empty list-of-strings parameter
+func GetStringSliceRequired(params map[string]any, key string) ([]string, error) {
+	s, err := GetStringSlice(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(s) == 0 {
+		return nil, invalid(key, "is required")
+	}
+	return s, nil
+}
+
+// GetStringMap returns an object parameter whose values are all strings,
+// nil when absent
+func GetStringMap(params map[string]any, key string) (map[string]string, error) {
+	v, ok := params[key]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	switch val := v.(type) {
+	case map[string]string:
+		return val, nil
+	case map[string]any:
+		result := make(map[string]string, len(val))
+		for k, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, invalid(key, "value for %q must be a string, got %T", k, item)
+			}
+			result[k] = s
+		}
+		return result, nil
+	default:
+		return nil, invalid(key, "must be an object of strings, got %T", v)
+	}
+}
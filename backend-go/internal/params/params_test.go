@@ -0,0 +1,157 @@
+package params
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetIntInRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]any
+		want    int
+		wantErr bool
+	}{
+		{name: "missing uses default", params: map[string]any{}, want: 100},
+		{name: "nil map uses default", params: nil, want: 100},
+		{name: "json number", params: map[string]any{"latency_ms": float64(250)}, want: 250},
+		{name: "go int", params: map[string]any{"latency_ms": 42}, want: 42},
+		{name: "upper bound inclusive", params: map[string]any{"latency_ms": float64(60000)}, want: 60000},
+		{name: "below range", params: map[string]any{"latency_ms": float64(0)}, wantErr: true},
+		{name: "above range", params: map[string]any{"latency_ms": float64(60001)}, wantErr: true},
+		{name: "fractional", params: map[string]any{"latency_ms": 1.5}, wantErr: true},
+		{name: "wrong type", params: map[string]any{"latency_ms": "100"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetIntInRange(tt.params, "latency_ms", 100, 1, 60000)
+			if tt.wantErr {
+				var pe *Error
+				require.True(t, errors.As(err, &pe))
+				assert.Equal(t, "latency_ms", pe.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetFloatInRange(t *testing.T) {
+	f, err := GetFloatInRange(map[string]any{}, "ratio", 0.5, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, f)
+
+	f, err = GetFloatInRange(map[string]any{"ratio": 0.8}, "ratio", 0.5, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, f)
+
+	f, err = GetFloatInRange(map[string]any{"ratio": 1}, "ratio", 0.5, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, f)
+
+	_, err = GetFloatInRange(map[string]any{"ratio": 1.5}, "ratio", 0.5, 0, 1)
+	assert.Error(t, err)
+
+	_, err = GetFloatInRange(map[string]any{"ratio": "high"}, "ratio", 0.5, 0, 1)
+	assert.Error(t, err)
+}
+
+func TestGetString(t *testing.T) {
+	s, err := GetString(map[string]any{}, "memory_bytes", "256M")
+	require.NoError(t, err)
+	assert.Equal(t, "256M", s)
+
+	s, err = GetString(map[string]any{"memory_bytes": "1G"}, "memory_bytes", "256M")
+	require.NoError(t, err)
+	assert.Equal(t, "1G", s)
+
+	_, err = GetString(map[string]any{"memory_bytes": float64(1024)}, "memory_bytes", "256M")
+	assert.Error(t, err)
+}
+
+func TestGetBool(t *testing.T) {
+	b, err := GetBool(map[string]any{}, "force_failover", false)
+	require.NoError(t, err)
+	assert.False(t, b)
+
+	b, err = GetBool(map[string]any{"force_failover": true}, "force_failover", false)
+	require.NoError(t, err)
+	assert.True(t, b)
+
+	_, err = GetBool(map[string]any{"force_failover": "yes"}, "force_failover", false)
+	assert.Error(t, err)
+}
+
+func TestGetStringRequired(t *testing.T) {
+	s, err := GetStringRequired(map[string]any{"route_table_id": "rtb-1"}, "route_table_id")
+	require.NoError(t, err)
+	assert.Equal(t, "rtb-1", s)
+
+	_, err = GetStringRequired(map[string]any{}, "route_table_id")
+	assert.EqualError(t, err, "route_table_id is required")
+
+	_, err = GetStringRequired(map[string]any{"route_table_id": ""}, "route_table_id")
+	assert.Error(t, err)
+
+	_, err = GetStringRequired(map[string]any{"route_table_id": 7}, "route_table_id")
+	assert.Error(t, err)
+}
+
+func TestGetStringSlice(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]any
+		want    []string
+		wantErr bool
+	}{
+		{name: "string slice", params: map[string]any{"ids": []string{"i-1", "i-2"}}, want: []string{"i-1", "i-2"}},
+		{name: "any slice", params: map[string]any{"ids": []any{"i-a", "i-b"}}, want: []string{"i-a", "i-b"}},
+		{name: "empty slice", params: map[string]any{"ids": []string{}}, want: []string{}},
+		{name: "missing key", params: map[string]any{"other": "x"}, want: nil},
+		{name: "nil map", params: nil, want: nil},
+		{name: "mixed items", params: map[string]any{"ids": []any{"ok", 42}}, wantErr: true},
+		{name: "wrong type", params: map[string]any{"ids": "not-a-slice"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetStringSlice(tt.params, "ids")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetStringSliceRequired(t *testing.T) {
+	_, err := GetStringSliceRequired(map[string]any{"ids": []any{}}, "ids")
+	assert.EqualError(t, err, "ids is required")
+
+	got, err := GetStringSliceRequired(map[string]any{"ids": []any{"i-1"}}, "ids")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"i-1"}, got)
+}
+
+func TestGetStringMap(t *testing.T) {
+	got, err := GetStringMap(map[string]any{"headers": map[string]any{"Authorization": "Bearer x"}}, "headers")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer x"}, got)
+
+	got, err = GetStringMap(map[string]any{}, "headers")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = GetStringMap(map[string]any{"headers": map[string]any{"X-Retry": float64(3)}}, "headers")
+	assert.Error(t, err)
+
+	_, err = GetStringMap(map[string]any{"headers": []any{"a"}}, "headers")
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+)
+
+// AlertmanagerProbe checks Alertmanager for firing alerts matching an alert
+// name and labels. By default it passes when none are firing; with
+// expectFiring it passes only when at least one is.
+type AlertmanagerProbe struct {
+	name         string
+	mode         domain.ProbeMode
+	endpoint     string
+	alertName    string
+	matchLabels  map[string]string
+	expectFiring bool
+	client       *http.Client
+}
+
+// AlertmanagerProbeConfig holds construction parameters for AlertmanagerProbe
+type AlertmanagerProbeConfig struct {
+	Name     string
+	Mode     domain.ProbeMode
+	Endpoint string
+	// AlertName matches the alertname label; empty matches every alert
+	AlertName   string
+	MatchLabels map[string]string
+	// ExpectFiring inverts the check: pass only while a matching alert fires
+	ExpectFiring bool
+	Timeout      time.Duration
+}
+
+// NewAlertmanagerProbe creates an Alertmanager alert probe
+func NewAlertmanagerProbe(cfg AlertmanagerProbeConfig) *AlertmanagerProbe {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	return &AlertmanagerProbe{
+		name:         cfg.Name,
+		mode:         cfg.Mode,
+		endpoint:     strings.TrimRight(cfg.Endpoint, "/"),
+		alertName:    cfg.AlertName,
+		matchLabels:  cfg.MatchLabels,
+		expectFiring: cfg.ExpectFiring,
+		client:       &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (p *AlertmanagerProbe) Name() string           { return p.name }
+func (p *AlertmanagerProbe) Type() string           { return "alertmanager" }
+func (p *AlertmanagerProbe) Mode() domain.ProbeMode { return p.mode }
+
+// alertmanagerAlert is the subset of a /api/v2/alerts entry the probe reads
+type alertmanagerAlert struct {
+	Labels map[string]string `json:"labels"`
+	Status struct {
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+// Execute lists active alerts and counts the ones matching the probe's
+// filters. An unreachable Alertmanager is an error, not a pass.
+func (p *AlertmanagerProbe) Execute(ctx context.Context) (*ProbeResult, error) {
+	q := url.Values{}
+	q.Set("active", "true")
+	q.Set("silenced", "false")
+	q.Set("inhibited", "false")
+	for _, m := range p.matchers() {
+		q.Add("filter", m)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", p.endpoint+"/api/v2/alerts?"+q.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("alertmanager request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("alertmanager returned %d", resp.StatusCode)
+	}
+
+	var alerts []alertmanagerAlert
+	if err := json.NewDecoder(resp.Body).Decode(&alerts); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	// Filter again locally: older Alertmanagers ignore unknown matchers
+	firing := []map[string]string{}
+	for _, a := range alerts {
+		if a.Status.State == "active" && p.matches(a.Labels) {
+			firing = append(firing, a.Labels)
+		}
+	}
+
+	passed := len(firing) == 0
+	if p.expectFiring {
+		passed = !passed
+	}
+
+	return &ProbeResult{
+		ProbeName: p.name,
+		ProbeType: "alertmanager",
+		Mode:      p.mode,
+		Passed:    passed,
+		Detail: map[string]any{
+			"alert_name":    p.alertName,
+			"match_labels":  p.matchLabels,
+			"expect_firing": p.expectFiring,
+			"firing_count":  len(firing),
+			"firing":        firing,
+		},
+		ExecutedAt: time.Now().UTC(),
+	}, nil
+}
+
+// matchers builds Alertmanager equality matchers in a stable order
+func (p *AlertmanagerProbe) matchers() []string {
+	labels := make(map[string]string, len(p.matchLabels)+1)
+	for k, v := range p.matchLabels {
+		labels[k] = v
+	}
+	if p.alertName != "" {
+		labels["alertname"] = p.alertName
+	}
+
+	matchers := make([]string, 0, len(labels))
+	for k, v := range labels {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", k, v))
+	}
+	sort.Strings(matchers)
+	return matchers
+}
+
+func (p *AlertmanagerProbe) matches(labels map[string]string) bool {
+	if p.alertName != "" && labels["alertname"] != p.alertName {
+		return false
+	}
+	for k, v := range p.matchLabels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package probe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const firingAlerts = `[
+	{"labels": {"alertname": "HighErrorRate", "severity": "critical", "service": "api"}, "status": {"state": "active"}},
+	{"labels": {"alertname": "HighErrorRate", "severity": "warning", "service": "web"}, "status": {"state": "active"}},
+	{"labels": {"alertname": "DiskFull", "severity": "critical"}, "status": {"state": "suppressed"}}
+]`
+
+func alertmanagerServer(t *testing.T, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/alerts", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("active"))
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestAlertmanagerProbeFailsWhenMatchingAlertFires(t *testing.T) {
+	srv := alertmanagerServer(t, firingAlerts)
+	defer srv.Close()
+
+	p := NewAlertmanagerProbe(AlertmanagerProbeConfig{
+		Name: "no-critical", Mode: domain.ProbeModeContinuous, Endpoint: srv.URL,
+		MatchLabels: map[string]string{"severity": "critical"},
+	})
+	assert.Equal(t, "alertmanager", p.Type())
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Passed)
+	// DiskFull is suppressed, so only the active critical alert counts
+	assert.Equal(t, 1, result.Detail["firing_count"])
+}
+
+func TestAlertmanagerProbeFilters(t *testing.T) {
+	srv := alertmanagerServer(t, firingAlerts)
+	defer srv.Close()
+
+	tests := []struct {
+		name         string
+		alertName    string
+		labels       map[string]string
+		expectFiring bool
+		passed       bool
+	}{
+		{"other alert", "PodCrashLooping", nil, false, true},
+		{"by name", "HighErrorRate", nil, false, false},
+		{"name and labels", "HighErrorRate", map[string]string{"service": "db"}, false, true},
+		{"suppressed ignored", "DiskFull", nil, false, true},
+		{"expect firing", "HighErrorRate", map[string]string{"service": "web"}, true, true},
+		{"expect firing missing", "PodCrashLooping", nil, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewAlertmanagerProbe(AlertmanagerProbeConfig{
+				Name: tt.name, Mode: domain.ProbeModeEOT, Endpoint: srv.URL,
+				AlertName: tt.alertName, MatchLabels: tt.labels, ExpectFiring: tt.expectFiring,
+			})
+			result, err := p.Execute(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.passed, result.Passed)
+		})
+	}
+}
+
+func TestAlertmanagerProbeEmptyAlertListPasses(t *testing.T) {
+	srv := alertmanagerServer(t, `[]`)
+	defer srv.Close()
+
+	p := NewAlertmanagerProbe(AlertmanagerProbeConfig{Name: "quiet", Mode: domain.ProbeModeEOT, Endpoint: srv.URL, AlertName: "HighErrorRate"})
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.Equal(t, 0, result.Detail["firing_count"])
+}
+
+func TestAlertmanagerProbeSendsMatchers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{`alertname="HighErrorRate"`, `severity="critical"`}, r.URL.Query()["filter"])
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	p := NewAlertmanagerProbe(AlertmanagerProbeConfig{
+		Name: "matchers", Endpoint: srv.URL + "/",
+		AlertName: "HighErrorRate", MatchLabels: map[string]string{"severity": "critical"},
+	})
+	_, err := p.Execute(context.Background())
+	require.NoError(t, err)
+}
+
+func TestAlertmanagerProbeUnreachable(t *testing.T) {
+	p := NewAlertmanagerProbe(AlertmanagerProbeConfig{Name: "down", Endpoint: "http://127.0.0.1:1"})
+	_, err := p.Execute(context.Background())
+	assert.Error(t, err)
+
+	result := SafeExecute(context.Background(), p)
+	assert.False(t, result.Passed)
+	require.NotNil(t, result.Error)
+}
+
+func TestAlertmanagerProbeServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	p := NewAlertmanagerProbe(AlertmanagerProbeConfig{Name: "503", Endpoint: srv.URL})
+	_, err := p.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/chaosduck/backend-go/internal/domain"
@@ -22,6 +24,9 @@ type HTTPProbe struct {
 	timeout        time.Duration
 	bodyPattern    *regexp.Regexp
 	headers        map[string]string
+	body           string
+	retries        int
+	retryInterval  time.Duration
 	client         *http.Client
 }
 
@@ -35,6 +40,18 @@ type HTTPProbeConfig struct {
 	Timeout        time.Duration
 	BodyPattern    string
 	Headers        map[string]string
+	// Body is sent with non-GET requests
+	Body string
+	// Retries is the number of extra attempts made before reporting failure
+	Retries       int
+	RetryInterval time.Duration
+	// FollowRedirects makes the probe judge the final response of a
+	// redirect chain; otherwise it judges the first response, e.g. a 302.
+	// buildProbes sets it unless a probe has follow_redirects: false.
+	FollowRedirects bool
+	// TLS trusts a private CA or skips verification, and presents a client
+	// certificate for mTLS
+	TLS TLSConfig
 }
 
 // NewHTTPProbe creates an HTTP probe from config
@@ -48,6 +65,12 @@ func NewHTTPProbe(cfg HTTPProbeConfig) (*HTTPProbe, error) {
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 5 * time.Second
 	}
+	if cfg.Retries < 0 {
+		cfg.Retries = 0
+	}
+	if cfg.RetryInterval == 0 {
+		cfg.RetryInterval = time.Second
+	}
 
 	var pat *regexp.Regexp
 	if cfg.BodyPattern != "" {
@@ -58,6 +81,19 @@ func NewHTTPProbe(cfg HTTPProbeConfig) (*HTTPProbe, error) {
 		}
 	}
 
+	client := &http.Client{Timeout: cfg.Timeout}
+	if err := applyTLS(client, cfg.TLS); err != nil {
+		return nil, err
+	}
+	if cfg.TLS.InsecureSkipVerify {
+		log.Printf("WARNING: HTTP probe %s skips TLS certificate verification; any server, including an impostor, will pass it", cfg.Name)
+	}
+	if !cfg.FollowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
 	return &HTTPProbe{
 		name:           cfg.Name,
 		mode:           cfg.Mode,
@@ -67,7 +103,10 @@ func NewHTTPProbe(cfg HTTPProbeConfig) (*HTTPProbe, error) {
 		timeout:        cfg.Timeout,
 		bodyPattern:    pat,
 		headers:        cfg.Headers,
-		client:         &http.Client{Timeout: cfg.Timeout},
+		body:           cfg.Body,
+		retries:        cfg.Retries,
+		retryInterval:  cfg.RetryInterval,
+		client:         client,
 	}, nil
 }
 
@@ -75,8 +114,51 @@ func (p *HTTPProbe) Name() string          { return p.name }
 func (p *HTTPProbe) Type() string          { return "http" }
 func (p *HTTPProbe) Mode() domain.ProbeMode { return p.mode }
 
+// Execute makes up to retries+1 attempts, returning as soon as one passes.
+// Waiting between attempts is bounded by the context deadline.
 func (p *HTTPProbe) Execute(ctx context.Context) (*ProbeResult, error) {
-	req, err := http.NewRequestWithContext(ctx, p.method, p.url, nil)
+	var (
+		result   *ProbeResult
+		err      error
+		attempts int
+	)
+	for {
+		attempts++
+		result, err = p.attempt(ctx)
+		if (err == nil && result.Passed) || attempts > p.retries {
+			break
+		}
+		if !waitRetry(ctx, p.retryInterval) {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w (after %d attempts)", err, attempts)
+	}
+	result.Detail["attempts"] = attempts
+	return result, nil
+}
+
+// waitRetry sleeps for d and reports false if ctx ends first
+func waitRetry(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// attempt performs a single request and evaluates the response
+func (p *HTTPProbe) attempt(ctx context.Context) (*ProbeResult, error) {
+	var body io.Reader
+	if p.body != "" && p.method != http.MethodGet && p.method != http.MethodHead {
+		body = strings.NewReader(p.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, p.method, p.url, body)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
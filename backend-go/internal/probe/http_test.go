@@ -2,8 +2,20 @@ package probe
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -173,3 +185,307 @@ func TestHTTPProbeResponseTime(t *testing.T) {
 	assert.True(t, ok)
 	assert.GreaterOrEqual(t, responseTime, int64(0))
 }
+
+// flakyServer fails the first n requests with 503, then returns 200
+func flakyServer(n int32) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= n {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return srv, &calls
+}
+
+func TestHTTPProbeRetriesUntilPass(t *testing.T) {
+	srv, calls := flakyServer(2)
+	defer srv.Close()
+
+	p, err := NewHTTPProbe(HTTPProbeConfig{
+		Name:          "flaky",
+		Mode:          domain.ProbeModeOnChaos,
+		URL:           srv.URL,
+		Retries:       3,
+		RetryInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, result.Passed)
+	assert.Equal(t, 3, result.Detail["attempts"])
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestHTTPProbeRetriesExhausted(t *testing.T) {
+	srv, calls := flakyServer(5)
+	defer srv.Close()
+
+	p, err := NewHTTPProbe(HTTPProbeConfig{
+		Name:          "flaky",
+		Mode:          domain.ProbeModeOnChaos,
+		URL:           srv.URL,
+		Retries:       2,
+		RetryInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, 3, result.Detail["attempts"])
+	assert.Equal(t, http.StatusServiceUnavailable, result.Detail["status_code"])
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestHTTPProbeNoRetriesByDefault(t *testing.T) {
+	srv, calls := flakyServer(1)
+	defer srv.Close()
+
+	p, err := NewHTTPProbe(HTTPProbeConfig{Name: "once", Mode: domain.ProbeModeSOT, URL: srv.URL})
+	require.NoError(t, err)
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, 1, result.Detail["attempts"])
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestHTTPProbeRetryBoundedByContext(t *testing.T) {
+	srv, calls := flakyServer(100)
+	defer srv.Close()
+
+	p, err := NewHTTPProbe(HTTPProbeConfig{
+		Name:          "bounded",
+		Mode:          domain.ProbeModeOnChaos,
+		URL:           srv.URL,
+		Retries:       50,
+		RetryInterval: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result, err := p.Execute(ctx)
+	if err == nil {
+		assert.False(t, result.Passed)
+	}
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Less(t, calls.Load(), int32(10))
+}
+
+func TestHTTPProbeSendsHeadersAndBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer token" || string(body) != `{"ping":true}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	p, err := NewHTTPProbe(HTTPProbeConfig{
+		Name:    "post-check",
+		Mode:    domain.ProbeModeSOT,
+		URL:     srv.URL,
+		Method:  http.MethodPost,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Body:    `{"ping":true}`,
+	})
+	require.NoError(t, err)
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+}
+
+func TestHTTPProbeGetIgnoresBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if len(body) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	p, err := NewHTTPProbe(HTTPProbeConfig{
+		Name: "get-check",
+		Mode: domain.ProbeModeSOT,
+		URL:  srv.URL,
+		Body: "ignored",
+	})
+	require.NoError(t, err)
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+}
+
+func TestHTTPProbeRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusFound)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		follow     bool
+		wantStatus int
+	}{
+		{"followed", true, http.StatusOK},
+		{"not followed", false, http.StatusFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewHTTPProbe(HTTPProbeConfig{
+				Name:            "redirect",
+				Mode:            domain.ProbeModeSOT,
+				URL:             srv.URL + "/old",
+				FollowRedirects: tt.follow,
+			})
+			require.NoError(t, err)
+
+			result, err := p.Execute(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, result.Detail["status_code"])
+			assert.Equal(t, tt.follow, result.Passed)
+		})
+	}
+}
+
+func TestHTTPProbeCustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+	certDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(certDir, "ca.pem"), []byte(caPEM), 0o600))
+
+	tests := []struct {
+		name    string
+		cfg     HTTPProbeConfig
+		wantErr bool
+	}{
+		{"untrusted by default", HTTPProbeConfig{}, true},
+		{"insecure", HTTPProbeConfig{TLS: TLSConfig{InsecureSkipVerify: true}}, false},
+		{"CA file", HTTPProbeConfig{TLS: TLSConfig{CAFile: "ca.pem", CertDir: certDir}}, false},
+		{"inline CA", HTTPProbeConfig{TLS: TLSConfig{CACert: caPEM}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Name, tt.cfg.Mode, tt.cfg.URL = "tls", domain.ProbeModeSOT, srv.URL
+			p, err := NewHTTPProbe(tt.cfg)
+			require.NoError(t, err)
+
+			result, err := p.Execute(context.Background())
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, result.Passed)
+		})
+	}
+}
+
+// clientCertPEM issues a throwaway self-signed client certificate and
+// returns it, its key and a pool trusting it
+func clientCertPEM(t *testing.T) (string, string, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "probe"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+		pool
+}
+
+func TestHTTPProbeClientCert(t *testing.T) {
+	certPEM, keyPEM, clientCAs := clientCertPEM(t)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+
+	cfg := HTTPProbeConfig{Name: "mtls", Mode: domain.ProbeModeSOT, URL: srv.URL, TLS: TLSConfig{InsecureSkipVerify: true}}
+
+	// Rejected without a client certificate
+	p, err := NewHTTPProbe(cfg)
+	require.NoError(t, err)
+	_, err = p.Execute(context.Background())
+	require.Error(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "client.pem"), []byte(certPEM), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "client.key"), []byte(keyPEM), 0o600))
+	fromFiles := cfg.TLS
+	fromFiles.CertFile, fromFiles.KeyFile, fromFiles.CertDir = "client.pem", "client.key", dir
+	inline := cfg.TLS
+	inline.Cert, inline.Key = certPEM, keyPEM
+	for _, tlsCfg := range []TLSConfig{fromFiles, inline} {
+		withCert := cfg
+		withCert.TLS = tlsCfg
+		p, err = NewHTTPProbe(withCert)
+		require.NoError(t, err)
+		result, err := p.Execute(context.Background())
+		require.NoError(t, err)
+		assert.True(t, result.Passed)
+	}
+}
+
+func TestHTTPProbeRejectsBadTLSConfig(t *testing.T) {
+	certPEM, _, _ := clientCertPEM(t)
+	certDir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(outside, []byte(certPEM), 0o600))
+	tests := []HTTPProbeConfig{
+		{TLS: TLSConfig{CACert: "not a cert"}},
+		{TLS: TLSConfig{CAFile: "missing.pem", CertDir: certDir}},
+		{TLS: TLSConfig{Cert: certPEM}},
+		// File references never reach outside the certificate directory
+		{TLS: TLSConfig{CACert: outside}},
+		{TLS: TLSConfig{CAFile: outside}},
+		{TLS: TLSConfig{CAFile: outside, CertDir: certDir}},
+		{TLS: TLSConfig{CAFile: "../" + filepath.Base(filepath.Dir(outside)) + "/ca.pem", CertDir: certDir}},
+	}
+	for _, cfg := range tests {
+		cfg.Name, cfg.URL = "tls", "https://example.invalid"
+		_, err := NewHTTPProbe(cfg)
+		assert.Error(t, err)
+	}
+}
@@ -3,14 +3,20 @@ package probe
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/chaosduck/backend-go/internal/domain"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-// K8sProbe checks Kubernetes resource state (deployment readiness, pod phase)
+// K8sProbe checks Kubernetes resource state (deployment readiness, pod phase,
+// job completion, service endpoints, HPA scaling)
 type K8sProbe struct {
 	name          string
 	mode          domain.ProbeMode
@@ -20,6 +26,11 @@ type K8sProbe struct {
 	resourceName  string
 	condition     string
 	expectedValue string
+	minReplicas   int32
+
+	// hpaBaseline is the HPA's current replica count at the first check
+	mu          sync.Mutex
+	hpaBaseline *int32
 }
 
 // K8sProbeConfig holds construction parameters for K8sProbe
@@ -32,6 +43,9 @@ type K8sProbeConfig struct {
 	ResourceName  string
 	Condition     string
 	ExpectedValue string
+	// MinReplicas makes an hpa check pass once the desired replica count
+	// reaches it, whatever the baseline
+	MinReplicas int32
 }
 
 // NewK8sProbe creates a Kubernetes resource probe
@@ -51,6 +65,7 @@ func NewK8sProbe(cfg K8sProbeConfig) *K8sProbe {
 		resourceName:  cfg.ResourceName,
 		condition:     cfg.Condition,
 		expectedValue: cfg.ExpectedValue,
+		minReplicas:   cfg.MinReplicas,
 	}
 }
 
@@ -64,6 +79,12 @@ func (p *K8sProbe) Execute(ctx context.Context) (*ProbeResult, error) {
 		return p.checkDeployment(ctx)
 	case "pod":
 		return p.checkPod(ctx)
+	case "job":
+		return p.checkJob(ctx)
+	case "service":
+		return p.checkService(ctx)
+	case "hpa":
+		return p.checkHPA(ctx)
 	default:
 		return nil, fmt.Errorf("unsupported resource kind: %s", p.resourceKind)
 	}
@@ -126,3 +147,199 @@ func (p *K8sProbe) checkPod(ctx context.Context) (*ProbeResult, error) {
 		ExecutedAt: time.Now().UTC(),
 	}, nil
 }
+
+// checkJob passes once the job has reached its completion count and fails
+// as soon as any pod of the job has failed
+func (p *K8sProbe) checkJob(ctx context.Context) (*ProbeResult, error) {
+	job, err := p.clientset.BatchV1().Jobs(p.namespace).Get(ctx, p.resourceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	succeeded := job.Status.Succeeded
+	failed := job.Status.Failed
+
+	passed := failed == 0 && succeeded >= completions
+
+	return &ProbeResult{
+		ProbeName: p.name,
+		ProbeType: "k8s",
+		Mode:      p.mode,
+		Passed:    passed,
+		Detail: map[string]any{
+			"job":         p.resourceName,
+			"namespace":   p.namespace,
+			"completions": completions,
+			"succeeded":   succeeded,
+			"failed":      failed,
+			"active":      job.Status.Active,
+		},
+		ExecutedAt: time.Now().UTC(),
+	}, nil
+}
+
+// checkService passes when the service has at least ExpectedValue (default
+// 1) ready endpoint addresses. EndpointSlices are read first; clusters
+// without them fall back to the legacy Endpoints object.
+func (p *K8sProbe) checkService(ctx context.Context) (*ProbeResult, error) {
+	minReady := 1
+	if p.expectedValue != "" {
+		n, err := strconv.Atoi(p.expectedValue)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("service probe expected_value must be a non-negative count, got %q", p.expectedValue)
+		}
+		minReady = n
+	}
+
+	if _, err := p.clientset.CoreV1().Services(p.namespace).Get(ctx, p.resourceName, metav1.GetOptions{}); err != nil {
+		return nil, fmt.Errorf("get service: %w", err)
+	}
+
+	ready, notReady, source, err := p.serviceEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProbeResult{
+		ProbeName: p.name,
+		ProbeType: "k8s",
+		Mode:      p.mode,
+		Passed:    ready >= minReady,
+		Detail: map[string]any{
+			"service":         p.resourceName,
+			"namespace":       p.namespace,
+			"ready_addresses": ready,
+			"not_ready":       notReady,
+			"min_ready":       minReady,
+			"source":          source,
+		},
+		ExecutedAt: time.Now().UTC(),
+	}, nil
+}
+
+// serviceEndpoints counts the service's distinct ready and not-ready
+// endpoint addresses
+func (p *K8sProbe) serviceEndpoints(ctx context.Context) (ready, notReady int, source string, err error) {
+	list, err := p.clientset.DiscoveryV1().EndpointSlices(p.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + p.resourceName,
+	})
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("list endpointslices: %w", err)
+	}
+	if len(list.Items) > 0 {
+		readySet, notReadySet := map[string]bool{}, map[string]bool{}
+		for _, slice := range list.Items {
+			for _, ep := range slice.Endpoints {
+				// A nil ready condition means unknown, which consumers treat as ready
+				isReady := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+				for _, addr := range ep.Addresses {
+					if isReady {
+						readySet[addr] = true
+					} else {
+						notReadySet[addr] = true
+					}
+				}
+			}
+		}
+		return len(readySet), len(notReadySet), "endpointslices", nil
+	}
+
+	ep, err := p.clientset.CoreV1().Endpoints(p.namespace).Get(ctx, p.resourceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return 0, 0, "none", nil
+	}
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("get endpoints: %w", err)
+	}
+	for _, subset := range ep.Subsets {
+		ready += len(subset.Addresses)
+		notReady += len(subset.NotReadyAddresses)
+	}
+	return ready, notReady, "endpoints", nil
+}
+
+// checkHPA passes once the HorizontalPodAutoscaler has scaled up: its desired
+// replica count is above the current count seen at the probe's first check,
+// or has reached MinReplicas when that is set
+func (p *K8sProbe) checkHPA(ctx context.Context) (*ProbeResult, error) {
+	hpa, err := p.clientset.AutoscalingV2().HorizontalPodAutoscalers(p.namespace).Get(ctx, p.resourceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get hpa: %w", err)
+	}
+
+	current := hpa.Status.CurrentReplicas
+	desired := hpa.Status.DesiredReplicas
+	p.mu.Lock()
+	if p.hpaBaseline == nil {
+		p.hpaBaseline = &current
+	}
+	baseline := *p.hpaBaseline
+	p.mu.Unlock()
+
+	scaledUp := desired > baseline
+	passed := scaledUp || (p.minReplicas > 0 && desired >= p.minReplicas)
+
+	detail := map[string]any{
+		"hpa":              p.resourceName,
+		"namespace":        p.namespace,
+		"current_replicas": current,
+		"desired_replicas": desired,
+		"baseline":         baseline,
+		"scaled_up":        scaledUp,
+		"max_replicas":     hpa.Spec.MaxReplicas,
+		"metrics":          hpaMetrics(hpa.Status.CurrentMetrics),
+	}
+	if p.minReplicas > 0 {
+		detail["min_replicas"] = p.minReplicas
+	}
+	return &ProbeResult{
+		ProbeName:  p.name,
+		ProbeType:  "k8s",
+		Mode:       p.mode,
+		Passed:     passed,
+		Detail:     detail,
+		ExecutedAt: time.Now().UTC(),
+	}, nil
+}
+
+// hpaMetrics flattens the HPA's current metric values into the probe detail
+func hpaMetrics(statuses []autoscalingv2.MetricStatus) []map[string]any {
+	metrics := make([]map[string]any, 0, len(statuses))
+	for _, s := range statuses {
+		m := map[string]any{"type": string(s.Type)}
+		var current autoscalingv2.MetricValueStatus
+		switch {
+		case s.Resource != nil:
+			m["name"] = string(s.Resource.Name)
+			current = s.Resource.Current
+		case s.ContainerResource != nil:
+			m["name"] = string(s.ContainerResource.Name)
+			m["container"] = s.ContainerResource.Container
+			current = s.ContainerResource.Current
+		case s.Pods != nil:
+			m["name"] = s.Pods.Metric.Name
+			current = s.Pods.Current
+		case s.Object != nil:
+			m["name"] = s.Object.Metric.Name
+			current = s.Object.Current
+		case s.External != nil:
+			m["name"] = s.External.Metric.Name
+			current = s.External.Current
+		}
+		if current.AverageUtilization != nil {
+			m["average_utilization"] = *current.AverageUtilization
+		}
+		if current.AverageValue != nil {
+			m["average_value"] = current.AverageValue.String()
+		}
+		if current.Value != nil {
+			m["value"] = current.Value.String()
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
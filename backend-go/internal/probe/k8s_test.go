@@ -2,13 +2,18 @@ package probe
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/chaosduck/backend-go/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 )
@@ -195,3 +200,243 @@ func TestK8sProbeDefaultNamespace(t *testing.T) {
 
 	assert.Equal(t, "default", p.namespace)
 }
+
+func newJobProbe(job *batchv1.Job) *K8sProbe {
+	return NewK8sProbe(K8sProbeConfig{
+		Name:         "job-done",
+		Mode:         domain.ProbeModeEOT,
+		Clientset:    fake.NewSimpleClientset(job),
+		Namespace:    "batch",
+		ResourceKind: "job",
+		ResourceName: "nightly",
+	})
+}
+
+func TestK8sProbeJobSucceeded(t *testing.T) {
+	p := newJobProbe(&batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "batch"},
+		Spec:       batchv1.JobSpec{Completions: int32Ptr(3)},
+		Status:     batchv1.JobStatus{Succeeded: 3},
+	})
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, result.Passed)
+	assert.Equal(t, int32(3), result.Detail["succeeded"])
+	assert.Equal(t, int32(0), result.Detail["failed"])
+	assert.Equal(t, int32(0), result.Detail["active"])
+}
+
+func TestK8sProbeJobDefaultCompletions(t *testing.T) {
+	p := newJobProbe(&batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "batch"},
+		Status:     batchv1.JobStatus{Succeeded: 1},
+	})
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, result.Passed)
+	assert.Equal(t, int32(1), result.Detail["completions"])
+}
+
+func TestK8sProbeJobFailed(t *testing.T) {
+	p := newJobProbe(&batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "batch"},
+		Spec:       batchv1.JobSpec{Completions: int32Ptr(2)},
+		Status:     batchv1.JobStatus{Succeeded: 2, Failed: 1},
+	})
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, int32(1), result.Detail["failed"])
+}
+
+func TestK8sProbeJobStillRunning(t *testing.T) {
+	p := newJobProbe(&batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "batch"},
+		Spec:       batchv1.JobSpec{Completions: int32Ptr(3)},
+		Status:     batchv1.JobStatus{Succeeded: 1, Active: 2},
+	})
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, int32(2), result.Detail["active"])
+}
+
+func TestK8sProbeJobNotFound(t *testing.T) {
+	p := NewK8sProbe(K8sProbeConfig{
+		Name:         "job-done",
+		Clientset:    fake.NewSimpleClientset(),
+		ResourceKind: "job",
+		ResourceName: "missing",
+	})
+
+	_, err := p.Execute(context.Background())
+	assert.Error(t, err)
+}
+
+func endpointSlice(name string, ready []bool) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+		},
+	}
+	for i, r := range ready {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{fmt.Sprintf("10.0.%s.%d", name[len(name)-1:], i+1)},
+			Conditions: discoveryv1.EndpointConditions{Ready: &r},
+		})
+	}
+	return slice
+}
+
+func serviceProbe(cs *fake.Clientset, expected string) *K8sProbe {
+	return NewK8sProbe(K8sProbeConfig{
+		Name:          "svc",
+		Clientset:     cs,
+		ResourceKind:  "service",
+		ResourceName:  "web",
+		ExpectedValue: expected,
+	})
+}
+
+func TestK8sProbeServiceReadyEndpoints(t *testing.T) {
+	cs := fake.NewSimpleClientset(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}})
+	p := serviceProbe(cs, "")
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Passed)
+	assert.Equal(t, 0, result.Detail["ready_addresses"])
+
+	ctx := context.Background()
+	_, err = cs.DiscoveryV1().EndpointSlices("default").Create(ctx, endpointSlice("web-1", []bool{true, true, false}), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = cs.DiscoveryV1().EndpointSlices("default").Create(ctx, endpointSlice("web-2", []bool{true}), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	result, err = p.Execute(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.Equal(t, 3, result.Detail["ready_addresses"])
+	assert.Equal(t, 1, result.Detail["not_ready"])
+	assert.Equal(t, "endpointslices", result.Detail["source"])
+
+	result, err = serviceProbe(cs, "4").Execute(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Passed)
+	assert.Equal(t, 4, result.Detail["min_ready"])
+}
+
+func TestK8sProbeServiceLegacyEndpoints(t *testing.T) {
+	cs := fake.NewSimpleClientset(
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Subsets: []corev1.EndpointSubset{{
+				Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+				NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.3"}},
+			}},
+		},
+	)
+
+	result, err := serviceProbe(cs, "2").Execute(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.Equal(t, 2, result.Detail["ready_addresses"])
+	assert.Equal(t, 1, result.Detail["not_ready"])
+	assert.Equal(t, "endpoints", result.Detail["source"])
+}
+
+func TestK8sProbeServiceErrors(t *testing.T) {
+	cs := fake.NewSimpleClientset(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}})
+
+	_, err := serviceProbe(cs, "many").Execute(context.Background())
+	assert.ErrorContains(t, err, "non-negative count")
+
+	_, err = serviceProbe(fake.NewSimpleClientset(), "").Execute(context.Background())
+	assert.ErrorContains(t, err, "get service")
+}
+
+func testHPA(current, desired int32) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{MaxReplicas: 10},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: current,
+			DesiredReplicas: desired,
+			CurrentMetrics: []autoscalingv2.MetricStatus{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricStatus{
+					Name:    corev1.ResourceCPU,
+					Current: autoscalingv2.MetricValueStatus{AverageUtilization: int32Ptr(92), AverageValue: resource.NewMilliQuantity(460, resource.DecimalSI)},
+				},
+			}},
+		},
+	}
+}
+
+func hpaProbe(cs *fake.Clientset, minReplicas int32) *K8sProbe {
+	return NewK8sProbe(K8sProbeConfig{
+		Name:         "hpa",
+		Mode:         domain.ProbeModeContinuous,
+		Clientset:    cs,
+		ResourceKind: "hpa",
+		ResourceName: "web",
+		MinReplicas:  minReplicas,
+	})
+}
+
+func TestK8sProbeHPAScaledUp(t *testing.T) {
+	ctx := context.Background()
+	cs := fake.NewSimpleClientset(testHPA(2, 2))
+	p := hpaProbe(cs, 0)
+
+	// The first check sets the baseline, with nothing scaled yet
+	result, err := p.Execute(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Passed)
+	assert.Equal(t, int32(2), result.Detail["baseline"])
+
+	_, err = cs.AutoscalingV2().HorizontalPodAutoscalers("default").Update(ctx, testHPA(2, 5), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	result, err = p.Execute(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.Equal(t, true, result.Detail["scaled_up"])
+	assert.Equal(t, int32(5), result.Detail["desired_replicas"])
+	assert.Equal(t, int32(2), result.Detail["current_replicas"])
+
+	metrics := result.Detail["metrics"].([]map[string]any)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "Resource", metrics[0]["type"])
+	assert.Equal(t, "cpu", metrics[0]["name"])
+	assert.Equal(t, int32(92), metrics[0]["average_utilization"])
+	assert.Equal(t, "460m", metrics[0]["average_value"])
+}
+
+func TestK8sProbeHPANotScaled(t *testing.T) {
+	cs := fake.NewSimpleClientset(testHPA(3, 3))
+
+	result, err := hpaProbe(cs, 4).Execute(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Passed)
+	assert.Equal(t, false, result.Detail["scaled_up"])
+	assert.Equal(t, int32(4), result.Detail["min_replicas"])
+
+	// A minimum already met passes without a scale-up
+	result, err = hpaProbe(cs, 3).Execute(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+
+	_, err = hpaProbe(fake.NewSimpleClientset(), 0).Execute(context.Background())
+	assert.ErrorContains(t, err, "get hpa")
+}
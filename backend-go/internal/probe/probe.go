@@ -25,7 +25,7 @@ type Probe interface {
 	Execute(ctx context.Context) (*ProbeResult, error)
 	// Name returns the probe's identifier
 	Name() string
-	// Type returns the probe type (http, cmd, k8s, prometheus)
+	// Type returns the probe type (http, cmd, k8s, prometheus, alertmanager)
 	Type() string
 	// Mode returns when this probe should fire
 	Mode() domain.ProbeMode
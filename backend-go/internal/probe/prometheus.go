@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +14,9 @@ import (
 	"github.com/chaosduck/backend-go/internal/domain"
 )
 
+// PromAggregations lists the supported ways of combining query samples
+var PromAggregations = []string{"max", "min", "avg", "last"}
+
 // PromProbe executes a PromQL query against a Prometheus endpoint
 // and compares the result against a threshold
 type PromProbe struct {
@@ -24,6 +28,18 @@ type PromProbe struct {
 	threshold  float64
 	timeout    time.Duration
 	client     *http.Client
+	// range query and aggregation; queryRange 0 means an instant query
+	queryRange  time.Duration
+	stepSeconds int
+	aggregation string
+	// evalTime and queryTimeout are sent as the time and timeout query
+	// parameters when set
+	evalTime     time.Time
+	queryTimeout time.Duration
+	// auth
+	bearerToken string
+	username    string
+	password    string
 }
 
 // PromProbeConfig holds construction parameters for PromProbe
@@ -35,26 +51,79 @@ type PromProbeConfig struct {
 	Comparator string
 	Threshold  float64
 	Timeout    time.Duration
+	// Range switches to a query_range over the last Range, sampled every
+	// StepSeconds (default 15)
+	Range       time.Duration
+	StepSeconds int
+	// Aggregation combines every returned sample into the compared value:
+	// one of PromAggregations. Required for range queries and for instant
+	// queries returning more than one series.
+	Aggregation string
+	// Time evaluates the query at that instant instead of now; a range query
+	// then ends at Time. Zero means now.
+	Time time.Time
+	// QueryTimeout is sent as Prometheus's evaluation timeout; zero leaves the
+	// server's default. Timeout still bounds the whole request.
+	QueryTimeout time.Duration
+	// BearerToken is sent as "Authorization: Bearer <token>" and takes
+	// precedence over basic auth
+	BearerToken string
+	// Username and Password enable HTTP basic auth
+	Username string
+	Password string
+	// TLS trusts a private CA or skips verification, and presents a client
+	// certificate for mTLS
+	TLS TLSConfig
 }
 
 // NewPromProbe creates a Prometheus query probe
-func NewPromProbe(cfg PromProbeConfig) *PromProbe {
+func NewPromProbe(cfg PromProbeConfig) (*PromProbe, error) {
 	if cfg.Comparator == "" {
 		cfg.Comparator = ">"
 	}
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 5 * time.Second
 	}
-	return &PromProbe{
-		name:       cfg.Name,
-		mode:       cfg.Mode,
-		endpoint:   strings.TrimRight(cfg.Endpoint, "/"),
-		query:      cfg.Query,
-		comparator: cfg.Comparator,
-		threshold:  cfg.Threshold,
-		timeout:    cfg.Timeout,
-		client:     &http.Client{Timeout: cfg.Timeout},
+	if cfg.Aggregation != "" && !slices.Contains(PromAggregations, cfg.Aggregation) {
+		return nil, fmt.Errorf("unknown aggregation %q (want one of %s)", cfg.Aggregation, strings.Join(PromAggregations, ", "))
+	}
+	if cfg.Range < 0 {
+		return nil, fmt.Errorf("range must not be negative")
+	}
+	if cfg.QueryTimeout < 0 {
+		return nil, fmt.Errorf("query timeout must not be negative")
+	}
+	if cfg.Range > 0 {
+		if cfg.Aggregation == "" {
+			return nil, fmt.Errorf("range queries require an aggregation")
+		}
+		if cfg.StepSeconds <= 0 {
+			cfg.StepSeconds = 15
+		}
 	}
+	client := &http.Client{Timeout: cfg.Timeout}
+	if err := applyTLS(client, cfg.TLS); err != nil {
+		return nil, err
+	}
+
+	return &PromProbe{
+		name:         cfg.Name,
+		mode:         cfg.Mode,
+		endpoint:     strings.TrimRight(cfg.Endpoint, "/"),
+		query:        cfg.Query,
+		comparator:   cfg.Comparator,
+		threshold:    cfg.Threshold,
+		timeout:      cfg.Timeout,
+		client:       client,
+		queryRange:   cfg.Range,
+		stepSeconds:  cfg.StepSeconds,
+		aggregation:  cfg.Aggregation,
+		evalTime:     cfg.Time,
+		queryTimeout: cfg.QueryTimeout,
+		bearerToken:  cfg.BearerToken,
+		username:     cfg.Username,
+		password:     cfg.Password,
+	}, nil
 }
 
 func (p *PromProbe) Name() string          { return p.name }
@@ -62,11 +131,35 @@ func (p *PromProbe) Type() string          { return "prometheus" }
 func (p *PromProbe) Mode() domain.ProbeMode { return p.mode }
 
 func (p *PromProbe) Execute(ctx context.Context) (*ProbeResult, error) {
-	queryURL := fmt.Sprintf("%s/api/v1/query?query=%s", p.endpoint, url.QueryEscape(p.query))
+	q := url.Values{}
+	q.Set("query", p.query)
+	if p.queryTimeout > 0 {
+		q.Set("timeout", strconv.FormatFloat(p.queryTimeout.Seconds(), 'f', -1, 64))
+	}
+	api := "query"
+	if p.queryRange > 0 {
+		end := time.Now()
+		if !p.evalTime.IsZero() {
+			end = p.evalTime
+		}
+		q.Set("start", strconv.FormatInt(end.Add(-p.queryRange).Unix(), 10))
+		q.Set("end", strconv.FormatInt(end.Unix(), 10))
+		q.Set("step", strconv.Itoa(p.stepSeconds))
+		api = "query_range"
+	} else if !p.evalTime.IsZero() {
+		q.Set("time", formatPromTime(p.evalTime))
+	}
+	queryURL := fmt.Sprintf("%s/api/v1/%s?%s", p.endpoint, api, q.Encode())
 	req, err := http.NewRequestWithContext(ctx, "GET", queryURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
+	switch {
+	case p.bearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+p.bearerToken)
+	case p.username != "":
+		req.SetBasicAuth(p.username, p.password)
+	}
 
 	resp, err := p.client.Do(req)
 	if err != nil {
@@ -78,10 +171,13 @@ func (p *PromProbe) Execute(ctx context.Context) (*ProbeResult, error) {
 		return nil, fmt.Errorf("prometheus returned %d", resp.StatusCode)
 	}
 
+	// Instant queries return one "value" per series, range queries a list of
+	// "values"
 	var body struct {
 		Data struct {
 			Result []struct {
-				Value [2]json.RawMessage `json:"value"`
+				Value  []json.RawMessage   `json:"value"`
+				Values [][]json.RawMessage `json:"values"`
 			} `json:"result"`
 		} `json:"data"`
 	}
@@ -89,7 +185,22 @@ func (p *PromProbe) Execute(ctx context.Context) (*ProbeResult, error) {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	if len(body.Data.Result) == 0 {
+	var samples []promSample
+	for _, series := range body.Data.Result {
+		points := series.Values
+		if len(series.Value) > 0 {
+			points = append(points, series.Value)
+		}
+		for _, pt := range points {
+			s, err := parsePromSample(pt)
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, s)
+		}
+	}
+
+	if len(samples) == 0 {
 		return &ProbeResult{
 			ProbeName: p.name,
 			ProbeType: "prometheus",
@@ -102,35 +213,106 @@ func (p *PromProbe) Execute(ctx context.Context) (*ProbeResult, error) {
 			ExecutedAt: time.Now().UTC(),
 		}, nil
 	}
-
-	// Parse the first result's value (index 1 is the actual value)
-	var valStr string
-	if err := json.Unmarshal(body.Data.Result[0].Value[1], &valStr); err != nil {
-		return nil, fmt.Errorf("parse value: %w", err)
-	}
-	value, err := strconv.ParseFloat(valStr, 64)
-	if err != nil {
-		return nil, fmt.Errorf("parse float value: %w", err)
+	if p.aggregation == "" && len(samples) > 1 {
+		return nil, fmt.Errorf("query returned %d series; set an aggregation (%s) to combine them",
+			len(body.Data.Result), strings.Join(PromAggregations, ", "))
 	}
 
+	value := aggregateSamples(p.aggregation, samples)
 	passed := p.compare(value)
 
+	detail := map[string]any{
+		"query":        p.query,
+		"value":        value,
+		"comparator":   p.comparator,
+		"threshold":    p.threshold,
+		"result_count": len(body.Data.Result),
+	}
+	if p.aggregation != "" {
+		detail["aggregation"] = p.aggregation
+		detail["sample_count"] = len(samples)
+	}
+	if p.queryRange > 0 {
+		detail["range_seconds"] = p.queryRange.Seconds()
+		detail["step_seconds"] = p.stepSeconds
+	}
+	if !p.evalTime.IsZero() {
+		detail["time"] = p.evalTime.UTC().Format(time.RFC3339Nano)
+	}
+
 	return &ProbeResult{
-		ProbeName: p.name,
-		ProbeType: "prometheus",
-		Mode:      p.mode,
-		Passed:    passed,
-		Detail: map[string]any{
-			"query":        p.query,
-			"value":        value,
-			"comparator":   p.comparator,
-			"threshold":    p.threshold,
-			"result_count": len(body.Data.Result),
-		},
+		ProbeName:  p.name,
+		ProbeType:  "prometheus",
+		Mode:       p.mode,
+		Passed:     passed,
+		Detail:     detail,
 		ExecutedAt: time.Now().UTC(),
 	}, nil
 }
 
+// formatPromTime renders t as the Unix seconds, with fractional
+// milliseconds, that the Prometheus API takes for time
+func formatPromTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
+}
+
+// promSample is one [timestamp, "value"] pair from a query result
+type promSample struct {
+	ts    float64
+	value float64
+}
+
+func parsePromSample(pt []json.RawMessage) (promSample, error) {
+	if len(pt) != 2 {
+		return promSample{}, fmt.Errorf("parse value: expected [timestamp, value], got %d elements", len(pt))
+	}
+	var s promSample
+	if err := json.Unmarshal(pt[0], &s.ts); err != nil {
+		return promSample{}, fmt.Errorf("parse timestamp: %w", err)
+	}
+	var valStr string
+	if err := json.Unmarshal(pt[1], &valStr); err != nil {
+		return promSample{}, fmt.Errorf("parse value: %w", err)
+	}
+	v, err := strconv.ParseFloat(valStr, 64)
+	if err != nil {
+		return promSample{}, fmt.Errorf("parse float value: %w", err)
+	}
+	s.value = v
+	return s, nil
+}
+
+// aggregateSamples reduces samples from every series to one value. "last"
+// picks the most recent sample across all series.
+func aggregateSamples(aggregation string, samples []promSample) float64 {
+	result := samples[0].value
+	switch aggregation {
+	case "max":
+		for _, s := range samples[1:] {
+			result = max(result, s.value)
+		}
+	case "min":
+		for _, s := range samples[1:] {
+			result = min(result, s.value)
+		}
+	case "avg":
+		sum := 0.0
+		for _, s := range samples {
+			sum += s.value
+		}
+		result = sum / float64(len(samples))
+	case "last":
+		latest := samples[0]
+		for _, s := range samples[1:] {
+			if s.ts >= latest.ts {
+				latest = s
+			}
+		}
+		result = latest.value
+	}
+	return result
+}
+
 func (p *PromProbe) compare(value float64) bool {
 	switch p.comparator {
 	case ">":
@@ -2,9 +2,14 @@ package probe
 
 import (
 	"context"
+	"encoding/pem"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/chaosduck/backend-go/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -25,7 +30,7 @@ func TestPromProbeSuccess(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	p := NewPromProbe(PromProbeConfig{
+	p, err := NewPromProbe(PromProbeConfig{
 		Name:       "latency-check",
 		Mode:       domain.ProbeModeSOT,
 		Endpoint:   srv.URL,
@@ -33,6 +38,7 @@ func TestPromProbeSuccess(t *testing.T) {
 		Comparator: ">",
 		Threshold:  0.5,
 	})
+	require.NoError(t, err)
 
 	assert.Equal(t, "latency-check", p.Name())
 	assert.Equal(t, "prometheus", p.Type())
@@ -61,7 +67,7 @@ func TestPromProbeFailsThreshold(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	p := NewPromProbe(PromProbeConfig{
+	p, err := NewPromProbe(PromProbeConfig{
 		Name:       "low-value",
 		Mode:       domain.ProbeModeSOT,
 		Endpoint:   srv.URL,
@@ -69,6 +75,7 @@ func TestPromProbeFailsThreshold(t *testing.T) {
 		Comparator: ">",
 		Threshold:  0.5,
 	})
+	require.NoError(t, err)
 
 	result, err := p.Execute(context.Background())
 	require.NoError(t, err)
@@ -82,12 +89,13 @@ func TestPromProbeNoResults(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	p := NewPromProbe(PromProbeConfig{
+	p, err := NewPromProbe(PromProbeConfig{
 		Name:     "empty",
 		Mode:     domain.ProbeModeSOT,
 		Endpoint: srv.URL,
 		Query:    "nonexistent_metric",
 	})
+	require.NoError(t, err)
 
 	result, err := p.Execute(context.Background())
 	require.NoError(t, err)
@@ -101,14 +109,15 @@ func TestPromProbeServerError(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	p := NewPromProbe(PromProbeConfig{
+	p, err := NewPromProbe(PromProbeConfig{
 		Name:     "server-err",
 		Mode:     domain.ProbeModeSOT,
 		Endpoint: srv.URL,
 		Query:    "up",
 	})
+	require.NoError(t, err)
 
-	_, err := p.Execute(context.Background())
+	_, err = p.Execute(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "500")
 }
@@ -142,7 +151,7 @@ func TestPromProbeComparators(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.comparator, func(t *testing.T) {
-			p := NewPromProbe(PromProbeConfig{
+			p, err := NewPromProbe(PromProbeConfig{
 				Name:       "cmp-test",
 				Mode:       domain.ProbeModeSOT,
 				Endpoint:   srv.URL,
@@ -150,6 +159,7 @@ func TestPromProbeComparators(t *testing.T) {
 				Comparator: tt.comparator,
 				Threshold:  tt.threshold,
 			})
+			require.NoError(t, err)
 
 			result, err := p.Execute(context.Background())
 			require.NoError(t, err)
@@ -159,24 +169,260 @@ func TestPromProbeComparators(t *testing.T) {
 }
 
 func TestPromProbeDefaultComparator(t *testing.T) {
-	p := NewPromProbe(PromProbeConfig{
+	p, err := NewPromProbe(PromProbeConfig{
 		Name:     "default-cmp",
 		Mode:     domain.ProbeModeSOT,
 		Endpoint: "http://localhost:9090",
 		Query:    "up",
 	})
+	require.NoError(t, err)
 	// Default comparator should be ">"
 	assert.Equal(t, ">", p.comparator)
 }
 
 func TestPromProbeConnectionRefused(t *testing.T) {
-	p := NewPromProbe(PromProbeConfig{
+	p, err := NewPromProbe(PromProbeConfig{
 		Name:     "unreachable",
 		Mode:     domain.ProbeModeSOT,
 		Endpoint: "http://127.0.0.1:1",
 		Query:    "up",
 	})
+	require.NoError(t, err)
+
+	_, err = p.Execute(context.Background())
+	assert.Error(t, err)
+}
+
+// promOK answers every query with a single passing sample
+func promOK(w http.ResponseWriter) {
+	_, _ = w.Write([]byte(`{"data":{"result":[{"value":[1234567890,"1"]}]}}`))
+}
+
+func TestPromProbeBearerToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		promOK(w)
+	}))
+	defer srv.Close()
+
+	p, err := NewPromProbe(PromProbeConfig{
+		Name: "auth", Mode: domain.ProbeModeSOT, Endpoint: srv.URL, Query: "up",
+		BearerToken: "s3cret",
+		// Ignored when a bearer token is set
+		Username: "admin", Password: "pw",
+	})
+	require.NoError(t, err)
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.NotContains(t, result.Detail, "bearer_token")
+}
+
+func TestPromProbeBasicAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "admin" || pass != "pw" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		promOK(w)
+	}))
+	defer srv.Close()
+
+	p, err := NewPromProbe(PromProbeConfig{
+		Name: "basic", Mode: domain.ProbeModeSOT, Endpoint: srv.URL, Query: "up",
+		Username: "admin", Password: "pw",
+	})
+	require.NoError(t, err)
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+}
+
+func TestPromProbeSelfSignedTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		promOK(w)
+	}))
+	defer srv.Close()
+
+	cfg := PromProbeConfig{Name: "tls", Mode: domain.ProbeModeSOT, Endpoint: srv.URL, Query: "up"}
+
+	// Untrusted by default
+	p, err := NewPromProbe(cfg)
+	require.NoError(t, err)
+	_, err = p.Execute(context.Background())
+	require.Error(t, err)
 
-	_, err := p.Execute(context.Background())
+	insecure := cfg
+	insecure.TLS.InsecureSkipVerify = true
+	p, err = NewPromProbe(insecure)
+	require.NoError(t, err)
+	_, err = p.Execute(context.Background())
+	require.NoError(t, err)
+
+	certDir := t.TempDir()
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, os.WriteFile(filepath.Join(certDir, "ca.pem"), certPEM, 0o600))
+	withCA := cfg
+	withCA.TLS = TLSConfig{CAFile: "ca.pem", CertDir: certDir}
+	p, err = NewPromProbe(withCA)
+	require.NoError(t, err)
+	_, err = p.Execute(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPromProbeBadCAFile(t *testing.T) {
+	certDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(certDir, "ca.pem"), []byte("not a cert"), 0o600))
+
+	_, err := NewPromProbe(PromProbeConfig{Name: "tls", Endpoint: "https://prom", Query: "up",
+		TLS: TLSConfig{CAFile: "ca.pem", CertDir: certDir}})
 	assert.Error(t, err)
 }
+
+// multiSeries answers with two series; instant queries can't pick between them
+const multiSeries = `{"data":{"result":[
+	{"value":[100,"0.2"]},
+	{"value":[101,"0.8"]}
+]}}`
+
+func TestPromProbeMultiSeriesIsAmbiguous(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(multiSeries))
+	}))
+	defer srv.Close()
+
+	p, err := NewPromProbe(PromProbeConfig{Name: "multi", Mode: domain.ProbeModeSOT, Endpoint: srv.URL, Query: "up"})
+	require.NoError(t, err)
+
+	_, err = p.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 series")
+}
+
+func TestPromProbeMultiSeriesAggregation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(multiSeries))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		aggregation string
+		expected    float64
+	}{
+		{"max", 0.8},
+		{"min", 0.2},
+		{"avg", 0.5},
+		{"last", 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.aggregation, func(t *testing.T) {
+			p, err := NewPromProbe(PromProbeConfig{
+				Name: "agg", Mode: domain.ProbeModeSOT, Endpoint: srv.URL, Query: "up",
+				Aggregation: tt.aggregation,
+			})
+			require.NoError(t, err)
+
+			result, err := p.Execute(context.Background())
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, result.Detail["value"], 1e-9)
+			assert.Equal(t, 2, result.Detail["sample_count"])
+		})
+	}
+}
+
+func TestPromProbeRangeQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query_range", r.URL.Path)
+		assert.Equal(t, "rate(errors[1m])", r.URL.Query().Get("query"))
+		assert.Equal(t, "30", r.URL.Query().Get("step"))
+		assert.NotEmpty(t, r.URL.Query().Get("start"))
+		assert.NotEmpty(t, r.URL.Query().Get("end"))
+		_, _ = w.Write([]byte(`{"data":{"result":[
+			{"values":[[100,"1"],[130,"9"],[160,"2"]]},
+			{"values":[[100,"3"],[130,"4"]]}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewPromProbe(PromProbeConfig{
+		Name: "range", Mode: domain.ProbeModeSOT, Endpoint: srv.URL, Query: "rate(errors[1m])",
+		Comparator: "<", Threshold: 5,
+		Range: 5 * time.Minute, StepSeconds: 30, Aggregation: "max",
+	})
+	require.NoError(t, err)
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+	// The 9 spike fails the check even though the latest sample is healthy
+	assert.False(t, result.Passed)
+	assert.Equal(t, 9.0, result.Detail["value"])
+	assert.Equal(t, 5, result.Detail["sample_count"])
+	assert.Equal(t, 300.0, result.Detail["range_seconds"])
+
+	p.aggregation = "last"
+	result, err = p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.Equal(t, 2.0, result.Detail["value"])
+}
+
+func TestPromProbeTimeAndQueryTimeout(t *testing.T) {
+	var got url.Values
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, got = r.URL.Path, r.URL.Query()
+		_, _ = w.Write([]byte(`{"data":{"result":[{"value":[1700000000,"1"]}]}}`))
+	}))
+	defer srv.Close()
+
+	at := time.Date(2023, 11, 14, 22, 13, 20, 500_000_000, time.UTC)
+	p, err := NewPromProbe(PromProbeConfig{
+		Name: "at", Mode: domain.ProbeModeSOT, Endpoint: srv.URL, Query: "up",
+		Time: at, QueryTimeout: 1500 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	result, err := p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/query", path)
+	assert.Equal(t, "up", got.Get("query"))
+	assert.Equal(t, "1700000000.5", got.Get("time"))
+	assert.Equal(t, "1.5", got.Get("timeout"))
+	assert.Equal(t, "2023-11-14T22:13:20.5Z", result.Detail["time"])
+
+	// A range query ends at the configured time
+	p.queryRange, p.aggregation = time.Minute, "max"
+	_, err = p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/query_range", path)
+	assert.Equal(t, "1700000000", got.Get("end"))
+	assert.Equal(t, "1699999940", got.Get("start"))
+	assert.Empty(t, got.Get("time"))
+	assert.Equal(t, "1.5", got.Get("timeout"))
+
+	// Neither parameter is sent by default
+	p, err = NewPromProbe(PromProbeConfig{Name: "now", Mode: domain.ProbeModeSOT, Endpoint: srv.URL, Query: "up"})
+	require.NoError(t, err)
+	_, err = p.Execute(context.Background())
+	require.NoError(t, err)
+	assert.False(t, got.Has("time"))
+	assert.False(t, got.Has("timeout"))
+}
+
+func TestPromProbeRangeConfigValidation(t *testing.T) {
+	_, err := NewPromProbe(PromProbeConfig{Name: "r", Endpoint: "http://prom", Query: "up", Range: time.Minute})
+	assert.ErrorContains(t, err, "require an aggregation")
+
+	_, err = NewPromProbe(PromProbeConfig{Name: "r", Endpoint: "http://prom", Query: "up", Aggregation: "median"})
+	assert.ErrorContains(t, err, "unknown aggregation")
+
+	p, err := NewPromProbe(PromProbeConfig{Name: "r", Endpoint: "http://prom", Query: "up", Range: time.Minute, Aggregation: "avg"})
+	require.NoError(t, err)
+	assert.Equal(t, 15, p.stepSeconds)
+}
@@ -0,0 +1,113 @@
+package probe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// TLSConfig holds the TLS settings shared by the HTTP and Prometheus probes.
+// CACert, Cert and Key are inline PEM. CAFile, CertFile and KeyFile name PEM
+// files inside CertDir and are used when the matching inline value is empty;
+// without a CertDir they are rejected, so a probe definition cannot make the
+// server read arbitrary files.
+type TLSConfig struct {
+	// InsecureSkipVerify disables TLS certificate verification
+	InsecureSkipVerify bool
+	// CACert or CAFile is a PEM bundle trusted in addition to the system roots
+	CACert string
+	CAFile string
+	// Cert and Key, or CertFile and KeyFile, are the client certificate and
+	// key for mTLS
+	Cert     string
+	CertFile string
+	Key      string
+	KeyFile  string
+	// CertDir is the directory file references are resolved in
+	CertDir string
+}
+
+func (c TLSConfig) enabled() bool {
+	return c.InsecureSkipVerify || c.CACert != "" || c.CAFile != "" ||
+		c.Cert != "" || c.CertFile != "" || c.Key != "" || c.KeyFile != ""
+}
+
+// applyTLS gives client a transport with c's TLS settings. A zero c leaves
+// the client on the default transport.
+func applyTLS(client *http.Client, c TLSConfig) error {
+	if !c.enabled() {
+		return nil
+	}
+	tlsConfig, err := c.clientConfig()
+	if err != nil {
+		return err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client.Transport = transport
+	return nil
+}
+
+// clientConfig builds the TLS settings for a self-signed, private-CA or mTLS
+// endpoint
+func (c TLSConfig) clientConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify} //nolint:gosec // opt-in, warned about
+	if c.CACert != "" || c.CAFile != "" {
+		caPEM, err := c.readPEM(c.CACert, c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA cert: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in CA cert")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	hasCert, hasKey := c.Cert != "" || c.CertFile != "", c.Key != "" || c.KeyFile != ""
+	if hasCert != hasKey {
+		return nil, fmt.Errorf("client cert and key must be set together")
+	}
+	if hasCert {
+		certPEM, err := c.readPEM(c.Cert, c.CertFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client cert: %w", err)
+		}
+		keyPEM, err := c.readPEM(c.Key, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client key: %w", err)
+		}
+		pair, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("load client cert: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+	return tlsConfig, nil
+}
+
+// readPEM returns inline when set, and otherwise reads file from CertDir.
+// The file cannot escape CertDir through ".." or symlinks.
+func (c TLSConfig) readPEM(inline, file string) ([]byte, error) {
+	if inline != "" {
+		if !strings.Contains(inline, "-----BEGIN ") {
+			return nil, errors.New("not inline PEM; reference files by name inside the probe certificate directory")
+		}
+		return []byte(inline), nil
+	}
+	if c.CertDir == "" {
+		return nil, fmt.Errorf("%s: no probe certificate directory is configured", file)
+	}
+	root, err := os.OpenRoot(c.CertDir)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+	return root.ReadFile(file)
+}
@@ -0,0 +1,162 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// NamespaceFreeze is a namespace pattern temporarily off-limits to chaos
+type NamespaceFreeze struct {
+	Pattern  string    `json:"pattern"`
+	Reason   string    `json:"reason,omitempty"`
+	FrozenAt time.Time `json:"frozen_at"`
+}
+
+// NamespaceFreezeManager tracks namespaces frozen from chaos. Patterns are
+// matched with filepath.Match, like RequireConfirmation, and persisted to
+// the namespace_freezes table when queries are set.
+type NamespaceFreezeManager struct {
+	mu      sync.RWMutex
+	freezes map[string]NamespaceFreeze
+	queries db.Querier
+}
+
+// NewNamespaceFreezeManager creates a new NamespaceFreezeManager
+func NewNamespaceFreezeManager(queries db.Querier) *NamespaceFreezeManager {
+	return &NamespaceFreezeManager{
+		freezes: make(map[string]NamespaceFreeze),
+		queries: queries,
+	}
+}
+
+// Load restores persisted freezes, replacing the in-memory set
+func (fm *NamespaceFreezeManager) Load(ctx context.Context) error {
+	if fm.queries == nil {
+		return nil
+	}
+	rows, err := fm.queries.ListNamespaceFreezes(ctx)
+	if err != nil {
+		return fmt.Errorf("list namespace freezes: %w", err)
+	}
+	freezes := make(map[string]NamespaceFreeze, len(rows))
+	for _, row := range rows {
+		freezes[row.Pattern] = NamespaceFreeze{
+			Pattern:  row.Pattern,
+			Reason:   row.Reason.String,
+			FrozenAt: row.CreatedAt.Time,
+		}
+	}
+
+	fm.mu.Lock()
+	fm.freezes = freezes
+	fm.mu.Unlock()
+	return nil
+}
+
+// Freeze marks namespaces matching pattern as off-limits. Freezing an
+// already frozen pattern updates its reason.
+func (fm *NamespaceFreezeManager) Freeze(ctx context.Context, pattern, reason string) (NamespaceFreeze, error) {
+	if err := ValidateNamespacePattern(pattern); err != nil {
+		return NamespaceFreeze{}, err
+	}
+	freeze := NamespaceFreeze{Pattern: pattern, Reason: reason, FrozenAt: time.Now().UTC()}
+	if fm.queries != nil {
+		row, err := fm.queries.UpsertNamespaceFreeze(ctx, db.UpsertNamespaceFreezeParams{
+			Pattern: pattern,
+			Reason:  pgtype.Text{String: reason, Valid: reason != ""},
+		})
+		if err != nil {
+			return NamespaceFreeze{}, fmt.Errorf("persist namespace freeze: %w", err)
+		}
+		freeze.FrozenAt = row.CreatedAt.Time
+	}
+
+	fm.mu.Lock()
+	if existing, ok := fm.freezes[pattern]; ok && fm.queries == nil {
+		freeze.FrozenAt = existing.FrozenAt
+	}
+	fm.freezes[pattern] = freeze
+	fm.mu.Unlock()
+
+	log.Printf("Namespace pattern %q frozen from chaos", pattern)
+	return freeze, nil
+}
+
+// Unfreeze lifts the freeze on pattern. It reports whether the pattern
+// was frozen.
+func (fm *NamespaceFreezeManager) Unfreeze(ctx context.Context, pattern string) (bool, error) {
+	fm.mu.RLock()
+	_, ok := fm.freezes[pattern]
+	fm.mu.RUnlock()
+	if !ok {
+		return false, nil
+	}
+	if fm.queries != nil {
+		if err := fm.queries.DeleteNamespaceFreeze(ctx, pattern); err != nil {
+			return false, fmt.Errorf("delete namespace freeze: %w", err)
+		}
+	}
+
+	fm.mu.Lock()
+	delete(fm.freezes, pattern)
+	fm.mu.Unlock()
+
+	log.Printf("Namespace pattern %q unfrozen", pattern)
+	return true, nil
+}
+
+// IsFrozen reports whether namespace matches any frozen pattern
+func (fm *NamespaceFreezeManager) IsFrozen(namespace string) bool {
+	_, ok := fm.match(namespace)
+	return ok
+}
+
+// CheckNamespace returns ErrNamespaceFrozen if namespace is frozen
+func (fm *NamespaceFreezeManager) CheckNamespace(namespace string) error {
+	if pattern, ok := fm.match(namespace); ok {
+		return fmt.Errorf("%w: %s (pattern %q)", domain.ErrNamespaceFrozen, namespace, pattern)
+	}
+	return nil
+}
+
+// List returns the active freezes ordered by pattern
+func (fm *NamespaceFreezeManager) List() []NamespaceFreeze {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+	out := make([]NamespaceFreeze, 0, len(fm.freezes))
+	for _, f := range fm.freezes {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Pattern < out[j].Pattern })
+	return out
+}
+
+// match returns the first frozen pattern, in pattern order, matching namespace
+func (fm *NamespaceFreezeManager) match(namespace string) (string, bool) {
+	for _, f := range fm.List() {
+		if matched, _ := filepath.Match(f.Pattern, namespace); matched {
+			return f.Pattern, true
+		}
+	}
+	return "", false
+}
+
+// ValidateNamespacePattern checks that pattern is a usable glob
+func ValidateNamespacePattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("%w: namespace pattern is required", domain.ErrInvalidConfig)
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("%w: namespace pattern %q: %v", domain.ErrInvalidConfig, pattern, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package safety
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chaosduck/backend-go/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceFreezeGlob(t *testing.T) {
+	fm := NewNamespaceFreezeManager(nil)
+	ctx := context.Background()
+
+	_, err := fm.Freeze(ctx, "payments", "incident INC-42")
+	require.NoError(t, err)
+	_, err = fm.Freeze(ctx, "team-a-*", "")
+	require.NoError(t, err)
+
+	assert.True(t, fm.IsFrozen("payments"))
+	assert.True(t, fm.IsFrozen("team-a-api"))
+	assert.False(t, fm.IsFrozen("payments-staging"))
+	assert.False(t, fm.IsFrozen("team-b-api"))
+
+	err = fm.CheckNamespace("team-a-api")
+	assert.ErrorIs(t, err, domain.ErrNamespaceFrozen)
+	assert.Contains(t, err.Error(), `"team-a-*"`)
+	assert.NoError(t, fm.CheckNamespace("default"))
+
+	freezes := fm.List()
+	require.Len(t, freezes, 2)
+	assert.Equal(t, "payments", freezes[0].Pattern)
+	assert.Equal(t, "incident INC-42", freezes[0].Reason)
+	assert.False(t, freezes[0].FrozenAt.IsZero())
+}
+
+func TestNamespaceUnfreeze(t *testing.T) {
+	fm := NewNamespaceFreezeManager(nil)
+	ctx := context.Background()
+	_, err := fm.Freeze(ctx, "team-a-*", "")
+	require.NoError(t, err)
+
+	// Unfreezing takes the exact pattern, not a namespace it matches
+	ok, err := fm.Unfreeze(ctx, "team-a-api")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.True(t, fm.IsFrozen("team-a-api"))
+
+	ok, err = fm.Unfreeze(ctx, "team-a-*")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, fm.IsFrozen("team-a-api"))
+	assert.Empty(t, fm.List())
+}
+
+func TestNamespaceFreezeRejectsBadPattern(t *testing.T) {
+	fm := NewNamespaceFreezeManager(nil)
+	for _, pattern := range []string{"", "team-[a"} {
+		_, err := fm.Freeze(context.Background(), pattern, "")
+		assert.ErrorIs(t, err, domain.ErrInvalidConfig, pattern)
+	}
+	assert.Empty(t, fm.List())
+}
@@ -84,6 +84,37 @@ func ValidateBlastRadius(affected, total int, maxRatio float64) error {
 	return nil
 }
 
+// ValidateWeightedBlastRadius is ValidateBlastRadius with each pod counted
+// by the weight of its criticality level instead of as 1. affected and all
+// hold the criticality level of each pod; levels missing from weights count
+// as domain.DefaultCriticality. When the pods' weights sum to zero, the
+// plain pod count is checked instead.
+func ValidateWeightedBlastRadius(affected, all []string, weights map[string]float64, maxRatio float64) error {
+	total := criticalityWeight(all, weights)
+	if total <= 0 {
+		return ValidateBlastRadius(len(affected), len(all), maxRatio)
+	}
+	ratio := criticalityWeight(affected, weights) / total
+	if ratio > maxRatio {
+		log.Printf("Weighted blast radius %.1f%% exceeds max %.1f%%", ratio*100, maxRatio*100)
+		return domain.ErrBlastRadiusExceeded
+	}
+	return nil
+}
+
+// criticalityWeight sums the weights of levels
+func criticalityWeight(levels []string, weights map[string]float64) float64 {
+	sum := 0.0
+	for _, level := range levels {
+		w, ok := weights[level]
+		if !ok {
+			w = weights[domain.DefaultCriticality]
+		}
+		sum += w
+	}
+	return sum
+}
+
 // RequireConfirmation checks if a namespace matches a production pattern
 // and ensures explicit confirmation is set
 func RequireConfirmation(namespace, pattern string, confirmed bool) error {
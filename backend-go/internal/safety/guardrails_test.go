@@ -109,6 +109,61 @@ func TestValidateBlastRadius(t *testing.T) {
 	}
 }
 
+func TestValidateWeightedBlastRadius(t *testing.T) {
+	weights := domain.DefaultCriticalityWeights
+	// A database tier of three high pods next to six low stateless replicas
+	all := []string{"high", "high", "high", "low", "low", "low", "low", "low", "low"}
+
+	tests := []struct {
+		name     string
+		affected []string
+		maxRatio float64
+		wantErr  bool
+	}{
+		// 4 of 18: a third of the database is 22% of the weight
+		{"one critical pod", []string{"high"}, 0.3, false},
+		// 8 of 18
+		{"two critical pods", []string{"high", "high"}, 0.3, true},
+		// 3 of 18: three pods is a third by count but well within by weight
+		{"three stateless pods", []string{"low", "low", "low"}, 0.2, false},
+		// 5 of 18
+		{"mixed", []string{"high", "low"}, 0.25, true},
+		{"zero total", nil, 0.3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := all
+			if tt.affected == nil {
+				total = nil
+			}
+			err := ValidateWeightedBlastRadius(tt.affected, total, weights, tt.maxRatio)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrBlastRadiusExceeded)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateWeightedBlastRadiusUnlabeledPodsCountAsDefault(t *testing.T) {
+	weights := map[string]float64{"high": 10, "medium": 1, "low": 1}
+	// "" and "unknown" weigh as medium, so one high pod is 10 of 13
+	all := []string{"high", "", "unknown", "low"}
+
+	assert.ErrorIs(t, ValidateWeightedBlastRadius([]string{"high"}, all, weights, 0.5), domain.ErrBlastRadiusExceeded)
+	assert.NoError(t, ValidateWeightedBlastRadius([]string{"", "unknown"}, all, weights, 0.2))
+}
+
+func TestValidateWeightedBlastRadiusZeroWeightsFallBackToCount(t *testing.T) {
+	weights := map[string]float64{"medium": 0}
+	all := []string{"", "", ""}
+
+	assert.ErrorIs(t, ValidateWeightedBlastRadius(all, all, weights, 0.3), domain.ErrBlastRadiusExceeded)
+	assert.NoError(t, ValidateWeightedBlastRadius([]string{""}, all, weights, 0.4))
+}
+
 func TestRequireConfirmation(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -120,7 +120,7 @@ func (hc *HealthCheckLoop) run(ctx context.Context) {
 				if hc.onFailure != nil {
 					hc.onFailure()
 				} else if hc.rollbackMgr != nil {
-					hc.rollbackMgr.Rollback(hc.experimentID)
+					hc.rollbackMgr.Rollback(context.Background(), hc.experimentID)
 				}
 
 				hc.Stop()
@@ -47,7 +47,7 @@ func TestHealthCheckLoopStartStop(t *testing.T) {
 
 func TestHealthCheckLoopFailureThreshold(t *testing.T) {
 	rm := NewRollbackManager()
-	rm.Push("exp-1", func() (map[string]any, error) {
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
 		return map[string]any{"rolled_back": true}, nil
 	}, "test-action")
 
@@ -66,7 +66,7 @@ func TestHealthCheckLoopFailureThreshold(t *testing.T) {
 
 func TestHealthCheckLoopAllPassing(t *testing.T) {
 	rm := NewRollbackManager()
-	rm.Push("exp-1", func() (map[string]any, error) {
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
 		return nil, nil
 	}, "should-not-rollback")
 
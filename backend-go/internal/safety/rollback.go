@@ -1,57 +1,118 @@
 package safety
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"sync"
+	"time"
 
+	"github.com/chaosduck/backend-go/internal/db"
 	"github.com/chaosduck/backend-go/internal/domain"
 )
 
-// rollbackEntry pairs a description with its undo function
+// rollbackEntry pairs a description with its undo function. ActionID is the
+// rollback_actions row persisted for the entry, or 0 when none was.
 type rollbackEntry struct {
 	Description string
 	Fn          domain.RollbackFunc
+	ActionID    int32
 }
 
 // RollbackResult describes the outcome of a single rollback operation
-type RollbackResult struct {
-	Description string         `json:"description"`
-	Status      string         `json:"status"`
-	Result      map[string]any `json:"result,omitempty"`
-	Error       string         `json:"error,omitempty"`
-}
+type RollbackResult = domain.RollbackResult
+
+// DefaultRollbackTimeout bounds a single rollback function
+const DefaultRollbackTimeout = 30 * time.Second
 
 // RollbackManager maintains per-experiment LIFO rollback stacks
 type RollbackManager struct {
-	mu     sync.Mutex
-	stacks map[string][]rollbackEntry
+	mu       sync.Mutex
+	stacks   map[string][]rollbackEntry
+	observer func(status string)
+	queries  db.Querier
+	timeout  time.Duration
 }
 
 // NewRollbackManager creates a new RollbackManager
 func NewRollbackManager() *RollbackManager {
 	return &RollbackManager{
-		stacks: make(map[string][]rollbackEntry),
+		stacks:  make(map[string][]rollbackEntry),
+		timeout: DefaultRollbackTimeout,
 	}
 }
 
+// SetTimeout sets how long a single rollback function may run
+func (rm *RollbackManager) SetTimeout(d time.Duration) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.timeout = d
+}
+
+// SetObserver registers a callback invoked with the status ("success" or
+// "failed") of every executed rollback entry
+func (rm *RollbackManager) SetObserver(fn func(status string)) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.observer = fn
+}
+
+// SetQueries enables persisting rollback actions to the rollback_actions
+// table so they can be reconstructed after a restart
+func (rm *RollbackManager) SetQueries(queries db.Querier) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.queries = queries
+}
+
 // Push adds a rollback function to the experiment's stack
 func (rm *RollbackManager) Push(experimentID string, fn domain.RollbackFunc, description string) {
+	rm.PushAction(experimentID, fn, description, nil)
+}
+
+// PushAction adds a rollback function to the experiment's stack and, when
+// action is non-nil, persists it until the rollback succeeds
+func (rm *RollbackManager) PushAction(experimentID string, fn domain.RollbackFunc, description string, action *domain.RollbackAction) {
+	rm.mu.Lock()
+	queries := rm.queries
+	rm.mu.Unlock()
+
+	var actionID int32
+	if queries != nil && action != nil {
+		row, err := queries.CreateRollbackAction(context.Background(), db.CreateRollbackActionParams{
+			ExperimentID: experimentID,
+			ChaosType:    string(action.ChaosType),
+			Description:  description,
+			Data:         action.Data,
+		})
+		if err != nil {
+			log.Printf("DB persistence skipped for rollback action: %v", err)
+		} else {
+			actionID = row.ID
+		}
+	}
+
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 
 	rm.stacks[experimentID] = append(rm.stacks[experimentID], rollbackEntry{
 		Description: description,
 		Fn:          fn,
+		ActionID:    actionID,
 	})
 	log.Printf("Rollback pushed for %s: %s (stack size: %d)",
 		experimentID, description, len(rm.stacks[experimentID]))
 }
 
-// Rollback executes all rollback functions for an experiment in LIFO order
-func (rm *RollbackManager) Rollback(experimentID string) []RollbackResult {
+// Rollback executes all rollback functions for an experiment in LIFO order.
+// Each one is bounded by the manager's per-rollback timeout and by ctx.
+func (rm *RollbackManager) Rollback(ctx context.Context, experimentID string) []RollbackResult {
 	rm.mu.Lock()
 	stack := rm.stacks[experimentID]
 	delete(rm.stacks, experimentID)
+	observer := rm.observer
+	queries := rm.queries
+	timeout := rm.timeout
 	rm.mu.Unlock()
 
 	var results []RollbackResult
@@ -59,7 +120,7 @@ func (rm *RollbackManager) Rollback(experimentID string) []RollbackResult {
 	// Execute in reverse (LIFO)
 	for i := len(stack) - 1; i >= 0; i-- {
 		entry := stack[i]
-		result, err := entry.Fn()
+		result, err := runRollback(ctx, entry.Fn, timeout)
 		if err != nil {
 			results = append(results, RollbackResult{
 				Description: entry.Description,
@@ -74,24 +135,61 @@ func (rm *RollbackManager) Rollback(experimentID string) []RollbackResult {
 				Result:      result,
 			})
 			log.Printf("Rollback success: %s", entry.Description)
+			if queries != nil && entry.ActionID != 0 {
+				if err := queries.DeleteRollbackAction(context.Background(), entry.ActionID); err != nil {
+					log.Printf("Failed to delete persisted rollback action %d: %v", entry.ActionID, err)
+				}
+			}
+		}
+		if observer != nil {
+			observer(results[len(results)-1].Status)
 		}
 	}
 
 	return results
 }
 
-// RollbackAll executes rollback for ALL active experiments (emergency stop)
-func (rm *RollbackManager) RollbackAll() map[string][]RollbackResult {
-	rm.mu.Lock()
-	ids := make([]string, 0, len(rm.stacks))
-	for id := range rm.stacks {
-		ids = append(ids, id)
+// runRollback calls fn with a deadline. A rollback that ignores its context
+// is abandoned when the deadline passes so it can't hang the caller.
+func runRollback(ctx context.Context, fn domain.RollbackFunc, timeout time.Duration) (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		result map[string]any
+		err    error
 	}
-	rm.mu.Unlock()
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := fn(ctx)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("rollback abandoned: %w", ctx.Err())
+	}
+}
 
+// RollbackAll executes rollback for ALL active experiments (emergency stop).
+// It keeps draining until no stacks remain, so rollbacks pushed by
+// experiments still mid-run are executed too, and stops early only when ctx
+// is done. Each stack is popped atomically, so concurrent calls never run
+// the same rollback twice.
+func (rm *RollbackManager) RollbackAll(ctx context.Context) map[string][]RollbackResult {
 	all := make(map[string][]RollbackResult)
-	for _, id := range ids {
-		all[id] = rm.Rollback(id)
+	for ctx.Err() == nil {
+		ids := rm.ActiveExperiments()
+		if len(ids) == 0 {
+			break
+		}
+		for _, id := range ids {
+			if results := rm.Rollback(ctx, id); len(results) > 0 {
+				all[id] = append(all[id], results...)
+			}
+		}
 	}
 	return all
 }
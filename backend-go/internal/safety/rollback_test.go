@@ -1,7 +1,12 @@
 package safety
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,13 +17,13 @@ func TestRollbackManagerPushAndSize(t *testing.T) {
 
 	assert.Equal(t, 0, rm.StackSize("exp-1"))
 
-	rm.Push("exp-1", func() (map[string]any, error) {
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
 		return nil, nil
 	}, "action-1")
 
 	assert.Equal(t, 1, rm.StackSize("exp-1"))
 
-	rm.Push("exp-1", func() (map[string]any, error) {
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
 		return nil, nil
 	}, "action-2")
 
@@ -30,22 +35,22 @@ func TestRollbackManagerLIFOOrder(t *testing.T) {
 	rm := NewRollbackManager()
 	var order []string
 
-	rm.Push("exp-1", func() (map[string]any, error) {
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
 		order = append(order, "first")
 		return map[string]any{"step": "first"}, nil
 	}, "first")
 
-	rm.Push("exp-1", func() (map[string]any, error) {
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
 		order = append(order, "second")
 		return map[string]any{"step": "second"}, nil
 	}, "second")
 
-	rm.Push("exp-1", func() (map[string]any, error) {
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
 		order = append(order, "third")
 		return map[string]any{"step": "third"}, nil
 	}, "third")
 
-	results := rm.Rollback("exp-1")
+	results := rm.Rollback(context.Background(), "exp-1")
 
 	require.Len(t, results, 3)
 	// LIFO: third, second, first
@@ -61,15 +66,15 @@ func TestRollbackManagerLIFOOrder(t *testing.T) {
 func TestRollbackManagerPartialFailure(t *testing.T) {
 	rm := NewRollbackManager()
 
-	rm.Push("exp-1", func() (map[string]any, error) {
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
 		return map[string]any{"ok": true}, nil
 	}, "success-action")
 
-	rm.Push("exp-1", func() (map[string]any, error) {
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
 		return nil, assert.AnError
 	}, "fail-action")
 
-	results := rm.Rollback("exp-1")
+	results := rm.Rollback(context.Background(), "exp-1")
 
 	require.Len(t, results, 2)
 	// fail-action runs first (LIFO)
@@ -81,10 +86,34 @@ func TestRollbackManagerPartialFailure(t *testing.T) {
 	assert.Equal(t, "success-action", results[1].Description)
 }
 
+func TestRollbackManagerObserver(t *testing.T) {
+	rm := NewRollbackManager()
+	counts := map[string]int{}
+	rm.SetObserver(func(status string) { counts[status]++ })
+
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
+		return map[string]any{"ok": true}, nil
+	}, "first")
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
+		return nil, assert.AnError
+	}, "second")
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
+		return map[string]any{"ok": true}, nil
+	}, "third")
+
+	rm.Rollback(context.Background(), "exp-1")
+
+	assert.Equal(t, map[string]int{"success": 2, "failed": 1}, counts)
+
+	// Nothing left to roll back, so the observer is not called again
+	rm.Rollback(context.Background(), "exp-1")
+	assert.Equal(t, 3, counts["success"]+counts["failed"])
+}
+
 func TestRollbackManagerEmptyRollback(t *testing.T) {
 	rm := NewRollbackManager()
 
-	results := rm.Rollback("nonexistent")
+	results := rm.Rollback(context.Background(), "nonexistent")
 	assert.Empty(t, results)
 }
 
@@ -93,8 +122,8 @@ func TestRollbackManagerActiveExperiments(t *testing.T) {
 
 	assert.Empty(t, rm.ActiveExperiments())
 
-	rm.Push("exp-1", func() (map[string]any, error) { return nil, nil }, "a")
-	rm.Push("exp-2", func() (map[string]any, error) { return nil, nil }, "b")
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) { return nil, nil }, "a")
+	rm.Push("exp-2", func(context.Context) (map[string]any, error) { return nil, nil }, "b")
 
 	active := rm.ActiveExperiments()
 	assert.Len(t, active, 2)
@@ -106,20 +135,20 @@ func TestRollbackManagerRollbackAll(t *testing.T) {
 	rm := NewRollbackManager()
 	var count int
 
-	rm.Push("exp-1", func() (map[string]any, error) {
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
 		count++
 		return nil, nil
 	}, "a")
-	rm.Push("exp-2", func() (map[string]any, error) {
+	rm.Push("exp-2", func(context.Context) (map[string]any, error) {
 		count++
 		return nil, nil
 	}, "b")
-	rm.Push("exp-2", func() (map[string]any, error) {
+	rm.Push("exp-2", func(context.Context) (map[string]any, error) {
 		count++
 		return nil, nil
 	}, "c")
 
-	all := rm.RollbackAll()
+	all := rm.RollbackAll(context.Background())
 
 	assert.Equal(t, 3, count)
 	assert.Len(t, all, 2)
@@ -127,3 +156,103 @@ func TestRollbackManagerRollbackAll(t *testing.T) {
 	assert.Len(t, all["exp-2"], 2)
 	assert.Empty(t, rm.ActiveExperiments())
 }
+
+func TestRollbackAllDrainsRollbacksPushedMidway(t *testing.T) {
+	rm := NewRollbackManager()
+	var ran []string
+
+	// An experiment still mid-run pushes its undo while the drain is running
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
+		ran = append(ran, "first")
+		rm.Push("exp-late", func(context.Context) (map[string]any, error) {
+			ran = append(ran, "late")
+			return nil, nil
+		}, "late")
+		return nil, nil
+	}, "first")
+
+	all := rm.RollbackAll(context.Background())
+
+	assert.Equal(t, []string{"first", "late"}, ran)
+	assert.Len(t, all["exp-late"], 1)
+	assert.Empty(t, rm.ActiveExperiments())
+}
+
+func TestRollbackAllConcurrentPushesDoNotLeak(t *testing.T) {
+	rm := NewRollbackManager()
+	const pushes = 200
+	var executed [pushes]atomic.Int32
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range pushes {
+			rm.Push(fmt.Sprintf("exp-%d", i%7), func(context.Context) (map[string]any, error) {
+				executed[i].Add(1)
+				return nil, nil
+			}, fmt.Sprintf("undo-%d", i))
+		}
+	}()
+	// Two emergency stops racing each other and the pusher
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rm.RollbackAll(context.Background())
+		}()
+	}
+	wg.Wait()
+	// The runner rolls back anything it pushed after the stop was triggered
+	rm.RollbackAll(context.Background())
+
+	assert.Empty(t, rm.ActiveExperiments())
+	for i := range executed {
+		assert.Equal(t, int32(1), executed[i].Load(), "rollback %d", i)
+	}
+}
+
+func TestRollbackManagerAbandonsSlowRollback(t *testing.T) {
+	rm := NewRollbackManager()
+	rm.SetTimeout(50 * time.Millisecond)
+
+	release := make(chan struct{})
+	defer close(release)
+	// Ignores its context entirely
+	rm.Push("exp-1", func(context.Context) (map[string]any, error) {
+		<-release
+		return nil, nil
+	}, "stuck")
+	rm.Push("exp-1", func(ctx context.Context) (map[string]any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, "slow")
+
+	start := time.Now()
+	results := rm.Rollback(context.Background(), "exp-1")
+	assert.Less(t, time.Since(start), time.Second)
+
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.Equal(t, "failed", r.Status, r.Description)
+		assert.Contains(t, r.Error, "deadline exceeded", r.Description)
+	}
+}
+
+func TestRollbackAllHonorsCallerDeadline(t *testing.T) {
+	rm := NewRollbackManager()
+	for _, id := range []string{"exp-1", "exp-2"} {
+		rm.Push(id, func(ctx context.Context) (map[string]any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}, "slow")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	all := rm.RollbackAll(ctx)
+	assert.Less(t, time.Since(start), time.Second, "shutdown deadline should bound every rollback")
+	assert.Len(t, all, 2)
+}
@@ -3,12 +3,16 @@ package safety
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/chaosduck/backend-go/internal/db"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -16,19 +20,40 @@ const maxSnapshots = 1000
 
 // SnapshotManager captures and stores state snapshots before chaos injection
 type SnapshotManager struct {
-	mu        sync.RWMutex
-	snapshots map[string]map[string]any
-	queries   *db.Queries
+	mu sync.RWMutex
+	// snapshots holds each experiment's snapshots keyed by layer, "k8s" or
+	// "aws"; a cross-layer experiment has one of each
+	snapshots map[string]map[string]map[string]any
+	// latest is the layer each experiment captured last
+	latest map[string]string
+	// captured orders snapshots by when they were stored, for eviction
+	captured map[string]uint64
+	seq      uint64
+	capacity int
+	isActive func(experimentID string) bool
+	queries  db.Querier
 }
 
 // NewSnapshotManager creates a new SnapshotManager
-func NewSnapshotManager(queries *db.Queries) *SnapshotManager {
+func NewSnapshotManager(queries db.Querier) *SnapshotManager {
 	return &SnapshotManager{
-		snapshots: make(map[string]map[string]any),
+		snapshots: make(map[string]map[string]map[string]any),
+		latest:    make(map[string]string),
+		captured:  make(map[string]uint64),
+		capacity:  maxSnapshots,
 		queries:   queries,
 	}
 }
 
+// SetActiveCheck registers a func reporting whether an experiment is still
+// running. Eviction never drops the snapshot of an active experiment, since
+// its rollback may still need it for drift detection.
+func (sm *SnapshotManager) SetActiveCheck(fn func(experimentID string) bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.isActive = fn
+}
+
 // CaptureK8sSnapshot captures Kubernetes resource state before mutation.
 // The actual K8s API calls are delegated to the engine layer;
 // this method stores the provided state data.
@@ -45,10 +70,7 @@ func (sm *SnapshotManager) CaptureK8sSnapshot(
 		"resources":   state,
 	}
 
-	sm.mu.Lock()
-	sm.evictIfNeeded()
-	sm.snapshots[experimentID] = snapshot
-	sm.mu.Unlock()
+	sm.store(experimentID, snapshot)
 
 	sm.persistSnapshot(ctx, experimentID, snapshot)
 	return snapshot, nil
@@ -70,52 +92,116 @@ func (sm *SnapshotManager) CaptureAWSSnapshot(
 		"state":         state,
 	}
 
-	sm.mu.Lock()
-	sm.evictIfNeeded()
-	sm.snapshots[experimentID] = snapshot
-	sm.mu.Unlock()
+	sm.store(experimentID, snapshot)
 
 	sm.persistSnapshot(ctx, experimentID, snapshot)
 	return snapshot, nil
 }
 
-// evictIfNeeded removes the oldest snapshot when at capacity.
+// store keeps a snapshot under its layer, evicting the oldest experiment's
+// snapshots first when at capacity. Replacing an experiment's snapshot of a
+// layer, or adding another layer, counts as a new capture.
+func (sm *SnapshotManager) store(experimentID string, snapshot map[string]any) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	layers, ok := sm.snapshots[experimentID]
+	if !ok {
+		sm.evictIfNeeded()
+		layers = make(map[string]map[string]any, 1)
+		sm.snapshots[experimentID] = layers
+	}
+	layer, _ := snapshot["type"].(string)
+	sm.seq++
+	layers[layer] = snapshot
+	sm.latest[experimentID] = layer
+	sm.captured[experimentID] = sm.seq
+}
+
+// evictIfNeeded removes the oldest snapshot of an inactive experiment when
+// at capacity. If every experiment is still active, nothing is evicted and
+// the store briefly grows past capacity.
 // Must be called with sm.mu held.
 func (sm *SnapshotManager) evictIfNeeded() {
-	if len(sm.snapshots) < maxSnapshots {
+	if len(sm.snapshots) < sm.capacity {
 		return
 	}
-	// Evict the first key found (pseudo-random from map iteration)
-	for k := range sm.snapshots {
-		delete(sm.snapshots, k)
-		break
+	oldest, found := "", false
+	for id, seq := range sm.captured {
+		if sm.isActive != nil && sm.isActive(id) {
+			continue
+		}
+		if !found || seq < sm.captured[oldest] {
+			oldest, found = id, true
+		}
+	}
+	if !found {
+		log.Printf("Snapshot store over capacity (%d): every snapshot belongs to an active experiment", sm.capacity)
+		return
 	}
+	delete(sm.snapshots, oldest)
+	delete(sm.latest, oldest)
+	delete(sm.captured, oldest)
 }
 
-// GetSnapshot returns the stored snapshot for an experiment
+// GetSnapshot returns the snapshot an experiment captured last. A
+// cross-layer experiment also has an earlier one; see GetSnapshots.
 func (sm *SnapshotManager) GetSnapshot(experimentID string) (map[string]any, bool) {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
-	snap, ok := sm.snapshots[experimentID]
+	snap, ok := sm.snapshots[experimentID][sm.latest[experimentID]]
 	return snap, ok
 }
 
+// GetSnapshots returns every snapshot stored for an experiment keyed by
+// layer, "k8s" or "aws". It is empty when none is held in memory.
+func (sm *SnapshotManager) GetSnapshots(experimentID string) map[string]map[string]any {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return maps.Clone(sm.snapshots[experimentID])
+}
+
+// LoadSnapshot returns the snapshot for an experiment, falling back to the
+// latest one persisted in the database when it is no longer held in memory,
+// e.g. after a restart. ok is false when neither has one.
+func (sm *SnapshotManager) LoadSnapshot(ctx context.Context, experimentID string) (snapshot map[string]any, ok bool, err error) {
+	if snap, ok := sm.GetSnapshot(experimentID); ok {
+		return snap, true, nil
+	}
+	if sm.queries == nil {
+		return nil, false, nil
+	}
+	rec, err := sm.queries.GetSnapshot(ctx, experimentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("load snapshot: %w", err)
+	}
+	if err := json.Unmarshal(rec.Data, &snapshot); err != nil {
+		return nil, false, fmt.Errorf("decode snapshot: %w", err)
+	}
+	return snapshot, true, nil
+}
+
 // DeleteSnapshot removes the snapshot for an experiment
 func (sm *SnapshotManager) DeleteSnapshot(experimentID string) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 	delete(sm.snapshots, experimentID)
+	delete(sm.latest, experimentID)
+	delete(sm.captured, experimentID)
 }
 
-// ListSnapshots returns all stored snapshots
+// ListSnapshots returns the snapshot each experiment captured last
 func (sm *SnapshotManager) ListSnapshots() map[string]map[string]any {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
 	result := make(map[string]map[string]any, len(sm.snapshots))
-	for k, v := range sm.snapshots {
-		result[k] = v
+	for k, layers := range sm.snapshots {
+		result[k] = layers[sm.latest[k]]
 	}
 	return result
 }
@@ -132,22 +218,23 @@ func (sm *SnapshotManager) RestoreFromSnapshot(
 		return nil, fmt.Errorf("no snapshot found for experiment %s", experimentID)
 	}
 
-	restored := map[string]any{
+	return map[string]any{
 		"experiment_id": experimentID,
-		"actions":       []map[string]any{},
-	}
+		"actions":       sm.DetectDrift(snapshot, currentState),
+	}, nil
+}
 
+// DetectDrift compares a snapshot, held in memory or loaded back from the
+// database, with current state and returns the drift actions found
+func (sm *SnapshotManager) DetectDrift(snapshot, currentState map[string]any) []map[string]any {
 	snapshotType, _ := snapshot["type"].(string)
 	switch snapshotType {
 	case "k8s":
-		actions := sm.restoreK8s(snapshot, currentState)
-		restored["actions"] = actions
+		return sm.restoreK8s(snapshot, currentState)
 	case "aws":
-		actions := sm.restoreAws(snapshot, currentState)
-		restored["actions"] = actions
+		return sm.restoreAws(snapshot, currentState)
 	}
-
-	return restored, nil
+	return []map[string]any{}
 }
 
 // restoreK8s detects drift between snapshot and current K8s state.
@@ -203,7 +290,7 @@ func (sm *SnapshotManager) restoreK8s(snapshot, currentState map[string]any) []m
 }
 
 // restoreAws detects drift between snapshot and current AWS state.
-// Checks for EC2 instance state changes.
+// Checks EC2 instance states and RDS cluster or instance status.
 func (sm *SnapshotManager) restoreAws(snapshot, currentState map[string]any) []map[string]any {
 	actions := []map[string]any{}
 
@@ -213,7 +300,29 @@ func (sm *SnapshotManager) restoreAws(snapshot, currentState map[string]any) []m
 	}
 
 	resourceType, _ := snapshot["resource_type"].(string)
-	if resourceType == "ec2" {
+	switch resourceType {
+	case "ec2":
+		// Steady-state captures hold every targeted instance
+		if instances, ok := state["instances"].(map[string]any); ok {
+			current, _ := currentState["instances"].(map[string]any)
+			for _, instanceID := range slices.Sorted(maps.Keys(instances)) {
+				snapshotState, _ := instances[instanceID].(string)
+				currentInstanceState, _ := current[instanceID].(string)
+				if currentInstanceState == "" {
+					currentInstanceState = "missing"
+				}
+				if snapshotState != "" && currentInstanceState != snapshotState {
+					actions = append(actions, map[string]any{
+						"action":         "state_drift",
+						"instance_id":    instanceID,
+						"snapshot_state": snapshotState,
+						"current_state":  currentInstanceState,
+					})
+				}
+			}
+			return actions
+		}
+
 		snapshotState, _ := state["state"].(string)
 		instanceID, _ := state["instance_id"].(string)
 		currentInstanceState, _ := currentState["state"].(string)
@@ -226,6 +335,20 @@ func (sm *SnapshotManager) restoreAws(snapshot, currentState map[string]any) []m
 				"current_state":  currentInstanceState,
 			})
 		}
+
+	case "rds_cluster", "rds_instance":
+		snapshotStatus, _ := state["status"].(string)
+		currentStatus, _ := currentState["status"].(string)
+		if snapshotStatus != "" && currentStatus != "" && currentStatus != snapshotStatus {
+			resourceID, _ := snapshot["resource_id"].(string)
+			actions = append(actions, map[string]any{
+				"action":          "status_drift",
+				"resource_type":   resourceType,
+				"resource_id":     resourceID,
+				"snapshot_status": snapshotStatus,
+				"current_status":  currentStatus,
+			})
+		}
 	}
 
 	return actions
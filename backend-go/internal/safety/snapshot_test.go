@@ -2,6 +2,7 @@ package safety
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,6 +52,22 @@ func TestSnapshotManagerCaptureAWS(t *testing.T) {
 	assert.Equal(t, snap, retrieved)
 }
 
+func TestSnapshotManagerKeepsEachLayer(t *testing.T) {
+	sm := NewSnapshotManager(nil)
+
+	k8sSnap, _ := sm.CaptureK8sSnapshot(context.Background(), "exp-1", "default", map[string]any{})
+	awsSnap, _ := sm.CaptureAWSSnapshot(context.Background(), "exp-1", "ec2", "i-1", map[string]any{})
+
+	assert.Equal(t, map[string]map[string]any{"k8s": k8sSnap, "aws": awsSnap}, sm.GetSnapshots("exp-1"))
+	latest, ok := sm.GetSnapshot("exp-1")
+	require.True(t, ok)
+	assert.Equal(t, awsSnap, latest)
+	assert.Len(t, sm.ListSnapshots(), 1)
+
+	sm.DeleteSnapshot("exp-1")
+	assert.Empty(t, sm.GetSnapshots("exp-1"))
+}
+
 func TestSnapshotManagerDelete(t *testing.T) {
 	sm := NewSnapshotManager(nil)
 
@@ -130,6 +147,29 @@ func TestRestoreFromSnapshotK8sMissingPods(t *testing.T) {
 	assert.Contains(t, missingNames, "web-3")
 }
 
+func TestDetectDriftPersistedSnapshot(t *testing.T) {
+	sm := NewSnapshotManager(nil)
+
+	snapshot, _ := sm.CaptureK8sSnapshot(context.Background(), "exp-1", "default", map[string]any{
+		"pods": []any{
+			map[string]any{"name": "web-1", "phase": "Running"},
+			map[string]any{"name": "web-2", "phase": "Running"},
+		},
+	})
+
+	// Round-trip through JSON as the snapshots table does
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	var loaded map[string]any
+	require.NoError(t, json.Unmarshal(data, &loaded))
+
+	actions := sm.DetectDrift(loaded, map[string]any{
+		"pods": []any{map[string]any{"name": "web-1"}},
+	})
+	require.Len(t, actions, 1)
+	assert.Equal(t, "web-2", actions[0]["name"])
+}
+
 func TestRestoreFromSnapshotK8sNoDrift(t *testing.T) {
 	sm := NewSnapshotManager(nil)
 
@@ -199,6 +239,40 @@ func TestRestoreFromSnapshotAWSNoDrift(t *testing.T) {
 	assert.Empty(t, actions)
 }
 
+func TestRestoreFromSnapshotAWSInstancesDrift(t *testing.T) {
+	sm := NewSnapshotManager(nil)
+
+	state := map[string]any{"instances": map[string]any{"i-1": "running", "i-2": "running", "i-3": "running"}}
+	_, _ = sm.CaptureAWSSnapshot(context.Background(), "exp-2", "ec2", "i-1,i-2,i-3", state)
+
+	// i-2 was left stopped and i-3 no longer exists
+	currentState := map[string]any{"instances": map[string]any{"i-1": "running", "i-2": "stopped"}}
+
+	result, err := sm.RestoreFromSnapshot("exp-2", currentState)
+	require.NoError(t, err)
+
+	actions, _ := result["actions"].([]map[string]any)
+	require.Len(t, actions, 2)
+	assert.Equal(t, "i-2", actions[0]["instance_id"])
+	assert.Equal(t, "stopped", actions[0]["current_state"])
+	assert.Equal(t, "i-3", actions[1]["instance_id"])
+	assert.Equal(t, "missing", actions[1]["current_state"])
+}
+
+func TestRestoreFromSnapshotRDSStatusDrift(t *testing.T) {
+	sm := NewSnapshotManager(nil)
+	_, _ = sm.CaptureAWSSnapshot(context.Background(), "exp-3", "rds_instance", "orders-db", map[string]any{"status": "available"})
+
+	result, err := sm.RestoreFromSnapshot("exp-3", map[string]any{"status": "rebooting"})
+	require.NoError(t, err)
+
+	actions, _ := result["actions"].([]map[string]any)
+	require.Len(t, actions, 1)
+	assert.Equal(t, "status_drift", actions[0]["action"])
+	assert.Equal(t, "orders-db", actions[0]["resource_id"])
+	assert.Equal(t, "rebooting", actions[0]["current_status"])
+}
+
 func TestRestoreFromSnapshotNotFound(t *testing.T) {
 	sm := NewSnapshotManager(nil)
 
@@ -206,3 +280,49 @@ func TestRestoreFromSnapshotNotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "no snapshot found")
 }
+
+func TestSnapshotManagerEvictsOldest(t *testing.T) {
+	ctx := context.Background()
+	sm := NewSnapshotManager(nil)
+	sm.capacity = 3
+	for _, id := range []string{"exp-1", "exp-2", "exp-3"} {
+		_, err := sm.CaptureK8sSnapshot(ctx, id, "default", map[string]any{})
+		require.NoError(t, err)
+	}
+	// Re-capturing exp-1 makes exp-2 the oldest, without evicting anything
+	_, err := sm.CaptureK8sSnapshot(ctx, "exp-1", "default", map[string]any{})
+	require.NoError(t, err)
+	assert.Len(t, sm.ListSnapshots(), 3)
+
+	_, err = sm.CaptureK8sSnapshot(ctx, "exp-4", "default", map[string]any{})
+	require.NoError(t, err)
+	_, ok := sm.GetSnapshot("exp-2")
+	assert.False(t, ok, "the oldest snapshot should be evicted")
+	for _, id := range []string{"exp-1", "exp-3", "exp-4"} {
+		_, ok := sm.GetSnapshot(id)
+		assert.True(t, ok, id)
+	}
+}
+
+func TestSnapshotManagerKeepsActiveSnapshots(t *testing.T) {
+	ctx := context.Background()
+	sm := NewSnapshotManager(nil)
+	sm.capacity = 2
+	active := map[string]bool{"exp-1": true}
+	sm.SetActiveCheck(func(id string) bool { return active[id] })
+
+	for _, id := range []string{"exp-1", "exp-2", "exp-3"} {
+		_, err := sm.CaptureK8sSnapshot(ctx, id, "default", map[string]any{})
+		require.NoError(t, err)
+	}
+	_, ok := sm.GetSnapshot("exp-1")
+	assert.True(t, ok, "an active experiment's snapshot is never evicted")
+	_, ok = sm.GetSnapshot("exp-2")
+	assert.False(t, ok)
+
+	// With every snapshot active, the store grows instead
+	active["exp-3"] = true
+	_, err := sm.CaptureK8sSnapshot(ctx, "exp-4", "default", map[string]any{})
+	require.NoError(t, err)
+	assert.Len(t, sm.ListSnapshots(), 3)
+}
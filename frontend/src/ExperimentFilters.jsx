@@ -1,5 +1,5 @@
 const STATUSES = ["all", "running", "completed", "failed", "rolled_back", "emergency_stopped", "pending"];
-const CHAOS_TYPES = ["all", "pod_delete", "network_latency", "network_loss", "cpu_stress", "memory_stress", "ec2_stop", "rds_failover", "route_blackhole"];
+const CHAOS_TYPES = ["all", "pod_delete", "network_latency", "network_loss", "cpu_stress", "memory_stress", "ec2_stop", "rds_failover", "rds_reboot", "route_blackhole", "lambda_throttle", "subnet_isolate"];
 const SORT_OPTIONS = [
   { value: "newest", label: "Newest first" },
   { value: "oldest", label: "Oldest first" },
@@ -6,7 +6,7 @@ import ProbeConfigSection from "./ProbeConfigSection";
 
 const CHAOS_TYPES = {
   "K8s": ["pod_delete", "network_latency", "network_loss", "cpu_stress", "memory_stress"],
-  "AWS": ["ec2_stop", "rds_failover", "route_blackhole"],
+  "AWS": ["ec2_stop", "rds_failover", "rds_reboot", "route_blackhole", "lambda_throttle", "subnet_isolate"],
 };
 
 const PARAM_FIELDS = {
@@ -22,10 +22,16 @@ const PARAM_FIELDS = {
   ],
   ec2_stop: [{ key: "instance_ids", label: "Instance IDs (comma-separated)", type: "text", placeholder: "i-abc123" }],
   rds_failover: [{ key: "db_cluster_id", label: "DB Cluster ID", type: "text", placeholder: "my-cluster" }],
+  rds_reboot: [{ key: "db_instance_id", label: "DB Instance ID", type: "text", placeholder: "my-instance" }],
   route_blackhole: [
     { key: "route_table_id", label: "Route Table ID", type: "text", placeholder: "rtb-abc123" },
     { key: "destination_cidr", label: "Destination CIDR", type: "text", placeholder: "10.0.0.0/24" },
   ],
+  lambda_throttle: [{ key: "function_name", label: "Function Name", type: "text", placeholder: "my-function" }],
+  subnet_isolate: [
+    { key: "subnet_id", label: "Subnet ID", type: "text", placeholder: "subnet-abc123" },
+    { key: "deny_acl_id", label: "Deny-all ACL ID", type: "text", placeholder: "acl-abc123" },
+  ],
 };
 
 const DEFAULT_SAFETY = {